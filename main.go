@@ -10,6 +10,8 @@ import (
 
 	"github.com/ekristen/gcp-nuke/pkg/common"
 
+	_ "github.com/ekristen/gcp-nuke/pkg/commands/config"
+	_ "github.com/ekristen/gcp-nuke/pkg/commands/inventory"
 	_ "github.com/ekristen/gcp-nuke/pkg/commands/list"
 	_ "github.com/ekristen/gcp-nuke/pkg/commands/project"
 	_ "github.com/ekristen/gcp-nuke/pkg/commands/run"
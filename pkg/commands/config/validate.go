@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/gcp-nuke/pkg/commands/global"
+	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/config"
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+
+	_ "github.com/ekristen/gcp-nuke/resources"
+)
+
+// executeValidate loads the config and reports its problems, it fails when there is at least one so that it can be
+// used in CI. The regions are only checked against the enabled regions with --project-id.
+func executeValidate(ctx context.Context, cmd *cli.Command) error {
+	logger := logrus.StandardLogger()
+
+	parsedConfig, err := libconfig.New(libconfig.Options{
+		Path:         cmd.String("config"),
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+		Log:          logger.WithField("component", "config"),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to load config %s: %w", cmd.String("config"), err)
+	}
+
+	var enabledRegions []string
+	if projectID := cmd.String("project-id"); projectID != "" {
+		endpoints, err := global.Endpoints(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Note: invalid endpoints of the config are reported with the other problems, the flags are used alone then
+		if addresses, err := config.Endpoints(cmd.String("config")); err == nil {
+			if merged, err := endpoints.Merge(addresses); err == nil {
+				endpoints = merged
+			}
+		}
+
+		gcp, err := gcputil.New(ctx, projectID, global.Impersonation(cmd), nil, endpoints)
+		if err != nil {
+			return err
+		}
+
+		enabledRegions = gcp.Regions
+	} else {
+		logger.Info("no --project-id given, the regions are not checked against the enabled regions")
+	}
+
+	problems := config.Validate(parsedConfig, enabledRegions)
+	problems = append(problems, config.ValidateExtensions(cmd.String("config"))...)
+
+	for _, problem := range problems {
+		logger.Error(problem)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("config %s has %d problem(s)", cmd.String("config"), len(problems))
+	}
+
+	logger.Infof("config %s is valid", cmd.String("config"))
+
+	return nil
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "path to config file",
+			Value: "config.yaml",
+		},
+		&cli.StringFlag{
+			Name:    "project-id",
+			Usage:   "check the regions against the regions enabled for this GCP project",
+			Sources: cli.EnvVars("GCP_NUKE_PROJECT_ID"),
+		},
+	}
+	flags = append(flags, global.ImpersonateFlags()...)
+	flags = append(flags, global.EndpointFlags()...)
+
+	common.RegisterCommand(&cli.Command{
+		Name: "validate-config",
+		Usage: "check that the resource types, filter properties, settings and regions of a config exist, fails " +
+			"when they do not",
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: executeValidate,
+	})
+}
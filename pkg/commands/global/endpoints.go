@@ -0,0 +1,44 @@
+package global
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+)
+
+// EndpointFlags are the flags used by the commands that talk to GCP to call the APIs on other endpoints than the
+// public ones
+func EndpointFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "universe-domain",
+			Usage:   "universe domain of the APIs, e.g. of a sovereign cloud (default: googleapis.com)",
+			Sources: cli.EnvVars("GCP_NUKE_UNIVERSE_DOMAIN", "GOOGLE_CLOUD_UNIVERSE_DOMAIN"),
+		},
+		&cli.StringSliceFlag{
+			Name: "endpoint",
+			Usage: "address the connections to an API go to, in the form api=address (e.g. " +
+				"storage=storage-myendpoint.p.googleapis.com), takes precedence over the endpoints of the config",
+			Sources: cli.EnvVars("GCP_NUKE_ENDPOINTS"),
+		},
+	}
+}
+
+// Endpoints returns the endpoints configured with the EndpointFlags, the commands with a config merge the endpoints of
+// the config into them, see gcputil.Endpoints.Merge
+func Endpoints(cmd *cli.Command) (*gcputil.Endpoints, error) {
+	addresses := make(map[string]string)
+	for _, value := range cmd.StringSlice("endpoint") {
+		api, address, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(api) == "" {
+			return nil, fmt.Errorf("invalid endpoint %q, must be in the form api=address", value)
+		}
+
+		addresses[strings.TrimSpace(api)] = address
+	}
+
+	return gcputil.ParseEndpoints(addresses, cmd.String("universe-domain"))
+}
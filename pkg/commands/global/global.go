@@ -9,6 +9,8 @@ import (
 	"github.com/ekristen/libnuke/pkg/log"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/graph"
 )
 
 func Flags() []cli.Flag {
@@ -99,9 +101,21 @@ func Before(ctx context.Context, cmd *cli.Command) (context.Context, error) {
 		logrus.SetLevel(logrus.ErrorLevel)
 	}
 
+	if logrus.IsLevelEnabled(logrus.DebugLevel) {
+		validateGraph()
+	}
+
 	return ctx, nil
 }
 
+// validateGraph warns about the cycles of the dependencies between the resource types and the dependencies on resource
+// types that are not registered, a missing or a wrong DependsOn shows up as removals that are retried until they fail
+func validateGraph() {
+	for _, err := range graph.FromRegistry().Validate() {
+		logrus.WithField("component", "graph").Warn(err)
+	}
+}
+
 type StructuredHook struct{}
 
 func (h *StructuredHook) Levels() []logrus.Level {
@@ -0,0 +1,50 @@
+package global
+
+import (
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+)
+
+// ImpersonateFlags are the flags used by the commands that talk to GCP to impersonate a service account
+func ImpersonateFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "impersonate-service-account",
+			Usage:   "impersonate a service account for all API calls",
+			Sources: cli.EnvVars("GCP_NUKE_IMPERSONATE_SERVICE_ACCOUNT"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "impersonate-delegates",
+			Usage:   "chain of service accounts to go through to impersonate the service account, in order",
+			Sources: cli.EnvVars("GCP_NUKE_IMPERSONATE_DELEGATES"),
+		},
+		&cli.DurationFlag{
+			Name:    "impersonate-lifetime",
+			Usage:   "lifetime of each impersonated access token, tokens are refreshed before they expire (max: 12h)",
+			Sources: cli.EnvVars("GCP_NUKE_IMPERSONATE_LIFETIME"),
+			Value:   gcputil.DefaultImpersonateLifetime,
+		},
+		&cli.StringSliceFlag{
+			Name:    "impersonate-scopes",
+			Usage:   "OAuth scopes to request for the impersonated access token",
+			Sources: cli.EnvVars("GCP_NUKE_IMPERSONATE_SCOPES"),
+			Value:   gcputil.DefaultImpersonateScopes,
+		},
+	}
+}
+
+// Impersonation returns the impersonation configured with the ImpersonateFlags, or nil if no service account is
+// being impersonated
+func Impersonation(cmd *cli.Command) *gcputil.Impersonation {
+	if cmd.String("impersonate-service-account") == "" {
+		return nil
+	}
+
+	return &gcputil.Impersonation{
+		ServiceAccount: cmd.String("impersonate-service-account"),
+		Delegates:      cmd.StringSlice("impersonate-delegates"),
+		Lifetime:       cmd.Duration("impersonate-lifetime"),
+		Scopes:         cmd.StringSlice("impersonate-scopes"),
+	}
+}
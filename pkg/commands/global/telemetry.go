@@ -0,0 +1,62 @@
+package global
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/telemetry"
+)
+
+// TelemetryFlags are the flags used by the commands that nuke to export their traces and metrics
+func TelemetryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "otlp-endpoint",
+			Usage:   "export the traces and the metrics to this OTLP/HTTP endpoint, e.g. http://localhost:4318",
+			Sources: cli.EnvVars("GCP_NUKE_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT"),
+		},
+		&cli.StringFlag{
+			Name:    "metrics-listen",
+			Usage:   "serve the Prometheus metrics on /metrics of this address, e.g. :9090",
+			Sources: cli.EnvVars("GCP_NUKE_METRICS_LISTEN"),
+		},
+		&cli.StringFlag{
+			Name:    "metrics-push-gateway",
+			Usage:   "push the Prometheus metrics to this push gateway after every run, e.g. http://pushgateway:9091",
+			Sources: cli.EnvVars("GCP_NUKE_METRICS_PUSH_GATEWAY"),
+		},
+		&cli.StringFlag{
+			Name:    "metrics-push-job",
+			Usage:   "job the metrics are pushed as to the push gateway",
+			Value:   telemetry.DefaultPushJob,
+			Sources: cli.EnvVars("GCP_NUKE_METRICS_PUSH_JOB"),
+		},
+	}
+}
+
+// Telemetry sets up the telemetry configured with the TelemetryFlags, the listers are instrumented when it is enabled
+func Telemetry(ctx context.Context, cmd *cli.Command, logger *logrus.Logger) (*telemetry.Telemetry, error) {
+	opts := telemetry.Options{
+		OTLPEndpoint:  cmd.String("otlp-endpoint"),
+		MetricsListen: cmd.String("metrics-listen"),
+		PushGateway:   cmd.String("metrics-push-gateway"),
+		PushJob:       cmd.String("metrics-push-job"),
+		Version:       common.AppVersion.Summary,
+		Logger:        logger,
+	}
+
+	t, err := telemetry.Setup(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Enabled() {
+		nuke.InstrumentListers()
+	}
+
+	return t, nil
+}
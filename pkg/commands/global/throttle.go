@@ -0,0 +1,36 @@
+package global
+
+import (
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+)
+
+// ThrottleFlags are the flags used by the commands that talk to GCP to limit the rate of the API calls
+func ThrottleFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name: "max-requests-per-second",
+			Usage: "maximum requests per second per API, in the form api=limit (e.g. compute=10) or limit for all " +
+				"APIs, 0 disables the limit",
+			Sources: cli.EnvVars("GCP_NUKE_MAX_REQUESTS_PER_SECOND"),
+		},
+	}
+}
+
+// Throttle returns the throttle configured with the ThrottleFlags, every run is throttled so that the API calls that
+// are rate limited by GCP are retried
+func Throttle(cmd *cli.Command) (*gcputil.Throttle, error) {
+	limits, err := RateLimits(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcputil.NewThrottle(limits), nil
+}
+
+// RateLimits returns the rate limits configured with the ThrottleFlags, for the commands that create a throttle for
+// each of their runs
+func RateLimits(cmd *cli.Command) (gcputil.RateLimits, error) {
+	return gcputil.ParseRateLimits(cmd.StringSlice("max-requests-per-second"))
+}
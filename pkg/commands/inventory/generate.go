@@ -0,0 +1,54 @@
+package inventory
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/commands/global"
+	"github.com/ekristen/gcp-nuke/pkg/common"
+)
+
+// executeGenerate lists every resource and writes a starter config with a filter that protects each of them
+func executeGenerate(ctx context.Context, cmd *cli.Command) error {
+	inv, listingErrors, err := collect(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	skipped := 0
+	if err := write(cmd, "config", len(inv.Resources), func(w io.Writer) error {
+		skipped, err = inv.WriteConfig(w, time.Now())
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if skipped > 0 {
+		logrus.Warnf("%d resource(s) have neither a name nor a property to filter on, they are not protected", skipped)
+	}
+
+	return listingErrors.Check(cmd.Bool("strict-listing"))
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "out",
+			Usage: "write the config to this path instead of stdout",
+		},
+	}
+	flags = append(flags, listFlags()...)
+
+	common.RegisterCommand(&cli.Command{
+		Name: "generate-config",
+		Usage: "write a starter config that protects every resource of a project or an organization, delete the " +
+			"filters of the resources to remove",
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: executeGenerate,
+	})
+}
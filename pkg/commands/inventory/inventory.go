@@ -0,0 +1,351 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/sync/errgroup"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/commands/global"
+	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+	"github.com/ekristen/gcp-nuke/pkg/inventory"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+
+	_ "github.com/ekristen/gcp-nuke/resources"
+)
+
+// listConcurrency is the number of resource types that are listed at the same time
+const listConcurrency = 16
+
+func execute(ctx context.Context, cmd *cli.Command) error {
+	format, err := inventory.ParseFormat(cmd.String("format"))
+	if err != nil {
+		return err
+	}
+
+	groupBy := cmd.String("group-by")
+	if err := inventory.ValidateGroupBy(groupBy); err != nil {
+		return err
+	}
+
+	inv, listingErrors, err := collect(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := write(cmd, "inventory", len(inv.Resources), func(w io.Writer) error {
+		return inv.Encode(w, format, groupBy)
+	}); err != nil {
+		return err
+	}
+
+	return listingErrors.Check(cmd.Bool("strict-listing"))
+}
+
+// collect lists every resource of the project and the organization given on the command line. The resource types that
+// could not be listed completely are logged and returned.
+func collect(ctx context.Context, cmd *cli.Command) (*inventory.Inventory, *nuke.ListingErrors, error) {
+	projectID := cmd.String("project-id")
+	organizationID := cmd.String("organization-id")
+
+	if projectID == "" && organizationID == "" {
+		return nil, nil, fmt.Errorf("at least one of --project-id or --organization-id must be provided")
+	}
+
+	throttle, err := global.Throttle(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoints, err := global.Endpoints(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gcp, err := gcputil.New(ctx, projectID, global.Impersonation(cmd), throttle, endpoints)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if projectID != "" && !gcp.HasProjects() {
+		return nil, nil, fmt.Errorf("no projects found")
+	}
+
+	if organizationID != "" && gcp.GetOrganization(organizationID) == nil {
+		return nil, nil, fmt.Errorf("organization %s not found or not accessible", organizationID)
+	}
+
+	defer closeListers()
+
+	inv := &inventory.Inventory{Project: projectID, Organization: organizationID}
+	listingErrors := &nuke.ListingErrors{}
+	cache := nuke.NewListingCache()
+
+	var jobs []listJob
+
+	if organizationID != "" {
+		for _, resourceType := range resolveResourceTypes(cmd, nuke.Organization) {
+			jobs = append(jobs, listJob{resourceType: resourceType, region: "global", opts: &nuke.ListerOpts{
+				Organization:  ptr.String(organizationID),
+				Region:        ptr.String("global"),
+				ClientOptions: gcp.GetClientOptions(),
+				ListingErrors: listingErrors,
+				Cache:         cache,
+
+				GRPCClientOptions:     gcp.GetGRPCClientOptions(),
+				EmulatorClientOptions: gcp.GetEmulatorClientOptions(),
+			}})
+		}
+	}
+
+	if projectID != "" {
+		regions, err := resolveRegions(cmd, gcp)
+		if err != nil {
+			return nil, nil, err
+		}
+		inv.Regions = regions
+
+		resourceTypes := resolveResourceTypes(cmd, nuke.Project)
+		for _, region := range regions {
+			opts := &nuke.ListerOpts{
+				Project:       ptr.String(projectID),
+				Region:        ptr.String(region),
+				Zones:         gcp.GetZones(region),
+				EnabledAPIs:   gcp.GetEnabledAPIs(),
+				ClientOptions: gcp.GetClientOptions(),
+				ListingErrors: listingErrors,
+				Cache:         cache,
+
+				GRPCClientOptions:     gcp.GetGRPCClientOptions(),
+				EmulatorClientOptions: gcp.GetEmulatorClientOptions(),
+			}
+
+			for _, resourceType := range resourceTypes {
+				jobs = append(jobs, listJob{resourceType: resourceType, region: region, opts: opts})
+			}
+		}
+	}
+
+	if err := list(ctx, inv, jobs); err != nil {
+		return nil, nil, err
+	}
+
+	if errs := listingErrors.Errors(); len(errs) > 0 {
+		logrus.Warnf("%d resource type(s) could not be listed completely, the inventory is incomplete:", len(errs))
+		for _, err := range errs {
+			logrus.Warnf("> %s", err)
+		}
+	}
+
+	return inv, listingErrors, nil
+}
+
+// write writes the output of the command to the path given by --out, or to stdout
+func write(cmd *cli.Command, what string, count int, encode func(w io.Writer) error) error {
+	path := cmd.String("out")
+	if path == "" {
+		return encode(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := encode(f); err != nil {
+		return err
+	}
+
+	logrus.Infof("%s with %d resource(s) written to %s", what, count, path)
+
+	return nil
+}
+
+// listJob is the listing of a resource type for a region
+type listJob struct {
+	resourceType string
+	region       string
+	opts         *nuke.ListerOpts
+}
+
+// list runs the listers of the jobs and adds the resources to the inventory. The resource types are listed
+// concurrently, the regions of a resource type one after another since a lister is not safe for concurrent use. The
+// listers that skip the region are ignored, the other errors are logged, the listers that could not list completely
+// are recorded in the listing errors of their options.
+func list(ctx context.Context, inv *inventory.Inventory, jobs []listJob) error {
+	var mu sync.Mutex
+
+	var resourceTypes []string
+	jobsByType := make(map[string][]listJob)
+	for _, job := range jobs {
+		if _, ok := jobsByType[job.resourceType]; !ok {
+			resourceTypes = append(resourceTypes, job.resourceType)
+		}
+		jobsByType[job.resourceType] = append(jobsByType[job.resourceType], job)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(listConcurrency)
+
+	for _, resourceType := range resourceTypes {
+		g.Go(func() error {
+			lister := registry.GetLister(resourceType)
+
+			for _, job := range jobsByType[resourceType] {
+				resources, err := lister.List(ctx, job.opts)
+				if err != nil {
+					var skipErr liberror.ErrSkipRequest
+					if errors.As(err, &skipErr) {
+						logrus.WithField("type", job.resourceType).WithField("region", job.region).
+							Trace("skipping request")
+						continue
+					}
+
+					if errors.Is(err, context.Canceled) {
+						return err
+					}
+
+					logrus.WithError(err).WithField("type", job.resourceType).WithField("region", job.region).
+						Error("unable to list resources")
+					continue
+				}
+
+				mu.Lock()
+				if job.opts.Organization != nil {
+					inv.AddOrganization(job.resourceType, resources)
+				} else {
+					inv.Add(job.resourceType, job.region, resources)
+				}
+				mu.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+// resolveRegions returns the regions given by --region, "all" is every enabled region, "global" and the pseudo-regions
+// of the multi-regions and dual-regions
+func resolveRegions(cmd *cli.Command, gcp *gcputil.GCP) ([]string, error) {
+	regions := cmd.StringSlice("region")
+	if slices.Contains(regions, "all") {
+		regions = append(slices.Clone(gcp.Regions), gcputil.PseudoRegions()...)
+	}
+
+	resolved := make([]string, 0, len(regions))
+	for _, region := range regions {
+		if slices.Contains(resolved, region) {
+			continue
+		}
+
+		if gcputil.IsPseudoRegion(region) && !slices.Contains(gcputil.PseudoRegions(), region) {
+			return nil, fmt.Errorf("unknown region %s, the supported pseudo-regions are: %s",
+				region, strings.Join(gcputil.PseudoRegions(), ", "))
+		}
+
+		resolved = append(resolved, region)
+	}
+
+	return resolved, nil
+}
+
+// resolveResourceTypes returns the resource types of the scope, restricted by --include and --exclude
+func resolveResourceTypes(cmd *cli.Command, scope registry.Scope) types.Collection {
+	return types.ResolveResourceTypes(registry.GetNamesForScope(scope),
+		[]types.Collection{cmd.StringSlice("include")},
+		[]types.Collection{cmd.StringSlice("exclude")},
+		nil, nil)
+}
+
+// closeListers closes the listers that hold a client, GCP rest clients have to be closed properly
+func closeListers() {
+	for _, l := range registry.GetListers() {
+		lc, ok := l.(registry.ListerWithClose)
+		if ok {
+			lc.Close()
+		}
+	}
+}
+
+// listFlags are the flags to select what is listed, shared by inventory and generate-config
+func listFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "project-id",
+			Usage:   "which GCP project should be listed",
+			Sources: cli.EnvVars("GCP_NUKE_PROJECT_ID"),
+		},
+		&cli.StringFlag{
+			Name:    "organization-id",
+			Usage:   "which GCP organization should have its organization level resources listed",
+			Sources: cli.EnvVars("GCP_NUKE_ORGANIZATION_ID"),
+		},
+		&cli.StringSliceFlag{
+			Name:  "region",
+			Usage: "the regions to list, \"all\" is every enabled region, \"global\" and the multi-regions",
+			Value: []string{"all"},
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only include this specific resource",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "exclude this specific resource",
+		},
+		&cli.BoolFlag{
+			Name:    "strict-listing",
+			Usage:   "fail when a resource type could not be listed completely",
+			Sources: cli.EnvVars("GCP_NUKE_STRICT_LISTING"),
+		},
+	}
+	flags = append(flags, global.ImpersonateFlags()...)
+	flags = append(flags, global.ThrottleFlags()...)
+	flags = append(flags, global.EndpointFlags()...)
+
+	return flags
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "the format of the inventory: table, json or csv",
+			Value: string(inventory.FormatTable),
+		},
+		&cli.StringFlag{
+			Name:  "group-by",
+			Usage: "group the resources, with a count per group, by type, region or label:<key>",
+		},
+		&cli.StringFlag{
+			Name:  "out",
+			Usage: "write the inventory to this path instead of stdout",
+		},
+	}
+	flags = append(flags, listFlags()...)
+
+	common.RegisterCommand(&cli.Command{
+		Name:   "inventory",
+		Usage:  "list every resource of a project or an organization, without a config and without removing anything",
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: execute,
+	})
+}
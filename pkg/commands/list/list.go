@@ -2,15 +2,19 @@ package list
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 
 	"github.com/ekristen/gcp-nuke/pkg/commands/global"
 	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/graph"
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
 
 	_ "github.com/ekristen/gcp-nuke/resources"
@@ -44,14 +48,62 @@ func execute(_ context.Context, _ *cli.Command) error {
 	return nil
 }
 
+// executeGraph renders the dependency graph of the resource types and logs the deletion order, it fails when the
+// graph has a cycle or a dependency on a resource type that is not registered
+func executeGraph(_ context.Context, cmd *cli.Command) error {
+	format, err := graph.ParseFormat(cmd.String("format"))
+	if err != nil {
+		return err
+	}
+
+	g := graph.FromRegistry()
+
+	if err := g.Encode(cmd.Root().Writer, format); err != nil {
+		return err
+	}
+
+	if stages, err := g.Order(); err == nil {
+		logrus.Infof("Deletion order (%d stages):", len(stages))
+		for i, stage := range stages {
+			logrus.Infof("> %d: %s", i+1, strings.Join(stage, ", "))
+		}
+	}
+
+	errs := g.Validate()
+	for _, err := range errs {
+		logrus.Error(err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("the dependency graph has %d problem(s)", len(errs))
+	}
+
+	return nil
+}
+
 func init() {
+	graphCmd := &cli.Command{
+		Name:  "graph",
+		Usage: "render the dependency graph of the resource types and check it for cycles",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "the format of the graph: dot, mermaid or json",
+				Value: string(graph.FormatDOT),
+			},
+		}, global.Flags()...),
+		Before: global.Before,
+		Action: executeGraph,
+	}
+
 	cmd := &cli.Command{
-		Name:    "resource-types",
-		Aliases: []string{"list-resources"},
-		Usage:   "list available resources to nuke",
-		Flags:   global.Flags(),
-		Before:  global.Before,
-		Action:  execute,
+		Name:     "resource-types",
+		Aliases:  []string{"list-resources"},
+		Usage:    "list available resources to nuke",
+		Flags:    global.Flags(),
+		Before:   global.Before,
+		Action:   execute,
+		Commands: []*cli.Command{graphCmd},
 	}
 
 	common.RegisterCommand(cmd)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
 	"github.com/ekristen/gcp-nuke/pkg/commands/global"
@@ -32,7 +33,12 @@ type CredentialsJSON struct {
 }
 
 func execute(ctx context.Context, cmd *cli.Command) error {
-	project, err := gcputil.New(ctx, cmd.String("project-id"), cmd.String("impersonate-service-account"))
+	endpoints, err := global.Endpoints(cmd)
+	if err != nil {
+		return err
+	}
+
+	project, err := gcputil.New(ctx, cmd.String("project-id"), global.Impersonation(cmd), nil, endpoints)
 	if err != nil {
 		return err
 	}
@@ -48,14 +54,24 @@ func execute(ctx context.Context, cmd *cli.Command) error {
 		return err
 	}
 
-	var parsed CredentialsJSON
-	if err := json.Unmarshal(creds.JSON, &parsed); err != nil {
-		return err
+	// Note: credentials from the metadata server do not have a JSON representation
+	parsed := CredentialsJSON{Type: "metadata_server"}
+	if len(creds.JSON) > 0 {
+		if err := json.Unmarshal(creds.JSON, &parsed); err != nil {
+			return err
+		}
+	}
+
+	principal, err := project.GetPrincipal(ctx)
+	if err != nil {
+		logrus.WithError(err).Warn("unable to determine the effective principal")
+		principal = "unknown"
 	}
 
 	fmt.Println("")
 	fmt.Println("Authentication:")
 	fmt.Println("--------------------------------------------------")
+	fmt.Println(">       Principal:", principal)
 	fmt.Println(">            Type:", parsed.Type)
 
 	switch parsed.Type {
@@ -71,9 +87,15 @@ func execute(ctx context.Context, cmd *cli.Command) error {
 				"https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/", ""))
 		fmt.Println(">     Source.File:", parsed.CredentialSource.File)
 		fmt.Println(">   Source.Format:", parsed.CredentialSource.Format.Type)
-		if cmd.String("impersonate-service-account") != "" {
-			fmt.Println(">   Impersonating:", cmd.String("impersonate-service-account"))
+	}
+
+	if impersonation := project.GetImpersonation(); impersonation != nil {
+		fmt.Println(">   Impersonating:", impersonation.ServiceAccount)
+		if len(impersonation.Delegates) > 0 {
+			fmt.Println(">       Delegates:", strings.Join(impersonation.Delegates, " -> "))
 		}
+		fmt.Println(">        Lifetime:", impersonation.Lifetime)
+		fmt.Println(">          Scopes:", strings.Join(impersonation.Scopes, ", "))
 	}
 
 	if cmd.Bool("with-regions") {
@@ -119,11 +141,6 @@ func init() {
 			Sources:  cli.EnvVars("GCP_NUKE_PROJECT_ID"),
 			Required: true,
 		},
-		&cli.StringFlag{
-			Name:    "impersonate-service-account",
-			Usage:   "impersonate a service account for all API calls",
-			Sources: cli.EnvVars("GCP_NUKE_IMPERSONATE_SERVICE_ACCOUNT"),
-		},
 		&cli.BoolFlag{
 			Name:  "with-regions",
 			Usage: "include regions in the output",
@@ -133,6 +150,8 @@ func init() {
 			Usage: "include enabled APIs in the output",
 		},
 	}
+	flags = append(flags, global.ImpersonateFlags()...)
+	flags = append(flags, global.EndpointFlags()...)
 
 	cmd := &cli.Command{
 		Name:        "explain-project",
@@ -2,6 +2,7 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -13,6 +14,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
 	libnuke "github.com/ekristen/libnuke/pkg/nuke"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/scanner"
@@ -22,25 +24,36 @@ import (
 	"github.com/ekristen/gcp-nuke/pkg/common"
 	"github.com/ekristen/gcp-nuke/pkg/gcputil"
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+	"github.com/ekristen/gcp-nuke/pkg/telemetry"
 )
 
 func execute(ctx context.Context, cmd *cli.Command) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	gcp, err := gcputil.New(ctx, cmd.String("project-id"), cmd.String("impersonate-service-account"))
-	if err != nil {
+	t := targetFromFlags(cmd)
+	if err := t.validate(); err != nil {
 		return err
 	}
 
-	if !gcp.HasProjects() {
-		return fmt.Errorf("no projects found")
+	if err := validateReportFormat(cmd); err != nil {
+		return err
 	}
 
 	logger := logrus.StandardLogger()
 	logger.SetOutput(os.Stdout)
 
-	logger.Trace("preparing to run nuke")
+	r, err := newRunner(cmd, logger)
+	if err != nil {
+		return err
+	}
+
+	tel, err := global.Telemetry(ctx, cmd, logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTelemetry(logger, tel)
 
 	params := &libnuke.Parameters{
 		Force:              cmd.Bool("no-prompt"),
@@ -51,87 +64,277 @@ func execute(ctx context.Context, cmd *cli.Command) error {
 		Excludes:           cmd.StringSlice("exclude"),
 		WaitOnDependencies: cmd.Bool("wait-on-dependencies"),
 	}
+	setWaitTimeout(cmd, params)
 
-	parsedConfig, err := libconfig.New(libconfig.Options{
-		Path:         cmd.String("config"),
-		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
-		Log:          logger.WithField("component", "config"),
-	})
-	if err != nil {
-		logger.Errorf("Failed to parse config file %s", cmd.String("config"))
-		return err
+	defer closeListers()
+
+	rep := report.New(common.AppVersion.Summary, !params.NoDryRun)
+
+	runErr := r.run(ctx, t, params, rep)
+	r.notify(ctx, rep, runErr)
+
+	return writeReport(cmd, rep, runErr)
+}
+
+// closeListers closes the listers that hold a client, GCP rest clients have to be closed properly
+func closeListers() {
+	for _, l := range registry.GetListers() {
+		lc, ok := l.(registry.ListerWithClose)
+		if ok {
+			lc.Close()
+		}
 	}
+}
 
-	projectID := cmd.String("project-id")
+// telemetryShutdownTimeout is how long the traces and the metrics that are left have to be exported when the process
+// ends
+const telemetryShutdownTimeout = 10 * time.Second
 
-	projectConfig := parsedConfig.Accounts[projectID]
+// shutdownTelemetry exports the traces and the metrics that are left, a failure only warns since the run is over
+func shutdownTelemetry(logger *logrus.Logger, t *telemetry.Telemetry) {
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+	defer cancel()
 
-	filters, err := parsedConfig.Filters(projectID)
-	if err != nil {
-		return err
+	if err := t.Shutdown(ctx); err != nil {
+		logger.WithError(err).Warn("unable to export the telemetry")
+	}
+}
+
+// summarizeThrottle logs the number of API calls that were throttled by GCP during the run and adds it to the report
+func summarizeThrottle(logger *logrus.Logger, gcp *gcputil.GCP, rep *report.Report) {
+	throttle := gcp.GetThrottle()
+	if throttle == nil {
+		return
+	}
+
+	logger.Infof("API calls throttled: %s", throttle.Summary())
+
+	if rep != nil {
+		rep.Throttled = throttle.Events()
+	}
+}
+
+// validateReportFormat checks the format given by --report-format, before the run starts
+func validateReportFormat(cmd *cli.Command) error {
+	if cmd.String("report") == "" {
+		return nil
+	}
+
+	_, err := report.ParseFormat(cmd.String("report-format"))
+	return err
+}
+
+// writeReport writes the report to the path given by --report, if any. The error of the run is returned as is,
+// unless the report could not be written.
+func writeReport(cmd *cli.Command, rep *report.Report, runErr error) error {
+	reportPath := cmd.String("report")
+	if reportPath == "" {
+		return runErr
+	}
+
+	rep.Finish()
+
+	if err := rep.Write(reportPath, report.Format(cmd.String("report-format"))); err != nil {
+		return errors.Join(runErr, fmt.Errorf("unable to write report to %s: %w", reportPath, err))
+	}
+
+	logrus.Infof("report written to %s", reportPath)
+
+	return runErr
+}
+
+// runSleep is the delay between the iterations of libnuke over the queue of resources
+const runSleep = 5 * time.Second
+
+// listingCheck warns about the resource types that could not be listed completely and, with --strict-listing, fails
+// the run before anything is removed. It also holds the listing cache shared by the listers of the run.
+type listingCheck struct {
+	errors  *nuke.ListingErrors
+	cache   *nuke.ListingCache
+	strict  bool
+	logger  *logrus.Logger
+	warned  bool
+	prompts int
+}
+
+func newListingCheck(strict bool, logger *logrus.Logger) *listingCheck {
+	return &listingCheck{
+		errors: &nuke.ListingErrors{},
+		cache:  nuke.NewListingCache(),
+		strict: strict,
+		logger: logger,
+	}
+}
+
+// wrapPrompt checks the listing before the prompt. libnuke prompts before the scan and, when there is something to
+// remove, right before the removal, so the second prompt is the last chance to stop the run. The scan is over by the
+// second prompt, so the listing cache is disabled there: libnuke lists the removed resources again to check whether
+// they are gone, and those listings must not be served from the cache.
+func (c *listingCheck) wrapPrompt(prompt func() error) func() error {
+	return func() error {
+		c.prompts++
+		if c.prompts > 1 {
+			c.cache.Disable()
+		}
+
+		if err := c.check(); err != nil {
+			return err
+		}
+
+		return prompt()
+	}
+}
+
+// finish checks the listing after the run, for the dry runs and the runs that found nothing to remove. The error of
+// the run takes precedence.
+func (c *listingCheck) finish(runErr error) error {
+	c.logger.Infof("Listing cache: %s", c.cache.Stats())
+
+	if runErr != nil {
+		return runErr
+	}
+
+	return c.check()
+}
+
+func (c *listingCheck) check() error {
+	errs := c.errors.Errors()
+	if len(errs) > 0 && !c.warned {
+		c.warned = true
+
+		c.logger.Warnf("%d resource type(s) could not be listed completely, their resources are not part of the run:",
+			len(errs))
+		for _, err := range errs {
+			c.logger.Warnf("> %s", err)
+		}
+	}
+
+	return c.errors.Check(c.strict)
+}
+
+// newNuke configures an instance of libnuke with the filters and the scanners for the project, the organization or
+// both. The prompt is left to the caller to register. The listers record the resource types they could not list
+// completely, and share the collections they list, through the listing check. The operations started by the removals
+// are waited for up to the wait timeout of the runner.
+func (r *runner) newNuke(
+	gcp *gcputil.GCP, parsedConfig *libconfig.Config, params *libnuke.Parameters, logger *logrus.Logger,
+	projectID, organizationID string, listing *listingCheck,
+) (*libnuke.Nuke, error) {
+	filters := filter.Filters{}
+
+	if projectID != "" {
+		projectFilters, err := parsedConfig.Filters(resolveAccountID(parsedConfig, projectID))
+		if err != nil {
+			return nil, err
+		}
+		filters.Append(projectFilters)
+	}
+
+	if organizationID != "" {
+		if err := parsedConfig.ValidateAccount(organizationID); err != nil {
+			return nil, fmt.Errorf("organization %s: %w", organizationID, err)
+		}
+
+		organizationFilters, err := parsedConfig.Filters(organizationID)
+		if err != nil {
+			return nil, err
+		}
+		filters.Append(organizationFilters)
 	}
 
 	n := libnuke.New(params, filters, parsedConfig.Settings)
 
-	n.SetRunSleep(5 * time.Second)
+	n.SetRunSleep(runSleep)
 	n.SetLogger(logger.WithField("component", "libnuke"))
 	n.RegisterVersion(fmt.Sprintf("> %s", common.AppVersion.String()))
 
-	p := &nuke.Prompt{Parameters: params, GCP: gcp}
-	n.RegisterPrompt(p.Prompt)
-
-	projectResourceTypes := types.ResolveResourceTypes(
-		registry.GetNamesForScope(nuke.Project),
-		[]types.Collection{
-			n.Parameters.Includes,
-			parsedConfig.ResourceTypes.GetIncludes(),
-			projectConfig.ResourceTypes.GetIncludes(),
-		},
-		[]types.Collection{
-			n.Parameters.Excludes,
-			parsedConfig.ResourceTypes.Excludes,
-			projectConfig.ResourceTypes.Excludes,
-		},
-		nil,
-		nil,
-	)
-
-	// GCP rest clients have to be closed, this ensures that they are closed properly
-	defer func() {
-		for _, l := range registry.GetListers() {
-			lc, ok := l.(registry.ListerWithClose)
-			if ok {
-				lc.Close()
-			}
+	if organizationID != "" {
+		organizationScanner, err := scanner.New(&scanner.Config{
+			Owner:         fmt.Sprintf("organizations/%s", organizationID),
+			ResourceTypes: resolveResourceTypes(nuke.Organization, params, parsedConfig, organizationID),
+			Opts: &nuke.ListerOpts{
+				Organization:  ptr.String(organizationID),
+				Region:        ptr.String("global"),
+				ClientOptions: gcp.GetClientOptions(),
+				ListingErrors: listing.errors,
+				Cache:         listing.cache,
+				WaitTimeout:   r.waitTimeout,
+
+				GRPCClientOptions:     gcp.GetGRPCClientOptions(),
+				EmulatorClientOptions: gcp.GetEmulatorClientOptions(),
+			},
+			Logger: logger,
+		})
+		if err != nil {
+			return nil, err
 		}
-	}()
 
-	if slices.Contains(parsedConfig.Regions, "all") {
-		parsedConfig.Regions = gcp.Regions
+		if err := n.RegisterScanner(nuke.Organization, organizationScanner); err != nil {
+			return nil, err
+		}
+	}
+
+	if projectID != "" {
+		if err := r.registerProjectScanners(n, gcp, parsedConfig, params, projectID, logger, listing); err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// registerProjectScanners registers a scanner for each region that is defined in the configuration for the project
+func (r *runner) registerProjectScanners(
+	n *libnuke.Nuke, gcp *gcputil.GCP, parsedConfig *libconfig.Config, params *libnuke.Parameters,
+	projectID string, logger *logrus.Logger, listing *listingCheck,
+) error {
+	projectResourceTypes := resolveResourceTypes(
+		nuke.Project, params, parsedConfig, resolveAccountID(parsedConfig, projectID))
+
+	// Note: parsedConfig is shared between projects, so the regions must be resolved without modifying it
+	regions := parsedConfig.Regions
+
+	if slices.Contains(regions, "all") {
+		// Note: the pseudo-regions of the multi-regions and dual-regions own the resources in those locations
+		regions = append(slices.Clone(gcp.Regions), gcputil.PseudoRegions()...)
 
 		logger.Info(
-			`"all" detected in region list, only enabled regions and "global" will be used, all others ignored`)
+			`"all" detected in region list, only enabled regions, "global" and the multi-region and dual-region ` +
+				`pseudo-regions will be used, all others ignored`)
 
 		if len(parsedConfig.Regions) > 1 {
 			logger.Warnf(`additional regions defined along with "all", these will be ignored!`)
 		}
 
-		logger.Infof("The following regions are enabled for the account (%d total):", len(parsedConfig.Regions))
+		logger.Infof("The following regions are enabled for the account (%d total):", len(regions))
 
 		printableRegions := make([]string, 0)
-		for i, region := range parsedConfig.Regions {
+		for i, region := range regions {
 			printableRegions = append(printableRegions, region)
 			if i%6 == 0 { // print 5 regions per line
 				logger.Infof("> %s", strings.Join(printableRegions, ", "))
 				printableRegions = make([]string, 0)
-			} else if i == len(parsedConfig.Regions)-1 {
+			} else if i == len(regions)-1 {
 				logger.Infof("> %s", strings.Join(printableRegions, ", "))
 			}
 		}
 	}
 
+	// Note: a resource is owned by exactly one region, so every region must be scanned only once
+	seen := make(map[string]bool, len(regions))
+
 	// Register the scanners for each region that is defined in the configuration.
-	for _, regionName := range parsedConfig.Regions {
+	for _, regionName := range regions {
+		if seen[regionName] {
+			continue
+		}
+		seen[regionName] = true
+
+		if gcputil.IsPseudoRegion(regionName) && !slices.Contains(gcputil.PseudoRegions(), regionName) {
+			return fmt.Errorf("unknown region %s, the supported pseudo-regions are: %s",
+				regionName, strings.Join(gcputil.PseudoRegions(), ", "))
+		}
+
 		scannerActual, err := scanner.New(&scanner.Config{
 			Owner:         regionName,
 			ResourceTypes: projectResourceTypes,
@@ -141,6 +344,12 @@ func execute(ctx context.Context, cmd *cli.Command) error {
 				Zones:         gcp.GetZones(regionName),
 				EnabledAPIs:   gcp.GetEnabledAPIs(),
 				ClientOptions: gcp.GetClientOptions(),
+				ListingErrors: listing.errors,
+				Cache:         listing.cache,
+				WaitTimeout:   r.waitTimeout,
+
+				GRPCClientOptions:     gcp.GetGRPCClientOptions(),
+				EmulatorClientOptions: gcp.GetEmulatorClientOptions(),
 			},
 			Logger: logger,
 		})
@@ -153,9 +362,93 @@ func execute(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	logger.Debug("running ...")
+	return nil
+}
+
+// resolveResourceTypes resolves the resource types registered for the scope against the includes and excludes from
+// the command line, the global config and the account (project or organization) config.
+func resolveResourceTypes(
+	scope registry.Scope, params *libnuke.Parameters, parsedConfig *libconfig.Config, accountID string,
+) types.Collection {
+	includes := []types.Collection{
+		params.Includes,
+		parsedConfig.ResourceTypes.GetIncludes(),
+	}
+	excludes := []types.Collection{
+		params.Excludes,
+		parsedConfig.ResourceTypes.Excludes,
+	}
 
-	return n.Run(ctx)
+	if accountConfig := parsedConfig.Accounts[accountID]; accountConfig != nil {
+		includes = append(includes, accountConfig.ResourceTypes.GetIncludes())
+		excludes = append(excludes, accountConfig.ResourceTypes.Excludes)
+	}
+
+	return types.ResolveResourceTypes(registry.GetNamesForScope(scope), includes, excludes, nil, nil)
+}
+
+// listingFlags are the flags to control how the resource types that could not be listed completely are handled, see
+// listingCheck
+func listingFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "strict-listing",
+			Usage:   "fail the run, before anything is removed, when a resource type could not be listed completely",
+			Sources: cli.EnvVars("GCP_NUKE_STRICT_LISTING"),
+		},
+	}
+}
+
+// waitFlags are the flags to bound the wait for the removal of the resources, see setWaitTimeout
+func waitFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name: "wait-timeout",
+			Usage: "fail the removal of a resource when it is still in progress after this duration, e.g. 30m " +
+				"(default: no limit)",
+			Sources: cli.EnvVars("GCP_NUKE_WAIT_TIMEOUT"),
+		},
+	}
+}
+
+// setWaitTimeout bounds the number of times libnuke checks again whether the resources that have no operation are
+// gone. The wait for the operations started by the removals is bounded by the WaitTimeout of the lister options.
+func setWaitTimeout(cmd *cli.Command, params *libnuke.Parameters) {
+	timeout := cmd.Duration("wait-timeout")
+	if timeout <= 0 {
+		return
+	}
+
+	params.MaxWaitRetries = int((timeout + runSleep - 1) / runSleep)
+}
+
+// ageFlags are the flags to keep the resources younger than a duration, see applyMaxAge
+func ageFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name: "older-than",
+			Usage: "only remove the resources created more than this duration ago, e.g. 72h or 3d, it overrides " +
+				"max-age of the config",
+			Sources: cli.EnvVars("GCP_NUKE_OLDER_THAN"),
+		},
+	}
+}
+
+// reportFlags are the flags to write a report of the run, see writeReport
+func reportFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "report",
+			Usage:   "write a report of every resource that was discovered and its final state to this path",
+			Sources: cli.EnvVars("GCP_NUKE_REPORT"),
+		},
+		&cli.StringFlag{
+			Name:    "report-format",
+			Usage:   "format of the report, one of json, junit or markdown",
+			Value:   string(report.FormatJSON),
+			Sources: cli.EnvVars("GCP_NUKE_REPORT_FORMAT"),
+		},
+	}
 }
 
 func init() {
@@ -201,17 +494,46 @@ func init() {
 			Sources: cli.EnvVars("GCP_NUKE_FEATURE_FLAGS"),
 		},
 		&cli.StringFlag{
-			Name:     "project-id",
-			Usage:    "which GCP project should be nuked",
-			Sources:  cli.EnvVars("GCP_NUKE_PROJECT_ID"),
-			Required: true,
+			Name:    "project-id",
+			Usage:   "which GCP project should be nuked",
+			Sources: cli.EnvVars("GCP_NUKE_PROJECT_ID"),
+		},
+		&cli.StringFlag{
+			Name:    "folder-id",
+			Usage:   "nuke every project under this GCP folder, including nested folders",
+			Sources: cli.EnvVars("GCP_NUKE_FOLDER_ID"),
+		},
+		&cli.BoolFlag{
+			Name:    "all-projects",
+			Usage:   "nuke every project the credentials have access to",
+			Sources: cli.EnvVars("GCP_NUKE_ALL_PROJECTS"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "project-include",
+			Usage:   "only nuke projects whose ID matches this glob (used with --folder-id or --all-projects)",
+			Sources: cli.EnvVars("GCP_NUKE_PROJECT_INCLUDE"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "project-exclude",
+			Usage:   "never nuke projects whose ID matches this glob (used with --folder-id or --all-projects)",
+			Sources: cli.EnvVars("GCP_NUKE_PROJECT_EXCLUDE"),
 		},
 		&cli.StringFlag{
-			Name:    "impersonate-service-account",
-			Usage:   "impersonate a service account for all API calls",
-			Sources: cli.EnvVars("GCP_NUKE_IMPERSONATE_SERVICE_ACCOUNT"),
+			Name:    "organization-id",
+			Usage:   "which GCP organization should have its organization level resources nuked",
+			Sources: cli.EnvVars("GCP_NUKE_ORGANIZATION_ID"),
 		},
 	}
+	flags = append(flags, listingFlags()...)
+	flags = append(flags, ageFlags()...)
+	flags = append(flags, modeFlags()...)
+	flags = append(flags, costFlags()...)
+	flags = append(flags, waitFlags()...)
+	flags = append(flags, reportFlags()...)
+	flags = append(flags, global.ImpersonateFlags()...)
+	flags = append(flags, global.ThrottleFlags()...)
+	flags = append(flags, global.EndpointFlags()...)
+	flags = append(flags, global.TelemetryFlags()...)
 
 	cmd := &cli.Command{
 		Name:    "run",
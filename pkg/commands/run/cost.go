@@ -0,0 +1,67 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/cost"
+)
+
+// costFlags are the flags to estimate the monthly cost of the resources that would be removed, see cost.Pricing
+func costFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "estimate-cost",
+			Usage:   "estimate the monthly cost of the resources that would be removed, before the prompt",
+			Sources: cli.EnvVars("GCP_NUKE_ESTIMATE_COST"),
+		},
+		&cli.StringFlag{
+			Name: "pricing-file",
+			Usage: "path to a pricing table whose prices are merged over the bundled ones, it implies " +
+				"--estimate-cost",
+			Sources: cli.EnvVars("GCP_NUKE_PRICING_FILE"),
+		},
+	}
+}
+
+// pricingFromFlags returns the pricing table of the cost estimates, nil when the cost is not estimated
+func pricingFromFlags(cmd *cli.Command) (*cost.Pricing, error) {
+	if !cmd.Bool("estimate-cost") && cmd.String("pricing-file") == "" {
+		return nil, nil
+	}
+
+	return cost.Load(cmd.String("pricing-file"))
+}
+
+// estimateCost logs the estimated monthly cost of each resource of the queue that would be removed, the most expensive
+// first, and their total. It does nothing when the cost is not estimated.
+func (r *runner) estimateCost(q *queue.Queue) {
+	if r.pricing == nil {
+		return
+	}
+
+	summary := r.pricing.Summarize(q.GetItems())
+	if len(summary.Estimates) == 0 && summary.Unestimated == 0 {
+		return
+	}
+
+	for _, estimate := range summary.Estimates {
+		r.logger.WithFields(logrus.Fields{
+			"owner": estimate.Owner,
+			"type":  estimate.Type,
+			"name":  estimate.Name,
+		}).Infof("estimated monthly cost: %s", formatCost(estimate.Monthly, summary.Currency))
+	}
+
+	r.logger.Infof("Estimated monthly cost of the resources that would be %s: %s for %d resource(s), "+
+		"%d resource(s) have no estimate", r.mode.Action(), formatCost(summary.Total, summary.Currency),
+		len(summary.Estimates), summary.Unestimated)
+}
+
+func formatCost(amount float64, currency string) string {
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
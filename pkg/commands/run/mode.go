@@ -0,0 +1,221 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/config"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// errAction stops libnuke right before the removal in the modes that do not remove the resources, the action of the
+// mode is done instead, see runNuke
+var errAction = errors.New("act instead of remove")
+
+// modeFlags are the flags to mark the resources for removal and sweep them after a grace period, or to suspend and
+// resume them, see nuke.Mode
+func modeFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name: "mode",
+			Usage: "remove the resources right away (remove), mark them for removal (mark), only remove the " +
+				"resources marked more than the grace period ago (sweep), suspend them (suspend) or resume the " +
+				"suspended resources of the state file (resume)",
+			Value:   string(nuke.ModeRemove),
+			Sources: cli.EnvVars("GCP_NUKE_MODE"),
+		},
+		&cli.StringFlag{
+			Name: "grace-period",
+			Usage: "how long the resources stay marked before a sweep removes them, e.g. 7d or 48h, it overrides " +
+				"grace-period of the config (default: 7d)",
+			Sources: cli.EnvVars("GCP_NUKE_GRACE_PERIOD"),
+		},
+		&cli.BoolFlag{
+			Name:    "stop-marked",
+			Usage:   "stop the compute instances and scale the node pools of the GKE clusters to zero when they are marked",
+			Sources: cli.EnvVars("GCP_NUKE_STOP_MARKED"),
+		},
+		&cli.StringFlag{
+			Name:    "state-file",
+			Usage:   "path to the state file of the suspended resources, read and written by the suspend and resume modes",
+			Value:   "gcp-nuke-suspended.json",
+			Sources: cli.EnvVars("GCP_NUKE_STATE_FILE"),
+		},
+	}
+}
+
+// applyGracePeriod keeps the resources that are not marked, or were marked less than --grace-period ago or, when it
+// is not given, less than the grace-period of the config. It only applies to the runs in sweep mode.
+func (r *runner) applyGracePeriod(parsedConfig *libconfig.Config) error {
+	if r.mode != nuke.ModeSweep {
+		return nil
+	}
+
+	gracePeriod, err := config.GracePeriod(r.configPath)
+	if err != nil {
+		return fmt.Errorf("invalid %s in config %s: %w", config.GracePeriodKey, r.configPath, err)
+	}
+
+	if r.gracePeriod != "" {
+		gracePeriod, err = nuke.ParseDuration(r.gracePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid --grace-period: %w", err)
+		}
+	}
+
+	r.logger.Infof("only the resources marked for removal more than %s ago are removed", gracePeriod)
+
+	config.ApplyGracePeriod(parsedConfig, gracePeriod)
+
+	return nil
+}
+
+// runNuke runs libnuke with the prompt for the project, it is empty for an organization. The monthly cost of the
+// resources that would be removed is estimated before the removal is confirmed. In the modes that do not remove the
+// resources, libnuke is stopped once the removal is confirmed and the action of the mode is done instead on the
+// resources that would be removed, e.g. they are marked.
+func (r *runner) runNuke(
+	ctx context.Context, n *libnuke.Nuke, listing *listingCheck, prompt func() error, project string,
+) error {
+	removes := r.mode == nuke.ModeRemove || r.mode == nuke.ModeSweep
+
+	// Note: libnuke prompts before the scan and, when there is something to remove, right before the removal. The
+	// resources the action cannot apply to are filtered, and the cost is estimated, on the second prompt, which
+	// confirms the removal or the action. A dry run ends after the scan, they are done once it is over then.
+	prepared := false
+	prepare := func() error {
+		if prepared {
+			return nil
+		}
+		prepared = true
+
+		if err := r.prepare(n.Queue, project); err != nil {
+			return err
+		}
+
+		r.estimateCost(n.Queue)
+
+		return nil
+	}
+
+	n.RegisterPrompt(listing.wrapPrompt(func() error {
+		if n.Queue.Total() > 0 {
+			if err := prepare(); err != nil {
+				return err
+			}
+		}
+
+		if err := prompt(); err != nil {
+			return err
+		}
+
+		if n.Queue.Total() > 0 && !removes {
+			return errAction
+		}
+
+		return nil
+	}))
+
+	err := n.Run(ctx)
+	switch {
+	case errors.Is(err, errAction):
+		err = r.act(ctx, n.Queue, project)
+	case err == nil && !n.Parameters.NoDryRun:
+		err = prepare()
+		if !removes {
+			r.logger.Infof("in %s mode, the above resources would be %s instead of removed", r.mode, r.mode.Action())
+		}
+	}
+
+	return listing.finish(err)
+}
+
+// prepare filters the resources of the queue that the action of the mode cannot apply to, see suspend.State.Prepare
+func (r *runner) prepare(q *queue.Queue, project string) error {
+	if r.mode != nuke.ModeSuspend && r.mode != nuke.ModeResume {
+		return nil
+	}
+
+	state, err := r.loadState()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range state.Prepare(project, q.GetItems(), r.mode == nuke.ModeResume) {
+		item.Print()
+	}
+
+	return nil
+}
+
+// act does the action of the mode on the resources of the queue that would be removed
+func (r *runner) act(ctx context.Context, q *queue.Queue, project string) error {
+	switch r.mode {
+	case nuke.ModeSuspend:
+		return r.suspend(ctx, q, project)
+	case nuke.ModeResume:
+		return r.resume(ctx, q, project)
+	default:
+		return r.mark(ctx, q)
+	}
+}
+
+// mark marks the resources of the queue that would be removed, it fails when at least one could not be marked
+func (r *runner) mark(ctx context.Context, q *queue.Queue) error {
+	r.logItems(nuke.MarkItems(ctx, q.GetItems(), time.Now(), r.stopMarked))
+
+	marked, failed := q.Count(queue.ItemStateFinished), q.Count(queue.ItemStateFailed)
+	r.logger.Infof("Mark complete: %d failed, %d marked. Sweep them with --mode sweep once the grace period is over.",
+		failed, marked)
+
+	if failed > 0 {
+		return fmt.Errorf("%d resource(s) could not be marked", failed)
+	}
+
+	return nil
+}
+
+// logItems logs the outcome of the action of the mode on each item
+func (r *runner) logItems(items []*queue.Item) {
+	for _, item := range items {
+		itemLog := r.logger.WithFields(logrus.Fields{
+			"owner": item.Owner,
+			"type":  item.Type,
+			"name":  nuke.ItemName(item),
+		})
+
+		switch item.GetState() {
+		case queue.ItemStateFailed:
+			itemLog.Error(item.GetReason())
+		case queue.ItemStateFiltered:
+			itemLog.Warnf("not %s: %s", r.mode.Action(), item.GetReason())
+		default:
+			itemLog.Info(item.GetReason())
+		}
+	}
+}
+
+// addNuke adds every item of the queue of the instance of libnuke to the run of the report, the resources of a run in
+// a mode that does not remove them had the action of the mode done instead
+func (r *runner) addNuke(run *report.Run, n *libnuke.Nuke) {
+	run.AddNuke(n, nuke.Project)
+
+	switch r.mode {
+	case nuke.ModeMark:
+		run.SetRemovedState(report.StateMarked)
+	case nuke.ModeSuspend:
+		run.SetRemovedState(report.StateSuspended)
+	case nuke.ModeResume:
+		run.SetRemovedState(report.StateResumed)
+	}
+}
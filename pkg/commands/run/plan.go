@@ -0,0 +1,306 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/commands/global"
+	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/plan"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// executePlan runs a dry run and writes every resource that would be removed to the plan file
+func executePlan(ctx context.Context, cmd *cli.Command) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	projectID := cmd.String("project-id")
+	organizationID := cmd.String("organization-id")
+
+	if projectID == "" && organizationID == "" {
+		return fmt.Errorf("at least one of --project-id or --organization-id must be provided")
+	}
+
+	logger := logrus.StandardLogger()
+	logger.SetOutput(os.Stdout)
+
+	r, err := newRunner(cmd, logger)
+	if err != nil {
+		return err
+	}
+
+	gcp, err := r.connect(ctx, projectID, organizationID)
+	if err != nil {
+		return err
+	}
+
+	params := &libnuke.Parameters{
+		Force:      cmd.Bool("no-prompt"),
+		ForceSleep: int(cmd.Int("prompt-delay")),
+		Quiet:      cmd.Bool("quiet"),
+		Includes:   cmd.StringSlice("include"),
+		Excludes:   cmd.StringSlice("exclude"),
+	}
+
+	parsedConfig, err := r.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	defer closeListers()
+
+	listing := newListingCheck(r.strictListing, logger)
+
+	n, err := r.newNuke(gcp, parsedConfig, params, logger, projectID, organizationID, listing)
+	if err != nil {
+		return err
+	}
+
+	p := &nuke.Prompt{Parameters: params, GCP: gcp, OrganizationID: organizationID}
+	n.RegisterPrompt(listing.wrapPrompt(p.Prompt))
+
+	if err := listing.finish(n.Run(ctx)); err != nil {
+		return err
+	}
+
+	summarizeThrottle(logger, gcp, nil)
+	r.estimateCost(n.Queue)
+
+	pl := plan.New(common.AppVersion.Summary, projectID, organizationID)
+	pl.AddItems(n.Queue.GetItems())
+
+	if err := pl.Write(cmd.String("out")); err != nil {
+		return fmt.Errorf("unable to write plan to %s: %w", cmd.String("out"), err)
+	}
+
+	logger.Infof("plan with %d resource(s) written to %s", len(pl.Resources), cmd.String("out"))
+
+	return nil
+}
+
+// executeApply removes the resources of a plan. The resources are listed again, anything that was not part of the
+// plan is filtered right before the removal, and the resources of the plan that are gone are reported.
+func executeApply(ctx context.Context, cmd *cli.Command) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if cmd.Args().Len() != 1 {
+		return fmt.Errorf("the path to exactly one plan file must be provided")
+	}
+
+	if err := validateReportFormat(cmd); err != nil {
+		return err
+	}
+
+	pl, err := plan.Load(cmd.Args().First())
+	if err != nil {
+		return err
+	}
+
+	logger := logrus.StandardLogger()
+	logger.SetOutput(os.Stdout)
+
+	if len(pl.Resources) == 0 {
+		logger.Info("the plan has no resources, nothing to apply")
+		return nil
+	}
+
+	logger.Infof("applying plan created at %s with %d resource(s)", pl.CreatedAt.Format("2006-01-02 15:04:05"),
+		len(pl.Resources))
+
+	r, err := newRunner(cmd, logger)
+	if err != nil {
+		return err
+	}
+
+	gcp, err := r.connect(ctx, pl.Project, pl.Organization)
+	if err != nil {
+		return err
+	}
+
+	// Note: only the resource types of the plan are listed, anything else can never be removed
+	params := &libnuke.Parameters{
+		Force:              cmd.Bool("no-prompt"),
+		ForceSleep:         int(cmd.Int("prompt-delay")),
+		Quiet:              cmd.Bool("quiet"),
+		NoDryRun:           true,
+		Includes:           pl.ResourceTypes(),
+		WaitOnDependencies: cmd.Bool("wait-on-dependencies"),
+	}
+	setWaitTimeout(cmd, params)
+
+	parsedConfig, err := r.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	defer closeListers()
+
+	listing := newListingCheck(r.strictListing, logger)
+
+	n, err := r.newNuke(gcp, parsedConfig, params, logger, pl.Project, pl.Organization, listing)
+	if err != nil {
+		return err
+	}
+
+	// Note: libnuke prompts before the scan and, when there is something to remove, right before the removal. The
+	// plan is enforced, and the cost is estimated, on the second prompt so that the confirmation is given for what
+	// will actually be removed.
+	enforced := false
+	p := &nuke.Prompt{Parameters: params, GCP: gcp, OrganizationID: pl.Organization}
+	n.RegisterPrompt(listing.wrapPrompt(func() error {
+		if n.Queue.Total() > 0 && !enforced {
+			enforcePlan(logger, pl, n.Queue)
+			r.estimateCost(n.Queue)
+			enforced = true
+		}
+
+		return p.Prompt()
+	}))
+
+	rep := report.New(common.AppVersion.Summary, false)
+
+	runErr := listing.finish(n.Run(ctx))
+	if runErr == nil && !enforced {
+		enforcePlan(logger, pl, n.Queue)
+	}
+
+	run := &report.Run{Project: pl.Project, Organization: pl.Organization}
+	run.AddNuke(n, nuke.Project)
+	run.AddListingErrors(listing.errors.Errors())
+	run.SetError(runErr)
+	rep.AddRun(run)
+
+	summarizeThrottle(logger, gcp, rep)
+	r.notify(ctx, rep, runErr)
+
+	return writeReport(cmd, rep, runErr)
+}
+
+// enforcePlan filters the items that are not part of the plan and warns about the resources of the plan that are gone
+func enforcePlan(logger *logrus.Logger, pl *plan.Plan, q *queue.Queue) {
+	excluded, missing := pl.Enforce(q.GetItems())
+
+	for _, item := range excluded {
+		item.Print()
+	}
+
+	for _, r := range missing {
+		logger.Warnf("resource in the plan was not found or is filtered now, it will not be removed: %s", r)
+	}
+
+	logger.Infof("Plan applied: %d planned, %d will be removed, %d not in plan, %d missing.",
+		len(pl.Resources), q.Count(queue.ItemStateNew, queue.ItemStateNewDependency), len(excluded), len(missing))
+}
+
+func init() {
+	planFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "path to config file",
+			Value: "config.yaml",
+		},
+		&cli.StringFlag{
+			Name:     "out",
+			Usage:    "path to write the plan to",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only include this specific resource",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "exclude this specific resource (this overrides everything)",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "hide filtered messages from display",
+		},
+		&cli.BoolFlag{
+			Name:  "no-prompt",
+			Usage: "disable prompting for verification to run",
+		},
+		&cli.IntFlag{
+			Name:  "prompt-delay",
+			Usage: "seconds to delay after prompt before running (minimum: 3 seconds)",
+			Value: 10,
+		},
+		&cli.StringFlag{
+			Name:    "project-id",
+			Usage:   "which GCP project should be planned",
+			Sources: cli.EnvVars("GCP_NUKE_PROJECT_ID"),
+		},
+		&cli.StringFlag{
+			Name:    "organization-id",
+			Usage:   "which GCP organization should have its organization level resources planned",
+			Sources: cli.EnvVars("GCP_NUKE_ORGANIZATION_ID"),
+		},
+	}
+	planFlags = append(planFlags, listingFlags()...)
+	planFlags = append(planFlags, ageFlags()...)
+	planFlags = append(planFlags, costFlags()...)
+	planFlags = append(planFlags, global.ImpersonateFlags()...)
+	planFlags = append(planFlags, global.ThrottleFlags()...)
+	planFlags = append(planFlags, global.EndpointFlags()...)
+
+	common.RegisterCommand(&cli.Command{
+		Name:   "plan",
+		Usage:  "write the resources a dry run would remove to a plan file, to be applied later with apply",
+		Flags:  append(planFlags, global.Flags()...),
+		Before: global.Before,
+		Action: executePlan,
+	})
+
+	applyFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "path to config file",
+			Value: "config.yaml",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "hide filtered messages from display",
+		},
+		&cli.BoolFlag{
+			Name:  "no-prompt",
+			Usage: "disable prompting for verification to run",
+		},
+		&cli.IntFlag{
+			Name:  "prompt-delay",
+			Usage: "seconds to delay after prompt before running (minimum: 3 seconds)",
+			Value: 10,
+		},
+		&cli.BoolFlag{
+			Name:  "wait-on-dependencies",
+			Usage: "wait for dependent resources to be deleted before deleting resources that depend on them",
+		},
+	}
+	applyFlags = append(applyFlags, listingFlags()...)
+	applyFlags = append(applyFlags, waitFlags()...)
+	applyFlags = append(applyFlags, costFlags()...)
+	applyFlags = append(applyFlags, reportFlags()...)
+	applyFlags = append(applyFlags, global.ImpersonateFlags()...)
+	applyFlags = append(applyFlags, global.ThrottleFlags()...)
+	applyFlags = append(applyFlags, global.EndpointFlags()...)
+
+	common.RegisterCommand(&cli.Command{
+		Name:      "apply",
+		Usage:     "remove exactly the resources of a plan file written by plan",
+		ArgsUsage: "<plan.json>",
+		Flags:     append(applyFlags, global.Flags()...),
+		Before:    global.Before,
+		Action:    executeApply,
+	})
+}
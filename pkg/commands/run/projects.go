@@ -0,0 +1,316 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+	"github.com/ekristen/gcp-nuke/pkg/telemetry"
+)
+
+// projectResult is the outcome of nuking a single project, it is used to print the summary at the end of the run
+type projectResult struct {
+	ProjectID string
+	Skipped   string
+	Err       error
+	DryRun    bool
+
+	Total    int
+	Filtered int
+	Removed  int
+	Failed   int
+
+	// Incomplete is the number of resource types that could not be listed completely
+	Incomplete int
+
+	// Cache is the statistics of the listing cache of the project
+	Cache nuke.CacheStats
+}
+
+// runMultiProject nukes every project under a folder or every project the credentials have access to. Each project
+// gets its own instance of libnuke, so that the filters and the discovered regions and APIs of one project never
+// apply to another one.
+func (r *runner) runMultiProject(
+	ctx context.Context, t *target, gcp *gcputil.GCP, parsedConfig *libconfig.Config, params *libnuke.Parameters,
+	rep *report.Report,
+) error {
+	folderID := t.FolderID
+	organizationID := t.OrganizationID
+	logger := r.logger
+
+	if !parsedConfig.HasBlocklist() {
+		return fmt.Errorf("a blocklist must be configured when using --folder-id or --all-projects")
+	}
+
+	candidates := gcp.Projects
+	if folderID != "" {
+		var err error
+		candidates, err = gcp.ListFolderProjects(ctx, folderID)
+		if err != nil {
+			return fmt.Errorf("unable to list projects for folder %s: %w", folderID, err)
+		}
+	}
+
+	projectIDs, err := selectProjects(candidates, t.ProjectIncludes, t.ProjectExcludes)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*projectResult, 0, len(projectIDs))
+	toNuke := make([]string, 0, len(projectIDs))
+	for _, projectID := range projectIDs {
+		if parsedConfig.InBlocklist(projectID) {
+			logger.Warnf("project %s is blocklisted, skipping", projectID)
+			results = append(results, &projectResult{ProjectID: projectID, Skipped: "blocklisted"})
+			rep.AddRun(&report.Run{Project: projectID, Skipped: "blocklisted"})
+			continue
+		}
+
+		if _, ok := parsedConfig.Accounts[resolveAccountID(parsedConfig, projectID)]; !ok {
+			logger.Warnf("project %s is not configured in the accounts section, skipping", projectID)
+			results = append(results, &projectResult{ProjectID: projectID, Skipped: "not configured"})
+			rep.AddRun(&report.Run{Project: projectID, Skipped: "not configured"})
+			continue
+		}
+
+		toNuke = append(toNuke, projectID)
+	}
+
+	if len(toNuke) == 0 {
+		printSummary(logger, results)
+		return fmt.Errorf("no projects to nuke")
+	}
+
+	p := &nuke.ProjectsPrompt{
+		Parameters:     params,
+		Projects:       toNuke,
+		FolderID:       folderID,
+		OrganizationID: organizationID,
+	}
+	if err := r.prompt(p.Prompt)(); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, projectID := range toNuke {
+		logger.Infof("nuking project %s", projectID)
+
+		result := r.nukeProject(ctx, gcp, parsedConfig, params, projectID, rep)
+		if result.Err != nil {
+			logger.WithError(result.Err).Errorf("unable to nuke project %s", projectID)
+			failed++
+		}
+
+		results = append(results, result)
+	}
+
+	if organizationID != "" {
+		logger.Infof("nuking organization %s", organizationID)
+
+		orgCtx, end := telemetry.Start(ctx, "nuke organization", telemetry.AttributeOrganization.String(organizationID))
+
+		listing := newListingCheck(r.strictListing, logger)
+
+		n, err := r.newNuke(gcp, parsedConfig, params, logger, "", organizationID, listing)
+		if err != nil {
+			end(err)
+			return err
+		}
+		err = r.runNuke(orgCtx, n, listing, func() error { return nil }, "")
+		end(err)
+		if err != nil {
+			logger.WithError(err).Errorf("unable to nuke organization %s", organizationID)
+			failed++
+		}
+
+		run := &report.Run{Organization: organizationID}
+		r.addNuke(run, n)
+		run.AddListingErrors(listing.errors.Errors())
+		run.SetError(err)
+		rep.AddRun(run)
+	}
+
+	printSummary(logger, results)
+	summarizeThrottle(logger, gcp, rep)
+
+	if failed > 0 {
+		return fmt.Errorf("%d run(s) failed", failed)
+	}
+
+	return nil
+}
+
+// nukeProject discovers the regions and enabled APIs of the project and then runs libnuke against it, the outcome is
+// added to the report
+func (r *runner) nukeProject(
+	ctx context.Context, gcp *gcputil.GCP, parsedConfig *libconfig.Config, params *libnuke.Parameters,
+	projectID string, rep *report.Report,
+) *projectResult {
+	result := &projectResult{ProjectID: projectID, DryRun: !params.NoDryRun}
+
+	ctx, end := telemetry.Start(ctx, "nuke project", telemetry.AttributeProject.String(projectID))
+
+	run := &report.Run{Project: projectID}
+	defer func() {
+		run.SetError(result.Err)
+		rep.AddRun(run)
+		end(result.Err)
+	}()
+
+	projectGCP, err := gcp.WithProject(ctx, projectID)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	listing := newListingCheck(r.strictListing, r.logger)
+
+	n, err := r.newNuke(projectGCP, parsedConfig, params, r.logger, projectID, "", listing)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Note: the confirmation was already given for all the projects at once
+	result.Err = r.runNuke(ctx, n, listing, func() error { return nil }, projectID)
+
+	r.addNuke(run, n)
+	run.AddListingErrors(listing.errors.Errors())
+
+	result.Incomplete = len(run.IncompleteListings)
+	result.Cache = listing.cache.Stats()
+
+	result.Total = n.Queue.Total()
+	result.Filtered = n.Queue.Count(queue.ItemStateFiltered)
+	result.Failed = n.Queue.Count(queue.ItemStateFailed)
+	if params.NoDryRun {
+		result.Removed = n.Queue.Count(queue.ItemStateFinished)
+	} else {
+		result.Removed = n.Queue.Count(queue.ItemStateNew)
+	}
+
+	return result
+}
+
+// selectProjects returns the sorted IDs of the active projects that match at least one of the includes (when any are
+// given) and none of the excludes. Includes and excludes are glob patterns.
+func selectProjects(projects []*gcputil.Project, includes, excludes []string) ([]string, error) {
+	projectIDs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		if !project.IsActive() || slices.Contains(projectIDs, project.ProjectID) {
+			continue
+		}
+
+		included := len(includes) == 0
+		for _, pattern := range includes {
+			matched, err := path.Match(pattern, project.ProjectID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid project include pattern %q: %w", pattern, err)
+			}
+			if matched {
+				included = true
+				break
+			}
+		}
+
+		excluded := false
+		for _, pattern := range excludes {
+			matched, err := path.Match(pattern, project.ProjectID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid project exclude pattern %q: %w", pattern, err)
+			}
+			if matched {
+				excluded = true
+				break
+			}
+		}
+
+		if included && !excluded {
+			projectIDs = append(projectIDs, project.ProjectID)
+		}
+	}
+
+	sort.Strings(projectIDs)
+
+	return projectIDs, nil
+}
+
+// resolveAccountID returns the key of the accounts section of the config that applies to the project. An exact match
+// on the project ID wins, otherwise the first key (in sorted order) that is a glob matching the project ID is used.
+// If nothing matches, the project ID is returned as is.
+func resolveAccountID(parsedConfig *libconfig.Config, projectID string) string {
+	if _, ok := parsedConfig.Accounts[projectID]; ok {
+		return projectID
+	}
+
+	keys := make([]string, 0, len(parsedConfig.Accounts))
+	for key := range parsedConfig.Accounts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if matched, err := path.Match(key, projectID); err == nil && matched {
+			return key
+		}
+	}
+
+	return projectID
+}
+
+// printSummary prints the outcome of every project that was part of the run
+func printSummary(logger *logrus.Logger, results []*projectResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].ProjectID < results[j].ProjectID
+	})
+
+	logger.Infof("Summary for %d project(s):", len(results))
+	for _, result := range results {
+		switch {
+		case result.Skipped != "":
+			logger.Infof("> %s: skipped (%s)", result.ProjectID, result.Skipped)
+		case result.Err != nil:
+			logger.Infof("> %s: error (%s)", result.ProjectID, result.Err)
+		case result.DryRun:
+			logger.Infof("> %s: %d total, %d filtered, %d would be removed%s",
+				result.ProjectID, result.Total, result.Filtered, result.Removed,
+				incompleteSummary(result)+cacheSummary(result))
+		default:
+			logger.Infof("> %s: %d total, %d filtered, %d removed, %d failed%s",
+				result.ProjectID, result.Total, result.Filtered, result.Removed, result.Failed,
+				incompleteSummary(result)+cacheSummary(result))
+		}
+	}
+}
+
+// incompleteSummary returns the part of the summary of a project about the resource types that could not be listed
+// completely, if any
+func incompleteSummary(result *projectResult) string {
+	if result.Incomplete == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(", %d resource type(s) incompletely listed", result.Incomplete)
+}
+
+// cacheSummary returns the part of the summary of a project about the listings served by the listing cache, if any
+func cacheSummary(result *projectResult) string {
+	if result.Cache.Hits == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(", %d listing(s) served from cache (~%s saved)", result.Cache.Hits,
+		result.Cache.Saved.Round(time.Millisecond))
+}
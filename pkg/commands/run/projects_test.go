@@ -0,0 +1,81 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+)
+
+func TestSelectProjects(t *testing.T) {
+	projects := []*gcputil.Project{
+		{ProjectID: "dev-web"},
+		{ProjectID: "dev-api", State: "ACTIVE"},
+		{ProjectID: "dev-old", State: "DELETE_REQUESTED"},
+		{ProjectID: "prod-web"},
+		{ProjectID: "sandbox"},
+		// Note: a project found under two folders is only nuked once
+		{ProjectID: "dev-web"},
+	}
+
+	cases := []struct {
+		name     string
+		includes []string
+		excludes []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "all", want: []string{"dev-api", "dev-web", "prod-web", "sandbox"}},
+		{name: "include", includes: []string{"dev-*"}, want: []string{"dev-api", "dev-web"}},
+		{name: "includes", includes: []string{"dev-api", "*-web"}, want: []string{"dev-api", "dev-web", "prod-web"}},
+		{name: "exclude", excludes: []string{"prod-*"}, want: []string{"dev-api", "dev-web", "sandbox"}},
+		{name: "exclude-wins", includes: []string{"dev-*"}, excludes: []string{"*-web"}, want: []string{"dev-api"}},
+		{name: "inactive", includes: []string{"dev-old"}, want: []string{}},
+		{name: "invalid-include", includes: []string{"dev-["}, wantErr: true},
+		{name: "invalid-exclude", excludes: []string{"dev-["}, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectProjects(projects, tc.includes, tc.excludes)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestResolveAccountID(t *testing.T) {
+	parsedConfig := &libconfig.Config{
+		Accounts: map[string]*libconfig.Account{
+			"dev-web": {},
+			"dev-*":   {},
+			"d*":      {},
+			"*-web":   {},
+		},
+	}
+
+	cases := []struct {
+		projectID string
+		want      string
+	}{
+		{projectID: "dev-web", want: "dev-web"},
+		{projectID: "dev-api", want: "d*"},
+		{projectID: "prod-web", want: "*-web"},
+		{projectID: "sandbox", want: "sandbox"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.projectID, func(t *testing.T) {
+			assert.Equal(t, tc.want, resolveAccountID(parsedConfig, tc.projectID))
+		})
+	}
+}
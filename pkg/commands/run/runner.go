@@ -0,0 +1,361 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/gcp-nuke/pkg/commands/global"
+	"github.com/ekristen/gcp-nuke/pkg/config"
+	"github.com/ekristen/gcp-nuke/pkg/cost"
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+	"github.com/ekristen/gcp-nuke/pkg/notify"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+	"github.com/ekristen/gcp-nuke/pkg/telemetry"
+)
+
+// runner runs nuke with the settings shared by every run of the process: the config, the credentials and the rate
+// limits of the API calls. The run command runs it once, the serve command for every scheduled or requested run.
+type runner struct {
+	configPath    string
+	olderThan     string
+	impersonation *gcputil.Impersonation
+	endpoints     *gcputil.Endpoints
+	rateLimits    gcputil.RateLimits
+	strictListing bool
+	logger        *logrus.Logger
+
+	// mode is what the runs do to the resources that are not filtered, see nuke.Mode
+	mode        nuke.Mode
+	gracePeriod string
+	stopMarked  bool
+
+	// stateFile is the state file of the suspended resources, it is read and written by the runs in the suspend and
+	// resume modes while holding stateMu
+	stateFile string
+	stateMu   sync.Mutex
+
+	// pricing is the pricing table of the estimated monthly cost of the resources that would be removed, nil when the
+	// cost is not estimated
+	pricing *cost.Pricing
+
+	// waitTimeout is how long the operations started by the removals are waited for, 0 means no limit
+	waitTimeout time.Duration
+
+	// unattended skips the confirmation prompts, there is no one to confirm the runs of the serve command
+	unattended bool
+
+	// throttle is shared by every run when it is set, otherwise every run gets its own. The listers keep the clients
+	// they created in the first run, so the API calls of the later runs go through the throttle of the first one.
+	throttle *gcputil.Throttle
+}
+
+// newRunner returns a runner with the settings of the flags of the command
+func newRunner(cmd *cli.Command, logger *logrus.Logger) (*runner, error) {
+	rateLimits, err := global.RateLimits(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := global.Endpoints(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := nuke.ParseMode(cmd.String("mode"))
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := pricingFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &runner{
+		configPath:    cmd.String("config"),
+		olderThan:     cmd.String("older-than"),
+		impersonation: global.Impersonation(cmd),
+		endpoints:     endpoints,
+		rateLimits:    rateLimits,
+		strictListing: cmd.Bool("strict-listing"),
+		logger:        logger,
+		mode:          mode,
+		gracePeriod:   cmd.String("grace-period"),
+		stopMarked:    cmd.Bool("stop-marked"),
+		stateFile:     cmd.String("state-file"),
+		pricing:       pricing,
+		waitTimeout:   cmd.Duration("wait-timeout"),
+	}, nil
+}
+
+// target is what a run nukes: a project, an organization, the projects under a folder or all the projects, and the
+// organization along with the projects when both are given
+type target struct {
+	ProjectID       string
+	OrganizationID  string
+	FolderID        string
+	AllProjects     bool
+	ProjectIncludes []string
+	ProjectExcludes []string
+}
+
+// targetFromFlags returns the target given by the flags of the run command
+func targetFromFlags(cmd *cli.Command) *target {
+	return &target{
+		ProjectID:       cmd.String("project-id"),
+		OrganizationID:  cmd.String("organization-id"),
+		FolderID:        cmd.String("folder-id"),
+		AllProjects:     cmd.Bool("all-projects"),
+		ProjectIncludes: cmd.StringSlice("project-include"),
+		ProjectExcludes: cmd.StringSlice("project-exclude"),
+	}
+}
+
+func (t *target) multiProject() bool {
+	return t.FolderID != "" || t.AllProjects
+}
+
+func (t *target) validate() error {
+	if t.ProjectID == "" && t.OrganizationID == "" && !t.multiProject() {
+		return fmt.Errorf("at least one of --project-id, --folder-id, --all-projects or " +
+			"--organization-id must be provided")
+	}
+
+	if t.FolderID != "" && t.AllProjects {
+		return fmt.Errorf("--folder-id and --all-projects cannot be used together")
+	}
+
+	if t.ProjectID != "" && t.multiProject() {
+		return fmt.Errorf("--project-id cannot be used together with --folder-id or --all-projects")
+	}
+
+	return nil
+}
+
+// run nukes the target and adds the outcome to the report, the config is loaded again for every run
+func (r *runner) run(ctx context.Context, t *target, params *libnuke.Parameters, rep *report.Report) (err error) {
+	ctx, end := telemetry.Start(ctx, "nuke",
+		telemetry.AttributeProject.String(t.ProjectID),
+		telemetry.AttributeOrganization.String(t.OrganizationID))
+	defer func() {
+		recordResources(ctx, rep)
+		end(err)
+	}()
+
+	gcp, err := r.connect(ctx, t.ProjectID, t.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	r.logger.Trace("preparing to run nuke")
+
+	parsedConfig, err := r.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if t.multiProject() {
+		return r.runMultiProject(ctx, t, gcp, parsedConfig, params, rep)
+	}
+
+	listing := newListingCheck(r.strictListing, r.logger)
+
+	n, err := r.newNuke(gcp, parsedConfig, params, r.logger, t.ProjectID, t.OrganizationID, listing)
+	if err != nil {
+		return err
+	}
+
+	p := &nuke.Prompt{Parameters: params, GCP: gcp, OrganizationID: t.OrganizationID}
+
+	r.logger.Debug("running ...")
+
+	runErr := r.runNuke(ctx, n, listing, r.prompt(p.Prompt), t.ProjectID)
+
+	run := &report.Run{Project: t.ProjectID, Organization: t.OrganizationID}
+	r.addNuke(run, n)
+	run.AddListingErrors(listing.errors.Errors())
+	run.SetError(runErr)
+	rep.AddRun(run)
+
+	summarizeThrottle(r.logger, gcp, rep)
+
+	return runErr
+}
+
+// notifyTimeout is how long the sinks of the notifications have to accept the summary of a run
+const notifyTimeout = 2 * time.Minute
+
+// notify sends the summary of the run to the sinks of the notifications of the config, a failure only warns since the
+// run is over. The sinks are notified even when the run was canceled.
+func (r *runner) notify(ctx context.Context, rep *report.Report, runErr error) {
+	sinks, err := config.Notifications(r.configPath)
+	if err != nil {
+		r.logger.WithError(err).Warnf("unable to read the %s of config %s", config.NotificationsKey, r.configPath)
+		return
+	}
+
+	if len(sinks) == 0 {
+		return
+	}
+
+	notifier, err := notify.New(sinks)
+	if err != nil {
+		r.logger.WithError(err).Warn("unable to send the notifications")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+	defer cancel()
+
+	rep.Finish()
+
+	notified, err := notifier.Notify(ctx, notify.NewSummary(rep, runErr))
+	if len(notified) > 0 {
+		r.logger.Infof("notified %s", strings.Join(notified, ", "))
+	}
+	if err != nil {
+		r.logger.WithError(err).Warn("unable to send the notifications")
+	}
+}
+
+// recordResources counts the resources of the report in the metrics, see telemetry.RecordResource
+func recordResources(ctx context.Context, rep *report.Report) {
+	for _, run := range rep.Runs {
+		for _, res := range run.Resources {
+			outcome := telemetry.OutcomeKept
+			switch res.State {
+			case report.StateRemoved:
+				outcome = telemetry.OutcomeRemoved
+			case report.StateFailed:
+				outcome = telemetry.OutcomeFailed
+			}
+
+			telemetry.RecordResource(ctx, res.Type, res.Owner, outcome)
+		}
+	}
+}
+
+// prompt returns the prompt, or a prompt that proceeds right away when the runner is unattended
+func (r *runner) prompt(prompt func() error) func() error {
+	if r.unattended {
+		return func() error { return nil }
+	}
+
+	return prompt
+}
+
+// connect creates the GCP client, with the API calls throttled, and checks that the project and the organization, when
+// given, are accessible
+func (r *runner) connect(ctx context.Context, projectID, organizationID string) (*gcputil.GCP, error) {
+	throttle := r.throttle
+	if throttle == nil {
+		throttle = gcputil.NewThrottle(r.rateLimits)
+	}
+
+	emulators, err := config.Emulators(r.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in config %s: %w", config.EmulatorsKey, r.configPath, err)
+	}
+
+	var gcp *gcputil.GCP
+	if emulators.Enabled() {
+		r.logger.Infof("using the emulators of %s", strings.Join(emulators.APIs(), ", "))
+		gcp, err = gcputil.NewEmulated(projectID, emulators, throttle)
+	} else {
+		var endpoints *gcputil.Endpoints
+		if endpoints, err = r.configEndpoints(); err != nil {
+			return nil, err
+		}
+
+		gcp, err = gcputil.New(ctx, projectID, r.impersonation, throttle, endpoints)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID != "" && !gcp.HasProjects() {
+		return nil, fmt.Errorf("no projects found")
+	}
+
+	if organizationID != "" && gcp.GetOrganization(organizationID) == nil {
+		return nil, fmt.Errorf("organization %s not found or not accessible", organizationID)
+	}
+
+	return gcp, nil
+}
+
+// configEndpoints returns the endpoints of the flags merged with the endpoints of the config, the flags take precedence
+func (r *runner) configEndpoints() (*gcputil.Endpoints, error) {
+	addresses, err := config.Endpoints(r.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in config %s: %w", config.EndpointsKey, r.configPath, err)
+	}
+
+	endpoints, err := r.endpoints.Merge(addresses)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in config %s: %w", config.EndpointsKey, r.configPath, err)
+	}
+
+	return endpoints, nil
+}
+
+// loadConfig parses the config file given by --config
+func (r *runner) loadConfig() (*libconfig.Config, error) {
+	parsedConfig, err := libconfig.New(libconfig.Options{
+		Path:         r.configPath,
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+		Log:          r.logger.WithField("component", "config"),
+	})
+	if err != nil {
+		r.logger.Errorf("Failed to parse config file %s", r.configPath)
+		return nil, err
+	}
+
+	if err := r.applyMaxAge(parsedConfig); err != nil {
+		return nil, err
+	}
+
+	if err := r.applyGracePeriod(parsedConfig); err != nil {
+		return nil, err
+	}
+
+	return parsedConfig, nil
+}
+
+// applyMaxAge keeps the resources created less than --older-than ago or, when it is not given, less than the max-age
+// of the config
+func (r *runner) applyMaxAge(parsedConfig *libconfig.Config) error {
+	maxAge, err := config.MaxAge(r.configPath)
+	if err != nil {
+		return fmt.Errorf("invalid %s in config %s: %w", config.MaxAgeKey, r.configPath, err)
+	}
+
+	if r.olderThan != "" {
+		maxAge, err = nuke.ParseDuration(r.olderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+	}
+
+	if maxAge <= 0 {
+		return nil
+	}
+
+	r.logger.Infof("only the resources created more than %s ago are removed, "+
+		"the resources without a creation time are kept because their age is unknown", maxAge)
+
+	config.ApplyMaxAge(parsedConfig, maxAge)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTargetValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		target  target
+		wantErr string
+	}{
+		{name: "project", target: target{ProjectID: "my-project"}},
+		{name: "organization", target: target{OrganizationID: "123"}},
+		{name: "organization-and-project", target: target{OrganizationID: "123", ProjectID: "my-project"}},
+		{name: "folder", target: target{FolderID: "456"}},
+		{name: "all-projects", target: target{AllProjects: true}},
+		{name: "organization-and-all-projects", target: target{OrganizationID: "123", AllProjects: true}},
+		{
+			name:    "none",
+			target:  target{ProjectIncludes: []string{"dev-*"}},
+			wantErr: "at least one of",
+		},
+		{
+			name:    "folder-and-all-projects",
+			target:  target{FolderID: "456", AllProjects: true},
+			wantErr: "--folder-id and --all-projects",
+		},
+		{
+			name:    "project-and-folder",
+			target:  target{ProjectID: "my-project", FolderID: "456"},
+			wantErr: "--project-id cannot be used",
+		},
+		{
+			name:    "project-and-all-projects",
+			target:  target{ProjectID: "my-project", AllProjects: true},
+			wantErr: "--project-id cannot be used",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.target.validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
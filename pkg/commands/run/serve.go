@@ -0,0 +1,221 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+
+	"github.com/ekristen/gcp-nuke/pkg/commands/global"
+	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/config"
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+	"github.com/ekristen/gcp-nuke/pkg/server"
+)
+
+// serveShutdownTimeout is how long the HTTP API waits for the requests in progress when the daemon stops
+const serveShutdownTimeout = 10 * time.Second
+
+// executeServe runs the daemon: the projects of the config are nuked on their schedules and on demand through the
+// HTTP API, until the process is interrupted
+func executeServe(ctx context.Context, cmd *cli.Command) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := logrus.StandardLogger()
+	logger.SetOutput(os.Stdout)
+
+	r, err := newRunner(cmd, logger)
+	if err != nil {
+		return err
+	}
+	r.unattended = true
+	r.throttle = gcputil.NewThrottle(r.rateLimits)
+
+	// Note: the config is loaded again for every run, it is loaded here to fail right away when it is invalid
+	if _, err := r.loadConfig(); err != nil {
+		return err
+	}
+
+	schedules, err := config.Schedules(r.configPath)
+	if err != nil {
+		return fmt.Errorf("invalid schedule in config %s: %w", r.configPath, err)
+	}
+
+	if len(schedules) == 0 {
+		logger.Warn("no project of the config has a schedule, runs are only started through the API")
+	}
+	for _, schedule := range schedules {
+		logger.Infof("project %s is scheduled on %q", schedule.Project, schedule.Cron)
+	}
+
+	dryRun := !cmd.Bool("no-dry-run")
+	if dryRun {
+		logger.Info("every run is a dry run, provide --no-dry-run to actually remove resources")
+	}
+
+	defer closeListers()
+
+	tel, err := global.Telemetry(ctx, cmd, logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTelemetry(logger, tel)
+
+	srv, err := server.New(server.Options{
+		Dir:          cmd.String("data-dir"),
+		HistoryLimit: int(cmd.Int("history-limit")),
+		DryRun:       dryRun,
+		Schedules:    schedules,
+		Token:        cmd.String("api-token"),
+		Logger:       logger,
+		Run: func(ctx context.Context, run server.Run) (*report.Report, error) {
+			nuke.SetRunID(run.ID)
+
+			// Note: the prompts are skipped, the delay only satisfies the validation of libnuke
+			params := &libnuke.Parameters{
+				Force:              true,
+				ForceSleep:         3,
+				Quiet:              cmd.Bool("quiet"),
+				NoDryRun:           !run.DryRun,
+				Includes:           cmd.StringSlice("include"),
+				Excludes:           cmd.StringSlice("exclude"),
+				WaitOnDependencies: cmd.Bool("wait-on-dependencies"),
+			}
+			setWaitTimeout(cmd, params)
+
+			rep := report.New(common.AppVersion.Summary, run.DryRun)
+			runErr := r.run(ctx, &target{ProjectID: run.Project}, params, rep)
+
+			r.notify(ctx, rep, runErr)
+
+			if err := tel.Push(ctx); err != nil {
+				logger.WithError(err).Warn("unable to push the metrics")
+			}
+
+			return rep, runErr
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:              cmd.String("listen"),
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	srv.Start(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		logger.Infof("listening on %s", httpServer.Addr)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info("stopping, the run in progress is canceled")
+	case err = <-serveErr:
+		stop()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+
+	if shutdownErr := httpServer.Shutdown(shutdownCtx); shutdownErr != nil {
+		logger.WithError(shutdownErr).Warn("unable to stop the HTTP API gracefully")
+	}
+
+	srv.Wait()
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "path to config file, the projects are scheduled with the schedule of their account",
+			Value: "config.yaml",
+		},
+		&cli.StringFlag{
+			Name:    "listen",
+			Usage:   "address the HTTP API listens on",
+			Value:   ":8080",
+			Sources: cli.EnvVars("GCP_NUKE_LISTEN"),
+		},
+		&cli.StringFlag{
+			Name:    "api-token",
+			Usage:   "require this token as a bearer token on every endpoint of the HTTP API but /healthz",
+			Sources: cli.EnvVars("GCP_NUKE_API_TOKEN"),
+		},
+		&cli.StringFlag{
+			Name:    "data-dir",
+			Usage:   "directory the history of the runs and their reports are kept in",
+			Value:   "gcp-nuke-data",
+			Sources: cli.EnvVars("GCP_NUKE_DATA_DIR"),
+		},
+		&cli.IntFlag{
+			Name:    "history-limit",
+			Usage:   "number of finished runs kept in the history, the oldest are removed first",
+			Value:   server.DefaultHistoryLimit,
+			Sources: cli.EnvVars("GCP_NUKE_HISTORY_LIMIT"),
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only include this specific resource",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "exclude this specific resource (this overrides everything)",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "hide filtered messages from display",
+		},
+		&cli.BoolFlag{
+			Name:    "no-dry-run",
+			Usage:   "actually remove the resources, otherwise every run is a dry run",
+			Sources: cli.EnvVars("GCP_NUKE_NO_DRY_RUN"),
+		},
+		&cli.BoolFlag{
+			Name:  "wait-on-dependencies",
+			Usage: "wait for dependent resources to be deleted before deleting resources that depend on them",
+		},
+	}
+	flags = append(flags, listingFlags()...)
+	flags = append(flags, ageFlags()...)
+	flags = append(flags, modeFlags()...)
+	flags = append(flags, costFlags()...)
+	flags = append(flags, waitFlags()...)
+	flags = append(flags, global.ImpersonateFlags()...)
+	flags = append(flags, global.ThrottleFlags()...)
+	flags = append(flags, global.EndpointFlags()...)
+	flags = append(flags, global.TelemetryFlags()...)
+
+	common.RegisterCommand(&cli.Command{
+		Name:   "serve",
+		Usage:  "run as a daemon that nukes the projects of the config on their schedules and on demand over HTTP",
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: executeServe,
+	})
+}
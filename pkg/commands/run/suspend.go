@@ -0,0 +1,81 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/common"
+	"github.com/ekristen/gcp-nuke/pkg/suspend"
+)
+
+// loadState reads the state file of the suspended resources
+func (r *runner) loadState() (*suspend.State, error) {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
+
+	return suspend.Load(r.stateFile, common.AppVersion.Summary)
+}
+
+// updateState reads the state file, updates it and writes it back, even when the update failed for some resources so
+// that the resources that were suspended or resumed are recorded
+func (r *runner) updateState(update func(state *suspend.State) error) error {
+	r.stateMu.Lock()
+	defer r.stateMu.Unlock()
+
+	state, err := suspend.Load(r.stateFile, common.AppVersion.Summary)
+	if err != nil {
+		return err
+	}
+
+	updateErr := update(state)
+
+	if err := state.Write(r.stateFile); err != nil {
+		return fmt.Errorf("unable to write the state file %s: %w", r.stateFile, err)
+	}
+
+	return updateErr
+}
+
+// suspend suspends the resources of the queue that would be removed and records them in the state file, it fails when
+// at least one could not be suspended
+func (r *runner) suspend(ctx context.Context, q *queue.Queue, project string) error {
+	return r.updateState(func(state *suspend.State) error {
+		r.logItems(state.Suspend(ctx, project, q.GetItems(), time.Now()))
+
+		suspended, failed := q.Count(queue.ItemStateFinished), q.Count(queue.ItemStateFailed)
+		r.logger.Infof("Suspend complete: %d failed, %d suspended. Resume them with --mode resume, the state is in %s.",
+			failed, suspended, r.stateFile)
+
+		if failed > 0 {
+			return fmt.Errorf("%d resource(s) could not be suspended", failed)
+		}
+
+		return nil
+	})
+}
+
+// resume resumes the resources of the queue that are in the state file and removes them from it, it fails when at
+// least one could not be resumed
+func (r *runner) resume(ctx context.Context, q *queue.Queue, project string) error {
+	return r.updateState(func(state *suspend.State) error {
+		resumed, missing := state.Resume(ctx, project, q.GetItems())
+		r.logItems(resumed)
+
+		for _, res := range missing {
+			r.logger.Warnf("suspended resource was not found or is filtered now, it stays in the state file: %s", res)
+		}
+
+		failed := q.Count(queue.ItemStateFailed)
+		r.logger.Infof("Resume complete: %d failed, %d resumed, %d missing.",
+			failed, q.Count(queue.ItemStateFinished), len(missing))
+
+		if failed > 0 {
+			return fmt.Errorf("%d resource(s) could not be resumed", failed)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+)
+
+// EmulatorsKey is the top-level key of the config for the hosts of the emulators the APIs are called on
+const EmulatorsKey = "emulators"
+
+// Emulators returns the emulators of the config at path, the environment variables of the emulators, e.g.
+// PUBSUB_EMULATOR_HOST, take precedence
+func Emulators(path string) (*gcputil.Emulators, error) {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.Emulators.WithEnv(), nil
+}
@@ -0,0 +1,14 @@
+package config
+
+// EndpointsKey is the top-level key of the config for the addresses the connections to the APIs go to, by API
+const EndpointsKey = "endpoints"
+
+// Endpoints returns the addresses of the APIs of the config at path, see gcputil.ParseEndpoints
+func Endpoints(path string) (map[string]string, error) {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.Endpoints, nil
+}
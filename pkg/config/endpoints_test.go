@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("endpoints:\n"+
+		"  storage: storage-myendpoint.p.googleapis.com\n"+
+		"  compute.googleapis.com: https://10.0.0.5\n"), 0600))
+
+	addresses, err := Endpoints(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"storage":                "storage-myendpoint.p.googleapis.com",
+		"compute.googleapis.com": "https://10.0.0.5",
+	}, addresses)
+	assert.Empty(t, ValidateExtensions(path))
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("endpoints:\n  storage: http://10.0.0.5\n"), 0600))
+
+	problems := ValidateExtensions(path)
+	require.Len(t, problems, 1)
+	assert.Equal(t, EndpointsKey, problems[0].Path)
+	assert.Contains(t, problems[0].Message, "invalid endpoint of storage.googleapis.com")
+}
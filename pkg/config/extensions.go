@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+	"github.com/ekristen/gcp-nuke/pkg/notify"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// extensions are the keys of the config that are specific to gcp-nuke. libnuke ignores the keys it does not know, so
+// they are read from the file separately.
+type extensions struct {
+	MaxAge        string                       `yaml:"max-age"`
+	GracePeriod   string                       `yaml:"grace-period"`
+	Accounts      map[string]*accountExtension `yaml:"accounts"`
+	Notifications []*notify.Config             `yaml:"notifications"`
+	Emulators     *gcputil.Emulators           `yaml:"emulators"`
+	Endpoints     map[string]string            `yaml:"endpoints"`
+}
+
+// accountExtension are the keys of an account of the config that are specific to gcp-nuke
+type accountExtension struct {
+	Schedule string `yaml:"schedule"`
+}
+
+func readExtensions(path string) (*extensions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := &extensions{}
+	if err := yaml.Unmarshal(data, ext); err != nil {
+		return nil, err
+	}
+
+	return ext, nil
+}
+
+// ValidateExtensions returns the problems of the keys of the config at path that are specific to gcp-nuke
+func ValidateExtensions(path string) []Problem {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return []Problem{{Path: path, Message: err.Error()}}
+	}
+
+	v := &validator{}
+
+	if ext.MaxAge != "" {
+		if _, err := nuke.ParseDuration(ext.MaxAge); err != nil {
+			v.add(MaxAgeKey, "%s", err)
+		}
+	}
+
+	if ext.GracePeriod != "" {
+		if _, err := nuke.ParseDuration(ext.GracePeriod); err != nil {
+			v.add(GracePeriodKey, "%s", err)
+		}
+	}
+
+	for _, id := range sortedKeys(ext.Accounts) {
+		if account := ext.Accounts[id]; account != nil && account.Schedule != "" {
+			if _, err := parseSchedule(id, account.Schedule); err != nil {
+				v.add(fmt.Sprintf("accounts.%s.%s", id, ScheduleKey), "%s", err)
+			}
+		}
+	}
+
+	for i, sink := range ext.Notifications {
+		if sink == nil {
+			continue
+		}
+
+		if err := sink.Validate(); err != nil {
+			v.add(fmt.Sprintf("%s[%d]", NotificationsKey, i), "%s", err)
+		}
+	}
+
+	if _, err := gcputil.ParseEndpoints(ext.Endpoints, ""); err != nil {
+		v.add(EndpointsKey, "%s", err)
+	}
+
+	return v.problems
+}
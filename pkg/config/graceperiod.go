@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// GracePeriodKey is the top-level key of the config for how long the resources stay marked before a sweep removes them
+const GracePeriodKey = "grace-period"
+
+// GracePeriod returns the grace-period of the config at path, nuke.DefaultGracePeriod when it is not set. It is a
+// number of days, e.g. 7d, or a duration, e.g. 48h.
+func GracePeriod(path string) (time.Duration, error) {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if ext.GracePeriod == "" {
+		return nuke.DefaultGracePeriod, nil
+	}
+
+	return nuke.ParseDuration(ext.GracePeriod)
+}
+
+// ApplyGracePeriod adds the global filters to every account of the config that keep the resources that are not marked
+// for removal, or were marked less than gracePeriod ago, see nuke.SweepFilters
+func ApplyGracePeriod(cfg *libconfig.Config, gracePeriod time.Duration) {
+	appendGlobalFilters(cfg, nuke.SweepFilters(gracePeriod)...)
+}
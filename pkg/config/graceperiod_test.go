@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+func TestGracePeriod(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset", config: "regions: [global]\n", want: nuke.DefaultGracePeriod},
+		{name: "days", config: "grace-period: 3d\n", want: 72 * time.Hour},
+		{name: "duration", config: "grace-period: 36h\n", want: 36 * time.Hour},
+		{name: "invalid", config: "grace-period: soon\n", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.config), 0600))
+
+			got, err := GracePeriod(path)
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.NotEmpty(t, ValidateExtensions(path))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyGracePeriod(t *testing.T) {
+	cfg := &libconfig.Config{
+		Accounts: map[string]*libconfig.Account{
+			"project": {},
+		},
+	}
+
+	ApplyGracePeriod(cfg, 72*time.Hour)
+
+	filters, err := cfg.Filters("project")
+	require.NoError(t, err)
+	assert.Equal(t, nuke.SweepFilters(72*time.Hour), filters[filter.Global])
+}
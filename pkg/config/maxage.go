@@ -0,0 +1,54 @@
+package config
+
+import (
+	"time"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// MaxAgeKey is the top-level key of the config for the age a resource must have to be removed
+const MaxAgeKey = "max-age"
+
+// MaxAge returns the max-age of the config at path, zero when it is not set. It is a number of days, e.g. 3d, or a
+// duration, e.g. 72h.
+func MaxAge(path string) (time.Duration, error) {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if ext.MaxAge == "" {
+		return 0, nil
+	}
+
+	return nuke.ParseDuration(ext.MaxAge)
+}
+
+// ApplyMaxAge adds global filters to every account of the config that keep the resources created less than maxAge
+// ago, and the resources without a creation time. The filters are ORed by libnuke, the resources filtered by the
+// config stay filtered.
+func ApplyMaxAge(cfg *libconfig.Config, maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+
+	appendGlobalFilters(cfg, nuke.MaxAgeFilters(maxAge)...)
+}
+
+// appendGlobalFilters adds the filters to the global filters of every account of the config
+func appendGlobalFilters(cfg *libconfig.Config, filters ...filter.Filter) {
+	for _, account := range cfg.Accounts {
+		if account == nil {
+			continue
+		}
+
+		if account.Filters == nil {
+			account.Filters = filter.Filters{}
+		}
+
+		account.Filters[filter.Global] = append(account.Filters[filter.Global], filters...)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+func TestMaxAge(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset", config: "regions: [global]\n"},
+		{name: "days", config: "max-age: 3d\n", want: 72 * time.Hour},
+		{name: "duration", config: "max-age: 36h\n", want: 36 * time.Hour},
+		{name: "invalid", config: "max-age: old\n", wantErr: true},
+		{name: "negative", config: "max-age: -1h\n", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.config), 0600))
+
+			got, err := MaxAge(path)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyMaxAge(t *testing.T) {
+	cfg := &libconfig.Config{
+		Accounts: map[string]*libconfig.Account{
+			"with-filters": {
+				Filters: filter.Filters{
+					"StorageBucket": {filter.NewExactFilter("keep")},
+				},
+			},
+			"without-filters": {},
+			"empty":           nil,
+		},
+	}
+
+	ApplyMaxAge(cfg, 0)
+	assert.Empty(t, cfg.Accounts["with-filters"].Filters[filter.Global])
+
+	ApplyMaxAge(cfg, 72*time.Hour)
+
+	for _, id := range []string{"with-filters", "without-filters"} {
+		filters, err := cfg.Filters(id)
+		require.NoError(t, err)
+		assert.Equal(t, nuke.MaxAgeFilters(72*time.Hour), filters[filter.Global], id)
+	}
+
+	// Note: the filters of the config are kept, libnuke ORs them with the global ones
+	assert.Len(t, cfg.Accounts["with-filters"].Filters.Get("StorageBucket"), 3)
+	assert.Nil(t, cfg.Accounts["empty"])
+}
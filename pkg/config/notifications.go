@@ -0,0 +1,18 @@
+package config
+
+import (
+	"github.com/ekristen/gcp-nuke/pkg/notify"
+)
+
+// NotificationsKey is the top-level key of the config for the sinks the summary of every run is sent to
+const NotificationsKey = "notifications"
+
+// Notifications returns the sinks of the notifications of the config at path, they are validated by notify.New
+func Notifications(path string) ([]*notify.Config, error) {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ext.Notifications, nil
+}
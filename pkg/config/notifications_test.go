@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/gcp-nuke/pkg/notify"
+)
+
+func TestNotifications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("notifications:\n"+
+		"  - name: ops\n    type: slack\n    url: https://hooks.slack.com/services/${SLACK_TOKEN}\n"+
+		"    triggers: [on_failure, on_removal]\n"+
+		"  - type: smtp\n    host: smtp.example.com\n    from: gcp-nuke@example.com\n    to: [ops@example.com]\n"+
+		"accounts:\n  my-project: {}\n"), 0600))
+
+	sinks, err := Notifications(path)
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+
+	assert.Equal(t, "ops", sinks[0].String())
+	assert.Equal(t, notify.SinkSlack, sinks[0].Type)
+	assert.Equal(t, []notify.Trigger{notify.TriggerOnFailure, notify.TriggerOnRemoval}, sinks[0].Triggers)
+
+	assert.Equal(t, "smtp", sinks[1].String())
+	assert.Equal(t, []string{"ops@example.com"}, sinks[1].To)
+
+	assert.Empty(t, ValidateExtensions(path))
+}
+
+func TestValidateNotifications(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("notifications:\n"+
+		"  - type: webhook\n    url: https://example.com/hook\n"+
+		"  - type: teams\n    url: https://example.com/hook\n"+
+		"  - type: google-chat\n    url: https://chat.googleapis.com/v1/spaces/x\n    triggers: [on_success]\n"+
+		"  - type: smtp\n    host: smtp.example.com\n"), 0600))
+
+	problems := ValidateExtensions(path)
+	require.Len(t, problems, 3)
+	assert.Equal(t, "notifications[1]", problems[0].Path)
+	assert.Contains(t, problems[0].Message, `unknown type "teams"`)
+	assert.Equal(t, "notifications[2]", problems[1].Path)
+	assert.Contains(t, problems[1].Message, `unknown trigger "on_success"`)
+	assert.Equal(t, "notifications[3]", problems[2].Path)
+	assert.Contains(t, problems[2].Message, "requires a host, a from and at least one to")
+}
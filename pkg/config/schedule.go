@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ekristen/gcp-nuke/pkg/cron"
+)
+
+// ScheduleKey is the key of an account of the config for the cron expression the serve command runs it on
+const ScheduleKey = "schedule"
+
+// Schedule is the schedule of a project of the config
+type Schedule struct {
+	Project string
+	Cron    *cron.Schedule
+}
+
+// Schedules returns the schedules of the accounts of the config at path, sorted by project. A schedule requires the
+// ID of a project, the accounts that are glob patterns cannot have one.
+func Schedules(path string) ([]*Schedule, error) {
+	ext, err := readExtensions(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*Schedule, 0)
+	for id, account := range ext.Accounts {
+		if account == nil || account.Schedule == "" {
+			continue
+		}
+
+		schedule, err := parseSchedule(id, account.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("accounts.%s.%s: %w", id, ScheduleKey, err)
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Project < schedules[j].Project
+	})
+
+	return schedules, nil
+}
+
+func parseSchedule(id, expression string) (*Schedule, error) {
+	if strings.ContainsAny(id, "*?[") {
+		return nil, fmt.Errorf("a schedule requires a project ID, %q is a pattern", id)
+	}
+
+	c, err := cron.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schedule{Project: id, Cron: c}, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedules(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:   "none",
+			config: "accounts:\n  my-project: {}\n  empty:\n",
+			want:   map[string]string{},
+		},
+		{
+			name: "schedules",
+			config: "accounts:\n" +
+				"  project-b:\n    schedule: \"0 2 * * *\"\n" +
+				"  project-a:\n    schedule: \"@daily\"\n    filters: {}\n" +
+				"  project-c: {}\n",
+			want: map[string]string{"project-a": "@daily", "project-b": "0 2 * * *"},
+		},
+		{
+			name:    "invalid",
+			config:  "accounts:\n  my-project:\n    schedule: \"every night\"\n",
+			wantErr: "accounts.my-project.schedule: invalid cron expression",
+		},
+		{
+			name:    "pattern",
+			config:  "accounts:\n  \"dev-*\":\n    schedule: \"@daily\"\n",
+			wantErr: `accounts.dev-*.schedule: a schedule requires a project ID, "dev-*" is a pattern`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.config), 0600))
+
+			schedules, err := Schedules(path)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			got := map[string]string{}
+			for _, s := range schedules {
+				got[s.Project] = s.Cron.String()
+			}
+			assert.Equal(t, tc.want, got)
+			assert.IsIncreasing(t, projects(schedules))
+		})
+	}
+}
+
+func projects(schedules []*Schedule) []string {
+	ids := make([]string, 0, len(schedules))
+	for _, s := range schedules {
+		ids = append(ids, s.Project)
+	}
+
+	return ids
+}
+
+func TestValidateExtensions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("max-age: old\n"+
+		"accounts:\n"+
+		"  project-a:\n    schedule: \"@daily\"\n"+
+		"  project-b:\n    schedule: \"61 * * * *\"\n"), 0600))
+
+	problems := ValidateExtensions(path)
+	require.Len(t, problems, 2)
+	assert.Equal(t, MaxAgeKey, problems[0].Path)
+	assert.Equal(t, "accounts.project-b.schedule", problems[1].Path)
+	assert.Contains(t, problems[1].Message, `invalid value "61" for the minute`)
+}
@@ -0,0 +1,381 @@
+// Package config validates a libnuke config against the resource types registered by gcp-nuke, the typos that would
+// otherwise silently do nothing, like a filter on a resource type or a property that does not exist, are reported.
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+)
+
+// Problem is a problem found in a config, Path is the location of the problem, e.g. "accounts.my-project.filters"
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Message)
+}
+
+// Validate returns the problems of the config. The regions are checked against the enabled regions when they are
+// given, otherwise only the pseudo-regions are checked.
+func Validate(cfg *libconfig.Config, enabledRegions []string) []Problem {
+	v := &validator{names: registry.GetNames()}
+	sort.Strings(v.names)
+
+	v.regions(cfg.Regions, enabledRegions)
+
+	v.resourceTypes("resource-types", cfg.ResourceTypes)
+
+	accounts := sortedKeys(cfg.Accounts)
+	for _, id := range accounts {
+		account := cfg.Accounts[id]
+		if account == nil {
+			continue
+		}
+
+		path := fmt.Sprintf("accounts.%s", id)
+
+		v.filters(path+".filters", account.Filters)
+		v.resourceTypes(path+".resource-types", account.ResourceTypes)
+
+		for _, preset := range account.Presets {
+			if _, ok := cfg.Presets[preset]; !ok {
+				v.add(path+".presets", "preset %q is not defined in presets", preset)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.Presets) {
+		v.filters(fmt.Sprintf("presets.%s.filters", name), cfg.Presets[name].Filters)
+	}
+
+	if cfg.Settings != nil {
+		for _, resourceType := range sortedKeys(*cfg.Settings) {
+			v.settings(resourceType, (*cfg.Settings)[resourceType])
+		}
+	}
+
+	return v.problems
+}
+
+type validator struct {
+	names    []string
+	problems []Problem
+}
+
+func (v *validator) add(path, format string, args ...any) {
+	v.problems = append(v.problems, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// resourceType returns the registration of the resource type, a problem is added when it does not exist
+func (v *validator) resourceType(path, resourceType string) *registry.Registration {
+	if reg := registry.GetRegistration(resourceType); reg != nil {
+		return reg
+	}
+
+	message := fmt.Sprintf("unknown resource type %q", resourceType)
+	if suggestion := nearest(resourceType, v.names); suggestion != "" {
+		message += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+
+	v.add(path, "%s", message)
+
+	return nil
+}
+
+func (v *validator) resourceTypes(path string, resourceTypes libconfig.ResourceTypes) {
+	collections := []struct {
+		key   string
+		names []string
+	}{
+		{"includes", resourceTypes.Includes},
+		{"excludes", resourceTypes.Excludes},
+		{"targets", resourceTypes.Targets},
+		{"alternatives", resourceTypes.Alternatives},
+	}
+
+	for _, collection := range collections {
+		for _, name := range collection.names {
+			v.resourceType(fmt.Sprintf("%s.%s", path, collection.key), name)
+		}
+	}
+}
+
+func (v *validator) filters(path string, filters filter.Filters) {
+	for _, resourceType := range sortedKeys(filters) {
+		// Note: the global filters apply to every resource type, their properties cannot be checked
+		if resourceType == filter.Global {
+			continue
+		}
+
+		reg := v.resourceType(path, resourceType)
+		if reg == nil || reg.Resource == nil {
+			continue
+		}
+
+		properties := Properties(reg.Resource)
+
+		for _, f := range filters[resourceType] {
+			if f.Property == "" {
+				continue
+			}
+
+			if err := properties.check(f.Property); err != nil {
+				v.add(fmt.Sprintf("%s.%s", path, resourceType), "%v", err)
+			}
+		}
+	}
+}
+
+func (v *validator) settings(resourceType string, setting *settings.Setting) {
+	path := "settings"
+
+	reg := v.resourceType(path, resourceType)
+	if reg == nil || setting == nil {
+		return
+	}
+
+	for _, key := range sortedKeys(*setting) {
+		if slices.Contains(reg.Settings, key) {
+			continue
+		}
+
+		if len(reg.Settings) == 0 {
+			v.add(fmt.Sprintf("%s.%s", path, resourceType), "unknown setting %q, %s has no settings", key, resourceType)
+			continue
+		}
+
+		message := fmt.Sprintf("unknown setting %q, the settings of %s are: %s",
+			key, resourceType, strings.Join(reg.Settings, ", "))
+		if suggestion := nearest(key, reg.Settings); suggestion != "" {
+			message = fmt.Sprintf("unknown setting %q, did you mean %q?", key, suggestion)
+		}
+
+		v.add(fmt.Sprintf("%s.%s", path, resourceType), "%s", message)
+	}
+}
+
+func (v *validator) regions(regions, enabledRegions []string) {
+	for _, region := range regions {
+		switch {
+		case region == "all", region == "global":
+		case gcputil.IsPseudoRegion(region):
+			if !slices.Contains(gcputil.PseudoRegions(), region) {
+				v.add("regions", "unknown pseudo-region %q, the supported pseudo-regions are: %s",
+					region, strings.Join(gcputil.PseudoRegions(), ", "))
+			}
+		case len(enabledRegions) > 0 && !slices.Contains(enabledRegions, region):
+			message := fmt.Sprintf("region %q is not enabled for the project", region)
+			if suggestion := nearest(region, enabledRegions); suggestion != "" {
+				message += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			v.add("regions", "%s", message)
+		}
+	}
+}
+
+// sortedKeys returns the keys of the map, sorted
+func sortedKeys[V any, M ~map[string]V](m M) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// nearest returns the candidate that is the closest to the name, an empty string when none is close enough to be a
+// typo. A candidate that only differs in case is always the closest.
+func nearest(name string, candidates []string) string {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		if strings.EqualFold(name, candidate) {
+			return candidate
+		}
+
+		distance := levenshtein(strings.ToLower(name), strings.ToLower(candidate))
+		if bestDistance == -1 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+
+	if bestDistance == -1 || bestDistance > max(2, len(name)/4) {
+		return ""
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// PropertySet are the properties a resource type can have, derived from its struct the same way libnuke builds the
+// properties with types.NewPropertiesFromStruct
+type PropertySet struct {
+	// Names are the properties with a fixed name
+	Names []string
+
+	// TagPrefixes are the prefixes of the properties of the labels and tags, e.g. "label" for "label:<key>"
+	TagPrefixes []string
+}
+
+// Properties returns the properties of the resource struct
+func Properties(resource any) PropertySet {
+	var set PropertySet
+
+	t := reflect.TypeOf(resource)
+	if t == nil {
+		return set
+	}
+
+	set.add(t, "tag")
+
+	return set
+}
+
+// add adds the properties of the fields of the struct, tagPrefix is the prefix of the tags set so far
+func (s *PropertySet) add(t reflect.Type, tagPrefix string) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return tagPrefix
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		options := strings.Split(field.Tag.Get("property"), ",")
+		if options[0] == "-" {
+			continue
+		}
+
+		name, prefix := field.Name, ""
+		inline := len(options) == 2 && options[1] == "inline"
+
+		for _, option := range options {
+			key, value, ok := strings.Cut(option, "=")
+			if !ok {
+				continue
+			}
+
+			switch key {
+			case "name":
+				name = value
+			case "prefix":
+				prefix = value
+			case "tagPrefix":
+				tagPrefix = value
+			}
+		}
+
+		if inline {
+			tagPrefix = s.add(field.Type, tagPrefix)
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		switch {
+		case fieldType.Kind() == reflect.Map || fieldType.Kind() == reflect.Slice && isTagStruct(fieldType.Elem()):
+			if prefix != "" {
+				s.TagPrefixes = append(s.TagPrefixes, fmt.Sprintf("%s:%s", tagPrefix, prefix))
+			} else {
+				s.TagPrefixes = append(s.TagPrefixes, tagPrefix)
+			}
+		case fieldType.Kind() == reflect.Slice,
+			fieldType.Kind() == reflect.Struct && fieldType.String() != "time.Time":
+			// Note: libnuke does not set the properties of nested structs and of the other slices
+		case prefix != "":
+			s.Names = append(s.Names, fmt.Sprintf("%s:%s", prefix, name))
+		default:
+			s.Names = append(s.Names, name)
+		}
+	}
+
+	return tagPrefix
+}
+
+// isTagStruct returns true for the elements of the slices that libnuke sets as tags, a struct with a key and a value
+func isTagStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct
+}
+
+// check returns an error when no property of the set has the name. The keys of the labels of GCP are lowercase, a
+// filter on a label with an uppercase key never matches.
+func (s PropertySet) check(property string) error {
+	if slices.Contains(s.Names, property) {
+		return nil
+	}
+
+	for _, tagPrefix := range s.TagPrefixes {
+		key, ok := strings.CutPrefix(property, tagPrefix+":")
+		if !ok || key == "" {
+			continue
+		}
+
+		if tagPrefix == "label" && key != strings.ToLower(key) {
+			return fmt.Errorf("property %q never matches, the keys of the labels are lowercase, did you mean %q?",
+				property, "label:"+strings.ToLower(key))
+		}
+
+		return nil
+	}
+
+	candidates := slices.Clone(s.Names)
+	for _, tagPrefix := range s.TagPrefixes {
+		candidates = append(candidates, tagPrefix+":<key>")
+	}
+
+	if suggestion := nearest(property, s.Names); suggestion != "" {
+		return fmt.Errorf("unknown property %q, did you mean %q?", property, suggestion)
+	}
+
+	return fmt.Errorf("unknown property %q, the properties are: %s", property, strings.Join(candidates, ", "))
+}
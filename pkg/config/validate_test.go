@@ -0,0 +1,229 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+type testItem struct {
+	Key   string
+	Value string
+}
+
+type TestEmbedded struct {
+	Project *string
+}
+
+type testValidateResource struct {
+	TestEmbedded `property:",inline"`
+
+	Name      *string
+	Secret    *string `property:"-"`
+	ID        *string `property:"name=Identifier"`
+	Disk      *string `property:"prefix=boot"`
+	CreatedAt *time.Time
+	Labels    map[string]string `property:"tagPrefix=label"`
+	Items     []*testItem       `property:"tagPrefix=item"`
+	Zones     []string
+}
+
+func (r *testValidateResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testValidateResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+type testValidateLister struct{}
+
+func (l *testValidateLister) List(_ context.Context, _ interface{}) ([]resource.Resource, error) {
+	return nil, nil
+}
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     "TestValidateResource",
+		Scope:    nuke.Project,
+		Resource: &testValidateResource{},
+		Lister:   &testValidateLister{},
+		Settings: []string{"DisableDeletionProtection"},
+	})
+}
+
+func TestProperties(t *testing.T) {
+	set := Properties(&testValidateResource{})
+
+	assert.ElementsMatch(t, []string{"Project", "Name", "Identifier", "boot:Disk", "CreatedAt"}, set.Names)
+	assert.Equal(t, []string{"label", "item"}, set.TagPrefixes)
+
+	// Note: the names must match the properties libnuke actually sets
+	project, name, id, disk, key := "p", "n", "i", "d", "k"
+	created := time.Now()
+	props := (&testValidateResource{
+		TestEmbedded: TestEmbedded{Project: &project},
+		Name:         &name,
+		ID:           &id,
+		Disk:         &disk,
+		CreatedAt:    &created,
+		Labels:       map[string]string{"env": "dev"},
+		Items:        []*testItem{{Key: key, Value: "v"}},
+	}).Properties()
+
+	for _, name := range set.Names {
+		assert.NotEmpty(t, props.Get(name), name)
+	}
+	assert.Equal(t, "dev", props.Get("label:env"))
+	assert.Equal(t, "v", props.Get("item:k"))
+}
+
+func TestPropertySetCheck(t *testing.T) {
+	set := Properties(&testValidateResource{})
+
+	cases := []struct {
+		property string
+		want     string
+	}{
+		{property: "Name"},
+		{property: "boot:Disk"},
+		{property: "label:env"},
+		{property: "item:anything"},
+		{property: "Nmae", want: `unknown property "Nmae", did you mean "Name"?`},
+		{property: "Secret", want: `unknown property "Secret", the properties are: `},
+		{property: "label:Env", want: `property "label:Env" never matches, the keys of the labels are lowercase, ` +
+			`did you mean "label:env"?`},
+		{property: "label:", want: `unknown property "label:"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.property, func(t *testing.T) {
+			err := set.check(tc.property)
+			if tc.want == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestNearest(t *testing.T) {
+	candidates := []string{"IAMRole", "IAMServiceAccount", "StorageBucket"}
+
+	assert.Equal(t, "IAMRole", nearest("IAMRoles", candidates))
+	assert.Equal(t, "StorageBucket", nearest("storagebucket", candidates))
+	assert.Equal(t, "", nearest("ComputeInstance", candidates))
+}
+
+func loadConfig(t *testing.T, content string) *libconfig.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	cfg, err := libconfig.New(libconfig.Options{Path: path})
+	require.NoError(t, err)
+
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	cfg := loadConfig(t, `
+blocklist:
+  - production-12345
+regions:
+  - global
+  - us-east1
+  - us-eastt1
+  - multi-region:us
+  - multi-region:mars
+accounts:
+  playground-12345:
+    presets:
+      - common
+      - missing
+    filters:
+      __global__:
+        - property: Anything
+          value: x
+      TestValidateResource:
+        - "name"
+        - property: Name
+          value: x
+        - property: label:Env
+          value: dev
+        - property: Nmae
+          value: x
+      TestValidateResources:
+        - "name"
+    resource-types:
+      excludes:
+        - TestValidateResourc
+presets:
+  common:
+    filters:
+      TestValidateResource:
+        - property: label:team
+          value: x
+resource-types:
+  includes:
+    - TestValidateResource
+    - Unknown
+settings:
+  TestValidateResource:
+    DisableDeletionProtection: true
+    DisableDeletionProtections: true
+`)
+
+	var got []string
+	for _, problem := range Validate(cfg, []string{"global", "us-east1", "us-west1"}) {
+		got = append(got, problem.String())
+	}
+
+	assert.Equal(t, []string{
+		`regions: region "us-eastt1" is not enabled for the project, did you mean "us-east1"?`,
+		`regions: unknown pseudo-region "multi-region:mars", the supported pseudo-regions are: ` +
+			`dual-region:asia1, dual-region:eur4, dual-region:eur5, dual-region:eur7, dual-region:eur8, ` +
+			`dual-region:nam4, multi-region:asia, multi-region:eu, multi-region:us`,
+		`resource-types.includes: unknown resource type "Unknown"`,
+		`accounts.playground-12345.filters.TestValidateResource: property "label:Env" never matches, the keys ` +
+			`of the labels are lowercase, did you mean "label:env"?`,
+		`accounts.playground-12345.filters.TestValidateResource: unknown property "Nmae", did you mean "Name"?`,
+		`accounts.playground-12345.filters: unknown resource type "TestValidateResources", ` +
+			`did you mean "TestValidateResource"?`,
+		`accounts.playground-12345.resource-types.excludes: unknown resource type "TestValidateResourc", ` +
+			`did you mean "TestValidateResource"?`,
+		`accounts.playground-12345.presets: preset "missing" is not defined in presets`,
+		`settings.TestValidateResource: unknown setting "DisableDeletionProtections", ` +
+			`did you mean "DisableDeletionProtection"?`,
+	}, got)
+}
+
+func TestValidateWithoutEnabledRegions(t *testing.T) {
+	cfg := loadConfig(t, `
+blocklist:
+  - production-12345
+regions:
+  - all
+  - us-eastt1
+accounts:
+  playground-12345: {}
+`)
+
+	assert.Empty(t, Validate(cfg, nil))
+}
@@ -0,0 +1,216 @@
+package cost
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// gkeNodeLabel is the label of the compute instances that are nodes of a GKE cluster, they are part of the estimate of
+// their cluster
+const gkeNodeLabel = "label:goog-gke-node"
+
+// estimator returns the estimated monthly cost of a resource from its properties, false when it cannot be estimated,
+// e.g. its machine type has no price
+type estimator func(p *Pricing, props types.Properties) (float64, bool)
+
+// estimators are the estimators by resource type, the properties are the ones captured by the listers of the resources
+var estimators = map[string]estimator{
+	"ComputeInstance":          estimateComputeInstance,
+	"ComputeDisk":              estimateComputeDisk,
+	"CloudSQLInstance":         estimateCloudSQLInstance,
+	"GKECluster":               estimateGKECluster,
+	"MemorystoreRedisInstance": estimateRedisInstance,
+	"FilestoreInstance":        estimateFilestoreInstance,
+	"VPCIPAddress":             estimateIPAddress,
+	"VPCGlobalIPAddress":       estimateIPAddress,
+}
+
+// Estimate returns the estimated monthly cost of a resource of the type, false when the type has no estimator or the
+// resource cannot be estimated
+func (p *Pricing) Estimate(resourceType string, props types.Properties) (float64, bool) {
+	estimate, ok := estimators[resourceType]
+	if !ok {
+		return 0, false
+	}
+
+	return estimate(p, props)
+}
+
+// Estimate is the estimated monthly cost of a resource
+type Estimate struct {
+	Type    string
+	Owner   string
+	Name    string
+	Monthly float64
+}
+
+// Summary are the estimates of the resources that would be removed, the most expensive first, and their total
+type Summary struct {
+	Currency  string
+	Estimates []*Estimate
+	Total     float64
+
+	// Unestimated is the number of resources that would be removed and have no estimate
+	Unestimated int
+}
+
+// Summarize returns the estimates of the resources of the items that would be removed
+func (p *Pricing) Summarize(items []*queue.Item) *Summary {
+	s := &Summary{Currency: p.Currency}
+	for _, item := range items {
+		if state := item.GetState(); state != queue.ItemStateNew && state != queue.ItemStateNewDependency {
+			continue
+		}
+
+		getter, ok := item.Resource.(resource.PropertyGetter)
+		if !ok {
+			s.Unestimated++
+			continue
+		}
+
+		monthly, ok := p.Estimate(item.Type, getter.Properties())
+		if !ok {
+			s.Unestimated++
+			continue
+		}
+
+		s.Estimates = append(s.Estimates, &Estimate{
+			Type:    item.Type,
+			Owner:   item.Owner,
+			Name:    nuke.ItemName(item),
+			Monthly: monthly,
+		})
+		s.Total += monthly
+	}
+
+	sort.SliceStable(s.Estimates, func(i, j int) bool {
+		return s.Estimates[i].Monthly > s.Estimates[j].Monthly
+	})
+
+	return s
+}
+
+// estimateComputeInstance prices the machine type of the running instances, the stopped ones only cost their disks,
+// which are estimated on their own. The nodes of the GKE clusters are part of the estimate of their cluster.
+func estimateComputeInstance(p *Pricing, props types.Properties) (float64, bool) {
+	if _, ok := props[gkeNodeLabel]; ok {
+		return 0, true
+	}
+
+	switch props.Get("Status") {
+	case "STOPPING", "TERMINATED", "SUSPENDING", "SUSPENDED":
+		return 0, true
+	}
+
+	hourly, ok := p.Compute.MachineType(props.Get("MachineType"))
+	if !ok {
+		return 0, false
+	}
+
+	return hourly * p.HoursPerMonth, true
+}
+
+// estimateComputeDisk prices the size of the disk by its type
+func estimateComputeDisk(p *Pricing, props types.Properties) (float64, bool) {
+	price, ok := p.Compute.Disks[props.Get("Type")]
+	if !ok {
+		return 0, false
+	}
+
+	return price * float64(intProperty(props, "Size")), true
+}
+
+// estimateCloudSQLInstance prices the tier of the running instances and the size of their data disk, the highly
+// available instances cost twice as much
+func estimateCloudSQLInstance(p *Pricing, props types.Properties) (float64, bool) {
+	monthly := 0.0
+
+	if props.Get("ActivationPolicy") != "NEVER" {
+		hourly, ok := p.CloudSQL.Tier(props.Get("Tier"))
+		if !ok {
+			return 0, false
+		}
+
+		monthly += hourly * p.HoursPerMonth
+	}
+
+	if size := intProperty(props, "DataDiskSizeGb"); size > 0 {
+		price, ok := p.CloudSQL.Storage[props.Get("DataDiskType")]
+		if !ok {
+			return 0, false
+		}
+
+		monthly += price * float64(size)
+	}
+
+	if props.Get("AvailabilityType") == "REGIONAL" {
+		monthly *= 2
+	}
+
+	return monthly, true
+}
+
+// estimateGKECluster prices the cluster management fee and the machine type of its nodes, the disks of the nodes are
+// estimated on their own
+func estimateGKECluster(p *Pricing, props types.Properties) (float64, bool) {
+	hourly := p.GKE.Cluster
+
+	if nodes := intProperty(props, "NodeCount"); nodes > 0 {
+		node, ok := p.Compute.MachineType(props.Get("NodeMachineType"))
+		if !ok {
+			return 0, false
+		}
+
+		hourly += node * float64(nodes)
+	}
+
+	return hourly * p.HoursPerMonth, true
+}
+
+// estimateRedisInstance prices the memory of the instance by its tier and its capacity tier
+func estimateRedisInstance(p *Pricing, props types.Properties) (float64, bool) {
+	size := intProperty(props, "MemorySizeGb")
+
+	price, ok := p.Redis.Price(props.Get("Tier"), size)
+	if !ok {
+		return 0, false
+	}
+
+	return price * float64(size) * p.HoursPerMonth, true
+}
+
+// estimateFilestoreInstance prices the capacity of the instance by its tier
+func estimateFilestoreInstance(p *Pricing, props types.Properties) (float64, bool) {
+	price, ok := p.Filestore.Tiers[props.Get("Tier")]
+	if !ok {
+		return 0, false
+	}
+
+	return price * float64(intProperty(props, "CapacityGb")), true
+}
+
+// estimateIPAddress prices the static external IP addresses by whether they are in use, the internal ones are free
+func estimateIPAddress(p *Pricing, props types.Properties) (float64, bool) {
+	if props.Get("AddressType") == "INTERNAL" {
+		return 0, true
+	}
+
+	hourly := p.IPAddresses.ExternalReserved
+	if props.Get("Status") == "IN_USE" {
+		hourly = p.IPAddresses.ExternalInUse
+	}
+
+	return hourly * p.HoursPerMonth, true
+}
+
+// intProperty returns the value of an integer property, zero when it is not set
+func intProperty(props types.Properties, key string) int64 {
+	value, _ := strconv.ParseInt(props.Get(key), 10, 64)
+	return value
+}
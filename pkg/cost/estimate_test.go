@@ -0,0 +1,218 @@
+package cost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testResource struct {
+	name  string
+	props types.Properties
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func (r *testResource) Properties() types.Properties {
+	return r.props.Set("Name", r.name)
+}
+
+func testPricing() *Pricing {
+	return &Pricing{
+		Currency:      "USD",
+		HoursPerMonth: 100,
+		Compute: ComputePricing{
+			MachineTypes: map[string]float64{"e2-medium": 0.03, "e2-standard-4": 0.13},
+			Disks:        map[string]float64{"pd-balanced": 0.1},
+		},
+		CloudSQL: CloudSQLPricing{
+			Tiers:   map[string]float64{"db-g1-small": 0.035},
+			Storage: map[string]float64{"PD_SSD": 0.17},
+		},
+		GKE: GKEPricing{Cluster: 0.1},
+		Redis: RedisPricing{Tiers: map[string][]*CapacityTier{
+			"BASIC": {{MaxGB: 4, Price: 0.05}, {Price: 0.02}},
+		}},
+		Filestore:   FilestorePricing{Tiers: map[string]float64{"BASIC_HDD": 0.2}},
+		IPAddresses: IPAddressesPricing{ExternalReserved: 0.01, ExternalInUse: 0.005},
+	}
+}
+
+func props(values map[string]string) types.Properties {
+	p := types.NewProperties()
+	for key, value := range values {
+		p.Set(key, value)
+	}
+
+	return p
+}
+
+func TestEstimate(t *testing.T) {
+	p := testPricing()
+
+	cases := []struct {
+		name         string
+		resourceType string
+		props        map[string]string
+		monthly      float64
+		ok           bool
+	}{
+		{
+			name:         "compute-instance",
+			resourceType: "ComputeInstance",
+			props:        map[string]string{"MachineType": "e2-medium", "Status": "RUNNING"},
+			monthly:      3,
+			ok:           true,
+		},
+		{
+			name:         "compute-instance-stopped",
+			resourceType: "ComputeInstance",
+			props:        map[string]string{"MachineType": "e2-medium", "Status": "TERMINATED"},
+			ok:           true,
+		},
+		{
+			name:         "compute-instance-gke-node",
+			resourceType: "ComputeInstance",
+			props:        map[string]string{"MachineType": "e2-medium", "label:goog-gke-node": ""},
+			ok:           true,
+		},
+		{
+			name:         "compute-instance-unknown-machine-type",
+			resourceType: "ComputeInstance",
+			props:        map[string]string{"MachineType": "a2-highgpu-1g", "Status": "RUNNING"},
+		},
+		{
+			name:         "compute-disk",
+			resourceType: "ComputeDisk",
+			props:        map[string]string{"Type": "pd-balanced", "Size": "50"},
+			monthly:      5,
+			ok:           true,
+		},
+		{
+			name:         "cloud-sql-instance",
+			resourceType: "CloudSQLInstance",
+			props:        map[string]string{"Tier": "db-g1-small", "DataDiskType": "PD_SSD", "DataDiskSizeGb": "10"},
+			monthly:      3.5 + 1.7,
+			ok:           true,
+		},
+		{
+			name:         "cloud-sql-instance-regional",
+			resourceType: "CloudSQLInstance",
+			props: map[string]string{
+				"Tier": "db-g1-small", "DataDiskType": "PD_SSD", "DataDiskSizeGb": "10", "AvailabilityType": "REGIONAL",
+			},
+			monthly: 2 * (3.5 + 1.7),
+			ok:      true,
+		},
+		{
+			name:         "cloud-sql-instance-stopped",
+			resourceType: "CloudSQLInstance",
+			props: map[string]string{
+				"Tier": "db-g1-small", "DataDiskType": "PD_SSD", "DataDiskSizeGb": "10", "ActivationPolicy": "NEVER",
+			},
+			monthly: 1.7,
+			ok:      true,
+		},
+		{
+			name:         "gke-cluster",
+			resourceType: "GKECluster",
+			props:        map[string]string{"NodeCount": "3", "NodeMachineType": "e2-standard-4"},
+			monthly:      10 + 3*13,
+			ok:           true,
+		},
+		{
+			name:         "gke-cluster-no-nodes",
+			resourceType: "GKECluster",
+			monthly:      10,
+			ok:           true,
+		},
+		{
+			name:         "gke-cluster-unknown-machine-type",
+			resourceType: "GKECluster",
+			props:        map[string]string{"NodeCount": "3", "NodeMachineType": "ek-standard-8"},
+		},
+		{
+			name:         "memorystore-redis-instance",
+			resourceType: "MemorystoreRedisInstance",
+			props:        map[string]string{"Tier": "BASIC", "MemorySizeGb": "5"},
+			monthly:      10,
+			ok:           true,
+		},
+		{
+			name:         "filestore-instance",
+			resourceType: "FilestoreInstance",
+			props:        map[string]string{"Tier": "BASIC_HDD", "CapacityGb": "1024"},
+			monthly:      204.8,
+			ok:           true,
+		},
+		{
+			name:         "ip-address-reserved",
+			resourceType: "VPCIPAddress",
+			props:        map[string]string{"AddressType": "EXTERNAL", "Status": "RESERVED"},
+			monthly:      1,
+			ok:           true,
+		},
+		{
+			name:         "ip-address-in-use",
+			resourceType: "VPCGlobalIPAddress",
+			props:        map[string]string{"Status": "IN_USE"},
+			monthly:      0.5,
+			ok:           true,
+		},
+		{
+			name:         "ip-address-internal",
+			resourceType: "VPCIPAddress",
+			props:        map[string]string{"AddressType": "INTERNAL", "Status": "RESERVED"},
+			ok:           true,
+		},
+		{
+			name:         "no-estimator",
+			resourceType: "PubSubTopic",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			monthly, ok := p.Estimate(tc.resourceType, props(tc.props))
+			assert.Equal(t, tc.ok, ok)
+			assert.InDelta(t, tc.monthly, monthly, 1e-9)
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	p := testPricing()
+
+	newItem := func(resourceType, name string, values map[string]string, state queue.ItemState) *queue.Item {
+		r := &testResource{name: name, props: props(values)}
+		return &queue.Item{Resource: r, Type: resourceType, Owner: "us-east1", State: state}
+	}
+
+	items := []*queue.Item{
+		newItem("ComputeDisk", "disk", map[string]string{"Type": "pd-balanced", "Size": "10"}, queue.ItemStateNew),
+		newItem("ComputeInstance", "vm", map[string]string{"MachineType": "e2-medium"}, queue.ItemStateNewDependency),
+		newItem("ComputeInstance", "unknown", map[string]string{"MachineType": "a2-highgpu-1g"}, queue.ItemStateNew),
+		newItem("PubSubTopic", "topic", nil, queue.ItemStateNew),
+		newItem("ComputeInstance", "filtered", map[string]string{"MachineType": "e2-medium"}, queue.ItemStateFiltered),
+	}
+
+	s := p.Summarize(items)
+	assert.Equal(t, "USD", s.Currency)
+	assert.InDelta(t, 4, s.Total, 1e-9)
+	assert.Equal(t, 2, s.Unestimated)
+
+	require.Len(t, s.Estimates, 2)
+	assert.Equal(t, &Estimate{Type: "ComputeInstance", Owner: "us-east1", Name: "vm", Monthly: 3}, s.Estimates[0])
+	assert.Equal(t, "disk", s.Estimates[1].Name)
+}
@@ -0,0 +1,208 @@
+// Package cost provides the estimated monthly cost of the resources that would be removed, so that the savings of a
+// run can be judged before it is confirmed. The estimates come from a pricing table bundled with gcp-nuke, whose
+// prices can be overridden by a local pricing file, and from the sizing properties of the resources, e.g. the machine
+// type of a compute instance. Only the resource types with obvious pricing dimensions have an estimate.
+package cost
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed pricing.yaml
+var bundledPricing []byte
+
+// Pricing is the pricing table of the estimates, the prices are in Currency. The prices per hour are multiplied by
+// HoursPerMonth to get the monthly cost.
+type Pricing struct {
+	Currency      string  `yaml:"currency"`
+	HoursPerMonth float64 `yaml:"hours-per-month"`
+
+	Compute     ComputePricing     `yaml:"compute"`
+	CloudSQL    CloudSQLPricing    `yaml:"cloud-sql"`
+	GKE         GKEPricing         `yaml:"gke"`
+	Redis       RedisPricing       `yaml:"memorystore-redis"`
+	Filestore   FilestorePricing   `yaml:"filestore"`
+	IPAddresses IPAddressesPricing `yaml:"ip-addresses"`
+}
+
+// ComputePricing are the prices of the compute instances, by machine type, and of the disks
+type ComputePricing struct {
+	// MachineTypes is the price per hour of the predefined machine types
+	MachineTypes map[string]float64 `yaml:"machine-types"`
+
+	// Custom is the price of the custom machine types by machine family, e.g. e2
+	Custom map[string]*ResourcePricing `yaml:"custom"`
+
+	// Disks is the price per GB month by disk type, e.g. pd-balanced
+	Disks map[string]float64 `yaml:"disks"`
+}
+
+// ResourcePricing is the price per vCPU hour and per GB of memory hour
+type ResourcePricing struct {
+	VCPU     float64 `yaml:"vcpu"`
+	MemoryGB float64 `yaml:"memory-gb"`
+}
+
+// CloudSQLPricing are the prices of the Cloud SQL instances
+type CloudSQLPricing struct {
+	// Tiers is the price per hour of the predefined tiers, e.g. db-f1-micro
+	Tiers map[string]float64 `yaml:"tiers"`
+
+	// ResourcePricing is the price of the custom tiers, e.g. db-custom-2-7680
+	ResourcePricing `yaml:",inline"`
+
+	// Storage is the price per GB month by data disk type, e.g. PD_SSD
+	Storage map[string]float64 `yaml:"storage"`
+}
+
+// GKEPricing are the prices of the GKE clusters, their nodes are priced as compute instances
+type GKEPricing struct {
+	// Cluster is the price per hour of the cluster management fee
+	Cluster float64 `yaml:"cluster"`
+}
+
+// RedisPricing are the prices of the Memorystore for Redis instances
+type RedisPricing struct {
+	// Tiers are the capacity tiers by tier, e.g. BASIC, sorted by their maximum size
+	Tiers map[string][]*CapacityTier `yaml:"tiers"`
+}
+
+// CapacityTier is the price per GB hour of the instances of up to MaxGB, the last capacity tier has no maximum
+type CapacityTier struct {
+	MaxGB int64   `yaml:"max-gb"`
+	Price float64 `yaml:"price"`
+}
+
+// FilestorePricing are the prices of the Filestore instances
+type FilestorePricing struct {
+	// Tiers is the price per GB month of the capacity by tier, e.g. BASIC_HDD
+	Tiers map[string]float64 `yaml:"tiers"`
+}
+
+// IPAddressesPricing are the prices per hour of the static external IP addresses, the internal ones are free
+type IPAddressesPricing struct {
+	ExternalReserved float64 `yaml:"external-reserved"`
+	ExternalInUse    float64 `yaml:"external-in-use"`
+}
+
+// Bundled returns the pricing table bundled with gcp-nuke
+func Bundled() (*Pricing, error) {
+	p := &Pricing{}
+	if err := yaml.Unmarshal(bundledPricing, p); err != nil {
+		return nil, fmt.Errorf("unable to parse the bundled pricing table: %w", err)
+	}
+
+	return p, nil
+}
+
+// Load returns the bundled pricing table with the prices of the pricing file at path merged over it, the bundled
+// table when path is empty. The prices of the file replace the bundled ones, the prices it does not have are kept.
+func Load(path string) (*Pricing, error) {
+	p, err := Bundled()
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: yaml.v3 decodes into the maps that are already set, the keys of the file are added to the bundled ones
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("unable to parse pricing file %s: %w", path, err)
+	}
+
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid pricing file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+func (p *Pricing) validate() error {
+	if p.Currency == "" {
+		return errors.New("currency must not be empty")
+	}
+
+	if p.HoursPerMonth <= 0 {
+		return errors.New("hours-per-month must be greater than zero")
+	}
+
+	return nil
+}
+
+// customMachineType is a custom machine type, e.g. e2-custom-4-8192 or custom-4-8192 for N1, with its memory in MB,
+// the extended memory suffix is ignored
+var customMachineType = regexp.MustCompile(`^(?:([a-z0-9]+)-)?custom-(\d+)-(\d+)(?:-ext)?$`)
+
+// MachineType returns the price per hour of the machine type, false when it has no price
+func (c *ComputePricing) MachineType(machineType string) (float64, bool) {
+	if price, ok := c.MachineTypes[machineType]; ok {
+		return price, true
+	}
+
+	match := customMachineType.FindStringSubmatch(machineType)
+	if match == nil {
+		return 0, false
+	}
+
+	family := match[1]
+	if family == "" {
+		family = "n1"
+	}
+
+	custom, ok := c.Custom[family]
+	if !ok || custom == nil {
+		return 0, false
+	}
+
+	return custom.price(match[2], match[3]), true
+}
+
+// customTier is a custom tier of Cloud SQL, e.g. db-custom-2-7680, with its memory in MB
+var customTier = regexp.MustCompile(`^db-custom-(\d+)-(\d+)$`)
+
+// Tier returns the price per hour of the Cloud SQL tier, false when it has no price
+func (c *CloudSQLPricing) Tier(tier string) (float64, bool) {
+	if price, ok := c.Tiers[tier]; ok {
+		return price, true
+	}
+
+	match := customTier.FindStringSubmatch(tier)
+	if match == nil {
+		return 0, false
+	}
+
+	return c.price(match[1], match[2]), true
+}
+
+// price returns the price per hour of the number of vCPUs and of the memory in MB, they are matched digits
+func (r *ResourcePricing) price(vcpus, memoryMB string) float64 {
+	cpus, _ := strconv.ParseFloat(vcpus, 64)
+	memory, _ := strconv.ParseFloat(memoryMB, 64)
+
+	return cpus*r.VCPU + memory/1024*r.MemoryGB
+}
+
+// Price returns the price per GB hour of an instance of the tier of sizeGB, false when the tier has no price
+func (r *RedisPricing) Price(tier string, sizeGB int64) (float64, bool) {
+	for _, capacity := range r.Tiers[tier] {
+		if capacity != nil && (capacity.MaxGB == 0 || sizeGB <= capacity.MaxGB) {
+			return capacity.Price, true
+		}
+	}
+
+	return 0, false
+}
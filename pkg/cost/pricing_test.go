@@ -0,0 +1,144 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundled(t *testing.T) {
+	p, err := Bundled()
+	require.NoError(t, err)
+	require.NoError(t, p.validate())
+
+	assert.Equal(t, "USD", p.Currency)
+	assert.Equal(t, 730.0, p.HoursPerMonth)
+	assert.NotEmpty(t, p.Compute.MachineTypes)
+	assert.NotEmpty(t, p.Compute.Disks)
+	assert.NotEmpty(t, p.CloudSQL.Tiers)
+	assert.NotEmpty(t, p.Redis.Tiers)
+	assert.NotEmpty(t, p.Filestore.Tiers)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pricing.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+currency: EUR
+compute:
+  machine-types:
+    e2-micro: 0.01
+    a2-highgpu-1g: 3.67
+gke:
+  cluster: 0
+`), 0600))
+
+	p, err := Load(path)
+	require.NoError(t, err)
+
+	bundled, err := Bundled()
+	require.NoError(t, err)
+
+	assert.Equal(t, "EUR", p.Currency)
+	assert.Equal(t, bundled.HoursPerMonth, p.HoursPerMonth)
+	assert.Equal(t, 0.01, p.Compute.MachineTypes["e2-micro"])
+	assert.Equal(t, 3.67, p.Compute.MachineTypes["a2-highgpu-1g"])
+	assert.Equal(t, bundled.Compute.MachineTypes["n1-standard-1"], p.Compute.MachineTypes["n1-standard-1"])
+	assert.Equal(t, bundled.Compute.Disks, p.Compute.Disks)
+	assert.Zero(t, p.GKE.Cluster)
+
+	p, err = Load("")
+	require.NoError(t, err)
+	assert.Equal(t, bundled, p)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := map[string]string{
+		"not-yaml":        "compute: [",
+		"currency":        `currency: ""`,
+		"hours-per-month": "hours-per-month: 0",
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".yaml")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Load(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestMachineType(t *testing.T) {
+	c := &ComputePricing{
+		MachineTypes: map[string]float64{"e2-medium": 0.03},
+		Custom: map[string]*ResourcePricing{
+			"e2": {VCPU: 0.02, MemoryGB: 0.003},
+			"n1": {VCPU: 0.03, MemoryGB: 0.004},
+		},
+	}
+
+	cases := []struct {
+		machineType string
+		price       float64
+		ok          bool
+	}{
+		{"e2-medium", 0.03, true},
+		{"e2-custom-4-8192", 4*0.02 + 8*0.003, true},
+		{"custom-2-4096", 2*0.03 + 4*0.004, true},
+		{"n1-custom-2-4096-ext", 2*0.03 + 4*0.004, true},
+		{"n2-custom-2-4096", 0, false},
+		{"e2-standard-2", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.machineType, func(t *testing.T) {
+			price, ok := c.MachineType(tc.machineType)
+			assert.Equal(t, tc.ok, ok)
+			assert.InDelta(t, tc.price, price, 1e-9)
+		})
+	}
+}
+
+func TestTier(t *testing.T) {
+	c := &CloudSQLPricing{
+		Tiers:           map[string]float64{"db-f1-micro": 0.01},
+		ResourcePricing: ResourcePricing{VCPU: 0.04, MemoryGB: 0.007},
+	}
+
+	price, ok := c.Tier("db-f1-micro")
+	assert.True(t, ok)
+	assert.Equal(t, 0.01, price)
+
+	price, ok = c.Tier("db-custom-2-7680")
+	assert.True(t, ok)
+	assert.InDelta(t, 2*0.04+7.5*0.007, price, 1e-9)
+
+	_, ok = c.Tier("db-perf-optimized-N-8")
+	assert.False(t, ok)
+}
+
+func TestRedisPrice(t *testing.T) {
+	r := &RedisPricing{Tiers: map[string][]*CapacityTier{
+		"BASIC": {{MaxGB: 4, Price: 0.05}, {MaxGB: 10, Price: 0.03}, {Price: 0.02}},
+	}}
+
+	cases := map[int64]float64{1: 0.05, 4: 0.05, 5: 0.03, 10: 0.03, 300: 0.02}
+	for size, expected := range cases {
+		price, ok := r.Price("BASIC", size)
+		assert.True(t, ok)
+		assert.Equal(t, expected, price, "size %d", size)
+	}
+
+	_, ok := r.Price("STANDARD_HA", 1)
+	assert.False(t, ok)
+}
@@ -0,0 +1,220 @@
+// Package cron parses cron expressions and computes the next time they are due. It supports the five standard fields
+// (minute, hour, day of month, month and day of week), the names of the months and the days, and the descriptors
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly and @every <duration>.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expression string
+
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny are true when the field is "*", a day matches when both match or, when both are restricted,
+	// when either matches
+	domAny, dowAny bool
+
+	// every is the interval of @every, the other fields are not used when it is set
+	every time.Duration
+}
+
+// field is the range of the values of a field and the names its values can be given with
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Note: both 0 and 7 are Sunday
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron expression, e.g. "0 2 * * mon-fri" or "@daily"
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	s := &Schedule{expression: expression}
+
+	if interval, ok := strings.CutPrefix(expression, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || every < time.Minute {
+			return nil, fmt.Errorf("invalid cron expression %q: @every requires a duration of at least 1m",
+				expression)
+		}
+
+		s.every = every
+
+		return s, nil
+	}
+
+	if standard, ok := descriptors[strings.ToLower(expression)]; ok {
+		expression = standard
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", s.expression, len(fields))
+	}
+
+	var err error
+	for i, f := range []struct {
+		field  field
+		bits   *uint64
+		anyDay *bool
+	}{
+		{field: minuteField, bits: &s.minute},
+		{field: hourField, bits: &s.hour},
+		{field: domField, bits: &s.dom, anyDay: &s.domAny},
+		{field: monthField, bits: &s.month},
+		{field: dowField, bits: &s.dow, anyDay: &s.dowAny},
+	} {
+		*f.bits, err = f.field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", s.expression, err)
+		}
+
+		if f.anyDay != nil {
+			*f.anyDay = fields[i] == "*"
+		}
+	}
+
+	// Note: 7 is Sunday too
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	return s, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expression
+}
+
+// Next returns the first time after t the schedule is due, in the location of t. The zero time is returned when it is
+// never due, e.g. on February 30th.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every).Truncate(time.Second)
+	}
+
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+
+	// Note: every valid expression is due at least once in 5 years, February 29th being the rarest day
+	limit := t.Year() + 5
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// parse returns the bits of the values of a field, e.g. "1-5", "*/15" or "mon,wed,fri"
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q for the %s", stepPart, f.name)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			low, err = f.value(lowPart)
+			if err != nil {
+				return 0, err
+			}
+
+			high = low
+			if isRange {
+				high, err = f.value(highPart)
+				if err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q for the %s", rangePart, f.name)
+			}
+		}
+
+		for i := low; i <= high; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f field) value(value string) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid value %q for the %s, must be between %d and %d", value, f.name, f.min, f.max)
+	}
+
+	return n, nil
+}
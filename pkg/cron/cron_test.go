@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every 30s",
+		"@every soon",
+		"@sometimes",
+	}
+
+	for _, expression := range cases {
+		t.Run(expression, func(t *testing.T) {
+			_, err := Parse(expression)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Note: a Saturday
+	from := time.Date(2024, 6, 1, 10, 30, 15, 0, time.UTC)
+
+	cases := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "* * * * *", want: time.Date(2024, 6, 1, 10, 31, 0, 0, time.UTC)},
+		{expression: "*/15 * * * *", want: time.Date(2024, 6, 1, 10, 45, 0, 0, time.UTC)},
+		{expression: "0 2 * * *", want: time.Date(2024, 6, 2, 2, 0, 0, 0, time.UTC)},
+		{expression: "30 10 * * *", want: time.Date(2024, 6, 2, 10, 30, 0, 0, time.UTC)},
+		{expression: "0 22 * * mon-fri", want: time.Date(2024, 6, 3, 22, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * 7", want: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 1,15 * *", want: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 13 * fri", want: time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 29 feb *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expression: "5-10/5 3 * JAN,jul *", want: time.Date(2024, 7, 1, 3, 5, 0, 0, time.UTC)},
+		{expression: "@hourly", want: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)},
+		{expression: "@weekly", want: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{expression: "@monthly", want: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "@every 6h", want: time.Date(2024, 6, 1, 16, 30, 15, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expression, func(t *testing.T) {
+			s, err := Parse(tc.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, s.Next(from))
+			assert.Equal(t, tc.expression, s.String())
+		})
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestNextLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*3600)
+
+	s, err := Parse("0 2 * * *")
+	require.NoError(t, err)
+
+	next := s.Next(time.Date(2024, 6, 1, 10, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2024, 6, 2, 2, 0, 0, 0, loc), next)
+	assert.Equal(t, time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), next.UTC())
+}
@@ -0,0 +1,163 @@
+package gcputil
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Emulators are the hosts of the local emulators of the GCP APIs, e.g. localhost:8085. The APIs that have an emulator
+// are called on it, without credentials, and every other API is considered disabled.
+type Emulators struct {
+	PubSub    string `yaml:"pubsub"`
+	Firestore string `yaml:"firestore"`
+	Spanner   string `yaml:"spanner"`
+	Bigtable  string `yaml:"bigtable"`
+}
+
+// emulatorEnvVars are the environment variables the GCP client libraries and the emulators use for the hosts of the
+// emulators, by API
+var emulatorEnvVars = map[string]string{
+	"pubsub.googleapis.com":    "PUBSUB_EMULATOR_HOST",
+	"firestore.googleapis.com": "FIRESTORE_EMULATOR_HOST",
+	"spanner.googleapis.com":   "SPANNER_EMULATOR_HOST",
+	"bigtable.googleapis.com":  "BIGTABLE_EMULATOR_HOST",
+}
+
+// EmulatorsFromEnv returns the emulators of the environment variables, see WithEnv
+func EmulatorsFromEnv() *Emulators {
+	return (&Emulators{}).WithEnv()
+}
+
+// WithEnv returns a copy of the emulators with the hosts of the environment variables, e.g. PUBSUB_EMULATOR_HOST,
+// they take precedence
+func (e *Emulators) WithEnv() *Emulators {
+	merged := &Emulators{}
+	if e != nil {
+		*merged = *e
+	}
+
+	for api, host := range merged.hosts() {
+		if env := os.Getenv(emulatorEnvVars[api]); env != "" {
+			*host = env
+		}
+	}
+
+	return merged
+}
+
+// Enabled returns true when at least one API has an emulator
+func (e *Emulators) Enabled() bool {
+	return len(e.APIs()) > 0
+}
+
+// APIs returns the APIs that have an emulator, sorted
+func (e *Emulators) APIs() []string {
+	if e == nil {
+		return nil
+	}
+
+	apis := make([]string, 0)
+	for api, host := range e.hosts() {
+		if *host != "" {
+			apis = append(apis, api)
+		}
+	}
+	sort.Strings(apis)
+
+	return apis
+}
+
+// Host returns the host of the emulator of the API, empty when the API has no emulator
+func (e *Emulators) Host(api string) string {
+	if e == nil {
+		return ""
+	}
+
+	if host, ok := e.hosts()[api]; ok {
+		return *host
+	}
+
+	return ""
+}
+
+func (e *Emulators) hosts() map[string]*string {
+	return map[string]*string{
+		"pubsub.googleapis.com":    &e.PubSub,
+		"firestore.googleapis.com": &e.Firestore,
+		"spanner.googleapis.com":   &e.Spanner,
+		"bigtable.googleapis.com":  &e.Bigtable,
+	}
+}
+
+// NewEmulated returns a GCP instance for the project on the emulators. Nothing is discovered: the project is the only
+// one, "global" is the only region and the APIs that have an emulator are the only enabled ones. It requires a
+// project, the emulators have no organization.
+func NewEmulated(projectID string, emulators *Emulators, throttle *Throttle) (*GCP, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("the emulators require a project ID")
+	}
+
+	if !emulators.Enabled() {
+		return nil, fmt.Errorf("no emulator is configured")
+	}
+
+	gcp := &GCP{
+		Organizations: make([]*Organization, 0),
+		Projects: []*Project{{
+			Name:      fmt.Sprintf("projects/%s", projectID),
+			ProjectID: projectID,
+			State:     "ACTIVE",
+		}},
+		clientOptions: make([]option.ClientOption, 0),
+		throttle:      throttle,
+		emulators:     emulators,
+	}
+
+	gcp.discoverEmulated(projectID)
+
+	return gcp, nil
+}
+
+// GetEmulators returns the emulators the APIs are called on, or nil when the real APIs are called
+func (g *GCP) GetEmulators() *Emulators {
+	return g.emulators
+}
+
+// GetEmulatorClientOptions returns the client options of the gRPC clients of the APIs that have an emulator, by API.
+// The clients connect to the emulator without TLS nor credentials, the calls still go through the throttle.
+func (g *GCP) GetEmulatorClientOptions() map[string][]option.ClientOption {
+	if g.emulators == nil {
+		return nil
+	}
+
+	clientOptions := make(map[string][]option.ClientOption)
+	for _, api := range g.emulators.APIs() {
+		opts := []option.ClientOption{
+			option.WithEndpoint(g.emulators.Host(api)),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		}
+
+		if g.throttle != nil {
+			opts = append(opts,
+				option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(g.throttle.UnaryClientInterceptor())))
+		}
+
+		clientOptions[api] = opts
+	}
+
+	return clientOptions
+}
+
+// discoverEmulated sets the project with the regions and the APIs of the emulators, instead of discovering them
+func (g *GCP) discoverEmulated(projectID string) {
+	g.ProjectID = projectID
+	g.Regions = []string{"global"}
+	g.APIS = g.emulators.APIs()
+	g.zones = make(map[string][]string)
+}
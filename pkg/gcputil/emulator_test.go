@@ -0,0 +1,48 @@
+package gcputil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulatorsWithEnv(t *testing.T) {
+	t.Setenv("PUBSUB_EMULATOR_HOST", "localhost:8085")
+	t.Setenv("SPANNER_EMULATOR_HOST", "")
+
+	var none *Emulators
+	assert.False(t, none.Enabled())
+
+	emulators := (&Emulators{PubSub: "pubsub:8085", Spanner: "spanner:9010"}).WithEnv()
+	assert.Equal(t, &Emulators{PubSub: "localhost:8085", Spanner: "spanner:9010"}, emulators)
+	assert.Equal(t, []string{"pubsub.googleapis.com", "spanner.googleapis.com"}, emulators.APIs())
+	assert.Equal(t, "spanner:9010", emulators.Host("spanner.googleapis.com"))
+	assert.Empty(t, emulators.Host("bigtable.googleapis.com"))
+
+	assert.Equal(t, []string{"pubsub.googleapis.com"}, EmulatorsFromEnv().APIs())
+}
+
+func TestNewEmulated(t *testing.T) {
+	t.Setenv("FIRESTORE_EMULATOR_HOST", "localhost:8080")
+
+	// Note: nothing is discovered, no API of GCP is called
+	gcp, err := New(context.TODO(), "test-project", nil, NewThrottle(RateLimits{}), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-project", gcp.ID())
+	assert.True(t, gcp.HasProjects())
+	assert.Equal(t, "test-project", gcp.Projects[0].ID())
+	assert.Equal(t, []string{"global"}, gcp.Regions)
+	assert.Equal(t, []string{"firestore.googleapis.com"}, gcp.GetEnabledAPIs())
+	assert.Len(t, gcp.GetEmulatorClientOptions()["firestore.googleapis.com"], 4)
+
+	other, err := gcp.WithProject(context.TODO(), "other-project")
+	require.NoError(t, err)
+	assert.Equal(t, "other-project", other.ID())
+	assert.Equal(t, []string{"firestore.googleapis.com"}, other.GetEnabledAPIs())
+
+	_, err = NewEmulated("", gcp.GetEmulators(), nil)
+	assert.EqualError(t, err, "the emulators require a project ID")
+}
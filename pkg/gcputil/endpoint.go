@@ -0,0 +1,161 @@
+package gcputil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+
+	"google.golang.org/api/option"
+)
+
+// DefaultUniverseDomain is the universe domain of the public APIs
+const DefaultUniverseDomain = "googleapis.com"
+
+// Endpoints are where the APIs are called: the universe domain of the APIs, e.g. of a sovereign cloud, and the
+// addresses the connections to some of them go to instead, e.g. a Private Service Connect endpoint.
+type Endpoints struct {
+	// UniverseDomain is the domain of the host names of the APIs, DefaultUniverseDomain when empty
+	UniverseDomain string
+
+	// Addresses are the addresses, as host:port, the connections to the APIs go to, by the host name of the API (e.g.
+	// "storage.googleapis.com"). The clients still call the API by its host name, the same as a DNS record for the
+	// API would, so that the certificates and the routing of Google keep working.
+	Addresses map[string]string
+}
+
+// ParseEndpoints returns the endpoints of the universe domain with the addresses of the APIs. The API can be given by
+// its host name or by its short name, e.g. "storage.googleapis.com" or "storage". The address is a host, a host:port
+// or an https URL, e.g. "storage-myendpoint.p.googleapis.com" or "https://10.0.0.5:443".
+func ParseEndpoints(addresses map[string]string, universeDomain string) (*Endpoints, error) {
+	e := &Endpoints{
+		UniverseDomain: strings.TrimSpace(universeDomain),
+		Addresses:      make(map[string]string, len(addresses)),
+	}
+
+	apis := make([]string, 0, len(addresses))
+	for api := range addresses {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+
+	for _, name := range apis {
+		api, value := strings.TrimSpace(name), addresses[name]
+		if !strings.Contains(api, ".") {
+			api += "." + DefaultUniverseDomain
+		}
+
+		address, err := parseAddress(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint of %s: %w", api, err)
+		}
+
+		e.Addresses[api] = address
+	}
+
+	return e, nil
+}
+
+// Merge returns a copy of the endpoints with the addresses of the APIs that have none yet, e.g. the endpoints of the
+// flags merged with the endpoints of the config
+func (e *Endpoints) Merge(addresses map[string]string) (*Endpoints, error) {
+	parsed, err := ParseEndpoints(addresses, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if e != nil {
+		parsed.UniverseDomain = e.UniverseDomain
+		for api, address := range e.Addresses {
+			parsed.Addresses[api] = address
+		}
+	}
+
+	return parsed, nil
+}
+
+// parseAddress returns the host:port of the address of an endpoint, the port is 443 when none is given
+func parseAddress(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("the address is empty")
+	}
+
+	host := value
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return "", fmt.Errorf("invalid address %q, must be a host, a host:port or an https URL", value)
+		}
+
+		if strings.Trim(u.Path, "/") != "" {
+			return "", fmt.Errorf("invalid address %q, the path of the API is kept, only the host can be given", value)
+		}
+
+		host = u.Host
+	}
+
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host, nil
+	}
+
+	return net.JoinHostPort(host, "443"), nil
+}
+
+// universeDomain returns the universe domain of the APIs
+func (e *Endpoints) universeDomain() string {
+	if e == nil || e.UniverseDomain == "" {
+		return DefaultUniverseDomain
+	}
+
+	return e.UniverseDomain
+}
+
+// hasAddresses returns true when the connections to at least one API go to another address
+func (e *Endpoints) hasAddresses() bool {
+	return e != nil && len(e.Addresses) > 0
+}
+
+// clientOptions returns the client options of every client, the universe domain when it is not the default one
+func (e *Endpoints) clientOptions() []option.ClientOption {
+	if e.universeDomain() == DefaultUniverseDomain {
+		return nil
+	}
+
+	return []option.ClientOption{option.WithUniverseDomain(e.universeDomain())}
+}
+
+// address returns the address the connection to the address of an API goes to, the address itself when the API has
+// no endpoint. The APIs of the universe domain are looked up by their googleapis.com host name.
+func (e *Endpoints) address(address string) string {
+	if !e.hasAddresses() {
+		return address
+	}
+
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+
+	if universe := e.universeDomain(); universe != DefaultUniverseDomain && strings.HasSuffix(host, "."+universe) {
+		host = strings.TrimSuffix(host, "."+universe) + "." + DefaultUniverseDomain
+	}
+
+	if endpoint, ok := e.Addresses[apiName(host)]; ok {
+		return endpoint
+	}
+
+	return address
+}
+
+// dialContext connects to the address of the API, see address. It is the dialer of the REST and the gRPC clients.
+func (e *Endpoints) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if network == "" {
+		network = "tcp"
+	}
+
+	dialer := &net.Dialer{}
+	return dialer.DialContext(ctx, network, e.address(address))
+}
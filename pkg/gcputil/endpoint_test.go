@@ -0,0 +1,111 @@
+package gcputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/api/option"
+)
+
+func TestParseEndpoints(t *testing.T) {
+	endpoints, err := ParseEndpoints(map[string]string{
+		"storage":                "storage-myendpoint.p.googleapis.com",
+		"compute.googleapis.com": "https://10.0.0.5/",
+		"pubsub":                 "10.0.0.6:8443",
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"storage.googleapis.com": "storage-myendpoint.p.googleapis.com:443",
+		"compute.googleapis.com": "10.0.0.5:443",
+		"pubsub.googleapis.com":  "10.0.0.6:8443",
+	}, endpoints.Addresses)
+	assert.Equal(t, DefaultUniverseDomain, endpoints.universeDomain())
+	assert.Empty(t, endpoints.clientOptions())
+
+	_, err = ParseEndpoints(map[string]string{"storage": "http://10.0.0.5"}, "")
+	assert.Error(t, err)
+
+	_, err = ParseEndpoints(map[string]string{"storage": "https://10.0.0.5/storage/v1/"}, "")
+	assert.Error(t, err)
+
+	_, err = ParseEndpoints(map[string]string{"storage": ""}, "")
+	assert.Error(t, err)
+}
+
+func TestEndpointsMerge(t *testing.T) {
+	flags, err := ParseEndpoints(map[string]string{"storage": "flag.example.com"}, "example.eu")
+	require.NoError(t, err)
+
+	merged, err := flags.Merge(map[string]string{"storage": "config.example.com", "compute": "10.0.0.5"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.eu", merged.UniverseDomain)
+	assert.Equal(t, map[string]string{
+		"storage.googleapis.com": "flag.example.com:443",
+		"compute.googleapis.com": "10.0.0.5:443",
+	}, merged.Addresses)
+
+	var none *Endpoints
+	merged, err = none.Merge(nil)
+	require.NoError(t, err)
+	assert.False(t, merged.hasAddresses())
+}
+
+func TestEndpointsAddress(t *testing.T) {
+	endpoints, err := ParseEndpoints(map[string]string{
+		"storage": "10.0.0.5",
+		"compute": "10.0.0.6",
+	}, "example.eu")
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		"storage.googleapis.com:443":             "10.0.0.5:443",
+		"storage.example.eu:443":                 "10.0.0.5:443",
+		"us-central1-compute.googleapis.com:443": "10.0.0.6:443",
+		"pubsub.googleapis.com:443":              "pubsub.googleapis.com:443",
+		"oauth2.googleapis.com:443":              "oauth2.googleapis.com:443",
+	}
+
+	for address, want := range cases {
+		assert.Equal(t, want, endpoints.address(address), address)
+	}
+
+	var none *Endpoints
+	assert.Equal(t, "storage.googleapis.com:443", none.address("storage.googleapis.com:443"))
+}
+
+func TestEndpointsDial(t *testing.T) {
+	var host string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer server.Close()
+
+	endpoints, err := ParseEndpoints(map[string]string{
+		"storage": strings.TrimPrefix(server.URL, "http://"),
+	}, "")
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{DialContext: endpoints.dialContext}}
+	resp, err := client.Get("http://storage.googleapis.com/storage/v1/b")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "storage.googleapis.com", host)
+}
+
+func TestIdentityPlatformUniverseDomain(t *testing.T) {
+	s, err := NewIdentityPlatformService(context.TODO(),
+		option.WithoutAuthentication(), option.WithUniverseDomain("example.eu"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://identitytoolkit.example.eu/admin/v2/", s.BasePath)
+
+	s, err = NewIdentityPlatformService(context.TODO(), option.WithoutAuthentication())
+	require.NoError(t, err)
+	assert.Equal(t, identityPlatformBasePath, s.BasePath)
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"net"
+	"net/http"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -16,14 +16,14 @@ import (
 
 	"cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
-	"cloud.google.com/go/iam/credentials/apiv1"
-	"cloud.google.com/go/iam/credentials/apiv1/credentialspb"
 
 	"google.golang.org/api/cloudresourcemanager/v3"
 	"google.golang.org/api/iterator"
+	oauth2api "google.golang.org/api/oauth2/v2"
 	"google.golang.org/api/option"
 	"google.golang.org/api/serviceusage/v1"
-	"google.golang.org/protobuf/types/known/durationpb"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
 )
 
 type Organization struct {
@@ -38,6 +38,13 @@ func (o *Organization) ID() string {
 type Project struct {
 	Name      string
 	ProjectID string
+	Parent    string
+	State     string
+}
+
+// IsActive returns true if the project is not pending deletion
+func (p *Project) IsActive() bool {
+	return p.State == "" || p.State == "ACTIVE"
 }
 
 func (p *Project) ID() string {
@@ -54,8 +61,21 @@ type GCP struct {
 
 	zones map[string][]string
 
+	credentials   *google.Credentials
+	impersonation *Impersonation
 	tokenSource   oauth2.TokenSource
+	throttle      *Throttle
 	clientOptions []option.ClientOption
+
+	// emulators are the emulators the APIs are called on, nil unless running against the emulators, see NewEmulated
+	emulators *Emulators
+
+	// endpoints are the universe domain and the addresses the APIs are called on, nil for the public endpoints
+	endpoints *Endpoints
+
+	// grpcClientOptions are the client options for the gRPC clients, they are the same as clientOptions unless the
+	// requests are throttled or routed to the addresses of the endpoints, see ThrottleRequests
+	grpcClientOptions []option.ClientOption
 }
 
 func (g *GCP) HasOrganizations() bool {
@@ -72,45 +92,125 @@ func (g *GCP) HasProjects() bool {
 	return len(g.Projects) > 0
 }
 
+// GetOrganization returns the organization with the given ID if the caller has access to it, otherwise nil
+func (g *GCP) GetOrganization(id string) *Organization {
+	for _, org := range g.Organizations {
+		if org.ID() == id {
+			return org
+		}
+	}
+	return nil
+}
+
 func (g *GCP) GetZones(region string) []string {
 	return g.zones[region]
 }
 
-func (g *GCP) ImpersonateServiceAccount(ctx context.Context, targetServiceAccount string) error {
-	credsClient, err := credentials.NewIamCredentialsClient(ctx)
-	if err != nil {
-		return err
+// ImpersonateServiceAccount configures all API calls to use access tokens of the impersonated service account. The
+// tokens are generated using the current credentials and are refreshed before they expire, so runs that last longer
+// than the lifetime of a single token keep working.
+func (g *GCP) ImpersonateServiceAccount(ctx context.Context, impersonation *Impersonation) error {
+	if impersonation.Lifetime == 0 {
+		impersonation.Lifetime = DefaultImpersonateLifetime
 	}
-	defer func() { _ = credsClient.Close() }()
-
-	req := &credentialspb.GenerateAccessTokenRequest{
-		Name: fmt.Sprintf("projects/-/serviceAccounts/%s", targetServiceAccount),
-		Scope: []string{
-			"https://www.googleapis.com/auth/cloud-platform",
-		},
-		Lifetime: &durationpb.Duration{
-			Seconds: int64(time.Hour.Seconds()), // 1 hour
-		},
+	if impersonation.Lifetime > MaxImpersonateLifetime {
+		return fmt.Errorf("impersonation lifetime must be at most %s", MaxImpersonateLifetime)
 	}
-	resp, err := credsClient.GenerateAccessToken(ctx, req)
+	if len(impersonation.Scopes) == 0 {
+		impersonation.Scopes = DefaultImpersonateScopes
+	}
+
+	// Note: the client options at this point only contain the base credentials, used to generate the tokens
+	baseOptions := make([]option.ClientOption, len(g.clientOptions))
+	copy(baseOptions, g.clientOptions)
+
+	tokenSource, err := impersonation.tokenSource(context.WithoutCancel(ctx), baseOptions...)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to impersonate service account %s: %w", impersonation.ServiceAccount, err)
 	}
 
-	// Create a new authenticated client using the impersonated access token
-	g.tokenSource = oauth2.StaticTokenSource(&oauth2.Token{
-		AccessToken: resp.AccessToken,
-	})
+	// Generate the first token right away, so a misconfiguration is reported before anything else happens
+	if _, err := tokenSource.Token(); err != nil {
+		return fmt.Errorf("unable to impersonate service account %s: %w", impersonation.ServiceAccount, err)
+	}
 
+	g.tokenSource = tokenSource
+	g.impersonation = impersonation
 	g.clientOptions = append(g.clientOptions, option.WithTokenSource(g.tokenSource))
 
 	return nil
 }
 
+// GetImpersonation returns the impersonation configuration, or nil if no service account is impersonated
+func (g *GCP) GetImpersonation() *Impersonation {
+	return g.impersonation
+}
+
+// GetClientOptions returns the client options for the REST clients, these are the clients created with NewService or
+// New...RESTClient, and the storage and bigquery clients
 func (g *GCP) GetClientOptions() []option.ClientOption {
 	return g.clientOptions
 }
 
+// GetGRPCClientOptions returns the client options for the gRPC clients. When the requests are throttled the REST
+// client options include an HTTP client, which the gRPC clients refuse.
+func (g *GCP) GetGRPCClientOptions() []option.ClientOption {
+	if g.grpcClientOptions == nil {
+		return g.clientOptions
+	}
+
+	return g.grpcClientOptions
+}
+
+// ThrottleRequests makes all API calls go through the throttle, for the REST clients with an HTTP client that uses
+// the current credentials and for the gRPC clients with an interceptor. The throttle can be nil when the requests are
+// only routed to the addresses of the endpoints, the connections of both clients go to them.
+func (g *GCP) ThrottleRequests(ctx context.Context, throttle *Throttle) error {
+	baseOptions := make([]option.ClientOption, len(g.clientOptions))
+	copy(baseOptions, g.clientOptions)
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.MaxIdleConnsPerHost = 100
+
+	grpcOptions := make([]option.ClientOption, 0)
+	if g.endpoints.hasAddresses() {
+		base.DialContext = g.endpoints.dialContext
+		grpcOptions = append(grpcOptions, option.WithGRPCDialOption(grpc.WithContextDialer(
+			func(ctx context.Context, address string) (net.Conn, error) {
+				return g.endpoints.dialContext(ctx, "tcp", address)
+			})))
+	}
+
+	var rt http.RoundTripper = base
+	if throttle != nil {
+		rt = throttle.Transport(base)
+		grpcOptions = append(grpcOptions,
+			option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(throttle.UnaryClientInterceptor())))
+	}
+
+	transport, err := htransport.NewTransport(ctx, rt,
+		append([]option.ClientOption{option.WithScopes("https://www.googleapis.com/auth/cloud-platform")}, baseOptions...)...)
+	if err != nil {
+		return err
+	}
+
+	g.throttle = throttle
+	g.clientOptions = append(baseOptions, option.WithHTTPClient(&http.Client{Transport: transport}))
+	g.grpcClientOptions = append(baseOptions, grpcOptions...)
+
+	return nil
+}
+
+// GetEndpoints returns the universe domain and the addresses the APIs are called on, or nil for the public endpoints
+func (g *GCP) GetEndpoints() *Endpoints {
+	return g.endpoints
+}
+
+// GetThrottle returns the throttle all API calls go through, or nil if the requests are not throttled
+func (g *GCP) GetThrottle() *Throttle {
+	return g.throttle
+}
+
 func (g *GCP) ID() string {
 	return g.ProjectID
 }
@@ -119,11 +219,65 @@ func (g *GCP) GetEnabledAPIs() []string {
 	return g.APIS
 }
 
+// GetCredentials returns the base credentials, these are the credentials from GOOGLE_APPLICATION_CREDENTIALS_JSON when
+// it is set, otherwise the Application Default Credentials
 func (g *GCP) GetCredentials(ctx context.Context) (*google.Credentials, error) {
+	if g.credentials != nil {
+		return g.credentials, nil
+	}
 	return google.FindDefaultCredentials(ctx)
 }
 
-func New(ctx context.Context, projectID, impersonateServiceAccount string) (*GCP, error) {
+// GetPrincipal returns the email of the principal used for all API calls. When a service account is impersonated that
+// is the service account, otherwise the access token of the base credentials is inspected.
+func (g *GCP) GetPrincipal(ctx context.Context) (string, error) {
+	if g.impersonation != nil {
+		return g.impersonation.Principal(), nil
+	}
+
+	creds := g.credentials
+	if creds == nil {
+		var err error
+		creds, err = google.FindDefaultCredentials(ctx,
+			"https://www.googleapis.com/auth/cloud-platform",
+			"https://www.googleapis.com/auth/userinfo.email")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+
+	service, err := oauth2api.NewService(ctx, option.WithHTTPClient(http.DefaultClient))
+	if err != nil {
+		return "", err
+	}
+
+	info, err := service.Tokeninfo().AccessToken(token.AccessToken).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+
+	if info.Email == "" {
+		return "", fmt.Errorf("the access token does not include the email of the principal")
+	}
+
+	return info.Email, nil
+}
+
+// New returns a GCP instance with the organizations and the projects the credentials have access to, and the regions
+// and the enabled APIs of the project when one is given. When an emulator is configured by its environment variable,
+// e.g. PUBSUB_EMULATOR_HOST, nothing is discovered, see NewEmulated. The endpoints can be nil for the public ones.
+func New(ctx context.Context, projectID string, impersonation *Impersonation, throttle *Throttle,
+	endpoints *Endpoints) (*GCP, error) {
+	if emulators := EmulatorsFromEnv(); emulators.Enabled() {
+		logrus.Infof("using the emulators of %s", strings.Join(emulators.APIs(), ", "))
+		return NewEmulated(projectID, emulators, throttle)
+	}
+
 	gcp := &GCP{
 		Organizations: make([]*Organization, 0),
 		Projects:      make([]*Project, 0),
@@ -140,11 +294,25 @@ func New(ctx context.Context, projectID, impersonateServiceAccount string) (*GCP
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse GOOGLE_APPLICATION_CREDENTIALS_JSON: %w", err)
 		}
+		gcp.credentials = creds
 		gcp.clientOptions = append(gcp.clientOptions, option.WithCredentials(creds))
 	}
 
-	if impersonateServiceAccount != "" {
-		if err := gcp.ImpersonateServiceAccount(ctx, impersonateServiceAccount); err != nil {
+	if endpoints != nil {
+		logrus.Debugf("using the universe domain %s", endpoints.universeDomain())
+		gcp.endpoints = endpoints
+		gcp.clientOptions = append(gcp.clientOptions, endpoints.clientOptions()...)
+	}
+
+	if impersonation != nil && impersonation.ServiceAccount != "" {
+		if err := gcp.ImpersonateServiceAccount(ctx, impersonation); err != nil {
+			return nil, err
+		}
+	}
+
+	// Note: the throttle must be set up last, the HTTP client it adds uses the credentials configured so far
+	if throttle != nil || endpoints.hasAddresses() {
+		if err := gcp.ThrottleRequests(ctx, throttle); err != nil {
 			return nil, err
 		}
 	}
@@ -181,6 +349,8 @@ func New(ctx context.Context, projectID, impersonateServiceAccount string) (*GCP
 			newProject := &Project{
 				Name:      project.Name,
 				ProjectID: project.ProjectId,
+				Parent:    project.Parent,
+				State:     project.State,
 			}
 			gcp.Projects = append(gcp.Projects, newProject)
 
@@ -195,9 +365,101 @@ func New(ctx context.Context, projectID, impersonateServiceAccount string) (*GCP
 		return nil, err
 	}
 
-	c, err := compute.NewRegionsRESTClient(ctx, gcp.GetClientOptions()...)
+	// Note: when only targeting an organization there is no project to discover regions and APIs for
+	if projectID == "" {
+		return gcp, nil
+	}
+
+	if err := gcp.discoverProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return gcp, nil
+}
+
+// WithProject returns a copy of the GCP instance that targets the given project, the regions, zones and enabled APIs
+// are discovered for that project. The organizations, projects and credentials are shared with the original.
+func (g *GCP) WithProject(ctx context.Context, projectID string) (*GCP, error) {
+	gcp := &GCP{
+		Organizations: g.Organizations,
+		Projects:      g.Projects,
+		credentials:   g.credentials,
+		impersonation: g.impersonation,
+		tokenSource:   g.tokenSource,
+		throttle:      g.throttle,
+		clientOptions: g.clientOptions,
+		emulators:     g.emulators,
+		endpoints:     g.endpoints,
+
+		grpcClientOptions: g.grpcClientOptions,
+	}
+
+	if err := gcp.discoverProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return gcp, nil
+}
+
+// ListFolderProjects returns all the active projects that are under the folder, including those that are in nested
+// folders.
+func (g *GCP) ListFolderProjects(ctx context.Context, folderID string) ([]*Project, error) {
+	service, err := cloudresourcemanager.NewService(ctx, g.GetClientOptions()...)
 	if err != nil {
-		log.Fatalf("Failed to create client: %v", err)
+		return nil, err
+	}
+
+	var projects []*Project
+
+	folders := []string{fmt.Sprintf("folders/%s", strings.TrimPrefix(folderID, "folders/"))}
+	for len(folders) > 0 {
+		parent := folders[0]
+		folders = folders[1:]
+
+		if err := service.Projects.List().Parent(parent).Pages(ctx,
+			func(page *cloudresourcemanager.ListProjectsResponse) error {
+				for _, project := range page.Projects {
+					projects = append(projects, &Project{
+						Name:      project.Name,
+						ProjectID: project.ProjectId,
+						Parent:    project.Parent,
+						State:     project.State,
+					})
+				}
+				return nil
+			}); err != nil {
+			return nil, err
+		}
+
+		if err := service.Folders.List().Parent(parent).Pages(ctx,
+			func(page *cloudresourcemanager.ListFoldersResponse) error {
+				for _, folder := range page.Folders {
+					folders = append(folders, folder.Name)
+				}
+				return nil
+			}); err != nil {
+			return nil, err
+		}
+	}
+
+	return projects, nil
+}
+
+// discoverProject sets the project and discovers the regions, zones and enabled APIs for it
+func (g *GCP) discoverProject(ctx context.Context, projectID string) error {
+	if g.emulators != nil {
+		g.discoverEmulated(projectID)
+		return nil
+	}
+
+	g.ProjectID = projectID
+	g.Regions = []string{"global"}
+	g.APIS = nil
+	g.zones = make(map[string][]string)
+
+	c, err := compute.NewRegionsRESTClient(ctx, g.GetClientOptions()...)
+	if err != nil {
+		return err
 	}
 	defer func() { _ = c.Close() }()
 
@@ -213,24 +475,24 @@ func New(ctx context.Context, projectID, impersonateServiceAccount string) (*GCP
 			break
 		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		gcp.Regions = append(gcp.Regions, resp.GetName())
+		g.Regions = append(g.Regions, resp.GetName())
 
-		if gcp.zones[resp.GetName()] == nil {
-			gcp.zones[resp.GetName()] = make([]string, 0)
+		if g.zones[resp.GetName()] == nil {
+			g.zones[resp.GetName()] = make([]string, 0)
 		}
 
 		for _, z := range resp.GetZones() {
 			zoneShort := strings.Split(z, "/")[len(strings.Split(z, "/"))-1]
-			gcp.zones[resp.GetName()] = append(gcp.zones[resp.GetName()], zoneShort)
+			g.zones[resp.GetName()] = append(g.zones[resp.GetName()], zoneShort)
 		}
 	}
 
-	serviceUsage, err := serviceusage.NewService(ctx, gcp.GetClientOptions()...)
+	serviceUsage, err := serviceusage.NewService(ctx, g.GetClientOptions()...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	suReq := serviceUsage.Services.
@@ -239,12 +501,12 @@ func New(ctx context.Context, projectID, impersonateServiceAccount string) (*GCP
 
 	if suErr := suReq.Pages(ctx, func(page *serviceusage.ListServicesResponse) error {
 		for _, svc := range page.Services {
-			gcp.APIS = append(gcp.APIS, svc.Config.Name)
+			g.APIS = append(g.APIS, svc.Config.Name)
 		}
 		return nil
 	}); suErr != nil {
-		return nil, suErr
+		return suErr
 	}
 
-	return gcp, nil
+	return nil
 }
@@ -0,0 +1,58 @@
+package gcputil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+func TestWithProject(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddComputeRegions("project-a", map[string][]string{
+		"us-east1": {"us-east1-b", "us-east1-c"},
+	})
+	fake.EnableServices("project-a", "compute.googleapis.com", "storage.googleapis.com")
+
+	base := &GCP{clientOptions: fake.ClientOptions()}
+
+	gcp, err := base.WithProject(context.TODO(), "project-a")
+	require.NoError(t, err)
+
+	assert.Equal(t, "project-a", gcp.ID())
+	assert.Equal(t, []string{"global", "us-east1"}, gcp.Regions)
+	assert.Equal(t, []string{"us-east1-b", "us-east1-c"}, gcp.GetZones("us-east1"))
+	assert.Equal(t, []string{"compute.googleapis.com", "storage.googleapis.com"}, gcp.GetEnabledAPIs())
+	assert.Empty(t, base.ID(), "the original instance must not be modified")
+}
+
+func TestListFolderProjects(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddFolders(
+		testutil.Item{"name": "folders/2", "parent": "folders/1"},
+		testutil.Item{"name": "folders/3", "parent": "folders/2"},
+		testutil.Item{"name": "folders/4", "parent": "organizations/1"},
+	)
+	fake.AddProjects(
+		testutil.Item{"name": "projects/1", "projectId": "dev-a", "parent": "folders/1", "state": "ACTIVE"},
+		testutil.Item{"name": "projects/2", "projectId": "dev-b", "parent": "folders/2", "state": "ACTIVE"},
+		testutil.Item{"name": "projects/3", "projectId": "dev-c", "parent": "folders/3", "state": "DELETE_REQUESTED"},
+		testutil.Item{"name": "projects/4", "projectId": "prod", "parent": "folders/4", "state": "ACTIVE"},
+	)
+
+	gcp := &GCP{clientOptions: fake.ClientOptions()}
+
+	projects, err := gcp.ListFolderProjects(context.TODO(), "1")
+	require.NoError(t, err)
+
+	var projectIDs []string
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ProjectID)
+	}
+
+	assert.Equal(t, []string{"dev-a", "dev-b", "dev-c"}, projectIDs)
+	assert.False(t, projects[2].IsActive())
+}
@@ -9,10 +9,14 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/api/option"
+	"google.golang.org/api/option/internaloption"
 	htransport "google.golang.org/api/transport/http"
 )
 
-const identityPlatformBasePath = "https://identitytoolkit.googleapis.com/admin/v2/"
+const (
+	identityPlatformBasePath         = "https://identitytoolkit.googleapis.com/admin/v2/"
+	identityPlatformBasePathTemplate = "https://identitytoolkit.UNIVERSE_DOMAIN/admin/v2/"
+)
 
 // ProjectConfig represents the configuration of a project in Identity Platform
 type ProjectConfig struct {
@@ -45,6 +49,8 @@ type IdentityPlatformService struct {
 
 // NewIdentityPlatformService creates a new Identity Platform client
 func NewIdentityPlatformService(ctx context.Context, opts ...option.ClientOption) (*IdentityPlatformService, error) {
+	// NOTE: the template resolves the endpoint in the universe domain of the client options
+	opts = append(opts, internaloption.WithDefaultEndpointTemplate(identityPlatformBasePathTemplate))
 	client, endpoint, err := htransport.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, err
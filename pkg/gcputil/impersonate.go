@@ -0,0 +1,84 @@
+package gcputil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+const (
+	// DefaultImpersonateLifetime is the lifetime of an impersonated access token, it is the longest lifetime allowed
+	// without the iam.allowServiceAccountCredentialLifetimeExtension organization policy
+	DefaultImpersonateLifetime = time.Hour
+
+	// MaxImpersonateLifetime is the longest lifetime the IAM Credentials API allows for an access token
+	MaxImpersonateLifetime = 12 * time.Hour
+)
+
+// DefaultImpersonateScopes are the scopes requested for the impersonated access token when none are configured
+var DefaultImpersonateScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+}
+
+// Impersonation configures the service account that is impersonated for all API calls
+type Impersonation struct {
+	// ServiceAccount is the email of the service account to impersonate
+	ServiceAccount string
+
+	// Delegates is the chain of service accounts to go through to reach ServiceAccount. Each service account in the
+	// chain must have roles/iam.serviceAccountTokenCreator on the next one.
+	Delegates []string
+
+	// Lifetime is the lifetime of each access token, tokens are refreshed before they expire
+	Lifetime time.Duration
+
+	// Scopes are the OAuth scopes requested for the access token
+	Scopes []string
+}
+
+// Principal returns a description of the impersonated principal, including the delegate chain when there is one
+func (i *Impersonation) Principal() string {
+	if len(i.Delegates) == 0 {
+		return i.ServiceAccount
+	}
+
+	return fmt.Sprintf("%s (via %s)", i.ServiceAccount, strings.Join(i.Delegates, " -> "))
+}
+
+// tokenSource returns the token source of the access tokens of the impersonated service account, generated with the
+// base credentials of the client options. The impersonate package refreshes the tokens of the default lifetime only,
+// it returns a single token for any other lifetime, so a new one is generated through it when that token expires.
+func (i *Impersonation) tokenSource(ctx context.Context, opts ...option.ClientOption) (oauth2.TokenSource, error) {
+	config := impersonate.CredentialsConfig{
+		TargetPrincipal: i.ServiceAccount,
+		Delegates:       i.Delegates,
+		Scopes:          i.Scopes,
+	}
+
+	if i.Lifetime == DefaultImpersonateLifetime {
+		return impersonate.CredentialsTokenSource(ctx, config, opts...)
+	}
+
+	config.Lifetime = i.Lifetime
+
+	return oauth2.ReuseTokenSource(nil, tokenSourceFunc(func() (*oauth2.Token, error) {
+		ts, err := impersonate.CredentialsTokenSource(ctx, config, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return ts.Token()
+	})), nil
+}
+
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
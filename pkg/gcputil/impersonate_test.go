@@ -0,0 +1,108 @@
+package gcputil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/api/option"
+)
+
+// redirectTransport sends every request to the test server, the impersonate package always calls
+// iamcredentials.googleapis.com
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newIAMCredentialsServer returns a fake IAM Credentials API, it records the requests and returns tokens that expire
+// after expiresIn
+func newIAMCredentialsServer(
+	t *testing.T, expiresIn time.Duration,
+) (*[]map[string]any, []option.ClientOption) {
+	t.Helper()
+
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{"path": r.URL.Path}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"accessToken": fmt.Sprintf("token-%d", len(requests)),
+			"expireTime":  time.Now().Add(expiresIn).UTC().Format(time.RFC3339),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return &requests, []option.ClientOption{
+		option.WithHTTPClient(&http.Client{Transport: &redirectTransport{target: target}}),
+	}
+}
+
+func TestImpersonationTokenSource(t *testing.T) {
+	requests, opts := newIAMCredentialsServer(t, time.Hour)
+
+	impersonation := &Impersonation{
+		ServiceAccount: "nuke@project.iam.gserviceaccount.com",
+		Delegates:      []string{"ci@build.iam.gserviceaccount.com"},
+		Lifetime:       DefaultImpersonateLifetime,
+		Scopes:         DefaultImpersonateScopes,
+	}
+
+	ts, err := impersonation.tokenSource(context.TODO(), opts...)
+	require.NoError(t, err)
+
+	for range 2 {
+		token, err := ts.Token()
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", token.AccessToken)
+	}
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/v1/projects/-/serviceAccounts/nuke@project.iam.gserviceaccount.com:generateAccessToken",
+		(*requests)[0]["path"])
+	assert.Equal(t, []any{"projects/-/serviceAccounts/ci@build.iam.gserviceaccount.com"}, (*requests)[0]["delegates"])
+	assert.Equal(t, "3600s", (*requests)[0]["lifetime"])
+}
+
+func TestImpersonationTokenSourceLifetime(t *testing.T) {
+	// Note: the tokens are already expired, so every call must generate a new one
+	requests, opts := newIAMCredentialsServer(t, -time.Minute)
+
+	impersonation := &Impersonation{
+		ServiceAccount: "nuke@project.iam.gserviceaccount.com",
+		Lifetime:       2 * time.Hour,
+		Scopes:         DefaultImpersonateScopes,
+	}
+
+	ts, err := impersonation.tokenSource(context.TODO(), opts...)
+	require.NoError(t, err)
+
+	for i := 1; i <= 2; i++ {
+		token, err := ts.Token()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("token-%d", i), token.AccessToken)
+	}
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "7200s", (*requests)[0]["lifetime"])
+}
@@ -0,0 +1,114 @@
+package gcputil
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	// MultiRegionPrefix is the prefix of the pseudo-regions of the multi-regions, e.g. "multi-region:us"
+	MultiRegionPrefix = "multi-region:"
+
+	// DualRegionPrefix is the prefix of the pseudo-regions of the predefined dual-regions, e.g. "dual-region:nam4"
+	DualRegionPrefix = "dual-region:"
+)
+
+// multiRegions maps the location codes of the multi-regions, as used by the different APIs, to their pseudo-region.
+// Storage and BigQuery use "EU" where KMS and Artifact Registry use "europe".
+var multiRegions = map[string]string{
+	"us":     MultiRegionPrefix + "us",
+	"eu":     MultiRegionPrefix + "eu",
+	"europe": MultiRegionPrefix + "eu",
+	"asia":   MultiRegionPrefix + "asia",
+}
+
+// dualRegions are the location codes of the predefined dual-regions. A configurable dual-region has the location of
+// its multi-region (e.g. "US") and belongs to the pseudo-region of that multi-region.
+var dualRegions = []string{"asia1", "eur4", "eur5", "eur7", "eur8", "nam4"}
+
+// apiLocations are the location codes, by pseudo-region, of the APIs that support multi-regions or dual-regions. An API
+// that is not listed, or a pseudo-region an API does not support, has no resources in the pseudo-region.
+var apiLocations = map[string]map[string]string{
+	"storage.googleapis.com": {
+		MultiRegionPrefix + "us":   "US",
+		MultiRegionPrefix + "eu":   "EU",
+		MultiRegionPrefix + "asia": "ASIA",
+		DualRegionPrefix + "asia1": "ASIA1",
+		DualRegionPrefix + "eur4":  "EUR4",
+		DualRegionPrefix + "eur5":  "EUR5",
+		DualRegionPrefix + "eur7":  "EUR7",
+		DualRegionPrefix + "eur8":  "EUR8",
+		DualRegionPrefix + "nam4":  "NAM4",
+	},
+	"bigquery.googleapis.com": {
+		MultiRegionPrefix + "us": "US",
+		MultiRegionPrefix + "eu": "EU",
+	},
+	"cloudkms.googleapis.com": {
+		MultiRegionPrefix + "us":   "us",
+		MultiRegionPrefix + "eu":   "europe",
+		MultiRegionPrefix + "asia": "asia",
+		DualRegionPrefix + "eur4":  "eur4",
+		DualRegionPrefix + "nam4":  "nam4",
+	},
+	"artifactregistry.googleapis.com": {
+		MultiRegionPrefix + "us":   "us",
+		MultiRegionPrefix + "eu":   "europe",
+		MultiRegionPrefix + "asia": "asia",
+	},
+}
+
+// LocationRegion returns the region that owns a resource in the location, the location is a region, a multi-region or
+// a dual-region in any case (e.g. "US-EAST1", "EU" or "NAM4"). Multi-regions and dual-regions are owned by their
+// pseudo-region, e.g. "multi-region:eu" for both "EU" and "europe", so that a resource in any location is owned by
+// exactly one region.
+func LocationRegion(location string) string {
+	location = strings.ToLower(location)
+
+	if region, ok := multiRegions[location]; ok {
+		return region
+	}
+
+	if slices.Contains(dualRegions, location) {
+		return DualRegionPrefix + location
+	}
+
+	return location
+}
+
+// IsPseudoRegion returns true when the region is the pseudo-region of a multi-region or of a dual-region
+func IsPseudoRegion(region string) bool {
+	return strings.HasPrefix(region, MultiRegionPrefix) || strings.HasPrefix(region, DualRegionPrefix)
+}
+
+// PseudoRegions returns every pseudo-region, sorted
+func PseudoRegions() []string {
+	regions := make([]string, 0, len(dualRegions)+len(multiRegions))
+	for _, dualRegion := range dualRegions {
+		regions = append(regions, DualRegionPrefix+dualRegion)
+	}
+
+	for _, region := range multiRegions {
+		if !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+
+	sort.Strings(regions)
+
+	return regions
+}
+
+// APILocation returns the location code of the region for the API, e.g. "europe" for "multi-region:eu" with KMS. A
+// region that is not a pseudo-region is returned as is, false is returned when the API does not support the
+// pseudo-region.
+func APILocation(api, region string) (string, bool) {
+	if !IsPseudoRegion(region) {
+		return region, true
+	}
+
+	location, ok := apiLocations[api][region]
+
+	return location, ok
+}
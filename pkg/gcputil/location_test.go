@@ -0,0 +1,73 @@
+package gcputil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationRegion(t *testing.T) {
+	cases := map[string]string{
+		"us-east1": "us-east1",
+		"US-EAST1": "us-east1",
+		"US":       "multi-region:us",
+		"EU":       "multi-region:eu",
+		"europe":   "multi-region:eu",
+		"ASIA":     "multi-region:asia",
+		"NAM4":     "dual-region:nam4",
+		"eur4":     "dual-region:eur4",
+		"global":   "global",
+	}
+
+	for location, want := range cases {
+		t.Run(location, func(t *testing.T) {
+			region := LocationRegion(location)
+			assert.Equal(t, want, region)
+			assert.Equal(t, strings.Contains(want, ":"), IsPseudoRegion(region))
+		})
+	}
+}
+
+func TestPseudoRegions(t *testing.T) {
+	assert.Equal(t, []string{
+		"dual-region:asia1",
+		"dual-region:eur4",
+		"dual-region:eur5",
+		"dual-region:eur7",
+		"dual-region:eur8",
+		"dual-region:nam4",
+		"multi-region:asia",
+		"multi-region:eu",
+		"multi-region:us",
+	}, PseudoRegions())
+
+	// Note: every location code of every API must be owned by the pseudo-region it is the code of
+	for api, locations := range apiLocations {
+		for region, location := range locations {
+			assert.Equalf(t, region, LocationRegion(location), "location %s of %s", location, api)
+		}
+	}
+}
+
+func TestAPILocation(t *testing.T) {
+	cases := []struct {
+		api, region string
+		want        string
+		ok          bool
+	}{
+		{api: "cloudkms.googleapis.com", region: "us-east1", want: "us-east1", ok: true},
+		{api: "cloudkms.googleapis.com", region: "multi-region:eu", want: "europe", ok: true},
+		{api: "storage.googleapis.com", region: "multi-region:eu", want: "EU", ok: true},
+		{api: "bigquery.googleapis.com", region: "multi-region:asia", ok: false},
+		{api: "compute.googleapis.com", region: "multi-region:us", ok: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.api+"/"+tc.region, func(t *testing.T) {
+			location, ok := APILocation(tc.api, tc.region)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, location)
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package gcputil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ekristen/gcp-nuke/pkg/telemetry"
+)
+
+// AllAPIs is the key of the rate limit that applies to every API without a rate limit of its own
+const AllAPIs = "*"
+
+// DefaultRateLimits are the rate limits, in requests per second, applied when none is configured for the API. Compute
+// has a per project quota on the read requests that large projects exhaust quickly when every zone is listed.
+var DefaultRateLimits = RateLimits{
+	"compute.googleapis.com": 20,
+}
+
+// RateLimits are the maximum number of requests per second for each API, by the host name of the API (e.g.
+// "compute.googleapis.com"). A limit of 0 means no limit.
+type RateLimits map[string]float64
+
+// ParseRateLimits parses rate limits in the form "api=limit", or "limit" for every API. The API can be given by its
+// host name or by its short name, e.g. "compute.googleapis.com" or "compute".
+func ParseRateLimits(values []string) (RateLimits, error) {
+	limits := RateLimits{}
+
+	for _, value := range values {
+		api, limit, ok := strings.Cut(value, "=")
+		if !ok {
+			api, limit = AllAPIs, value
+		}
+
+		rps, err := strconv.ParseFloat(strings.TrimSpace(limit), 64)
+		if err != nil || rps < 0 {
+			return nil, fmt.Errorf("invalid rate limit %q, must be a positive number of requests per second", value)
+		}
+
+		api = strings.TrimSpace(api)
+		if api != AllAPIs && !strings.Contains(api, ".") {
+			api += ".googleapis.com"
+		}
+
+		limits[api] = rps
+	}
+
+	return limits, nil
+}
+
+// Throttle limits the rate of the requests to each API and retries, with an exponential backoff, the requests an API
+// rejected because of a rate limit or because it was unavailable. It is shared by all the clients of a run, for both
+// the REST and the gRPC clients, and counts the throttled requests of every API. Every attempt of a request is traced
+// and measured, see telemetry.StartAPIRequest.
+type Throttle struct {
+	limits RateLimits
+
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	events   map[string]int
+}
+
+// NewThrottle returns a throttle with the rate limits, the DefaultRateLimits apply to the APIs that have no limit
+func NewThrottle(limits RateLimits) *Throttle {
+	return &Throttle{
+		limits:     limits,
+		maxRetries: 5,
+		baseDelay:  time.Second,
+		maxDelay:   30 * time.Second,
+		limiters:   make(map[string]*rate.Limiter),
+		events:     make(map[string]int),
+	}
+}
+
+// Events returns the number of throttled requests for every API
+func (t *Throttle) Events() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	events := make(map[string]int, len(t.events))
+	for api, count := range t.events {
+		events[api] = count
+	}
+
+	return events
+}
+
+// Summary returns the number of throttled requests in total and for every API, in the form
+// "12 (compute.googleapis.com: 10, storage.googleapis.com: 2)"
+func (t *Throttle) Summary() string {
+	events := t.Events()
+
+	apis := make([]string, 0, len(events))
+	total := 0
+	for api, count := range events {
+		apis = append(apis, fmt.Sprintf("%s: %d", api, count))
+		total += count
+	}
+
+	if total == 0 {
+		return "0"
+	}
+
+	sort.Strings(apis)
+
+	return fmt.Sprintf("%d (%s)", total, strings.Join(apis, ", "))
+}
+
+// limit returns the rate limit of the API, an explicit limit for the API wins over the limit for all APIs, which wins
+// over the default limit
+func (t *Throttle) limit(api string) float64 {
+	if rps, ok := t.limits[api]; ok {
+		return rps
+	}
+
+	if rps, ok := t.limits[AllAPIs]; ok {
+		return rps
+	}
+
+	return DefaultRateLimits[api]
+}
+
+// wait blocks until the rate limit of the API allows another request
+func (t *Throttle) wait(ctx context.Context, api string) error {
+	t.mu.Lock()
+	limiter, ok := t.limiters[api]
+	if !ok {
+		if rps := t.limit(api); rps > 0 {
+			limiter = rate.NewLimiter(rate.Limit(rps), max(1, int(rps)))
+		}
+		t.limiters[api] = limiter
+	}
+	t.mu.Unlock()
+
+	if limiter == nil {
+		return nil
+	}
+
+	return limiter.Wait(ctx)
+}
+
+func (t *Throttle) record(api string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events[api]++
+}
+
+// call is a single attempt of a request, it returns whether the request was throttled and, when the API said so, how
+// long to wait before the next attempt
+type call func() (throttled bool, retryAfter time.Duration, err error)
+
+// do makes the request to the API until it is not throttled anymore, the retries are exhausted or the context is done
+func (t *Throttle) do(ctx context.Context, api string, retryable bool, fn call) error {
+	delay := t.baseDelay
+
+	for attempt := 0; ; attempt++ {
+		if err := t.wait(ctx, api); err != nil {
+			return err
+		}
+
+		throttled, retryAfter, err := fn()
+		if !throttled {
+			return err
+		}
+
+		t.record(api)
+
+		if !retryable || attempt >= t.maxRetries {
+			return err
+		}
+
+		wait := retryAfter
+		if wait == 0 {
+			// Note: the jitter spreads the retries of the requests that were throttled at the same time
+			wait = delay/2 + rand.N(delay/2+1)
+		}
+
+		logrus.WithField("api", api).
+			WithField("attempt", attempt+1).
+			Debugf("request throttled, retrying in %s", wait)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		delay = min(delay*2, t.maxDelay)
+	}
+}
+
+// Transport returns an http.RoundTripper that throttles the requests made with base
+func (t *Throttle) Transport(base http.RoundTripper) http.RoundTripper {
+	return &throttleTransport{throttle: t, base: base}
+}
+
+type throttleTransport struct {
+	throttle *Throttle
+	base     http.RoundTripper
+}
+
+func (tt *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Note: a request can only be sent again when its body can be read again
+	retryable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	var resp *http.Response
+	attempt := 0
+	api := apiName(req.URL.Host)
+
+	err := tt.throttle.do(req.Context(), api, retryable, func() (bool, time.Duration, error) {
+		r := req
+		if attempt > 0 {
+			_ = resp.Body.Close()
+
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return false, 0, err
+				}
+				r.Body = body
+			}
+		}
+		attempt++
+
+		end := telemetry.StartAPIRequest(r.Context(), api, r.Method)
+
+		var err error
+		resp, err = tt.base.RoundTrip(r)
+		if err != nil {
+			end(telemetry.ResultError, err)
+			return false, 0, err
+		}
+
+		end(strconv.Itoa(resp.StatusCode), nil)
+
+		throttled, err := throttledResponse(resp)
+		if err != nil {
+			return false, 0, err
+		}
+
+		return throttled, retryAfter(resp), nil
+	})
+	if err != nil {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// throttledResponse returns true when the API rejected the request because of a rate limit or because it was
+// unavailable. The body of a 403 response is read to find the reason, it is restored afterward.
+func throttledResponse(resp *http.Response) (bool, error) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true, nil
+	case http.StatusForbidden:
+	default:
+		return false, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var apiErr struct {
+		Error struct {
+			Status string `json:"status"`
+			Errors []struct {
+				Reason string `json:"reason"`
+			} `json:"errors"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return false, nil
+	}
+
+	if apiErr.Error.Status == "RESOURCE_EXHAUSTED" {
+		return true, nil
+	}
+
+	for _, item := range apiErr.Error.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded":
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// retryAfter returns the delay of the Retry-After header of the response, only the delay in seconds is supported
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that throttles the unary calls
+func (t *Throttle) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		api := apiName(cc.Target())
+
+		return t.do(ctx, api, true, func() (bool, time.Duration, error) {
+			end := telemetry.StartAPIRequest(ctx, api, strings.TrimPrefix(method, "/"))
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			end(status.Code(err).String(), err)
+
+			switch status.Code(err) {
+			case codes.ResourceExhausted, codes.Unavailable:
+				return true, 0, err
+			}
+
+			return false, 0, err
+		})
+	}
+}
+
+// apiName returns the host name of the API of the address of an endpoint, e.g. "compute.googleapis.com" for
+// "https://compute.googleapis.com" or "dns:///compute.googleapis.com:443". Regional endpoints, e.g.
+// "us-central1-aiplatform.googleapis.com", are named after the API.
+func apiName(address string) string {
+	if _, after, ok := strings.Cut(address, "://"); ok {
+		address = strings.TrimLeft(after, "/")
+	}
+
+	host := address
+	if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+
+	if !strings.HasSuffix(host, ".googleapis.com") {
+		return host
+	}
+
+	name, _, _ := strings.Cut(host, ".")
+	if idx := strings.LastIndex(name, "-"); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	return name + ".googleapis.com"
+}
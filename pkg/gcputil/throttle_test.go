@@ -0,0 +1,169 @@
+package gcputil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func newTestThrottle(limits RateLimits) *Throttle {
+	t := NewThrottle(limits)
+	t.maxRetries = 2
+	t.baseDelay = 0
+
+	return t
+}
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits([]string{"10", "compute=5", "storage.googleapis.com=0.5"})
+	require.NoError(t, err)
+	assert.Equal(t, RateLimits{
+		AllAPIs:                  10,
+		"compute.googleapis.com": 5,
+		"storage.googleapis.com": 0.5,
+	}, limits)
+
+	_, err = ParseRateLimits([]string{"compute=fast"})
+	assert.Error(t, err)
+
+	_, err = ParseRateLimits([]string{"-1"})
+	assert.Error(t, err)
+}
+
+func TestThrottleLimit(t *testing.T) {
+	assert.Equal(t, float64(20), NewThrottle(RateLimits{}).limit("compute.googleapis.com"))
+	assert.Equal(t, float64(0), NewThrottle(RateLimits{}).limit("storage.googleapis.com"))
+
+	throttle := NewThrottle(RateLimits{AllAPIs: 10, "storage.googleapis.com": 5})
+	assert.Equal(t, float64(10), throttle.limit("compute.googleapis.com"))
+	assert.Equal(t, float64(5), throttle.limit("storage.googleapis.com"))
+}
+
+func TestAPIName(t *testing.T) {
+	cases := map[string]string{
+		"compute.googleapis.com":                "compute.googleapis.com",
+		"compute.googleapis.com:443":            "compute.googleapis.com",
+		"dns:///pubsub.googleapis.com:443":      "pubsub.googleapis.com",
+		"us-central1-aiplatform.googleapis.com": "aiplatform.googleapis.com",
+		"storage.mtls.googleapis.com":           "storage.googleapis.com",
+		"127.0.0.1:8080":                        "127.0.0.1",
+	}
+
+	for address, want := range cases {
+		assert.Equal(t, want, apiName(address), address)
+	}
+}
+
+func TestThrottleTransport(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "body", string(body))
+
+		switch {
+		case r.URL.Path == "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"errors": [{"reason": "forbidden"}]}}`))
+		case r.URL.Path == "/always":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n == 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case n == 2:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": {"errors": [{"reason": "rateLimitExceeded"}]}}`))
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	throttle := newTestThrottle(RateLimits{})
+	client := &http.Client{Transport: throttle.Transport(http.DefaultTransport)}
+
+	post := func(path string) *http.Response {
+		req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, server.URL+path,
+			strings.NewReader("body"))
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+
+		return resp
+	}
+
+	t.Run("retried", func(t *testing.T) {
+		resp := post("/retried")
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "ok", string(body))
+		assert.Equal(t, int32(3), requests.Load())
+		assert.Equal(t, map[string]int{"127.0.0.1": 2}, throttle.Events())
+	})
+
+	t.Run("not-throttled", func(t *testing.T) {
+		requests.Store(10)
+
+		resp := post("/forbidden")
+		body, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(body), "forbidden", "the body must be restored")
+		assert.Equal(t, int32(11), requests.Load())
+	})
+
+	t.Run("retries-exhausted", func(t *testing.T) {
+		requests.Store(10)
+
+		resp := post("/always")
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, int32(13), requests.Load())
+		assert.Equal(t, "5 (127.0.0.1: 5)", throttle.Summary())
+	})
+}
+
+func TestThrottleUnaryClientInterceptor(t *testing.T) {
+	cc, err := grpc.NewClient("passthrough:///pubsub.googleapis.com:443",
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	throttle := newTestThrottle(RateLimits{})
+	interceptor := throttle.UnaryClientInterceptor()
+
+	calls := 0
+	err = interceptor(context.TODO(), "/method", nil, nil, cc,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			calls++
+			if calls == 1 {
+				return status.Error(codes.ResourceExhausted, "quota exceeded")
+			}
+			return nil
+		})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, map[string]int{"pubsub.googleapis.com": 1}, throttle.Events())
+
+	err = interceptor(context.TODO(), "/method", nil, nil, cc,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(codes.PermissionDenied, "denied")
+		})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
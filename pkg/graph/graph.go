@@ -0,0 +1,333 @@
+// Package graph builds the graph of the dependencies between the resource types, as declared by the DependsOn of their
+// registrations. A resource type depends on the resource types that must be removed before it, e.g. a VPC network
+// depends on its subnets. The graph can be rendered as DOT, Mermaid or JSON and is checked for cycles and for
+// dependencies on resource types that are not registered.
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+)
+
+// ErrCycle is returned when the dependencies have a cycle, no resource type of the cycle can be removed first
+var ErrCycle = errors.New("dependency cycle")
+
+// Format is the format the graph is rendered in
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatJSON    Format = "json"
+)
+
+// Formats are all the supported formats
+var Formats = []Format{FormatDOT, FormatMermaid, FormatJSON}
+
+// ParseFormat returns the Format for the name, or an error if the format is not supported
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported graph format %q, must be one of %v", name, Formats)
+}
+
+// Node is a resource type of the graph
+type Node struct {
+	Name      string   `json:"name"`
+	Scope     string   `json:"scope"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// Graph is the graph of the dependencies between the resource types
+type Graph struct {
+	nodes map[string]*Node
+	names []string
+}
+
+// New returns the graph of the registrations
+func New(registrations registry.Registrations) *Graph {
+	g := &Graph{nodes: make(map[string]*Node, len(registrations))}
+
+	for name, reg := range registrations {
+		dependsOn := append([]string(nil), reg.DependsOn...)
+		sort.Strings(dependsOn)
+
+		g.nodes[name] = &Node{Name: name, Scope: string(reg.Scope), DependsOn: dependsOn}
+		g.names = append(g.names, name)
+	}
+
+	sort.Strings(g.names)
+
+	return g
+}
+
+// FromRegistry returns the graph of every registered resource type
+func FromRegistry() *Graph {
+	return New(registry.GetRegistrations())
+}
+
+// Nodes returns the resource types, sorted by name
+func (g *Graph) Nodes() []*Node {
+	nodes := make([]*Node, 0, len(g.names))
+	for _, name := range g.names {
+		nodes = append(nodes, g.nodes[name])
+	}
+
+	return nodes
+}
+
+// Unregistered returns the dependencies on resource types that are not registered, in the form "A -> B"
+func (g *Graph) Unregistered() []string {
+	var missing []string
+	for _, node := range g.Nodes() {
+		for _, dependency := range node.DependsOn {
+			if _, ok := g.nodes[dependency]; !ok {
+				missing = append(missing, fmt.Sprintf("%s -> %s", node.Name, dependency))
+			}
+		}
+	}
+
+	return missing
+}
+
+// Cycles returns the cycles of the dependencies, each as the path of the resource types of the cycle that ends with
+// the resource type it starts with, e.g. [A B A]
+func (g *Graph) Cycles() [][]string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(g.names))
+	var stack []string
+	var cycles [][]string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, dependency := range g.nodes[name].DependsOn {
+			if _, ok := g.nodes[dependency]; !ok {
+				continue
+			}
+
+			switch state[dependency] {
+			case unvisited:
+				visit(dependency)
+			case visiting:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dependency {
+						cycle := append([]string(nil), stack[i:]...)
+						cycles = append(cycles, append(cycle, dependency))
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+
+	for _, name := range g.names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
+
+// Order returns the deletion order, in stages: the resource types of a stage only depend on the resource types of the
+// previous stages. The resource types of a stage are sorted by name. ErrCycle is returned when there is a cycle, the
+// dependencies on resource types that are not registered are ignored.
+func (g *Graph) Order() ([][]string, error) {
+	remaining := make(map[string]int, len(g.names))
+	dependents := make(map[string][]string, len(g.names))
+
+	for _, name := range g.names {
+		for _, dependency := range g.nodes[name].DependsOn {
+			if _, ok := g.nodes[dependency]; !ok {
+				continue
+			}
+
+			remaining[name]++
+			dependents[dependency] = append(dependents[dependency], name)
+		}
+	}
+
+	var stages [][]string
+	var stage []string
+	for _, name := range g.names {
+		if remaining[name] == 0 {
+			stage = append(stage, name)
+		}
+	}
+
+	done := 0
+	for len(stage) > 0 {
+		stages = append(stages, stage)
+		done += len(stage)
+
+		var next []string
+		for _, name := range stage {
+			for _, dependent := range dependents[name] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+
+		sort.Strings(next)
+		stage = next
+	}
+
+	if done != len(g.names) {
+		return stages, fmt.Errorf("%w: %d resource type(s) cannot be ordered", ErrCycle, len(g.names)-done)
+	}
+
+	return stages, nil
+}
+
+// Validate returns an error for every cycle and every dependency on a resource type that is not registered
+func (g *Graph) Validate() []error {
+	var errs []error
+
+	for _, cycle := range g.Cycles() {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrCycle, strings.Join(cycle, " -> ")))
+	}
+
+	for _, dependency := range g.Unregistered() {
+		errs = append(errs, fmt.Errorf("dependency on a resource type that is not registered: %s", dependency))
+	}
+
+	return errs
+}
+
+// Encode writes the graph to w in the format
+func (g *Graph) Encode(w io.Writer, format Format) error {
+	switch format {
+	case FormatMermaid:
+		return g.encodeMermaid(w)
+	case FormatJSON:
+		return g.encodeJSON(w)
+	default:
+		return g.encodeDOT(w)
+	}
+}
+
+// encodeDOT writes the graph in the DOT language of Graphviz, the resource types are clustered by scope and the
+// resource types that are not registered are drawn dashed
+func (g *Graph) encodeDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, scope := range g.scopes() {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%q;\n", scope)
+		for _, node := range g.Nodes() {
+			if node.Scope == scope {
+				fmt.Fprintf(&b, "    %q;\n", node.Name)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+
+	for _, node := range g.Nodes() {
+		for _, dependency := range node.DependsOn {
+			if _, ok := g.nodes[dependency]; !ok {
+				fmt.Fprintf(&b, "  %q [style=dashed];\n", dependency)
+			}
+			fmt.Fprintf(&b, "  %q -> %q;\n", node.Name, dependency)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// encodeMermaid writes the graph as a Mermaid flowchart, the resource types are grouped by scope
+func (g *Graph) encodeMermaid(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	for _, scope := range g.scopes() {
+		fmt.Fprintf(&b, "  subgraph %s\n", scope)
+		for _, node := range g.Nodes() {
+			if node.Scope == scope {
+				fmt.Fprintf(&b, "    %s\n", node.Name)
+			}
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, node := range g.Nodes() {
+		for _, dependency := range node.DependsOn {
+			if _, ok := g.nodes[dependency]; ok {
+				fmt.Fprintf(&b, "  %s --> %s\n", node.Name, dependency)
+			} else {
+				fmt.Fprintf(&b, "  %s -.-> %s\n", node.Name, dependency)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// encodeJSON writes the resource types, the deletion order and the problems of the graph as JSON
+func (g *Graph) encodeJSON(w io.Writer) error {
+	out := struct {
+		ResourceTypes []*Node    `json:"resourceTypes"`
+		Order         [][]string `json:"order"`
+		Cycles        [][]string `json:"cycles,omitempty"`
+		Unregistered  []string   `json:"unregistered,omitempty"`
+	}{
+		ResourceTypes: g.Nodes(),
+		Cycles:        g.Cycles(),
+		Unregistered:  g.Unregistered(),
+	}
+
+	// Note: with a cycle the order has the stages that could be ordered, the cycles are part of the output
+	out.Order, _ = g.Order()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(out)
+}
+
+// scopes returns the scopes of the resource types, sorted
+func (g *Graph) scopes() []string {
+	var scopes []string
+	seen := make(map[string]bool)
+	for _, node := range g.nodes {
+		if !seen[node.Scope] {
+			seen[node.Scope] = true
+			scopes = append(scopes, node.Scope)
+		}
+	}
+
+	sort.Strings(scopes)
+
+	return scopes
+}
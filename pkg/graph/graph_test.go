@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+)
+
+func newTestGraph(dependsOn map[string][]string) *Graph {
+	registrations := registry.Registrations{}
+	for name, deps := range dependsOn {
+		registrations[name] = &registry.Registration{Name: name, Scope: "project", DependsOn: deps}
+	}
+
+	return New(registrations)
+}
+
+func TestOrder(t *testing.T) {
+	g := newTestGraph(map[string][]string{
+		"VPCNetwork": {"VPCSubnet", "VPCRoute"},
+		"VPCSubnet":  {"ComputeInstance"},
+		"VPCRoute":   nil,
+		"KMSKey":     {"ComputeDisk", "Unregistered"},
+
+		"ComputeInstance": nil,
+		"ComputeDisk":     nil,
+	})
+
+	stages, err := g.Order()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"ComputeDisk", "ComputeInstance", "VPCRoute"},
+		{"KMSKey", "VPCSubnet"},
+		{"VPCNetwork"},
+	}, stages)
+
+	assert.Equal(t, []string{"KMSKey -> Unregistered"}, g.Unregistered())
+	assert.Empty(t, g.Cycles())
+}
+
+func TestCycles(t *testing.T) {
+	g := newTestGraph(map[string][]string{
+		"A": {"B"},
+		"B": {"C"},
+		"C": {"A"},
+		"D": {"D"},
+		"E": {"A"},
+	})
+
+	assert.Equal(t, [][]string{{"A", "B", "C", "A"}, {"D", "D"}}, g.Cycles())
+
+	stages, err := g.Order()
+	assert.True(t, errors.Is(err, ErrCycle))
+	assert.Empty(t, stages)
+
+	errs := g.Validate()
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "dependency cycle: A -> B -> C -> A")
+	assert.EqualError(t, errs[1], "dependency cycle: D -> D")
+}
+
+func TestValidate(t *testing.T) {
+	g := newTestGraph(map[string][]string{
+		"A": {"B", "Missing"},
+		"B": nil,
+	})
+
+	errs := g.Validate()
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "dependency on a resource type that is not registered: A -> Missing")
+}
+
+func TestEncode(t *testing.T) {
+	registrations := registry.Registrations{
+		"OrganizationIAMRole":          {Name: "OrganizationIAMRole", Scope: "organization"},
+		"OrganizationIAMPolicyBinding": {Name: "OrganizationIAMPolicyBinding", Scope: "organization"},
+		"PubSubTopic":                  {Name: "PubSubTopic", Scope: "project", DependsOn: []string{"PubSubSubscription"}},
+		"PubSubSubscription":           {Name: "PubSubSubscription", Scope: "project"},
+	}
+	registrations["OrganizationIAMRole"].DependsOn = []string{"OrganizationIAMPolicyBinding", "Missing"}
+	g := New(registrations)
+
+	t.Run("dot", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, g.Encode(&buf, FormatDOT))
+
+		assert.Equal(t, `digraph dependencies {
+  rankdir=LR;
+  node [shape=box];
+  subgraph cluster_0 {
+    label="organization";
+    "OrganizationIAMPolicyBinding";
+    "OrganizationIAMRole";
+  }
+  subgraph cluster_1 {
+    label="project";
+    "PubSubSubscription";
+    "PubSubTopic";
+  }
+  "Missing" [style=dashed];
+  "OrganizationIAMRole" -> "Missing";
+  "OrganizationIAMRole" -> "OrganizationIAMPolicyBinding";
+  "PubSubTopic" -> "PubSubSubscription";
+}
+`, buf.String())
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, g.Encode(&buf, FormatMermaid))
+
+		assert.Equal(t, `flowchart LR
+  subgraph organization
+    OrganizationIAMPolicyBinding
+    OrganizationIAMRole
+  end
+  subgraph project
+    PubSubSubscription
+    PubSubTopic
+  end
+  OrganizationIAMRole -.-> Missing
+  OrganizationIAMRole --> OrganizationIAMPolicyBinding
+  PubSubTopic --> PubSubSubscription
+`, buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, g.Encode(&buf, FormatJSON))
+
+		var out struct {
+			ResourceTypes []*Node    `json:"resourceTypes"`
+			Order         [][]string `json:"order"`
+			Unregistered  []string   `json:"unregistered"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+		assert.Len(t, out.ResourceTypes, 4)
+		assert.Equal(t, "organization", out.ResourceTypes[0].Scope)
+		assert.Equal(t, [][]string{
+			{"OrganizationIAMPolicyBinding", "PubSubSubscription"},
+			{"OrganizationIAMRole", "PubSubTopic"},
+		}, out.Order)
+		assert.Equal(t, []string{"OrganizationIAMRole -> Missing"}, out.Unregistered)
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("mermaid")
+	require.NoError(t, err)
+	assert.Equal(t, FormatMermaid, format)
+
+	_, err = ParseFormat("svg")
+	assert.Error(t, err)
+}
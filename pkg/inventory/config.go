@@ -0,0 +1,207 @@
+package inventory
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// blocklistPlaceholder is the entry of the blocklist of a generated config, the blocklist must not be empty
+const blocklistPlaceholder = "replace-with-a-project-that-must-never-be-nuked"
+
+// filterProperties are the properties used to protect a resource that has no name, in order of preference. Without any
+// of them the first property, sorted by name, is used.
+var filterProperties = []string{"Name", "ID", "SelfLink"}
+
+// filter is the filter that protects a resource, an exact match on the name of the resource or on a property
+type filter struct {
+	property string
+	value    string
+}
+
+// filterOf returns the filter that protects the resource, false when the resource has neither a name nor a property
+func filterOf(r *Resource) (filter, bool) {
+	if r.Name != "" {
+		return filter{value: r.Name}, true
+	}
+
+	for _, property := range filterProperties {
+		if value := r.Properties[property]; value != "" {
+			return filter{property: property, value: value}, true
+		}
+	}
+
+	keys := make([]string, 0, len(r.Properties))
+	for key, value := range r.Properties {
+		if value != "" && !strings.HasPrefix(key, "label:") {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return filter{}, false
+	}
+
+	sort.Strings(keys)
+
+	return filter{property: keys[0], value: r.Properties[keys[0]]}, true
+}
+
+// WriteConfig writes a starter config that protects every resource of the inventory: the blocklist has a placeholder,
+// the regions are the regions where resources were found and every resource has an exact filter in the account of the
+// project, or of the organization for the organization level resources. The resources to remove are removed from the
+// filters by hand. The number of resources that could not be protected, because they have neither a name nor a
+// property, is returned.
+func (i *Inventory) WriteConfig(w io.Writer, generatedAt time.Time) (int, error) {
+	i.sort()
+
+	doc := mapping()
+	header := fmt.Sprintf(
+		"Generated by gcp-nuke generate-config on %s.\n"+
+			"Every resource that exists now is protected by a filter, delete the filters of the resources to remove.",
+		generatedAt.UTC().Format(time.RFC3339))
+
+	blocklist := sequence(scalar(blocklistPlaceholder))
+	addKey(doc, "blocklist", blocklist, "the projects that must never be nuked, at least one is required")
+
+	if i.Project != "" {
+		var regions []string
+		for _, r := range i.Resources {
+			if !r.organization && !slices.Contains(regions, r.Region) {
+				regions = append(regions, r.Region)
+			}
+		}
+		sort.Strings(regions)
+
+		regionNodes := sequence()
+		for _, region := range regions {
+			regionNodes.Content = append(regionNodes.Content, scalar(region))
+		}
+		addKey(doc, "regions", regionNodes, "the regions where resources were found")
+	}
+
+	skipped := 0
+	accounts := mapping()
+
+	for _, account := range []struct {
+		id           string
+		organization bool
+	}{{i.Project, false}, {i.Organization, true}} {
+		if account.id == "" {
+			continue
+		}
+
+		filters, n := i.filters(account.organization)
+		skipped += n
+
+		accountNode := mapping()
+		addKey(accountNode, "filters", filters, "")
+		addKey(accounts, account.id, accountNode, "")
+	}
+
+	addKey(doc, "accounts", accounts, "")
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	root := &yaml.Node{Kind: yaml.DocumentNode, HeadComment: header, Content: []*yaml.Node{doc}}
+	if err := enc.Encode(root); err != nil {
+		return skipped, err
+	}
+
+	return skipped, enc.Close()
+}
+
+// filters returns the filters, by resource type, that protect the resources of the project or of the organization.
+// Every filter is commented with the regions of the resources it protects.
+func (i *Inventory) filters(organization bool) (*yaml.Node, int) {
+	type entry struct {
+		filter  filter
+		regions []string
+	}
+
+	var resourceTypes []string
+	entries := make(map[string][]*entry)
+	skipped := 0
+
+	for _, r := range i.Resources {
+		if r.organization != organization {
+			continue
+		}
+
+		f, ok := filterOf(r)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		if _, ok := entries[r.Type]; !ok {
+			resourceTypes = append(resourceTypes, r.Type)
+		}
+
+		var existing *entry
+		for _, e := range entries[r.Type] {
+			if e.filter == f {
+				existing = e
+				break
+			}
+		}
+
+		if existing == nil {
+			existing = &entry{filter: f}
+			entries[r.Type] = append(entries[r.Type], existing)
+		}
+
+		if !slices.Contains(existing.regions, r.Region) {
+			existing.regions = append(existing.regions, r.Region)
+		}
+	}
+
+	filters := mapping()
+	for _, resourceType := range resourceTypes {
+		list := sequence()
+		for _, e := range entries[resourceType] {
+			value := scalar(e.filter.value)
+			if !organization {
+				value.LineComment = strings.Join(e.regions, ", ")
+			}
+
+			if e.filter.property == "" {
+				list.Content = append(list.Content, value)
+				continue
+			}
+
+			node := mapping()
+			addKey(node, "property", scalar(e.filter.property), "")
+			addKey(node, "value", value, "")
+			list.Content = append(list.Content, node)
+		}
+
+		addKey(filters, resourceType, list, "")
+	}
+
+	return filters, skipped
+}
+
+func mapping() *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode}
+}
+
+func sequence(content ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Content: content}
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value, Style: yaml.DoubleQuotedStyle}
+}
+
+// addKey adds the key, with an optional comment above it, and its value to the mapping
+func addKey(node *yaml.Node, key string, value *yaml.Node, comment string) {
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key, HeadComment: comment}
+	node.Content = append(node.Content, keyNode, value)
+}
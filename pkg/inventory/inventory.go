@@ -0,0 +1,304 @@
+// Package inventory provides the inventory of the resources of a project or an organization, as listed by the
+// listers of the registry, without filtering or removing anything. The inventory can be written as a table, JSON or
+// CSV and grouped by resource type, region or the value of a label.
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ekristen/libnuke/pkg/resource"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// Format is the format the inventory is written in
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+)
+
+// Formats are all the supported formats
+var Formats = []Format{FormatTable, FormatJSON, FormatCSV}
+
+// ParseFormat returns the Format for the name, or an error if the format is not supported
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported inventory format %q, must be one of %v", name, Formats)
+}
+
+const (
+	GroupByType   = "type"
+	GroupByRegion = "region"
+
+	// GroupByLabelPrefix is the prefix of the group by the value of a label, e.g. "label:team"
+	GroupByLabelPrefix = "label:"
+)
+
+// ValidateGroupBy returns an error if the resources cannot be grouped by the key, an empty key means no grouping
+func ValidateGroupBy(key string) error {
+	switch {
+	case key == "", key == GroupByType, key == GroupByRegion:
+		return nil
+	case strings.HasPrefix(key, GroupByLabelPrefix) && len(key) > len(GroupByLabelPrefix):
+		return nil
+	}
+
+	return fmt.Errorf("unsupported group by %q, must be one of %s, %s or %s<key>",
+		key, GroupByType, GroupByRegion, GroupByLabelPrefix)
+}
+
+// createdAtProperties are the names of the property with the creation time of the resources, in order of preference
+var createdAtProperties = []string{"CreatedAt", "CreationTimestamp", "CreateTime", "CreationDate", "CreationTime"}
+
+// Resource is a resource of the inventory
+type Resource struct {
+	Type      string            `json:"type"`
+	Region    string            `json:"region"`
+	Zone      string            `json:"zone,omitempty"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt string            `json:"createdAt,omitempty"`
+
+	// Properties are the properties of the resource, they are used to protect the resources without a name
+	Properties map[string]string `json:"-"`
+
+	// organization is true for the organization level resources
+	organization bool
+}
+
+// NewResource returns the inventory entry of a resource listed for the region, the labels, the zone and the creation
+// time are taken from the properties of the resource
+func NewResource(resourceType, region string, r resource.Resource) *Resource {
+	res := &Resource{
+		Type:   resourceType,
+		Region: region,
+		Name:   nuke.ResourceName(r),
+	}
+
+	properties := nuke.ResourceProperties(r)
+	res.Properties = properties
+
+	for key, value := range properties {
+		if label, ok := strings.CutPrefix(key, "label:"); ok {
+			if res.Labels == nil {
+				res.Labels = make(map[string]string)
+			}
+			res.Labels[label] = value
+		}
+	}
+
+	res.Zone = properties["Zone"]
+
+	for _, key := range createdAtProperties {
+		if value := properties[key]; value != "" {
+			res.CreatedAt = value
+			break
+		}
+	}
+
+	return res
+}
+
+// groupKey returns the key of the group of the resource, resources without the label are grouped under "(none)"
+func (r *Resource) groupKey(groupBy string) string {
+	switch groupBy {
+	case GroupByType:
+		return r.Type
+	case GroupByRegion:
+		return r.Region
+	}
+
+	if value, ok := r.Labels[strings.TrimPrefix(groupBy, GroupByLabelPrefix)]; ok {
+		return value
+	}
+
+	return "(none)"
+}
+
+// labels returns the labels in the form "key=value", sorted by key
+func (r *Resource) labels() []string {
+	labels := make([]string, 0, len(r.Labels))
+	for key, value := range r.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	sort.Strings(labels)
+
+	return labels
+}
+
+// Group is a group of resources of the inventory
+type Group struct {
+	Key       string      `json:"key"`
+	Count     int         `json:"count"`
+	Resources []*Resource `json:"resources"`
+}
+
+// Inventory is the inventory of the resources of a project or an organization
+type Inventory struct {
+	Project      string      `json:"project,omitempty"`
+	Organization string      `json:"organization,omitempty"`
+	Regions      []string    `json:"regions,omitempty"`
+	Resources    []*Resource `json:"resources"`
+}
+
+// Add adds the resources listed for the resource type in the region
+func (i *Inventory) Add(resourceType, region string, resources []resource.Resource) {
+	for _, r := range resources {
+		i.Resources = append(i.Resources, NewResource(resourceType, region, r))
+	}
+}
+
+// AddOrganization adds the organization level resources listed for the resource type
+func (i *Inventory) AddOrganization(resourceType string, resources []resource.Resource) {
+	for _, r := range resources {
+		res := NewResource(resourceType, "global", r)
+		res.organization = true
+		i.Resources = append(i.Resources, res)
+	}
+}
+
+// sort sorts the resources by type, region and name
+func (i *Inventory) sort() {
+	sort.SliceStable(i.Resources, func(a, b int) bool {
+		x, y := i.Resources[a], i.Resources[b]
+		if x.Type != y.Type {
+			return x.Type < y.Type
+		}
+		if x.Region != y.Region {
+			return x.Region < y.Region
+		}
+		return x.Name < y.Name
+	})
+}
+
+// Groups returns the resources grouped by the key, sorted by the key of the group. Without a key all the resources
+// are in a single group with an empty key.
+func (i *Inventory) Groups(groupBy string) []*Group {
+	i.sort()
+
+	if groupBy == "" {
+		return []*Group{{Count: len(i.Resources), Resources: i.Resources}}
+	}
+
+	groups := make(map[string]*Group)
+	for _, r := range i.Resources {
+		key := r.groupKey(groupBy)
+		if _, ok := groups[key]; !ok {
+			groups[key] = &Group{Key: key}
+		}
+		groups[key].Count++
+		groups[key].Resources = append(groups[key].Resources, r)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*Group, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, groups[key])
+	}
+
+	return result
+}
+
+// Encode writes the inventory to w in the format, grouped by the key
+func (i *Inventory) Encode(w io.Writer, format Format, groupBy string) error {
+	switch format {
+	case FormatJSON:
+		return i.encodeJSON(w, groupBy)
+	case FormatCSV:
+		return i.encodeCSV(w, groupBy)
+	default:
+		return i.encodeTable(w, groupBy)
+	}
+}
+
+func (i *Inventory) encodeJSON(w io.Writer, groupBy string) error {
+	i.sort()
+
+	out := struct {
+		*Inventory
+		Total   int      `json:"total"`
+		GroupBy string   `json:"groupBy,omitempty"`
+		Groups  []*Group `json:"groups,omitempty"`
+	}{Inventory: i, Total: len(i.Resources), GroupBy: groupBy}
+
+	// Note: the resources are only listed once, either in their group or at the top level
+	if groupBy != "" {
+		out.Groups = i.Groups(groupBy)
+		out.Inventory = &Inventory{Project: i.Project, Organization: i.Organization, Regions: i.Regions}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(out)
+}
+
+func (i *Inventory) encodeCSV(w io.Writer, groupBy string) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"type", "region", "zone", "name", "labels", "created_at"}
+	if groupBy != "" {
+		header = append([]string{groupBy}, header...)
+	}
+
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, group := range i.Groups(groupBy) {
+		for _, r := range group.Resources {
+			record := []string{r.Type, r.Region, r.Zone, r.Name, strings.Join(r.labels(), ";"), r.CreatedAt}
+			if groupBy != "" {
+				record = append([]string{group.Key}, record...)
+			}
+
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func (i *Inventory) encodeTable(w io.Writer, groupBy string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, group := range i.Groups(groupBy) {
+		if groupBy != "" {
+			fmt.Fprintf(tw, "\n%s: %s (%d)\n", groupBy, group.Key, group.Count)
+		}
+
+		fmt.Fprintln(tw, "TYPE\tREGION\tZONE\tNAME\tLABELS\tCREATED")
+		for _, r := range group.Resources {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				r.Type, r.Region, r.Zone, r.Name, strings.Join(r.labels(), ","), r.CreatedAt)
+		}
+	}
+
+	fmt.Fprintf(tw, "\nTotal: %d resource(s)\n", len(i.Resources))
+
+	return tw.Flush()
+}
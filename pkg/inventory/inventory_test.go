@@ -0,0 +1,264 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testResource struct {
+	Name      *string
+	Zone      *string
+	CreatedAt *string
+	Labels    map[string]string `property:"tagPrefix=label"`
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return *r.Name
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func newTestInventory() *Inventory {
+	inv := &Inventory{Project: "test-project"}
+	inv.Add("StorageBucket", "multi-region:us", []resource.Resource{
+		&testResource{Name: ptr("bucket-b"), CreatedAt: ptr("2024-01-02T00:00:00Z"),
+			Labels: map[string]string{"team": "data"}},
+		&testResource{Name: ptr("bucket-a"), Labels: map[string]string{"team": "web", "env": "dev"}},
+	})
+	inv.Add("ComputeInstance", "us-east1", []resource.Resource{
+		&testResource{Name: ptr("vm-1"), Zone: ptr("us-east1-b"), Labels: map[string]string{"team": "web"}},
+	})
+	inv.Add("ComputeInstance", "global", nil)
+
+	return inv
+}
+
+func TestNewResource(t *testing.T) {
+	r := NewResource("StorageBucket", "us-east1", &testResource{
+		Name:      ptr("bucket"),
+		Zone:      ptr("us-east1-b"),
+		CreatedAt: ptr("2024-01-02T00:00:00Z"),
+		Labels:    map[string]string{"team": "data"},
+	})
+
+	assert.Equal(t, &Resource{
+		Type:      "StorageBucket",
+		Region:    "us-east1",
+		Zone:      "us-east1-b",
+		Name:      "bucket",
+		Labels:    map[string]string{"team": "data"},
+		CreatedAt: "2024-01-02T00:00:00Z",
+		Properties: map[string]string{
+			"Name":       "bucket",
+			"Zone":       "us-east1-b",
+			"CreatedAt":  "2024-01-02T00:00:00Z",
+			"label:team": "data",
+		},
+	}, r)
+}
+
+func TestGroups(t *testing.T) {
+	cases := []struct {
+		name    string
+		groupBy string
+		want    map[string][]string
+	}{
+		{
+			name: "none",
+			want: map[string][]string{"": {"vm-1", "bucket-a", "bucket-b"}},
+		},
+		{
+			name:    "type",
+			groupBy: GroupByType,
+			want: map[string][]string{
+				"ComputeInstance": {"vm-1"},
+				"StorageBucket":   {"bucket-a", "bucket-b"},
+			},
+		},
+		{
+			name:    "region",
+			groupBy: GroupByRegion,
+			want: map[string][]string{
+				"multi-region:us": {"bucket-a", "bucket-b"},
+				"us-east1":        {"vm-1"},
+			},
+		},
+		{
+			name:    "label",
+			groupBy: "label:env",
+			want: map[string][]string{
+				"(none)": {"vm-1", "bucket-b"},
+				"dev":    {"bucket-a"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			groups := newTestInventory().Groups(tc.groupBy)
+
+			got := make(map[string][]string)
+			for _, group := range groups {
+				assert.Equal(t, len(group.Resources), group.Count)
+				for _, r := range group.Resources {
+					got[group.Key] = append(got[group.Key], r.Name)
+				}
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidateGroupBy(t *testing.T) {
+	for _, key := range []string{"", "type", "region", "label:team"} {
+		assert.NoError(t, ValidateGroupBy(key), key)
+	}
+
+	for _, key := range []string{"zone", "label:", "Type"} {
+		assert.Error(t, ValidateGroupBy(key), key)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("csv")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	_, err = ParseFormat("yaml")
+	assert.Error(t, err)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestInventory().Encode(&buf, FormatJSON, ""))
+
+	var out struct {
+		Project   string      `json:"project"`
+		Total     int         `json:"total"`
+		Resources []*Resource `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+	assert.Equal(t, "test-project", out.Project)
+	assert.Equal(t, 3, out.Total)
+	require.Len(t, out.Resources, 3)
+	assert.Equal(t, "vm-1", out.Resources[0].Name)
+	assert.Equal(t, "us-east1-b", out.Resources[0].Zone)
+}
+
+func TestEncodeJSONGrouped(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestInventory().Encode(&buf, FormatJSON, GroupByType))
+
+	var out struct {
+		Total     int         `json:"total"`
+		GroupBy   string      `json:"groupBy"`
+		Resources []*Resource `json:"resources"`
+		Groups    []*Group    `json:"groups"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+	assert.Equal(t, 3, out.Total)
+	assert.Equal(t, GroupByType, out.GroupBy)
+	assert.Empty(t, out.Resources)
+	require.Len(t, out.Groups, 2)
+	assert.Equal(t, "StorageBucket", out.Groups[1].Key)
+	assert.Equal(t, 2, out.Groups[1].Count)
+}
+
+func TestEncodeCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestInventory().Encode(&buf, FormatCSV, "label:team"))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"label:team", "type", "region", "zone", "name", "labels", "created_at"},
+		{"data", "StorageBucket", "multi-region:us", "", "bucket-b", "team=data", "2024-01-02T00:00:00Z"},
+		{"web", "ComputeInstance", "us-east1", "us-east1-b", "vm-1", "team=web", ""},
+		{"web", "StorageBucket", "multi-region:us", "", "bucket-a", "env=dev;team=web", ""},
+	}, records)
+}
+
+func TestEncodeTable(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestInventory().Encode(&buf, FormatTable, GroupByRegion))
+
+	out := buf.String()
+	assert.Contains(t, out, "region: multi-region:us (2)")
+	assert.Contains(t, out, "region: us-east1 (1)")
+	assert.Contains(t, out, "env=dev,team=web")
+	assert.Contains(t, out, "Total: 3 resource(s)")
+}
+
+type unnamedResource struct {
+	ID *string
+}
+
+func (r *unnamedResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *unnamedResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func TestWriteConfig(t *testing.T) {
+	inv := newTestInventory()
+	inv.Organization = "1234567890"
+	inv.Add("StorageBucket", "us-east1", []resource.Resource{&testResource{Name: ptr("bucket-a")}})
+	inv.Add("DNSPolicy", "global", []resource.Resource{&unnamedResource{ID: ptr("42")}, &unnamedResource{}})
+	inv.AddOrganization("OrganizationIAMRole", []resource.Resource{&testResource{Name: ptr("roles/custom")}})
+
+	var buf bytes.Buffer
+	skipped, err := inv.WriteConfig(&buf, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+
+	var cfg struct {
+		Blocklist []string `yaml:"blocklist"`
+		Regions   []string `yaml:"regions"`
+		Accounts  map[string]struct {
+			Filters map[string][]any `yaml:"filters"`
+		} `yaml:"accounts"`
+	}
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &cfg))
+
+	assert.Equal(t, []string{blocklistPlaceholder}, cfg.Blocklist)
+	assert.Equal(t, []string{"global", "multi-region:us", "us-east1"}, cfg.Regions)
+	assert.Equal(t, map[string][]any{
+		"ComputeInstance": {"vm-1"},
+		"DNSPolicy":       {map[string]any{"property": "ID", "value": "42"}},
+		"StorageBucket":   {"bucket-a", "bucket-b"},
+	}, cfg.Accounts["test-project"].Filters)
+	assert.Equal(t, map[string][]any{
+		"OrganizationIAMRole": {"roles/custom"},
+	}, cfg.Accounts["1234567890"].Filters)
+
+	out := buf.String()
+	assert.Contains(t, out, "# Generated by gcp-nuke generate-config on 2024-01-02T03:04:05Z.")
+	assert.Contains(t, out, `- "bucket-a" # multi-region:us, us-east1`)
+}
@@ -0,0 +1,230 @@
+// Package notify sends the summary of a run to the sinks of the notifications of the config: a generic JSON webhook,
+// a Slack incoming webhook, a Google Chat webhook or an email through SMTP. Every sink has triggers that decide which
+// runs it is notified of, e.g. only the runs that failed.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+)
+
+// SinkType is the kind of destination of the notifications
+type SinkType string
+
+const (
+	SinkWebhook    SinkType = "webhook"
+	SinkSlack      SinkType = "slack"
+	SinkGoogleChat SinkType = "google-chat"
+	SinkSMTP       SinkType = "smtp"
+)
+
+// SinkTypes are all the supported kinds of sinks
+var SinkTypes = []SinkType{SinkWebhook, SinkSlack, SinkGoogleChat, SinkSMTP}
+
+// Trigger is a condition on the run for a sink to be notified of it
+type Trigger string
+
+const (
+	// TriggerAlways notifies of every run
+	TriggerAlways Trigger = "always"
+
+	// TriggerOnFailure notifies of the runs that failed, that failed to remove a resource or that could not list every
+	// resource type completely
+	TriggerOnFailure Trigger = "on_failure"
+
+	// TriggerOnRemoval notifies of the runs that removed at least one resource
+	TriggerOnRemoval Trigger = "on_removal"
+)
+
+// Triggers are all the supported triggers
+var Triggers = []Trigger{TriggerAlways, TriggerOnFailure, TriggerOnRemoval}
+
+// DefaultSMTPPort is the port of the SMTP server when none is given, the submission port
+const DefaultSMTPPort = 587
+
+// Config is a sink of the notifications of the config. The URL, the values of the headers, the username and the
+// password can reference environment variables as ${NAME}, so that the secrets stay out of the config.
+type Config struct {
+	// Name identifies the sink in the logs, the type is used when it is empty
+	Name string   `yaml:"name"`
+	Type SinkType `yaml:"type"`
+
+	// Triggers are the runs the sink is notified of, any of them is enough. Every run is notified when it is empty.
+	Triggers []Trigger `yaml:"triggers"`
+
+	// URL is the URL of the webhook, for the webhook, slack and google-chat sinks
+	URL string `yaml:"url"`
+
+	// Headers are added to the requests of the webhook sink, e.g. an Authorization header
+	Headers map[string]string `yaml:"headers"`
+
+	// Host, Port, Username, Password, From and To are the settings of the smtp sink. The username and the password are
+	// only needed when the server requires authentication, STARTTLS is used when the server supports it.
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// String returns the name of the sink, or its type when it has no name
+func (c *Config) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	return string(c.Type)
+}
+
+// Validate returns an error when the sink is missing a setting its type requires, or has an unknown type or trigger
+func (c *Config) Validate() error {
+	for _, trigger := range c.Triggers {
+		if !slices.Contains(Triggers, trigger) {
+			return fmt.Errorf("unknown trigger %q, must be one of %v", trigger, Triggers)
+		}
+	}
+
+	switch c.Type {
+	case SinkWebhook, SinkSlack, SinkGoogleChat:
+		if c.URL == "" {
+			return fmt.Errorf("a %s sink requires a url", c.Type)
+		}
+
+		// Note: the error of the parsing is left out, it would have the secrets of the URL
+		u, err := url.Parse(expandEnv(c.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid url %q, must be an http or https URL", c.URL)
+		}
+	case SinkSMTP:
+		if c.Host == "" || c.From == "" || len(c.To) == 0 {
+			return fmt.Errorf("a %s sink requires a host, a from and at least one to", c.Type)
+		}
+	default:
+		return fmt.Errorf("unknown type %q, must be one of %v", c.Type, SinkTypes)
+	}
+
+	return nil
+}
+
+// Matches returns true when the sink is notified of the run of the summary
+func (c *Config) Matches(s *Summary) bool {
+	if len(c.Triggers) == 0 {
+		return true
+	}
+
+	for _, trigger := range c.Triggers {
+		switch trigger {
+		case TriggerAlways:
+			return true
+		case TriggerOnFailure:
+			if s.Failed() {
+				return true
+			}
+		case TriggerOnRemoval:
+			if s.Removed() > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Sink sends the summary of a run to a destination
+type Sink interface {
+	Send(ctx context.Context, s *Summary) error
+}
+
+// newSink returns the sink of the config, with the environment variables it references expanded
+func newSink(c *Config) Sink {
+	switch c.Type {
+	case SinkSlack:
+		return &slackSink{url: expandEnv(c.URL)}
+	case SinkGoogleChat:
+		return &googleChatSink{url: expandEnv(c.URL)}
+	case SinkSMTP:
+		port := c.Port
+		if port == 0 {
+			port = DefaultSMTPPort
+		}
+
+		return &smtpSink{
+			host:     c.Host,
+			port:     port,
+			username: expandEnv(c.Username),
+			password: expandEnv(c.Password),
+			from:     c.From,
+			to:       c.To,
+		}
+	default:
+		headers := make(map[string]string, len(c.Headers))
+		for name, value := range c.Headers {
+			headers[name] = expandEnv(value)
+		}
+
+		return &webhookSink{url: expandEnv(c.URL), headers: headers}
+	}
+}
+
+// Notifier sends the summary of the runs to the sinks whose triggers match
+type Notifier struct {
+	configs []*Config
+	sinks   []Sink
+}
+
+// New returns a notifier for the sinks of the configs, or an error when one of them is invalid
+func New(configs []*Config) (*Notifier, error) {
+	n := &Notifier{}
+	for i, c := range configs {
+		if c == nil {
+			continue
+		}
+
+		if err := c.Validate(); err != nil {
+			return nil, fmt.Errorf("notifications[%d]: %w", i, err)
+		}
+
+		n.configs = append(n.configs, c)
+		n.sinks = append(n.sinks, newSink(c))
+	}
+
+	return n, nil
+}
+
+// Notify sends the summary to every sink whose triggers match, a sink that fails does not keep the others from being
+// notified. The names of the sinks that were notified are returned along with the errors of the others.
+func (n *Notifier) Notify(ctx context.Context, s *Summary) ([]string, error) {
+	var notified []string
+	var errs []error
+
+	for i, c := range n.configs {
+		if !c.Matches(s) {
+			continue
+		}
+
+		if err := n.sinks[i].Send(ctx, s); err != nil {
+			errs = append(errs, fmt.Errorf("unable to notify %s: %w", c, err))
+			continue
+		}
+
+		notified = append(notified, c.String())
+	}
+
+	return notified, errors.Join(errs...)
+}
+
+// envReference is a reference to an environment variable, only the ${NAME} form is expanded so that a $ in a password
+// is kept as is
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+
+func expandEnv(value string) string {
+	return envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		return os.Getenv(envReference.FindStringSubmatch(ref)[1])
+	})
+}
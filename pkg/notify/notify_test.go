@@ -0,0 +1,282 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+func newTestReport(removed, failed int) *report.Report {
+	rep := &report.Report{
+		Version:    "v1.0.0",
+		RunID:      "20240102-030405-abcd",
+		StartedAt:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		FinishedAt: time.Date(2024, 1, 2, 3, 6, 5, 0, time.UTC),
+		Duration:   120,
+	}
+
+	run := &report.Run{Project: "my-project"}
+	for i := 0; i < removed; i++ {
+		run.Resources = append(run.Resources, &report.Resource{
+			Type: "StorageBucket", Owner: "us", Name: fmt.Sprintf("removed-%d", i), State: report.StateRemoved,
+		})
+	}
+	for i := 0; i < failed; i++ {
+		run.Resources = append(run.Resources, &report.Resource{
+			Type: "ComputeInstance", Owner: "us-east1-b", Name: fmt.Sprintf("failed-%d", i), State: report.StateFailed,
+			Reason: "permission denied",
+		})
+	}
+	run.Resources = append(run.Resources, &report.Resource{
+		Type: "StorageBucket", Owner: "us", Name: "kept", State: report.StateFiltered,
+	})
+
+	rep.AddRun(run)
+	rep.AddRun(&report.Run{Project: "other-project", Skipped: "blocklisted"})
+
+	return rep
+}
+
+func TestSummary(t *testing.T) {
+	s := NewSummary(newTestReport(2, 1), nil)
+
+	assert.Equal(t, map[report.State]int{report.StateRemoved: 2, report.StateFailed: 1, report.StateFiltered: 1},
+		s.Counts)
+	assert.True(t, s.Failed())
+	assert.Equal(t, 2, s.Removed())
+	require.Len(t, s.Projects, 2)
+	assert.Equal(t, []*FailedResource{{
+		Type: "ComputeInstance", Owner: "us-east1-b", Name: "failed-0", Error: "permission denied",
+	}}, s.Projects[0].Failed)
+
+	assert.Equal(t, "gcp-nuke run of my-project, other-project failed", s.Title())
+	assert.Equal(t, "Run 20240102-030405-abcd, 2024-01-02T03:04:05Z, took 2m0s\n"+
+		"\nmy-project: 2 removed, 1 filtered, 1 failed\n"+
+		"- ComputeInstance us-east1-b failed-0: permission denied\n"+
+		"\nother-project: skipped, blocklisted\n", s.Text())
+
+	clean := NewSummary(newTestReport(0, 0), nil)
+	assert.False(t, clean.Failed())
+	assert.Equal(t, "gcp-nuke run of my-project, other-project succeeded", clean.Title())
+
+	assert.True(t, NewSummary(newTestReport(0, 0), errors.New("canceled")).Failed())
+}
+
+func TestSummaryTextLimit(t *testing.T) {
+	text := NewSummary(newTestReport(0, maxFailedInText+5), nil).Text()
+	assert.Contains(t, text, "- and 5 more\n")
+	assert.Equal(t, maxFailedInText, strings.Count(text, "permission denied"))
+}
+
+func TestMatches(t *testing.T) {
+	clean := NewSummary(newTestReport(0, 0), nil)
+	removed := NewSummary(newTestReport(1, 0), nil)
+	failed := NewSummary(newTestReport(0, 1), nil)
+
+	cases := []struct {
+		triggers []Trigger
+		want     []bool
+	}{
+		{nil, []bool{true, true, true}},
+		{[]Trigger{TriggerAlways}, []bool{true, true, true}},
+		{[]Trigger{TriggerOnFailure}, []bool{false, false, true}},
+		{[]Trigger{TriggerOnRemoval}, []bool{false, true, false}},
+		{[]Trigger{TriggerOnFailure, TriggerOnRemoval}, []bool{false, true, true}},
+	}
+
+	for _, tc := range cases {
+		c := &Config{Type: SinkWebhook, Triggers: tc.triggers}
+		assert.Equal(t, tc.want, []bool{c.Matches(clean), c.Matches(removed), c.Matches(failed)}, "%v", tc.triggers)
+	}
+}
+
+type request struct {
+	Path    string
+	Auth    string
+	Payload map[string]any
+}
+
+func newTestServer(t *testing.T, status int) (*httptest.Server, func() []request) {
+	var mu sync.Mutex
+	var requests []request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		payload := map[string]any{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		mu.Lock()
+		requests = append(requests, request{Path: r.URL.Path, Auth: r.Header.Get("Authorization"), Payload: payload})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, "invalid_token")
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestNotify(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusOK)
+	t.Setenv("TEST_WEBHOOK_TOKEN", "secret")
+
+	n, err := New([]*Config{
+		{Name: "audit", Type: SinkWebhook, URL: server.URL + "/webhook",
+			Headers: map[string]string{"Authorization": "Bearer ${TEST_WEBHOOK_TOKEN}"}},
+		{Type: SinkSlack, URL: server.URL + "/slack", Triggers: []Trigger{TriggerOnFailure}},
+		{Type: SinkGoogleChat, URL: server.URL + "/chat", Triggers: []Trigger{TriggerOnRemoval}},
+	})
+	require.NoError(t, err)
+
+	notified, err := n.Notify(context.TODO(), NewSummary(newTestReport(0, 1), nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audit", "slack"}, notified)
+
+	got := requests()
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "/webhook", got[0].Path)
+	assert.Equal(t, "Bearer secret", got[0].Auth)
+	assert.Equal(t, "20240102-030405-abcd", got[0].Payload["runId"])
+	assert.Equal(t, map[string]any{"failed": 1.0, "filtered": 1.0}, got[0].Payload["counts"])
+
+	assert.Equal(t, "/slack", got[1].Path)
+	text, ok := got[1].Payload["text"].(string)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(text, "*gcp-nuke run of my-project, other-project failed*\n```\n"))
+	assert.Contains(t, text, "- ComputeInstance us-east1-b failed-0: permission denied")
+}
+
+func TestNotifyError(t *testing.T) {
+	failing, _ := newTestServer(t, http.StatusForbidden)
+	working, requests := newTestServer(t, http.StatusOK)
+
+	n, err := New([]*Config{
+		{Name: "broken", Type: SinkSlack, URL: failing.URL},
+		{Type: SinkGoogleChat, URL: working.URL},
+	})
+	require.NoError(t, err)
+
+	notified, err := n.Notify(context.TODO(), NewSummary(newTestReport(0, 0), nil))
+	require.Error(t, err)
+	assert.Equal(t, "unable to notify broken: unexpected status 403 Forbidden: invalid_token", err.Error())
+	assert.Equal(t, []string{"google-chat"}, notified)
+	assert.Len(t, requests(), 1)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New([]*Config{{Type: SinkWebhook, URL: "https://example.com"}, {Type: SinkSlack}})
+	require.Error(t, err)
+	assert.Equal(t, "notifications[1]: a slack sink requires a url", err.Error())
+
+	_, err = New([]*Config{{Type: SinkWebhook, URL: "ftp://example.com"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be an http or https URL")
+}
+
+// serveSMTP accepts a single email on the listener, the same as an SMTP server without TLS and authentication would,
+// and returns the commands it received and the email
+func serveSMTP(listener net.Listener) <-chan []string {
+	received := make(chan []string, 1)
+
+	go func() {
+		defer close(received)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = fmt.Fprintf(conn, "%s\r\n", line) }
+
+		var lines []string
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250-localhost")
+				reply("250 8BITMIME")
+			case line == "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					data = strings.TrimRight(data, "\r\n")
+					if data == "." {
+						break
+					}
+					lines = append(lines, data)
+				}
+				reply("250 queued")
+			case line == "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	return received
+}
+
+func TestSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := serveSMTP(listener)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	n, err := New([]*Config{{
+		Type: SinkSMTP, Host: "127.0.0.1", Port: port,
+		From: "gcp-nuke@example.com", To: []string{"ops@example.com", "dev@example.com"},
+	}})
+	require.NoError(t, err)
+
+	notified, err := n.Notify(context.TODO(), NewSummary(newTestReport(0, 1), nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"smtp"}, notified)
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<gcp-nuke@example.com> BODY=8BITMIME")
+	assert.Contains(t, lines, "RCPT TO:<ops@example.com>")
+	assert.Contains(t, lines, "RCPT TO:<dev@example.com>")
+	assert.Contains(t, lines, "To: ops@example.com, dev@example.com")
+	assert.Contains(t, lines, "Subject: gcp-nuke run of my-project, other-project failed")
+	assert.Contains(t, lines, "- ComputeInstance us-east1-b failed-0: permission denied")
+}
@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// maxFailedInText is the number of failed resources of a project listed in the text of a notification, the JSON
+// webhook gets all of them
+const maxFailedInText = 20
+
+// Summary is the summary of a run that is sent to the sinks, it is the payload of the webhook sink
+type Summary struct {
+	RunID      string    `json:"runId"`
+	Version    string    `json:"version"`
+	DryRun     bool      `json:"dryRun"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Duration   float64   `json:"durationSeconds"`
+
+	// Error is the error the run ended with, if any
+	Error string `json:"error,omitempty"`
+
+	// Counts is the number of resources of every project by state
+	Counts   map[report.State]int `json:"counts"`
+	Projects []*Project           `json:"projects"`
+}
+
+// Project is the summary of a project or an organization of the run
+type Project struct {
+	Name         string               `json:"name"`
+	Project      string               `json:"project,omitempty"`
+	Organization string               `json:"organization,omitempty"`
+	Skipped      string               `json:"skipped,omitempty"`
+	Error        string               `json:"error,omitempty"`
+	Counts       map[report.State]int `json:"counts"`
+	Failed       []*FailedResource    `json:"failed,omitempty"`
+
+	// IncompleteListings is the number of resource types that could not be listed completely
+	IncompleteListings int `json:"incompleteListings,omitempty"`
+}
+
+// FailedResource is a resource that could not be removed
+type FailedResource struct {
+	Type  string `json:"type"`
+	Owner string `json:"owner"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewSummary returns the summary of the report of a run that ended with runErr, the report must be finished
+func NewSummary(rep *report.Report, runErr error) *Summary {
+	s := &Summary{
+		RunID:      rep.RunID,
+		Version:    rep.Version,
+		DryRun:     rep.DryRun,
+		StartedAt:  rep.StartedAt,
+		FinishedAt: rep.FinishedAt,
+		Duration:   rep.Duration,
+		Counts:     make(map[report.State]int),
+		Projects:   make([]*Project, 0, len(rep.Runs)),
+	}
+
+	if runErr != nil {
+		s.Error = runErr.Error()
+	}
+
+	for _, run := range rep.Runs {
+		p := &Project{
+			Name:               run.Name(),
+			Project:            run.Project,
+			Organization:       run.Organization,
+			Skipped:            run.Skipped,
+			Error:              run.Error,
+			Counts:             make(map[report.State]int),
+			IncompleteListings: len(run.IncompleteListings),
+		}
+
+		for _, res := range run.Resources {
+			p.Counts[res.State]++
+			s.Counts[res.State]++
+
+			if res.State == report.StateFailed {
+				p.Failed = append(p.Failed, &FailedResource{
+					Type:  res.Type,
+					Owner: res.Owner,
+					Name:  res.Name,
+					Error: res.Reason,
+				})
+			}
+		}
+
+		s.Projects = append(s.Projects, p)
+	}
+
+	return s
+}
+
+// Failed returns true when the run ended with an error, failed to remove a resource or could not list every resource
+// type completely
+func (s *Summary) Failed() bool {
+	if s.Error != "" || s.Counts[report.StateFailed] > 0 {
+		return true
+	}
+
+	for _, p := range s.Projects {
+		if p.Error != "" || p.IncompleteListings > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Removed returns the number of resources the run removed
+func (s *Summary) Removed() int {
+	return s.Counts[report.StateRemoved]
+}
+
+// Title returns a single line about the outcome of the run, e.g. the subject of the email
+func (s *Summary) Title() string {
+	names := make([]string, 0, len(s.Projects))
+	for _, p := range s.Projects {
+		names = append(names, p.Name)
+	}
+
+	target := strings.Join(names, ", ")
+	if len(names) > 3 {
+		target = fmt.Sprintf("%d projects", len(names))
+	}
+	if target == "" {
+		target = "no project"
+	}
+
+	mode := "run"
+	if s.DryRun {
+		mode = "dry run"
+	}
+
+	outcome := "succeeded"
+	if s.Failed() {
+		outcome = "failed"
+	}
+
+	return fmt.Sprintf("gcp-nuke %s of %s %s", mode, target, outcome)
+}
+
+// Text returns the summary as plain text, with the failed resources of every project, it is the body of the chat
+// messages and of the email
+func (s *Summary) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Run %s, %s, took %s\n", s.RunID, s.StartedAt.Format(time.RFC3339),
+		time.Duration(s.Duration*float64(time.Second)).Round(time.Second))
+
+	if s.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", s.Error)
+	}
+
+	for _, p := range s.Projects {
+		fmt.Fprintf(&b, "\n%s: ", p.Name)
+
+		if p.Skipped != "" {
+			fmt.Fprintf(&b, "skipped, %s\n", p.Skipped)
+			continue
+		}
+
+		counts := make([]string, 0, len(report.States))
+		for _, state := range report.States {
+			if count := p.Counts[state]; count > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", count, state))
+			}
+		}
+
+		if len(counts) == 0 {
+			b.WriteString("no resources found\n")
+		} else {
+			fmt.Fprintf(&b, "%s\n", strings.Join(counts, ", "))
+		}
+
+		if p.Error != "" {
+			fmt.Fprintf(&b, "Error: %s\n", p.Error)
+		}
+
+		if p.IncompleteListings > 0 {
+			fmt.Fprintf(&b, "%d resource type(s) could not be listed completely\n", p.IncompleteListings)
+		}
+
+		for i, res := range p.Failed {
+			if i == maxFailedInText {
+				fmt.Fprintf(&b, "- and %d more\n", len(p.Failed)-maxFailedInText)
+				break
+			}
+
+			fmt.Fprintf(&b, "- %s %s %s: %s\n", res.Type, res.Owner, res.Name, res.Error)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// webhookTimeout is how long a webhook has to answer
+const webhookTimeout = 30 * time.Second
+
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// webhookSink posts the summary as JSON to a URL
+type webhookSink struct {
+	url     string
+	headers map[string]string
+}
+
+func (w *webhookSink) Send(ctx context.Context, s *Summary) error {
+	return postJSON(ctx, w.url, w.headers, s)
+}
+
+// slackSink posts the summary as a message to a Slack incoming webhook
+type slackSink struct {
+	url string
+}
+
+func (w *slackSink) Send(ctx context.Context, s *Summary) error {
+	return postJSON(ctx, w.url, nil, map[string]string{"text": chatMessage(s)})
+}
+
+// googleChatSink posts the summary as a message to a Google Chat webhook
+type googleChatSink struct {
+	url string
+}
+
+func (w *googleChatSink) Send(ctx context.Context, s *Summary) error {
+	return postJSON(ctx, w.url, nil, map[string]string{"text": chatMessage(s)})
+}
+
+// chatMessage is the text of the summary with its title in bold, Slack and Google Chat share the same syntax
+func chatMessage(s *Summary) string {
+	return fmt.Sprintf("*%s*\n```\n%s```", s.Title(), s.Text())
+}
+
+func postJSON(ctx context.Context, target string, headers map[string]string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		// Note: the URL of a webhook is a secret, it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Note: the body of the answer usually explains the error, e.g. an invalid token
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(message))
+	}
+
+	return nil
+}
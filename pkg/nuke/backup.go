@@ -0,0 +1,177 @@
+package nuke
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/settings"
+)
+
+const (
+	// BackupBeforeDeleteSetting is the setting of the stateful resource types to create a backup, or a snapshot, of a
+	// resource and to wait for it before the resource is removed
+	BackupBeforeDeleteSetting = "BackupBeforeDelete"
+
+	// BackupRetentionSetting is the setting of how long the backups created before the removal are kept, e.g. "7d" or
+	// "36h". A later run removes the backups once they expired, or GCP expires them for the APIs that support it.
+	BackupRetentionSetting = "BackupRetention"
+
+	// BackupRunLabel is the label with the ID of the run that created the backup
+	BackupRunLabel = "gcp-nuke-run"
+
+	// BackupExpiresLabel is the label with the time, in seconds since the epoch, the backup expires at
+	BackupExpiresLabel = "gcp-nuke-expires"
+)
+
+// DefaultBackupRetention is the retention of the backups when the BackupRetention setting is not set
+const DefaultBackupRetention = 7 * 24 * time.Hour
+
+// runID is the ID of the run, the backups created before the removal of the resources are labelled with it
+var runID = NewRunID(time.Now())
+
+// SetRunID sets the ID of the run, the backups created before the removal of the resources are labelled with it
+func SetRunID(id string) {
+	runID = id
+}
+
+// RunID returns the ID of the run, it defaults to the time the process started at
+func RunID() string {
+	return runID
+}
+
+// NewRunID returns a run ID for a run started at the time, e.g. "20240601-120000", it is a valid label value
+func NewRunID(startedAt time.Time) string {
+	return startedAt.UTC().Format("20060102-150405")
+}
+
+// BackupPolicy is the policy of the backups created before the removal of the resources of a type
+type BackupPolicy struct {
+	Enabled   bool
+	Retention time.Duration
+}
+
+// NewBackupPolicy returns the backup policy of the settings of a resource type, see BackupBeforeDeleteSetting and
+// BackupRetentionSetting
+func NewBackupPolicy(setting *settings.Setting) (*BackupPolicy, error) {
+	policy := &BackupPolicy{Retention: DefaultBackupRetention}
+	if setting == nil {
+		return policy, nil
+	}
+
+	if enabled, ok := (*setting)[BackupBeforeDeleteSetting].(bool); ok {
+		policy.Enabled = enabled
+	}
+
+	if value, ok := (*setting)[BackupRetentionSetting]; ok {
+		retention, err := ParseDuration(fmt.Sprint(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s setting: %w", BackupRetentionSetting, err)
+		}
+
+		policy.Retention = retention
+	}
+
+	return policy, nil
+}
+
+// ParseDuration parses a positive duration like time.ParseDuration, with the additional unit "d" for days, e.g. "7d"
+func ParseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return duration, nil
+}
+
+// ExpireTime returns the time the backups created now expire at
+func (p *BackupPolicy) ExpireTime() time.Time {
+	return time.Now().Add(p.Retention).UTC()
+}
+
+// ExpireTimeWithin returns the time the backups created now expire at, with the retention bounded to the range an API
+// accepts, e.g. Spanner keeps the backups for at most a year
+func (p *BackupPolicy) ExpireTimeWithin(minRetention, maxRetention time.Duration) time.Time {
+	return time.Now().Add(min(max(p.Retention, minRetention), maxRetention)).UTC()
+}
+
+// Labels returns the labels of the backups, with the run ID and the expiry time
+func (p *BackupPolicy) Labels() map[string]string {
+	return map[string]string{
+		BackupRunLabel:     RunID(),
+		BackupExpiresLabel: strconv.FormatInt(p.ExpireTime().Unix(), 10),
+	}
+}
+
+// invalidBackupIDChars are the characters that are not allowed in the IDs of the backups by the strictest API
+var invalidBackupIDChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// BackupID returns the ID of the backup of the resource in the form "<name>-<run ID>", it is valid for every API
+// (lowercase letters, digits and dashes, starting with a letter) and at most maxLength characters long. The name is
+// truncated when needed, the run ID never is.
+func BackupID(name string, maxLength int) string {
+	return backupID(name, "-"+RunID(), maxLength)
+}
+
+// SourceBackupID returns the ID of the backup of the resource like BackupID, in the form "<name>-<hash>-<run ID>"
+// where hash is a short hash of the full name of the resource. It is for the backups that are in a wider scope than
+// the resources, e.g. the snapshots are global and the disks of two zones can have the same name.
+func SourceBackupID(name, source string, maxLength int) string {
+	sum := sha256.Sum256([]byte(source))
+	return backupID(name, "-"+hex.EncodeToString(sum[:4])+"-"+RunID(), maxLength)
+}
+
+func backupID(name, suffix string, maxLength int) string {
+	name = invalidBackupIDChars.ReplaceAllString(strings.ToLower(name), "-")
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = "b-" + name
+	}
+
+	if len(name)+len(suffix) > maxLength {
+		name = name[:max(1, maxLength-len(suffix))]
+	}
+
+	return strings.TrimRight(name, "-") + suffix
+}
+
+// BackupRetainedUntil returns the expiry time of a backup created by gcp-nuke, from its labels, and true when it has not
+// expired yet. The backups that were not created by gcp-nuke are never retained.
+func BackupRetainedUntil(labels map[string]string) (time.Time, bool) {
+	if labels[BackupRunLabel] == "" {
+		return time.Time{}, false
+	}
+
+	expires, err := strconv.ParseInt(labels[BackupExpiresLabel], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	expireTime := time.Unix(expires, 0).UTC()
+
+	return expireTime, time.Now().Before(expireTime)
+}
+
+// FilterRetainedBackup returns an error, for the Filter of the backup resource types, when the backup was created by
+// gcp-nuke and its retention has not expired yet
+func FilterRetainedBackup(labels map[string]string) error {
+	if expireTime, retained := BackupRetainedUntil(labels); retained {
+		return fmt.Errorf("backup created by gcp-nuke run %s is retained until %s",
+			labels[BackupRunLabel], expireTime.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package nuke
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/settings"
+)
+
+func withRunID(t *testing.T, id string) {
+	t.Helper()
+
+	previous := RunID()
+	SetRunID(id)
+	t.Cleanup(func() { SetRunID(previous) })
+}
+
+func TestNewBackupPolicy(t *testing.T) {
+	cases := []struct {
+		name    string
+		setting *settings.Setting
+		want    *BackupPolicy
+		wantErr bool
+	}{
+		{
+			name: "nil",
+			want: &BackupPolicy{Retention: DefaultBackupRetention},
+		},
+		{
+			name:    "enabled",
+			setting: &settings.Setting{BackupBeforeDeleteSetting: true},
+			want:    &BackupPolicy{Enabled: true, Retention: DefaultBackupRetention},
+		},
+		{
+			name:    "days",
+			setting: &settings.Setting{BackupBeforeDeleteSetting: true, BackupRetentionSetting: "30d"},
+			want:    &BackupPolicy{Enabled: true, Retention: 30 * 24 * time.Hour},
+		},
+		{
+			name:    "duration",
+			setting: &settings.Setting{BackupRetentionSetting: "36h"},
+			want:    &BackupPolicy{Retention: 36 * time.Hour},
+		},
+		{
+			name:    "invalid",
+			setting: &settings.Setting{BackupRetentionSetting: "a week"},
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			setting: &settings.Setting{BackupRetentionSetting: "-1d"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewBackupPolicy(tc.setting)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, policy)
+		})
+	}
+}
+
+func TestBackupID(t *testing.T) {
+	withRunID(t, "20240601-120000")
+
+	assert.Equal(t, "data-disk-20240601-120000", BackupID("data-disk", 63))
+	assert.Equal(t, "my-table-v1-20240601-120000", BackupID("My_Table.v1", 63))
+	assert.Equal(t, "b-1st-disk-20240601-120000", BackupID("1st-disk", 63))
+
+	id := BackupID(strings.Repeat("a", 60)+"-b", 50)
+	assert.Len(t, id, 50)
+	assert.True(t, strings.HasSuffix(id, "-20240601-120000"))
+
+	id = BackupID(strings.Repeat("a", 33)+"-b", 50)
+	assert.Equal(t, strings.Repeat("a", 33)+"-20240601-120000", id)
+}
+
+func TestSourceBackupID(t *testing.T) {
+	withRunID(t, "20240601-120000")
+
+	id := SourceBackupID("data-disk", "projects/my-project/zones/us-east1-b/disks/data-disk", 63)
+	assert.Regexp(t, `^data-disk-[0-9a-f]{8}-20240601-120000$`, id)
+	assert.Equal(t, id, SourceBackupID("data-disk", "projects/my-project/zones/us-east1-b/disks/data-disk", 63))
+	assert.NotEqual(t, id, SourceBackupID("data-disk", "projects/my-project/zones/us-east1-c/disks/data-disk", 63))
+
+	id = SourceBackupID(strings.Repeat("a", 60), "source", 63)
+	assert.Len(t, id, 63)
+	assert.True(t, strings.HasSuffix(id, "-20240601-120000"))
+}
+
+func TestBackupLabels(t *testing.T) {
+	withRunID(t, "20240601-120000")
+
+	policy := &BackupPolicy{Enabled: true, Retention: time.Hour}
+	labels := policy.Labels()
+
+	assert.Equal(t, "20240601-120000", labels[BackupRunLabel])
+
+	expireTime, retained := BackupRetainedUntil(labels)
+	assert.True(t, retained)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expireTime, time.Minute)
+
+	assert.Error(t, FilterRetainedBackup(labels))
+}
+
+func TestFilterRetainedBackup(t *testing.T) {
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+
+	assert.NoError(t, FilterRetainedBackup(nil))
+	assert.NoError(t, FilterRetainedBackup(map[string]string{BackupRunLabel: "r", BackupExpiresLabel: expired}))
+	assert.NoError(t, FilterRetainedBackup(map[string]string{BackupRunLabel: "r", BackupExpiresLabel: "never"}))
+	assert.NoError(t, FilterRetainedBackup(map[string]string{BackupExpiresLabel: "9999999999"}))
+	assert.EqualError(t, FilterRetainedBackup(map[string]string{BackupRunLabel: "r", BackupExpiresLabel: "9999999999"}),
+		"backup created by gcp-nuke run r is retained until 2286-11-20T17:46:39Z")
+}
+
+func TestExpireTimeWithin(t *testing.T) {
+	policy := &BackupPolicy{Retention: time.Hour}
+	assert.WithinDuration(t, time.Now().Add(6*time.Hour), policy.ExpireTimeWithin(6*time.Hour, 90*24*time.Hour),
+		time.Minute)
+
+	policy.Retention = 400 * 24 * time.Hour
+	assert.WithinDuration(t, time.Now().Add(366*24*time.Hour),
+		policy.ExpireTimeWithin(6*time.Hour, 366*24*time.Hour), time.Minute)
+}
@@ -0,0 +1,177 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ListingCache caches the project wide collections that more than one lister, or the same lister for every region,
+// needs during a run. A collection is listed once and every lister partitions it, for example by location. It is safe
+// for concurrent use by the listers, a collection that is being listed is waited for instead of being listed again.
+//
+// The cache is for the scan only. libnuke checks whether a removed resource is gone by listing it again through the
+// same options, so the cache must be disabled once the scan is over, see Disable.
+type ListingCache struct {
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry
+	stats    CacheStats
+	disabled bool
+}
+
+type cacheEntry struct {
+	done     chan struct{}
+	value    any
+	err      error
+	duration time.Duration
+}
+
+// CacheStats are the statistics of a ListingCache
+type CacheStats struct {
+	// Hits is the number of times a collection was served from the cache instead of being listed again
+	Hits int
+
+	// Misses is the number of times a collection was listed
+	Misses int
+
+	// Saved is the time it took to list the collections that were served from the cache, it is an estimate of the
+	// time saved by the cache
+	Saved time.Duration
+}
+
+// String returns the statistics in the form "12 hits, 3 misses, ~4s saved"
+func (s CacheStats) String() string {
+	return fmt.Sprintf("%d hits, %d misses, ~%s saved", s.Hits, s.Misses, s.Saved.Round(time.Millisecond))
+}
+
+// NewListingCache returns a new, empty, cache
+func NewListingCache() *ListingCache {
+	return &ListingCache{
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// Stats returns the statistics of the cache
+func (c *ListingCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// Disable drops the cached collections and makes Cached list every collection again, it is called once the scan is
+// over so that the listings that confirm the removals see the current state
+func (c *ListingCache) Disable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.disabled = true
+	c.entries = make(map[string]*cacheEntry)
+}
+
+// cacheKey returns the key of a collection of an API for the project, or the organization, of the options
+func cacheKey(opts *ListerOpts, api, collection string) string {
+	owner := ""
+	if opts.Project != nil {
+		owner = fmt.Sprintf("projects/%s", *opts.Project)
+	} else if opts.Organization != nil {
+		owner = fmt.Sprintf("organizations/%s", *opts.Organization)
+	}
+
+	return fmt.Sprintf("%s|%s|%s", owner, api, collection)
+}
+
+// Cached returns the collection of the API from the cache of the options, it is listed with fetch when it is not
+// cached yet. Errors are not cached, the next lister lists the collection again. When the options have no cache the
+// collection is always listed, as it is once the cache is disabled.
+//
+// A ListError of fetch is attributed to the lister that listed the collection, the listers that were waiting for it
+// get the error attributed to their resourceType and owner instead.
+func Cached[T any](
+	ctx context.Context, opts *ListerOpts, resourceType, api, collection string, fetch func() (T, error),
+) (T, error) {
+	if opts.Cache == nil {
+		return fetch()
+	}
+
+	c := opts.Cache
+	key := cacheKey(opts, api, collection)
+
+	c.mu.Lock()
+	if c.disabled {
+		c.mu.Unlock()
+		return fetch()
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-entry.done:
+		}
+
+		if entry.err != nil {
+			var zero T
+			return zero, reattribute(opts, resourceType, entry.err)
+		}
+
+		c.mu.Lock()
+		c.stats.Hits++
+		c.stats.Saved += entry.duration
+		c.mu.Unlock()
+
+		return entry.value.(T), nil
+	}
+
+	entry := &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	start := time.Now()
+	value, err := fetch()
+
+	entry.value, entry.err, entry.duration = value, err, time.Since(start)
+
+	if err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+
+	close(entry.done)
+
+	return value, err
+}
+
+// reattribute returns the error of another lister as a ListError of the resourceType and the owner of the options
+func reattribute(opts *ListerOpts, resourceType string, err error) error {
+	var listErr *ListError
+	if !errors.As(err, &listErr) {
+		return opts.ListError(resourceType, err)
+	}
+
+	if listErr.ResourceType == resourceType && listErr.Owner == opts.owner() {
+		return err
+	}
+
+	reattributed := &ListError{
+		ResourceType: resourceType,
+		Owner:        opts.owner(),
+		Kind:         listErr.Kind,
+		Err:          listErr.Err,
+	}
+
+	if opts.ListingErrors != nil {
+		opts.ListingErrors.Add(reattributed)
+	}
+
+	return reattributed
+}
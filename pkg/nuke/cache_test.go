@@ -0,0 +1,161 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCached(t *testing.T) {
+	cache := NewListingCache()
+
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"bucket-a", "bucket-b"}, nil
+	}
+
+	for _, region := range []string{"global", "us-east1", "us-west1"} {
+		opts := &ListerOpts{Project: ptr.String("project"), Region: ptr.String(region), Cache: cache}
+
+		items, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bucket-a", "bucket-b"}, items)
+	}
+
+	assert.Equal(t, 1, calls)
+
+	// Note: another project has its own collection
+	opts := &ListerOpts{Project: ptr.String("other-project"), Region: ptr.String("global"), Cache: cache}
+	_, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	stats := cache.Stats()
+	assert.Equal(t, 2, stats.Hits)
+	assert.Equal(t, 2, stats.Misses)
+}
+
+func TestCachedErrorsNotCached(t *testing.T) {
+	cache := NewListingCache()
+	errs := &ListingErrors{}
+
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("failed")
+		}
+		return []string{"bucket"}, nil
+	}
+
+	opts := &ListerOpts{Project: ptr.String("project"), Region: ptr.String("global"), Cache: cache, ListingErrors: errs}
+
+	_, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+	assert.Error(t, err)
+
+	items, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bucket"}, items)
+	assert.Equal(t, 2, calls)
+}
+
+func TestCachedConcurrent(t *testing.T) {
+	cache := NewListingCache()
+
+	var calls atomic.Int32
+	fetch := func() ([]string, error) {
+		calls.Add(1)
+		return []string{"bucket"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for _, region := range []string{"global", "us-east1", "us-west1", "europe-west1"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			opts := &ListerOpts{Project: ptr.String("project"), Region: ptr.String(region), Cache: cache}
+
+			items, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"bucket"}, items)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 3, cache.Stats().Hits)
+}
+
+func TestReattribute(t *testing.T) {
+	errs := &ListingErrors{}
+	opts := &ListerOpts{Project: ptr.String("project"), Region: ptr.String("us-east1"), ListingErrors: errs}
+
+	original := &ListError{
+		ResourceType: "StorageBucket",
+		Owner:        "global",
+		Kind:         ListErrorPermissionDenied,
+		Err:          errors.New("denied"),
+	}
+
+	err := reattribute(opts, "StorageBucketObject", original)
+
+	var listErr *ListError
+	require.ErrorAs(t, err, &listErr)
+	assert.Equal(t, "StorageBucketObject", listErr.ResourceType)
+	assert.Equal(t, "us-east1", listErr.Owner)
+	assert.Equal(t, ListErrorPermissionDenied, listErr.Kind)
+	assert.Equal(t, original.Err, listErr.Err)
+	assert.Equal(t, []*ListError{listErr}, errs.Errors())
+
+	// Note: an error of the same resource type and owner was already recorded
+	assert.Same(t, err, reattribute(opts, "StorageBucketObject", err))
+	assert.Len(t, errs.Errors(), 1)
+}
+
+func TestCachedWithoutCache(t *testing.T) {
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	opts := &ListerOpts{Project: ptr.String("project"), Region: ptr.String("global")}
+
+	for i := 1; i <= 2; i++ {
+		value, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, i, value)
+	}
+}
+
+func TestCachedDisabled(t *testing.T) {
+	cache := NewListingCache()
+
+	buckets := []string{"bucket-a", "bucket-b"}
+	fetch := func() ([]string, error) {
+		return slices.Clone(buckets), nil
+	}
+
+	opts := &ListerOpts{Project: ptr.String("project"), Region: ptr.String("global"), Cache: cache}
+
+	items, err := Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bucket-a", "bucket-b"}, items)
+
+	// Note: the removal is confirmed by listing again through the same options once the scan is over
+	buckets = buckets[1:]
+	cache.Disable()
+
+	items, err = Cached(context.TODO(), opts, "StorageBucket", "storage.googleapis.com", "buckets", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bucket-b"}, items)
+}
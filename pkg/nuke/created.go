@@ -0,0 +1,85 @@
+package nuke
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+)
+
+// CreatedAtProperty is the property with the normalized creation time of the resources, in UTC, as RFC3339. The
+// resource types whose API does not return a creation time do not have it.
+const CreatedAtProperty = "CreatedAt"
+
+// ParseCreatedAt returns the creation time of a resource, in UTC, from a timestamp of a REST API: RFC3339 with or
+// without fractional seconds. nil is returned when the timestamp is empty or invalid.
+func ParseCreatedAt(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+
+	return CreatedAtFromTime(t)
+}
+
+// ParseCreatedAtPtr is ParseCreatedAt for the timestamps of the APIs that return pointers
+func ParseCreatedAtPtr(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+
+	return ParseCreatedAt(*value)
+}
+
+// CreatedAtFromProto returns the creation time of a resource, in UTC, from a timestamp of a gRPC API, nil when it is
+// not set
+func CreatedAtFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+
+	return CreatedAtFromTime(ts.AsTime())
+}
+
+// CreatedAtFromUnixMilli returns the creation time of a resource, in UTC, from milliseconds since the epoch, nil when
+// it is zero
+func CreatedAtFromUnixMilli(ms int64) *time.Time {
+	if ms == 0 {
+		return nil
+	}
+
+	return CreatedAtFromTime(time.UnixMilli(ms))
+}
+
+// CreatedAtFromTime returns the creation time of a resource in UTC, nil when it is the zero time
+func CreatedAtFromTime(t time.Time) *time.Time {
+	if t.IsZero() || t.Unix() == 0 {
+		return nil
+	}
+
+	t = t.UTC()
+
+	return &t
+}
+
+// MaxAgeFilters returns the filters that keep the resources created less than maxAge ago. The resources without a
+// creation time are kept as well, their age is unknown.
+func MaxAgeFilters(maxAge time.Duration) []filter.Filter {
+	return []filter.Filter{
+		{
+			Property: CreatedAtProperty,
+			Type:     filter.Exact,
+			Value:    "",
+		},
+		{
+			Property: CreatedAtProperty,
+			Type:     filter.DateOlderThan,
+			Value:    maxAge.String(),
+		},
+	}
+}
@@ -0,0 +1,98 @@
+package nuke
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+func TestParseCreatedAt(t *testing.T) {
+	want := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name  string
+		value string
+		want  *time.Time
+	}{
+		{name: "empty"},
+		{name: "invalid", value: "yesterday"},
+		{name: "utc", value: "2024-06-01T10:00:00Z", want: &want},
+		{name: "offset", value: "2024-06-01T03:00:00.000-07:00", want: &want},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ParseCreatedAt(tc.value)
+			if tc.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NotNil(t, got)
+			assert.Equal(t, *tc.want, *got)
+			assert.Equal(t, time.UTC, got.Location())
+		})
+	}
+
+	assert.Nil(t, ParseCreatedAtPtr(nil))
+}
+
+func TestCreatedAtFrom(t *testing.T) {
+	want := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, want, *CreatedAtFromProto(timestamppb.New(want)))
+	assert.Nil(t, CreatedAtFromProto(nil))
+
+	assert.Equal(t, want, *CreatedAtFromUnixMilli(want.UnixMilli()))
+	assert.Nil(t, CreatedAtFromUnixMilli(0))
+
+	assert.Equal(t, want, *CreatedAtFromTime(want.In(time.FixedZone("PDT", -7*3600))))
+	assert.Nil(t, CreatedAtFromTime(time.Time{}))
+	assert.Nil(t, CreatedAtFromTime(time.Unix(0, 0)))
+}
+
+func TestMaxAgeFilters(t *testing.T) {
+	filters := MaxAgeFilters(72 * time.Hour)
+	for _, f := range filters {
+		require.NoError(t, f.Validate())
+	}
+
+	// Note: the property is set the way libnuke sets it for the resources
+	property := func(createdAt *time.Time) string {
+		return types.NewPropertiesFromStruct(struct{ CreatedAt *time.Time }{createdAt}).Get(CreatedAtProperty)
+	}
+
+	cases := []struct {
+		name      string
+		createdAt *time.Time
+		filtered  bool
+	}{
+		{name: "younger", createdAt: CreatedAtFromTime(time.Now().Add(-time.Hour)), filtered: true},
+		{name: "older", createdAt: CreatedAtFromTime(time.Now().Add(-96 * time.Hour))},
+		{name: "unknown", filtered: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Note: libnuke ORs the filters
+			filtered := false
+			for _, f := range filters {
+				match, err := f.Match(property(tc.createdAt))
+				require.NoError(t, err)
+				filtered = filtered || match
+			}
+			assert.Equal(t, tc.filtered, filtered)
+		})
+	}
+
+	// Note: the resource types without a creation time have no CreatedAt property, libnuke gets an empty value for it
+	withoutCreatedAt := types.NewPropertiesFromStruct(struct{ Name string }{"topic"}).Get(CreatedAtProperty)
+	match, err := filters[0].Match(withoutCreatedAt)
+	require.NoError(t, err)
+	assert.True(t, match)
+}
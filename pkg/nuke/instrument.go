@@ -0,0 +1,178 @@
+package nuke
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/telemetry"
+)
+
+var instrumentOnce sync.Once
+
+// InstrumentListers wraps the listers of the registry so that the listing of every resource type, and the removal and
+// the wait of every resource they list, are traced and measured, see telemetry. It is called once, before the first
+// run, when the telemetry is enabled.
+func InstrumentListers() {
+	instrumentOnce.Do(func() {
+		registrations := registry.GetRegistrations()
+
+		names := make([]string, 0, len(registrations))
+		for name := range registrations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// Note: libnuke only takes the lister of a resource type when it is registered, so the resource types are
+		// registered again with their lister wrapped. gcp-nuke has no alternative resource types, libnuke keeps these
+		// across ClearRegistry.
+		registry.ClearRegistry()
+
+		for _, name := range names {
+			reg := *registrations[name]
+			reg.Lister = &instrumentedLister{resourceType: name, lister: reg.Lister}
+			registry.Register(&reg)
+		}
+	})
+}
+
+// instrumentedLister traces and measures the listing of a resource type, the resources it lists are instrumented too
+type instrumentedLister struct {
+	resourceType string
+	lister       registry.Lister
+}
+
+func (l *instrumentedLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var owner, project string
+	if opts, ok := o.(*ListerOpts); ok {
+		owner = opts.owner()
+		if opts.Project != nil {
+			project = *opts.Project
+		}
+	}
+
+	ctx, end := telemetry.StartList(ctx, l.resourceType, owner, project)
+	resources, err := l.lister.List(ctx, o)
+	end(len(resources), err)
+
+	for i, r := range resources {
+		resources[i] = instrumentResource(r, l.resourceType, owner)
+	}
+
+	return resources, err
+}
+
+// Close closes the lister it wraps, when it holds a client
+func (l *instrumentedLister) Close() {
+	if lc, ok := l.lister.(registry.ListerWithClose); ok {
+		lc.Close()
+	}
+}
+
+// instrumentResource returns the resource with its removal and its wait traced and measured. libnuke finds out what a
+// resource supports through the interfaces it implements, so only the resources that have a name and properties, and
+// no unique key, are instrumented. These are all the resources of gcp-nuke, the others are returned as is.
+func instrumentResource(r resource.Resource, resourceType, owner string) resource.Resource {
+	if _, ok := r.(resource.UniqueKeyGetter); ok {
+		return r
+	}
+
+	stringer, ok := r.(resource.LegacyStringer)
+	if !ok {
+		return r
+	}
+
+	getter, ok := r.(resource.PropertyGetter)
+	if !ok {
+		return r
+	}
+
+	return &instrumentedResource{
+		Resource:     r,
+		stringer:     stringer,
+		getter:       getter,
+		resourceType: resourceType,
+		owner:        owner,
+	}
+}
+
+// instrumentedResource forwards every interface of libnuke to the resource it wraps, the ones the resource does not
+// implement behave as if they were not implemented
+type instrumentedResource struct {
+	resource.Resource
+
+	stringer     resource.LegacyStringer
+	getter       resource.PropertyGetter
+	resourceType string
+	owner        string
+}
+
+// Unwrap returns the resource that is wrapped
+func (r *instrumentedResource) Unwrap() resource.Resource {
+	return r.Resource
+}
+
+// UnwrapResource returns the resource wrapped by the instrumentation, the resource itself when it is not wrapped. The
+// interfaces of gcp-nuke that libnuke does not know, e.g. Marker or Suspender, must be checked on the unwrapped
+// resource.
+func UnwrapResource(r resource.Resource) resource.Resource {
+	if wrapper, ok := r.(interface{ Unwrap() resource.Resource }); ok {
+		return wrapper.Unwrap()
+	}
+
+	return r
+}
+
+func (r *instrumentedResource) Remove(ctx context.Context) error {
+	ctx, end := telemetry.StartRemove(ctx, r.resourceType, r.owner, r.String())
+	err := r.Resource.Remove(ctx)
+	end(err)
+
+	return err
+}
+
+func (r *instrumentedResource) HandleWait(ctx context.Context) error {
+	hook, ok := r.Resource.(resource.HandleWaitHook)
+	if !ok {
+		return nil
+	}
+
+	ctx, end := telemetry.StartWait(ctx, r.resourceType, r.owner, r.String())
+	err := hook.HandleWait(ctx)
+	end(err)
+
+	return err
+}
+
+func (r *instrumentedResource) String() string {
+	return r.stringer.String()
+}
+
+func (r *instrumentedResource) Properties() types.Properties {
+	return r.getter.Properties()
+}
+
+func (r *instrumentedResource) Filter() error {
+	if filter, ok := r.Resource.(resource.Filter); ok {
+		return filter.Filter()
+	}
+
+	return nil
+}
+
+func (r *instrumentedResource) Settings(setting *settings.Setting) {
+	if getter, ok := r.Resource.(resource.SettingsGetter); ok {
+		getter.Settings(setting)
+	}
+}
+
+func (r *instrumentedResource) BeforeEnqueue(item interface{}) {
+	if hook, ok := r.Resource.(resource.QueueItemHook); ok {
+		hook.BeforeEnqueue(item)
+	}
+}
@@ -0,0 +1,124 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testInstrumentedResource struct {
+	Name    string
+	removed int
+	waitErr error
+}
+
+func (r *testInstrumentedResource) Remove(_ context.Context) error {
+	r.removed++
+	return nil
+}
+
+func (r *testInstrumentedResource) HandleWait(_ context.Context) error {
+	return r.waitErr
+}
+
+func (r *testInstrumentedResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *testInstrumentedResource) String() string {
+	return r.Name
+}
+
+// testPlainResource has no name and no properties, it is not instrumented
+type testPlainResource struct{}
+
+func (r *testPlainResource) Remove(_ context.Context) error {
+	return nil
+}
+
+type testInstrumentedLister struct {
+	closed bool
+}
+
+func (l *testInstrumentedLister) List(_ context.Context, _ interface{}) ([]resource.Resource, error) {
+	return []resource.Resource{&testInstrumentedResource{Name: "a"}, &testPlainResource{}}, nil
+}
+
+func (l *testInstrumentedLister) Close() {
+	l.closed = true
+}
+
+func TestInstrumentResource(t *testing.T) {
+	inner := &testInstrumentedResource{Name: "my-bucket", waitErr: liberror.ErrWaitResource("still there")}
+
+	r := instrumentResource(inner, "StorageBucket", "us")
+	require.IsType(t, &instrumentedResource{}, r)
+
+	require.NoError(t, r.Remove(context.TODO()))
+	assert.Equal(t, 1, inner.removed)
+
+	assert.Equal(t, "my-bucket", ResourceName(r))
+	assert.Equal(t, map[string]string{"Name": "my-bucket"}, ResourceProperties(r))
+
+	hook, ok := r.(resource.HandleWaitHook)
+	require.True(t, ok)
+	var waitErr liberror.ErrWaitResource
+	assert.True(t, errors.As(hook.HandleWait(context.TODO()), &waitErr))
+
+	// Note: libnuke checks that a removed resource is gone by comparing it with the resources listed again
+	item := &queue.Item{Resource: r}
+	assert.True(t, item.Equals(instrumentResource(&testInstrumentedResource{Name: "my-bucket"}, "StorageBucket", "us")))
+	assert.False(t, item.Equals(instrumentResource(&testInstrumentedResource{Name: "other"}, "StorageBucket", "us")))
+
+	plain := &testPlainResource{}
+	assert.Same(t, plain, instrumentResource(plain, "StorageBucket", "us"))
+}
+
+func TestInstrumentListers(t *testing.T) {
+	lister := &testInstrumentedLister{}
+	registry.Register(&registry.Registration{
+		Name:      "TestInstrumented",
+		Scope:     Project,
+		Lister:    lister,
+		DependsOn: []string{"TestInstrumentedDependency"},
+	})
+	registry.Register(&registry.Registration{
+		Name:   "TestInstrumentedDependency",
+		Scope:  Project,
+		Lister: &testInstrumentedLister{},
+	})
+
+	InstrumentListers()
+	// Note: the listers are only wrapped once
+	InstrumentListers()
+
+	reg := registry.GetRegistration("TestInstrumented")
+	require.NotNil(t, reg)
+	assert.Equal(t, []string{"TestInstrumentedDependency"}, reg.DependsOn)
+	assert.ElementsMatch(t, []string{"TestInstrumented", "TestInstrumentedDependency"}, registry.GetNamesForScope(Project))
+
+	instrumented, ok := registry.GetLister("TestInstrumented").(*instrumentedLister)
+	require.True(t, ok)
+	assert.Same(t, lister, instrumented.lister)
+
+	resources, err := instrumented.List(context.TODO(), &ListerOpts{Project: ptr.String("my-project"),
+		Region: ptr.String("us-east1")})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.IsType(t, &instrumentedResource{}, resources[0])
+	assert.IsType(t, &testPlainResource{}, resources[1])
+	assert.Equal(t, "us-east1", resources[0].(*instrumentedResource).owner)
+
+	instrumented.Close()
+	assert.True(t, lister.closed)
+}
@@ -0,0 +1,49 @@
+package nuke
+
+import (
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// ItemName returns the name of the resource of the item as shown in the output, it is empty if the resource does not
+// implement String
+func ItemName(item *queue.Item) string {
+	return ResourceName(item.Resource)
+}
+
+// ResourceName returns the name of the resource as shown in the output, it is empty if the resource does not implement
+// String
+func ResourceName(r resource.Resource) string {
+	if stringer, ok := r.(resource.LegacyStringer); ok {
+		return stringer.String()
+	}
+
+	return ""
+}
+
+// ItemProperties returns the properties of the resource of the item, it is nil if the resource does not implement
+// Properties. The properties that are internal to libnuke (prefixed with an underscore, e.g. "_tagPrefix") are left
+// out.
+func ItemProperties(item *queue.Item) map[string]string {
+	return ResourceProperties(item.Resource)
+}
+
+// ResourceProperties returns the properties of the resource, see ItemProperties
+func ResourceProperties(r resource.Resource) map[string]string {
+	getter, ok := r.(resource.PropertyGetter)
+	if !ok {
+		return nil
+	}
+
+	properties := make(map[string]string)
+	for key, value := range getter.Properties() {
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		properties[key] = value
+	}
+
+	return properties
+}
@@ -0,0 +1,268 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googleapis/gax-go/v2/apierror"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+)
+
+// ListErrorKind is the class of an error that stopped a lister from listing all the resources
+type ListErrorKind string
+
+const (
+	ListErrorPermissionDenied ListErrorKind = "permission-denied"
+	ListErrorAPIDisabled      ListErrorKind = "api-disabled"
+	ListErrorQuota            ListErrorKind = "quota"
+	ListErrorTransient        ListErrorKind = "transient"
+	ListErrorUnknown          ListErrorKind = "unknown"
+)
+
+// ErrIncompleteListing is returned when --strict-listing is set and at least one resource type could not be listed
+// completely
+var ErrIncompleteListing = errors.New("listing is incomplete")
+
+// listRetries is how many times a listing is attempted again after a transient error, listRetryDelay is the delay
+// before the first retry, it is doubled for every following retry
+var (
+	listRetries    = 3
+	listRetryDelay = time.Second
+)
+
+// ListError is the typed error returned by a lister that could not list all the resources of a type. libnuke drops
+// the resources of a lister that returns an error, so the resource type is reported as incompletely scanned.
+type ListError struct {
+	ResourceType string
+	Owner        string
+	Kind         ListErrorKind
+	Err          error
+}
+
+func (e *ListError) Error() string {
+	return fmt.Sprintf("unable to list all %s in %s (%s): %v", e.ResourceType, e.Owner, e.Kind, e.Err)
+}
+
+func (e *ListError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns the kind of error returned by a GCP API, it works for both the gRPC and the REST clients
+func ClassifyError(err error) ListErrorKind {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ListErrorTransient
+	}
+
+	ae, ok := apierror.FromError(err)
+	if !ok {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return ListErrorTransient
+		}
+
+		return ListErrorUnknown
+	}
+
+	// Note: the reason is set by the newer APIs in the ErrorInfo, the older REST APIs only set it on the error items
+	reasons := []string{strings.ToLower(ae.Reason())}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		for _, item := range gerr.Errors {
+			reasons = append(reasons, strings.ToLower(item.Reason))
+		}
+	}
+
+	for _, reason := range reasons {
+		switch reason {
+		case "service_disabled", "accessnotconfigured":
+			return ListErrorAPIDisabled
+		case "rate_limit_exceeded", "ratelimitexceeded", "userratelimitexceeded",
+			"quotaexceeded", "dailylimitexceeded":
+			return ListErrorQuota
+		}
+	}
+
+	// Note: only the errors of the gRPC clients have a status, the errors of the REST clients have an HTTP code
+	if ae.GRPCStatus() == nil {
+		switch ae.HTTPCode() {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ListErrorPermissionDenied
+		case http.StatusTooManyRequests:
+			return ListErrorQuota
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return ListErrorTransient
+		}
+
+		return ListErrorUnknown
+	}
+
+	switch ae.GRPCStatus().Code() {
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return ListErrorPermissionDenied
+	case codes.ResourceExhausted:
+		return ListErrorQuota
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Aborted:
+		return ListErrorTransient
+	}
+
+	return ListErrorUnknown
+}
+
+// ListError wraps an error of a lister into a ListError and records it for the run. Errors that make libnuke skip the
+// request (e.g. a global resource in a region) and errors that already are a ListError are returned as is.
+func (o *ListerOpts) ListError(resourceType string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var skipErr liberror.ErrSkipRequest
+	if errors.As(err, &skipErr) {
+		return err
+	}
+
+	// Note: the emulators do not implement every method of the APIs, e.g. the Bigtable emulator has no instances
+	if o.Emulated() && status.Code(err) == codes.Unimplemented {
+		return liberror.ErrSkipRequest(fmt.Sprintf("%s is not supported by the emulator", resourceType))
+	}
+
+	// Note: the error was already recorded when it was wrapped, e.g. by ListAll
+	var listErr *ListError
+	if errors.As(err, &listErr) {
+		return err
+	}
+
+	listErr = &ListError{
+		ResourceType: resourceType,
+		Owner:        o.owner(),
+		Kind:         ClassifyError(err),
+		Err:          err,
+	}
+
+	if o.ListingErrors != nil {
+		o.ListingErrors.Add(listErr)
+	}
+
+	return listErr
+}
+
+// owner returns the owner of the resources listed with the options, the same as the owner of the libnuke scanner
+func (o *ListerOpts) owner() string {
+	if o.Project == nil && o.Organization != nil {
+		return fmt.Sprintf("organizations/%s", *o.Organization)
+	}
+
+	if o.Region == nil {
+		return ""
+	}
+
+	return *o.Region
+}
+
+// Iterator is implemented by the iterators of the GCP client libraries
+type Iterator[T any] interface {
+	Next() (T, error)
+}
+
+// ListAll returns every item of the iterator created by newIterator. Transient errors are retried with a backoff,
+// starting over with a new iterator since the iterators of the GCP client libraries stop at the first error. Any other
+// error is returned as a ListError, see ListerOpts.ListError.
+func ListAll[T any](
+	ctx context.Context, opts *ListerOpts, resourceType string, newIterator func() Iterator[T],
+) ([]T, error) {
+	delay := listRetryDelay
+
+	for attempt := 0; ; attempt++ {
+		items, err := listAll(newIterator())
+		if err == nil {
+			return items, nil
+		}
+
+		if ClassifyError(err) != ListErrorTransient || attempt >= listRetries {
+			return nil, opts.ListError(resourceType, err)
+		}
+
+		logrus.WithError(err).
+			WithField("resource_type", resourceType).
+			WithField("attempt", attempt+1).
+			Debugf("transient error while listing, retrying in %s", delay)
+
+		select {
+		case <-ctx.Done():
+			return nil, opts.ListError(resourceType, err)
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func listAll[T any](it Iterator[T]) ([]T, error) {
+	var items []T
+	for {
+		item, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+}
+
+// ListingErrors collects the ListError of every lister of a run, it is safe for concurrent use by the listers
+type ListingErrors struct {
+	mu   sync.Mutex
+	errs []*ListError
+}
+
+// Add records the error
+func (l *ListingErrors) Add(err *ListError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.errs = append(l.errs, err)
+}
+
+// Errors returns the recorded errors sorted by resource type and owner
+func (l *ListingErrors) Errors() []*ListError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	errs := make([]*ListError, len(l.errs))
+	copy(errs, l.errs)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].ResourceType != errs[j].ResourceType {
+			return errs[i].ResourceType < errs[j].ResourceType
+		}
+		return errs[i].Owner < errs[j].Owner
+	})
+
+	return errs
+}
+
+// Check returns ErrIncompleteListing when strict is true and at least one error was recorded
+func (l *ListingErrors) Check(strict bool) error {
+	errs := l.Errors()
+	if !strict || len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d resource type(s) could not be listed completely", ErrIncompleteListing, len(errs))
+}
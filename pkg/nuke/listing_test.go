@@ -0,0 +1,216 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want ListErrorKind
+	}{
+		"grpc-permission-denied": {
+			err:  status.Error(codes.PermissionDenied, "denied"),
+			want: ListErrorPermissionDenied,
+		},
+		"grpc-resource-exhausted": {
+			err:  status.Error(codes.ResourceExhausted, "quota"),
+			want: ListErrorQuota,
+		},
+		"grpc-unavailable": {
+			err:  status.Error(codes.Unavailable, "unavailable"),
+			want: ListErrorTransient,
+		},
+		"grpc-not-found": {
+			err:  status.Error(codes.NotFound, "not found"),
+			want: ListErrorUnknown,
+		},
+		"rest-forbidden": {
+			err:  &googleapi.Error{Code: http.StatusForbidden},
+			want: ListErrorPermissionDenied,
+		},
+		"rest-api-disabled": {
+			err: &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: "accessNotConfigured"}},
+			},
+			want: ListErrorAPIDisabled,
+		},
+		"rest-rate-limit": {
+			err: &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}},
+			},
+			want: ListErrorQuota,
+		},
+		"rest-too-many-requests": {
+			err:  &googleapi.Error{Code: http.StatusTooManyRequests},
+			want: ListErrorQuota,
+		},
+		"rest-service-unavailable": {
+			err:  fmt.Errorf("wrapped: %w", &googleapi.Error{Code: http.StatusServiceUnavailable}),
+			want: ListErrorTransient,
+		},
+		"deadline-exceeded": {
+			err:  context.DeadlineExceeded,
+			want: ListErrorTransient,
+		},
+		"other": {
+			err:  errors.New("other"),
+			want: ListErrorUnknown,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ClassifyError(tc.err))
+		})
+	}
+}
+
+type testIterator struct {
+	items []string
+	err   error
+}
+
+func (it *testIterator) Next() (string, error) {
+	if len(it.items) == 0 {
+		if it.err != nil {
+			return "", it.err
+		}
+		return "", iterator.Done
+	}
+
+	item := it.items[0]
+	it.items = it.items[1:]
+
+	return item, nil
+}
+
+func newTestOpts() *ListerOpts {
+	return &ListerOpts{
+		Project:       ptr.String("test-project"),
+		Region:        ptr.String("us-east1"),
+		ListingErrors: &ListingErrors{},
+	}
+}
+
+func TestListAll(t *testing.T) {
+	listRetryDelay = 0
+
+	t.Run("success", func(t *testing.T) {
+		opts := newTestOpts()
+
+		items, err := ListAll(context.TODO(), opts, "TestResource", func() Iterator[string] {
+			return &testIterator{items: []string{"a", "b"}}
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, items)
+		assert.Empty(t, opts.ListingErrors.Errors())
+	})
+
+	t.Run("transient-retried", func(t *testing.T) {
+		opts := newTestOpts()
+
+		attempts := 0
+		items, err := ListAll(context.TODO(), opts, "TestResource", func() Iterator[string] {
+			attempts++
+			if attempts < 3 {
+				return &testIterator{items: []string{"a"}, err: status.Error(codes.Unavailable, "unavailable")}
+			}
+			return &testIterator{items: []string{"a", "b"}}
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, items)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("transient-exhausted", func(t *testing.T) {
+		opts := newTestOpts()
+
+		attempts := 0
+		_, err := ListAll(context.TODO(), opts, "TestResource", func() Iterator[string] {
+			attempts++
+			return &testIterator{err: status.Error(codes.Unavailable, "unavailable")}
+		})
+
+		var listErr *ListError
+		require.ErrorAs(t, err, &listErr)
+		assert.Equal(t, ListErrorTransient, listErr.Kind)
+		assert.Equal(t, listRetries+1, attempts)
+	})
+
+	t.Run("permission-denied", func(t *testing.T) {
+		opts := newTestOpts()
+
+		attempts := 0
+		_, err := ListAll(context.TODO(), opts, "TestResource", func() Iterator[string] {
+			attempts++
+			return &testIterator{items: []string{"a"}, err: status.Error(codes.PermissionDenied, "denied")}
+		})
+
+		var listErr *ListError
+		require.ErrorAs(t, err, &listErr)
+		assert.Equal(t, &ListError{
+			ResourceType: "TestResource",
+			Owner:        "us-east1",
+			Kind:         ListErrorPermissionDenied,
+			Err:          listErr.Err,
+		}, listErr)
+		assert.Equal(t, 1, attempts)
+		assert.Equal(t, []*ListError{listErr}, opts.ListingErrors.Errors())
+	})
+}
+
+func TestListError(t *testing.T) {
+	opts := newTestOpts()
+
+	skipErr := liberror.ErrSkipRequest("global resource")
+	assert.Equal(t, skipErr, opts.ListError("TestResource", skipErr))
+	assert.NoError(t, opts.ListError("TestResource", nil))
+
+	err := opts.ListError("TestResource", status.Error(codes.PermissionDenied, "denied"))
+	assert.Equal(t, err, opts.ListError("OtherResource", err))
+	assert.Len(t, opts.ListingErrors.Errors(), 1)
+
+	// Note: the methods an emulator does not implement are skipped, the same methods of the real APIs are errors
+	unimplemented := status.Error(codes.Unimplemented, "not implemented")
+	require.ErrorAs(t, opts.ListError("TestResource", unimplemented), new(*ListError))
+
+	emulated := newTestOpts()
+	emulated.EmulatorClientOptions = map[string][]option.ClientOption{"pubsub.googleapis.com": nil}
+	require.ErrorAs(t, emulated.ListError("TestResource", unimplemented), &skipErr)
+	assert.Empty(t, emulated.ListingErrors.Errors())
+
+	opts = &ListerOpts{Organization: ptr.String("123")}
+	var listErr *ListError
+	require.ErrorAs(t, opts.ListError("TestResource", errors.New("other")), &listErr)
+	assert.Equal(t, "organizations/123", listErr.Owner)
+}
+
+func TestListingErrorsCheck(t *testing.T) {
+	l := &ListingErrors{}
+	assert.NoError(t, l.Check(true))
+
+	l.Add(&ListError{ResourceType: "B", Owner: "us-east1"})
+	l.Add(&ListError{ResourceType: "A", Owner: "us-east1"})
+
+	assert.NoError(t, l.Check(false))
+	assert.ErrorIs(t, l.Check(true), ErrIncompleteListing)
+	assert.Equal(t, "A", l.Errors()[0].ResourceType)
+}
@@ -0,0 +1,223 @@
+package nuke
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// Mode is what a run does to the resources that are not filtered
+type Mode string
+
+const (
+	// ModeRemove removes the resources right away, it is the default mode
+	ModeRemove Mode = "remove"
+
+	// ModeMark marks the resources for removal with the MarkedLabel instead of removing them
+	ModeMark Mode = "mark"
+
+	// ModeSweep only removes the resources that were marked for removal more than the grace period ago
+	ModeSweep Mode = "sweep"
+
+	// ModeSuspend suspends the resources instead of removing them, e.g. it stops the compute instances, and records
+	// what they looked like before in the state file
+	ModeSuspend Mode = "suspend"
+
+	// ModeResume resumes the resources suspended by a run in suspend mode, from the state file
+	ModeResume Mode = "resume"
+)
+
+// Modes are all the supported modes
+var Modes = []Mode{ModeRemove, ModeMark, ModeSweep, ModeSuspend, ModeResume}
+
+// ParseMode returns the Mode for the name, the default mode when the name is empty
+func ParseMode(name string) (Mode, error) {
+	if name == "" {
+		return ModeRemove, nil
+	}
+
+	for _, mode := range Modes {
+		if string(mode) == name {
+			return mode, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported mode %q, must be one of %v", name, Modes)
+}
+
+// Action returns what the mode does to the resources, e.g. "suspended"
+func (m Mode) Action() string {
+	switch m {
+	case ModeMark:
+		return "marked for removal"
+	case ModeSuspend:
+		return "suspended"
+	case ModeResume:
+		return "resumed"
+	default:
+		return "removed"
+	}
+}
+
+const (
+	// MarkedLabel is the label of the resources marked for removal, with the time they were marked at in seconds
+	// since the epoch, it is a valid label value
+	MarkedLabel = "gcp-nuke-marked"
+
+	// MarkedProperty is the property with the time a resource was marked for removal at. The resource types without
+	// labels that keep the mark in their metadata expose it with the same property.
+	MarkedProperty = "label:" + MarkedLabel
+)
+
+// DefaultGracePeriod is how long the resources stay marked before a sweep removes them when the grace period is not
+// set
+const DefaultGracePeriod = 7 * 24 * time.Hour
+
+// Marker is implemented by the resources that can be marked for removal, it adds the MarkedLabel to the resource, or
+// its metadata when the resource type has no labels. The resources that are already marked are not marked again, so
+// that a later run in mark mode does not restart their grace period.
+type Marker interface {
+	Mark(ctx context.Context, markedAt time.Time) error
+}
+
+// Stopper is implemented by the resources that keep costing while they are marked, e.g. the compute instances, they
+// can be stopped when they are marked
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// MarkValue returns the value of the MarkedLabel for a resource marked at the time
+func MarkValue(markedAt time.Time) string {
+	return strconv.FormatInt(markedAt.Unix(), 10)
+}
+
+// ParseMarkedAt returns the time of a value of the MarkedLabel, nil when it is empty or invalid
+func ParseMarkedAt(value string) *time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return nil
+	}
+
+	t := time.Unix(seconds, 0).UTC()
+
+	return &t
+}
+
+// MarkedAt returns the time the resource was marked for removal at, nil when it is not marked, see MarkedProperty
+func MarkedAt(r resource.Resource) *time.Time {
+	getter, ok := r.(resource.PropertyGetter)
+	if !ok {
+		return nil
+	}
+
+	return ParseMarkedAt(getter.Properties().Get(MarkedProperty))
+}
+
+// WithMark returns a copy of the labels with the MarkedLabel, the labels are not modified
+func WithMark(labels map[string]string, markedAt time.Time) map[string]string {
+	marked := make(map[string]string, len(labels)+1)
+	for key, value := range labels {
+		marked[key] = value
+	}
+	marked[MarkedLabel] = MarkValue(markedAt)
+
+	return marked
+}
+
+// SweepFilters returns the filters that keep the resources that are not marked for removal, or were marked less than
+// gracePeriod ago. The resources whose mark was removed in the meantime are kept as well.
+func SweepFilters(gracePeriod time.Duration) []filter.Filter {
+	return []filter.Filter{
+		{
+			Property: MarkedProperty,
+			Type:     filter.Exact,
+			Value:    "",
+		},
+		{
+			Property: MarkedProperty,
+			Type:     filter.DateOlderThan,
+			Value:    gracePeriod.String(),
+		},
+	}
+}
+
+// MarkItems marks the resources of the items that would be removed, and stops them as well when stop is true. The
+// state of each of these items is set to the outcome: finished when the resource is marked, failed when it could not
+// be marked or stopped and filtered when its type cannot be marked. The resources that are already marked keep their
+// mark and are not stopped again. The items that would be removed are returned.
+func MarkItems(ctx context.Context, items []*queue.Item, markedAt time.Time, stop bool) []*queue.Item {
+	marked := make([]*queue.Item, 0)
+	for _, item := range items {
+		state := item.GetState()
+		if state != queue.ItemStateNew && state != queue.ItemStateNewDependency {
+			continue
+		}
+
+		item.State, item.Reason = markResource(ctx, item.Resource, markedAt, stop)
+		marked = append(marked, item)
+	}
+
+	return marked
+}
+
+// markResource marks the resource and returns the state and the reason of its item
+func markResource(ctx context.Context, r resource.Resource, markedAt time.Time, stop bool) (queue.ItemState, string) {
+	r = UnwrapResource(r)
+
+	marker, ok := r.(Marker)
+	if !ok {
+		return queue.ItemStateFiltered, "the resource type cannot be marked"
+	}
+
+	if existing := MarkedAt(r); existing != nil {
+		return queue.ItemStateFinished, fmt.Sprintf("already marked at %s", existing.Format(time.RFC3339))
+	}
+
+	if err := marker.Mark(ctx, markedAt); err != nil {
+		return queue.ItemStateFailed, fmt.Sprintf("unable to mark: %s", err)
+	}
+
+	if stopper, ok := r.(Stopper); ok && stop {
+		if err := stopper.Stop(ctx); err != nil {
+			return queue.ItemStateFailed, fmt.Sprintf("marked, but unable to stop: %s", err)
+		}
+
+		return queue.ItemStateFinished, fmt.Sprintf("marked at %s and stopped", markedAt.UTC().Format(time.RFC3339))
+	}
+
+	return queue.ItemStateFinished, fmt.Sprintf("marked at %s", markedAt.UTC().Format(time.RFC3339))
+}
+
+// descriptionMark is the mark of the resource types without labels that keep it in their description
+var descriptionMark = regexp.MustCompile(regexp.QuoteMeta(MarkedLabel) + `=(\d+)`)
+
+// MarkFromDescription returns the value of the mark in a description, nil when the description has none, see
+// DescriptionWithMark
+func MarkFromDescription(description string) *string {
+	match := descriptionMark.FindStringSubmatch(description)
+	if match == nil {
+		return nil
+	}
+
+	return &match[1]
+}
+
+// DescriptionWithMark returns the description with the mark appended, e.g. "CI runner gcp-nuke-marked=1717243200",
+// for the resource types without labels. A mark already in the description is replaced.
+func DescriptionWithMark(description string, markedAt time.Time) string {
+	mark := fmt.Sprintf("%s=%s", MarkedLabel, MarkValue(markedAt))
+
+	description = strings.TrimSpace(descriptionMark.ReplaceAllString(description, ""))
+	if description == "" {
+		return mark
+	}
+
+	return description + " " + mark
+}
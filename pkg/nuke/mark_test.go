@@ -0,0 +1,195 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testMarkedResource struct {
+	Name   string
+	Labels map[string]string `property:"tagPrefix=label"`
+
+	markErr error
+	stopErr error
+	marked  bool
+	stopped bool
+}
+
+func (r *testMarkedResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testMarkedResource) Mark(_ context.Context, markedAt time.Time) error {
+	if r.markErr != nil {
+		return r.markErr
+	}
+	r.marked = true
+	r.Labels = WithMark(r.Labels, markedAt)
+	return nil
+}
+
+func (r *testMarkedResource) Stop(_ context.Context) error {
+	if r.stopErr != nil {
+		return r.stopErr
+	}
+	r.stopped = true
+	return nil
+}
+
+func (r *testMarkedResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *testMarkedResource) String() string {
+	return r.Name
+}
+
+func TestParseMode(t *testing.T) {
+	for name, want := range map[string]Mode{
+		"": ModeRemove, "remove": ModeRemove, "mark": ModeMark, "sweep": ModeSweep, "suspend": ModeSuspend,
+		"resume": ModeResume,
+	} {
+		got, err := ParseMode(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := ParseMode("delete")
+	assert.Error(t, err)
+}
+
+func TestWithMark(t *testing.T) {
+	markedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	labels := map[string]string{"env": "dev"}
+
+	assert.Equal(t, map[string]string{"env": "dev", MarkedLabel: "1717243200"}, WithMark(labels, markedAt))
+	assert.Equal(t, map[string]string{"env": "dev"}, labels)
+	assert.Equal(t, map[string]string{MarkedLabel: "1717243200"}, WithMark(nil, markedAt))
+
+	assert.Equal(t, markedAt, *ParseMarkedAt("1717243200"))
+	assert.Nil(t, ParseMarkedAt(""))
+	assert.Nil(t, ParseMarkedAt("yesterday"))
+}
+
+func TestDescriptionWithMark(t *testing.T) {
+	markedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		description string
+		want        string
+	}{
+		{description: "", want: "gcp-nuke-marked=1717243200"},
+		{description: "CI runner", want: "CI runner gcp-nuke-marked=1717243200"},
+		{description: "CI runner gcp-nuke-marked=1700000000", want: "CI runner gcp-nuke-marked=1717243200"},
+	}
+
+	for _, tc := range cases {
+		got := DescriptionWithMark(tc.description, markedAt)
+		assert.Equal(t, tc.want, got)
+		assert.Equal(t, "1717243200", *MarkFromDescription(got))
+	}
+
+	assert.Nil(t, MarkFromDescription("CI runner"))
+}
+
+func TestMarkItems(t *testing.T) {
+	markedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	unmarked := &testMarkedResource{}
+	alreadyMarked := &testMarkedResource{Labels: map[string]string{MarkedLabel: "1700000000"}}
+	failing := &testMarkedResource{markErr: errors.New("permission denied")}
+	notStopped := &testMarkedResource{stopErr: errors.New("permission denied")}
+	filtered := &testMarkedResource{}
+
+	items := []*queue.Item{
+		{Resource: unmarked, State: queue.ItemStateNew},
+		{Resource: alreadyMarked, State: queue.ItemStateNew},
+		{Resource: failing, State: queue.ItemStateNew},
+		{Resource: notStopped, State: queue.ItemStateNewDependency},
+		{Resource: &testPlainResource{}, State: queue.ItemStateNew},
+		{Resource: filtered, State: queue.ItemStateFiltered, Reason: "filtered by config"},
+	}
+
+	marked := MarkItems(context.TODO(), items, markedAt, true)
+	assert.Len(t, marked, 5)
+
+	assert.Equal(t, queue.ItemStateFinished, items[0].State)
+	assert.Equal(t, "marked at 2024-06-01T12:00:00Z and stopped", items[0].Reason)
+	assert.True(t, unmarked.stopped)
+	assert.Equal(t, markedAt, *MarkedAt(unmarked))
+
+	assert.Equal(t, queue.ItemStateFinished, items[1].State)
+	assert.Equal(t, "already marked at 2023-11-14T22:13:20Z", items[1].Reason)
+	assert.False(t, alreadyMarked.marked)
+	assert.False(t, alreadyMarked.stopped)
+
+	assert.Equal(t, queue.ItemStateFailed, items[2].State)
+	assert.Equal(t, "unable to mark: permission denied", items[2].Reason)
+
+	assert.Equal(t, queue.ItemStateFailed, items[3].State)
+	assert.Equal(t, "marked, but unable to stop: permission denied", items[3].Reason)
+	assert.True(t, notStopped.marked)
+
+	assert.Equal(t, queue.ItemStateFiltered, items[4].State)
+	assert.Equal(t, "the resource type cannot be marked", items[4].Reason)
+
+	assert.Equal(t, queue.ItemStateFiltered, items[5].State)
+	assert.False(t, filtered.marked)
+}
+
+func TestMarkInstrumentedItems(t *testing.T) {
+	markedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Note: with the telemetry enabled, the resources are wrapped, they are marked and stopped all the same
+	inner := &testMarkedResource{Name: "my-disk"}
+	r := instrumentResource(inner, "ComputeDisk", "us-east1-b")
+	require.IsType(t, &instrumentedResource{}, r)
+	assert.Same(t, inner, UnwrapResource(r))
+
+	items := []*queue.Item{{Resource: r, State: queue.ItemStateNew}}
+
+	assert.Len(t, MarkItems(context.TODO(), items, markedAt, true), 1)
+	assert.Equal(t, queue.ItemStateFinished, items[0].State)
+	assert.Equal(t, "marked at 2024-06-01T12:00:00Z and stopped", items[0].Reason)
+	assert.True(t, inner.marked)
+	assert.True(t, inner.stopped)
+	assert.Equal(t, markedAt, *MarkedAt(r))
+}
+
+func TestSweepFilters(t *testing.T) {
+	filters := SweepFilters(7 * 24 * time.Hour)
+	for _, f := range filters {
+		require.NoError(t, f.Validate())
+	}
+
+	cases := []struct {
+		name     string
+		value    string
+		filtered bool
+	}{
+		{name: "not marked", value: "", filtered: true},
+		{name: "marked recently", value: MarkValue(time.Now().Add(-time.Hour)), filtered: true},
+		{name: "marked long ago", value: MarkValue(time.Now().Add(-8 * 24 * time.Hour))},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := false
+			for _, f := range filters {
+				match, err := f.Match(tc.value)
+				require.NoError(t, err)
+				filtered = filtered || match
+			}
+
+			assert.Equal(t, tc.filtered, filtered)
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/container/apiv1"
+	"cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/googleapis/gax-go/v2"
+	"github.com/googleapis/gax-go/v2/apierror"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/googleapi"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+)
+
+// ErrOperationTimeout is returned when an operation did not complete within the wait timeout, see
+// ListerOpts.WaitTimeout
+var ErrOperationTimeout = errors.New("operation did not complete in time")
+
+// operationPollInterval is the delay before an operation that is still running is polled again, it is doubled after
+// every poll up to operationMaxPollInterval
+var (
+	operationPollInterval    = 5 * time.Second
+	operationMaxPollInterval = 30 * time.Second
+)
+
+// Operation is a long-running operation started by the removal of a resource
+type Operation interface {
+	// Name returns the name of the operation
+	Name() string
+
+	// Poll refreshes the state of the operation and returns true when it is done. An *OperationError is returned when
+	// the operation failed, any other error is an error of the poll itself.
+	Poll(ctx context.Context) (done bool, err error)
+}
+
+// OperationError is the error of an operation that failed
+type OperationError struct {
+	Name string
+	Err  error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %s failed: %v", e.Name, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// OperationTracker tracks the operation started by the removal of a resource for its HandleWait, the zero value is
+// ready to use and has no wait timeout, see ListerOpts.OperationTracker. The operation is polled at a growing interval,
+// the removal fails when the operation failed or did not complete within the wait timeout.
+type OperationTracker struct {
+	// timeout is how long the operation is waited for before the removal fails, 0 means no limit
+	timeout time.Duration
+
+	name     string
+	started  time.Time
+	nextPoll time.Time
+	interval time.Duration
+}
+
+// Wait polls the operation when it is due and maps its state for libnuke: nil when the operation is done, in which
+// case libnuke verifies that the resource is gone, liberror.ErrWaitResource while it is running and an error when it
+// failed or timed out. A nil operation is done. Errors of the poll itself are retried until the timeout.
+func (t *OperationTracker) Wait(ctx context.Context, op Operation) error {
+	if op == nil {
+		return nil
+	}
+
+	now := time.Now()
+
+	// Note: the resource starts a new operation when its removal is attempted again
+	if t.started.IsZero() || t.name != op.Name() {
+		t.name = op.Name()
+		t.started = now
+		t.nextPoll = now
+		t.interval = operationPollInterval
+	}
+
+	if t.timeout > 0 && now.Sub(t.started) > t.timeout {
+		return fmt.Errorf("%w: operation %s is still running after %s", ErrOperationTimeout, t.name, t.timeout)
+	}
+
+	if now.Before(t.nextPoll) {
+		return liberror.ErrWaitResource("waiting for operation to complete")
+	}
+
+	done, err := op.Poll(ctx)
+
+	var opErr *OperationError
+	switch {
+	case errors.As(err, &opErr):
+		return err
+	case err != nil && isNotFound(err):
+		logrus.WithField("operation", t.name).Trace("operation not found, assuming completed")
+		return nil
+	case err != nil:
+		logrus.WithError(err).WithField("operation", t.name).Trace("unable to poll operation")
+		t.schedule(now)
+		return liberror.ErrWaitResource(fmt.Sprintf("unable to poll operation: %v", err))
+	case !done:
+		t.schedule(now)
+		return liberror.ErrWaitResource("waiting for operation to complete")
+	}
+
+	return nil
+}
+
+func (t *OperationTracker) schedule(now time.Time) {
+	t.nextPoll = now.Add(t.interval)
+	t.interval = min(t.interval*2, operationMaxPollInterval)
+}
+
+// WaitOperation blocks until the operation is done, for the operations a removal depends on before it can continue.
+// The removal fails when the operation did not complete within the timeout, 0 means no limit.
+func WaitOperation(ctx context.Context, timeout time.Duration, op Operation) error {
+	t := OperationTracker{timeout: timeout}
+
+	for {
+		err := t.Wait(ctx, op)
+
+		var waitErr liberror.ErrWaitResource
+		if !errors.As(err, &waitErr) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(t.nextPoll)):
+		}
+	}
+}
+
+func isNotFound(err error) bool {
+	ae, ok := apierror.FromError(err)
+	if !ok {
+		return false
+	}
+
+	if ae.GRPCStatus() != nil {
+		return ae.GRPCStatus().Code() == codes.NotFound
+	}
+
+	return ae.HTTPCode() == http.StatusNotFound
+}
+
+// IsAlreadyExists returns whether the error of an API is that the resource to create already exists
+func IsAlreadyExists(err error) bool {
+	// Note: the REST clients wrap the HTTP error with a status, a conflict is mapped to Aborted in it
+	var herr *googleapi.Error
+	if errors.As(err, &herr) {
+		return herr.Code == http.StatusConflict
+	}
+
+	ae, ok := apierror.FromError(err)
+	if !ok {
+		return false
+	}
+
+	return ae.GRPCStatus().Code() == codes.AlreadyExists
+}
+
+// ComputeOperation returns the Operation of a compute operation
+func ComputeOperation(op *compute.Operation) Operation {
+	if op == nil {
+		return nil
+	}
+
+	return &computeOperation{op: op}
+}
+
+// ComputeMetadataOperation returns the Operation of a compute operation that sets the common instance metadata. The
+// compute client fails to decode the response of the poll of these operations, the operation is considered done then
+// and libnuke verifies the removal by listing again.
+func ComputeMetadataOperation(op *compute.Operation) Operation {
+	if op == nil {
+		return nil
+	}
+
+	return &computeOperation{op: op, decodeErrorDone: true}
+}
+
+type computeOperation struct {
+	op *compute.Operation
+
+	// decodeErrorDone is true when a response of the poll that cannot be decoded means the operation is done
+	decodeErrorDone bool
+}
+
+func (o *computeOperation) Name() string {
+	return o.op.Name()
+}
+
+func (o *computeOperation) Poll(ctx context.Context) (bool, error) {
+	err := o.op.Poll(ctx)
+
+	if o.decodeErrorDone && isDecodeError(err) {
+		logrus.WithError(err).WithField("operation", o.op.Name()).Trace("unable to decode operation, assuming done")
+		return true, nil
+	}
+
+	if !o.op.Done() {
+		return false, err
+	}
+
+	if err != nil || o.op.Proto().GetError() != nil {
+		return true, &OperationError{Name: o.op.Name(), Err: computeOperationError(o.op, err)}
+	}
+
+	return true, nil
+}
+
+// computeOperationError returns the error of a failed compute operation, with the messages of its errors
+func computeOperationError(op *compute.Operation, err error) error {
+	var messages []string
+	for _, e := range op.Proto().GetError().GetErrors() {
+		messages = append(messages, e.GetMessage())
+	}
+
+	if len(messages) == 0 {
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, op.Proto().GetHttpErrorMessage())
+	}
+
+	return fmt.Errorf("%s: %s", op.Proto().GetTargetLink(), strings.Join(messages, "; "))
+}
+
+// isDecodeError returns true when the response of a poll could not be decoded by the protobuf runtime
+func isDecodeError(err error) bool {
+	return errors.Is(err, proto.Error)
+}
+
+// lroOperation is implemented by the delete operations of the GCP client libraries, they wrap a
+// longrunning.Operation
+type lroOperation interface {
+	Name() string
+	Done() bool
+	Poll(ctx context.Context, opts ...gax.CallOption) error
+}
+
+// LROOperation returns the Operation of a long-running operation of a GCP client library
+func LROOperation(op lroOperation) Operation {
+	return &lro{name: op.Name, done: op.Done, poll: func(ctx context.Context) error {
+		return op.Poll(ctx)
+	}}
+}
+
+// lroResultOperation is implemented by the delete operations of the GCP client libraries that return the deleted
+// resource
+type lroResultOperation[T any] interface {
+	Name() string
+	Done() bool
+	Poll(ctx context.Context, opts ...gax.CallOption) (T, error)
+}
+
+// LROResultOperation returns the Operation of a long-running operation of a GCP client library that returns the
+// deleted resource, T is the type of the resource
+func LROResultOperation[T any](op lroResultOperation[T]) Operation {
+	return &lro{name: op.Name, done: op.Done, poll: func(ctx context.Context) error {
+		_, err := op.Poll(ctx)
+		return err
+	}}
+}
+
+type lro struct {
+	name func() string
+	done func() bool
+	poll func(ctx context.Context) error
+}
+
+func (o *lro) Name() string {
+	return o.name()
+}
+
+// Poll polls the operation, the client libraries return the error of the operation from the poll once it is done
+func (o *lro) Poll(ctx context.Context) (bool, error) {
+	err := o.poll(ctx)
+	if !o.done() {
+		return false, err
+	}
+
+	if err != nil {
+		return true, &OperationError{Name: o.name(), Err: err}
+	}
+
+	return true, nil
+}
+
+// SQLAdminOperation returns the Operation of a Cloud SQL operation
+func SQLAdminOperation(svc *sqladmin.Service, project string, op *sqladmin.Operation) Operation {
+	if op == nil {
+		return nil
+	}
+
+	return &sqlAdminOperation{svc: svc, project: project, op: op}
+}
+
+type sqlAdminOperation struct {
+	svc     *sqladmin.Service
+	project string
+	op      *sqladmin.Operation
+}
+
+func (o *sqlAdminOperation) Name() string {
+	return o.op.Name
+}
+
+func (o *sqlAdminOperation) Poll(ctx context.Context) (bool, error) {
+	op, err := o.svc.Operations.Get(o.project, o.op.Name).Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+
+	if op.Status != "DONE" {
+		return false, nil
+	}
+
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		var messages []string
+		for _, e := range op.Error.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		return true, &OperationError{
+			Name: op.Name,
+			Err:  fmt.Errorf("%s: %s", op.TargetLink, strings.Join(messages, "; ")),
+		}
+	}
+
+	return true, nil
+}
+
+// ContainerOperation returns the Operation of a GKE operation
+func ContainerOperation(svc *container.ClusterManagerClient, project string, op *containerpb.Operation) Operation {
+	if op == nil {
+		return nil
+	}
+
+	return &containerOperation{svc: svc, project: project, op: op}
+}
+
+type containerOperation struct {
+	svc     *container.ClusterManagerClient
+	project string
+	op      *containerpb.Operation
+}
+
+func (o *containerOperation) Name() string {
+	return o.op.GetName()
+}
+
+func (o *containerOperation) Poll(ctx context.Context) (bool, error) {
+	location := o.op.GetLocation()
+	if location == "" {
+		location = o.op.GetZone()
+	}
+
+	op, err := o.svc.GetOperation(ctx, &containerpb.GetOperationRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/operations/%s", o.project, location, o.op.GetName()),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if op.GetStatus() != containerpb.Operation_DONE {
+		return false, nil
+	}
+
+	if op.GetError() != nil {
+		return true, &OperationError{Name: op.GetName(), Err: errors.New(op.GetError().GetMessage())}
+	}
+
+	return true, nil
+}
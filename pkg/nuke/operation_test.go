@@ -0,0 +1,197 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+)
+
+type testOperation struct {
+	name  string
+	polls int
+
+	// doneAfter is the number of polls after which the operation is done
+	doneAfter int
+
+	// errs are the errors returned by the polls, in order
+	errs []error
+}
+
+func (o *testOperation) Name() string {
+	return o.name
+}
+
+func (o *testOperation) Poll(_ context.Context) (bool, error) {
+	o.polls++
+
+	if len(o.errs) > 0 {
+		err := o.errs[0]
+		o.errs = o.errs[1:]
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return o.polls >= o.doneAfter, nil
+}
+
+func withPollInterval(t *testing.T, interval time.Duration) {
+	t.Helper()
+
+	previous := operationPollInterval
+	operationPollInterval = interval
+	t.Cleanup(func() { operationPollInterval = previous })
+}
+
+func isWaiting(err error) bool {
+	var waitErr liberror.ErrWaitResource
+	return errors.As(err, &waitErr)
+}
+
+func TestOperationTrackerWait(t *testing.T) {
+	withPollInterval(t, 0)
+
+	failed := &OperationError{Name: "op", Err: errors.New("quota exceeded")}
+
+	cases := map[string]struct {
+		op      *testOperation
+		waiting int
+		err     error
+	}{
+		"done": {
+			op: &testOperation{name: "op", doneAfter: 1},
+		},
+		"running": {
+			op:      &testOperation{name: "op", doneAfter: 3},
+			waiting: 2,
+		},
+		"failed": {
+			op:  &testOperation{name: "op", doneAfter: 1, errs: []error{failed}},
+			err: failed,
+		},
+		"poll-error-retried": {
+			op:      &testOperation{name: "op", doneAfter: 1, errs: []error{status.Error(codes.Unavailable, "down")}},
+			waiting: 1,
+		},
+		"not-found": {
+			op: &testOperation{name: "op", errs: []error{status.Error(codes.NotFound, "gone")}},
+		},
+		"decode-error-retried": {
+			op:      &testOperation{name: "op", doneAfter: 1, errs: []error{errors.New("proto: required field missing")}},
+			waiting: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var tracker OperationTracker
+
+			var err error
+			waiting := 0
+			for range 10 {
+				err = tracker.Wait(context.TODO(), tc.op)
+				if !isWaiting(err) {
+					break
+				}
+				waiting++
+			}
+
+			assert.Equal(t, tc.waiting, waiting)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOperationTrackerNil(t *testing.T) {
+	var tracker OperationTracker
+	assert.NoError(t, tracker.Wait(context.TODO(), nil))
+	assert.NoError(t, tracker.Wait(context.TODO(), ComputeOperation(nil)))
+	assert.NoError(t, tracker.Wait(context.TODO(), SQLAdminOperation(nil, "project", nil)))
+}
+
+func TestOperationTrackerInterval(t *testing.T) {
+	withPollInterval(t, time.Hour)
+
+	var tracker OperationTracker
+	op := &testOperation{name: "op", doneAfter: 2}
+
+	// Note: the first poll is immediate, the next one is only due after the interval
+	assert.True(t, isWaiting(tracker.Wait(context.TODO(), op)))
+	assert.True(t, isWaiting(tracker.Wait(context.TODO(), op)))
+	assert.Equal(t, 1, op.polls)
+
+	// Note: a new operation, e.g. when the removal is attempted again, is polled right away
+	other := &testOperation{name: "other", doneAfter: 1}
+	assert.NoError(t, tracker.Wait(context.TODO(), other))
+	assert.Equal(t, 1, other.polls)
+}
+
+func TestOperationTrackerTimeout(t *testing.T) {
+	withPollInterval(t, 0)
+
+	opts := &ListerOpts{WaitTimeout: time.Millisecond}
+	tracker := opts.OperationTracker()
+	op := &testOperation{name: "op", doneAfter: 100}
+
+	assert.True(t, isWaiting(tracker.Wait(context.TODO(), op)))
+
+	time.Sleep(5 * time.Millisecond)
+
+	err := tracker.Wait(context.TODO(), op)
+	assert.ErrorIs(t, err, ErrOperationTimeout)
+	assert.False(t, isWaiting(err))
+}
+
+func TestWaitOperation(t *testing.T) {
+	withPollInterval(t, time.Millisecond)
+
+	op := &testOperation{name: "op", doneAfter: 3}
+	require.NoError(t, WaitOperation(context.TODO(), 0, op))
+	assert.Equal(t, 3, op.polls)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	assert.ErrorIs(t, WaitOperation(ctx, 0, &testOperation{name: "op", doneAfter: 3}), context.Canceled)
+
+	err := WaitOperation(context.TODO(), time.Millisecond, &testOperation{name: "op", doneAfter: 100})
+	assert.ErrorIs(t, err, ErrOperationTimeout)
+}
+
+func TestIsDecodeError(t *testing.T) {
+	err := protojson.Unmarshal([]byte(`{"name": 1}`), &timestamppb.Timestamp{})
+	require.Error(t, err)
+	assert.True(t, isDecodeError(err))
+	assert.True(t, isDecodeError(fmt.Errorf("poll: %w", err)))
+
+	// Note: an error that only mentions proto is not a decode error
+	assert.False(t, isDecodeError(errors.New("proto: required field missing")))
+	assert.False(t, isDecodeError(nil))
+}
+
+func TestIsAlreadyExists(t *testing.T) {
+	assert.True(t, IsAlreadyExists(status.Error(codes.AlreadyExists, "backup already exists")))
+	assert.True(t, IsAlreadyExists(fmt.Errorf("insert: %w", &googleapi.Error{Code: http.StatusConflict})))
+
+	assert.False(t, IsAlreadyExists(status.Error(codes.Aborted, "concurrent update")))
+	assert.False(t, IsAlreadyExists(&googleapi.Error{Code: http.StatusNotFound}))
+	assert.False(t, IsAlreadyExists(errors.New("already exists")))
+	assert.False(t, IsAlreadyExists(nil))
+}
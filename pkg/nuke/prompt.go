@@ -11,22 +11,77 @@ import (
 )
 
 type Prompt struct {
-	Parameters *libnuke.Parameters
-	GCP        *gcputil.GCP
+	Parameters     *libnuke.Parameters
+	GCP            *gcputil.GCP
+	OrganizationID string
+}
+
+// ProjectsPrompt is used when nuking multiple projects in a single run, the confirmation is asked once for all the
+// projects instead of once per project.
+type ProjectsPrompt struct {
+	Parameters     *libnuke.Parameters
+	Projects       []string
+	FolderID       string
+	OrganizationID string
+}
+
+// Prompt lists the projects that are going to be nuked and asks for the folder ID (or "all-projects") to continue
+func (p *ProjectsPrompt) Prompt() error {
+	promptDelay := time.Duration(p.Parameters.ForceSleep) * time.Second
+
+	confirmKind, confirmID := "folder ID", p.FolderID
+	if p.FolderID == "" {
+		confirmKind, confirmID = "the text", "all-projects"
+	}
+
+	fmt.Printf("Do you really want to nuke the following %d project(s)?\n", len(p.Projects))
+	for _, projectID := range p.Projects {
+		fmt.Printf("  - %s\n", projectID)
+	}
+
+	if p.OrganizationID != "" {
+		fmt.Printf("The organization with the ID '%s' will also be nuked.\n", p.OrganizationID)
+	}
+
+	if p.Parameters.Force {
+		fmt.Printf("Waiting %v before continuing.\n", promptDelay)
+		time.Sleep(promptDelay)
+	} else {
+		fmt.Printf("Do you want to continue? Enter %s '%s' to continue.\n", confirmKind, confirmID)
+		if err := utils.Prompt(confirmID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // Prompt is the actual function called by the libnuke process during it's run
 func (p *Prompt) Prompt() error {
 	promptDelay := time.Duration(p.Parameters.ForceSleep) * time.Second
 
-	fmt.Printf("Do you really want to nuke the project with "+
-		"the ID '%s'?\n", p.GCP.ID())
+	// Note: when an organization is targeted, it has the larger blast radius so its ID is required to confirm
+	confirmKind, confirmID := "project", p.GCP.ID()
+	switch {
+	case p.OrganizationID != "" && p.GCP.ID() != "":
+		confirmKind, confirmID = "organization", p.OrganizationID
+		fmt.Printf("Do you really want to nuke the project with the ID '%s' and "+
+			"the organization with the ID '%s'?\n", p.GCP.ID(), p.OrganizationID)
+	case p.OrganizationID != "":
+		confirmKind, confirmID = "organization", p.OrganizationID
+		fmt.Printf("Do you really want to nuke the organization with "+
+			"the ID '%s'?\n", p.OrganizationID)
+	default:
+		fmt.Printf("Do you really want to nuke the project with "+
+			"the ID '%s'?\n", p.GCP.ID())
+	}
+
 	if p.Parameters.Force {
 		fmt.Printf("Waiting %v before continuing.\n", promptDelay)
 		time.Sleep(promptDelay)
 	} else {
-		fmt.Printf("Do you want to continue? Enter project ID to continue.\n")
-		if err := utils.Prompt(p.GCP.ID()); err != nil {
+		fmt.Printf("Do you want to continue? Enter %s ID to continue.\n", confirmKind)
+		if err := utils.Prompt(confirmID); err != nil {
 			return err
 		}
 	}
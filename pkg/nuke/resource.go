@@ -3,12 +3,15 @@ package nuke
 import (
 	"fmt"
 	"slices"
+	"time"
 
 	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/api/option"
 
 	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
 )
 
 type Geography string
@@ -21,14 +24,36 @@ const (
 	Global   Geography = "global"
 	Regional Geography = "regional"
 	Zonal    Geography = "zonal"
+
+	// MultiRegional is the geography of the resources that are in a region, a multi-region or a dual-region, they are
+	// listed for the regions and for the pseudo-regions of the multi-regions and dual-regions, see gcputil.LocationRegion
+	MultiRegional Geography = "multi-regional"
 )
 
 type ListerOpts struct {
+	Organization  *string
 	Project       *string
 	Region        *string
 	Zones         []string
 	EnabledAPIs   []string
 	ClientOptions []option.ClientOption
+
+	// GRPCClientOptions are the client options for the gRPC clients, ClientOptions are for the REST clients only
+	GRPCClientOptions []option.ClientOption
+
+	// EmulatorClientOptions are the client options for the gRPC clients of the APIs that are called on an emulator, by
+	// API, see GRPCClientOptionsFor
+	EmulatorClientOptions map[string][]option.ClientOption
+
+	// ListingErrors collects the errors of the listers that could not list all the resources, it is optional
+	ListingErrors *ListingErrors
+
+	// Cache is the cache of the collections that are shared by the listers of a run, see Cached, it is optional
+	Cache *ListingCache
+
+	// WaitTimeout is how long the operations started by the removal of the resources are waited for before the
+	// removal fails, 0 means no limit
+	WaitTimeout time.Duration
 }
 
 func (o *ListerOpts) BeforeList(geo Geography, service string, resourceNames ...string) error {
@@ -45,12 +70,19 @@ func (o *ListerOpts) BeforeList(geo Geography, service string, resourceNames ...
 	if geo == Global && *o.Region != "global" {
 		log.Trace("before-list: skipping resource, global")
 		return liberror.ErrSkipRequest("resource is global")
-	} else if geo == Regional && *o.Region == "global" {
+	} else if geo == Regional && (*o.Region == "global" || gcputil.IsPseudoRegion(*o.Region)) {
 		log.Trace("before-list: skipping resource, regional")
 		return liberror.ErrSkipRequest("resource is regional")
+	} else if geo == MultiRegional && *o.Region == "global" {
+		log.Trace("before-list: skipping resource, multi-regional")
+		return liberror.ErrSkipRequest("resource is multi-regional")
+	} else if geo == MultiRegional && !o.SupportsLocation(service) {
+		log.Trace("before-list: skipping resource, location not supported")
+		return liberror.ErrSkipRequest(fmt.Sprintf("api '%s' has no location for '%s'", service, *o.Region))
 	}
 
-	if !slices.Contains(o.EnabledAPIs, service) {
+	// Note: organization scoped resources are not tied to a project, so there is no list of enabled APIs to check
+	if o.Organization == nil && !slices.Contains(o.EnabledAPIs, service) {
 		log.Warn("before-list: skipping resource, api not enabled")
 		return liberror.ErrSkipRequest(fmt.Sprintf("api '%s' not enabled", service))
 	}
@@ -59,3 +91,36 @@ func (o *ListerOpts) BeforeList(geo Geography, service string, resourceNames ...
 
 	return nil
 }
+
+// OperationTracker returns the tracker of the operation started by the removal of a resource, with the wait timeout
+func (o *ListerOpts) OperationTracker() OperationTracker {
+	return OperationTracker{timeout: o.WaitTimeout}
+}
+
+// GRPCClientOptionsFor returns the client options for a gRPC client of the API, the ones of its emulator when it is
+// called on one. The listers of the APIs that have an emulator, see gcputil.Emulators, must use it.
+func (o *ListerOpts) GRPCClientOptionsFor(service string) []option.ClientOption {
+	if opts, ok := o.EmulatorClientOptions[service]; ok {
+		return opts
+	}
+
+	return o.GRPCClientOptions
+}
+
+// Emulated returns true when the APIs are called on emulators
+func (o *ListerOpts) Emulated() bool {
+	return len(o.EmulatorClientOptions) > 0
+}
+
+// Location returns the location of the region for the API, for a pseudo-region it is the location code of the API,
+// e.g. "europe" for "multi-region:eu" with KMS, see gcputil.APILocation
+func (o *ListerOpts) Location(service string) string {
+	location, _ := gcputil.APILocation(service, *o.Region)
+	return location
+}
+
+// SupportsLocation returns false when the region is a pseudo-region the API does not support
+func (o *ListerOpts) SupportsLocation(service string) bool {
+	_, ok := gcputil.APILocation(service, *o.Region)
+	return ok
+}
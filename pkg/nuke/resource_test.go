@@ -0,0 +1,49 @@
+package nuke
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+)
+
+func TestBeforeList(t *testing.T) {
+	cases := []struct {
+		geo     Geography
+		service string
+		region  string
+		skipped bool
+	}{
+		{geo: Global, service: "storage.googleapis.com", region: "global"},
+		{geo: Global, service: "storage.googleapis.com", region: "us-east1", skipped: true},
+		{geo: Regional, service: "compute.googleapis.com", region: "us-east1"},
+		{geo: Regional, service: "compute.googleapis.com", region: "global", skipped: true},
+		{geo: Regional, service: "compute.googleapis.com", region: "multi-region:us", skipped: true},
+		{geo: MultiRegional, service: "storage.googleapis.com", region: "us-east1"},
+		{geo: MultiRegional, service: "storage.googleapis.com", region: "dual-region:nam4"},
+		{geo: MultiRegional, service: "storage.googleapis.com", region: "global", skipped: true},
+		{geo: MultiRegional, service: "bigquery.googleapis.com", region: "dual-region:nam4", skipped: true},
+		{geo: Regional, service: "disabled.googleapis.com", region: "us-east1", skipped: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.geo)+"/"+tc.service+"/"+tc.region, func(t *testing.T) {
+			opts := &ListerOpts{
+				Project:     ptr.String("project"),
+				Region:      ptr.String(tc.region),
+				EnabledAPIs: []string{"bigquery.googleapis.com", "compute.googleapis.com", "storage.googleapis.com"},
+			}
+
+			err := opts.BeforeList(tc.geo, tc.service)
+			if !tc.skipped {
+				assert.NoError(t, err)
+				return
+			}
+
+			var skipErr liberror.ErrSkipRequest
+			assert.ErrorAs(t, err, &skipErr)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package nuke
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Suspender is implemented by the resources that can stop costing without being removed, e.g. the compute instances
+// are stopped and the node pools of the GKE clusters are scaled down to zero. Suspend returns what the resource looked
+// like before, Resume restores it from that state. A resource that was only partly suspended returns its state along
+// with the error, so that it can still be resumed.
+type Suspender interface {
+	Suspend(ctx context.Context) (SuspendedState, error)
+	Resume(ctx context.Context, state SuspendedState) error
+}
+
+// SuspendedState is what a resource looked like before it was suspended, as JSON, it is kept in the state file until
+// the resource is resumed. Each resource type has its own state, see NewSuspendedState and Decode.
+type SuspendedState json.RawMessage
+
+// NewSuspendedState returns the state of the value, e.g. a struct with the size of a resource before it was suspended
+func NewSuspendedState(value interface{}) (SuspendedState, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Decode decodes the state into the value, the same type of value it was created from
+func (s SuspendedState) Decode(value interface{}) error {
+	return json.Unmarshal(s, value)
+}
+
+// MarshalJSON keeps the state as is in the state file
+func (s SuspendedState) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	return s, nil
+}
+
+// UnmarshalJSON keeps the state as is, it is decoded by the resource when it is resumed
+func (s *SuspendedState) UnmarshalJSON(data []byte) error {
+	*s = append((*s)[0:0], data...)
+	return nil
+}
+
+// notSuspendedError is the error of a resource that was left as is, see NotSuspended
+type notSuspendedError struct {
+	reason string
+}
+
+func (e *notSuspendedError) Error() string {
+	return e.reason
+}
+
+// NotSuspended returns the error of Suspend when there is nothing to suspend, e.g. the instance is already stopped.
+// Nothing is recorded for the resource, so it is left as is when it is resumed as well.
+func NotSuspended(reason string) error {
+	return &notSuspendedError{reason: reason}
+}
+
+// IsNotSuspended returns the reason the resource was left as is when the error is one of NotSuspended
+func IsNotSuspended(err error) (string, bool) {
+	var notSuspended *notSuspendedError
+	if errors.As(err, &notSuspended) {
+		return notSuspended.reason, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,48 @@
+package nuke
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuspendedState(t *testing.T) {
+	type poolState struct {
+		Name      string `json:"name"`
+		NodeCount int32  `json:"nodeCount"`
+	}
+
+	state, err := NewSuspendedState([]*poolState{{Name: "default-pool", NodeCount: 3}})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(struct {
+		State SuspendedState `json:"state"`
+	}{state})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"state": [{"name": "default-pool", "nodeCount": 3}]}`, string(data))
+
+	decoded := struct {
+		State SuspendedState `json:"state"`
+	}{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	pools := make([]*poolState, 0)
+	require.NoError(t, decoded.State.Decode(&pools))
+	assert.Equal(t, []*poolState{{Name: "default-pool", NodeCount: 3}}, pools)
+}
+
+func TestNotSuspended(t *testing.T) {
+	reason, ok := IsNotSuspended(fmt.Errorf("instance: %w", NotSuspended("the instance is TERMINATED")))
+	assert.True(t, ok)
+	assert.Equal(t, "the instance is TERMINATED", reason)
+
+	_, ok = IsNotSuspended(errors.New("permission denied"))
+	assert.False(t, ok)
+
+	_, ok = IsNotSuspended(nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,204 @@
+// Package plan provides the plan of a run, the exact set of resources a dry run would remove. A plan is written by
+// the plan command so that it can be reviewed, and later applied by the apply command which removes only the resources
+// that are part of the plan.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// FormatVersion is the version of the format of the plan file, it is bumped on incompatible changes
+const FormatVersion = 1
+
+// ReasonNotInPlan is the reason given to the resources that are filtered because they are not part of the plan
+const ReasonNotInPlan = "not in plan"
+
+// Plan is the set of resources that a dry run would remove for a project, an organization or both
+type Plan struct {
+	FormatVersion int         `json:"formatVersion"`
+	Version       string      `json:"version"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	Project       string      `json:"project,omitempty"`
+	Organization  string      `json:"organization,omitempty"`
+	Resources     []*Resource `json:"resources"`
+}
+
+// Resource is a single resource that is part of the plan, it is identified by its type, owner, name and creation
+// time, so that a resource recreated with the same name after the plan is not part of it. The creation time is empty
+// for the resource types that do not have one. The properties are informational, they are what the resource looked
+// like when the plan was created.
+type Resource struct {
+	Type       string            `json:"type"`
+	Owner      string            `json:"owner"`
+	Name       string            `json:"name"`
+	CreatedAt  string            `json:"createdAt,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// String returns the resource in the form "type - owner - name"
+func (r *Resource) String() string {
+	return fmt.Sprintf("%s - %s - %s", r.Type, r.Owner, r.Name)
+}
+
+func (r *Resource) key() string {
+	return resourceKey(r.Type, r.Owner, r.Name, r.CreatedAt)
+}
+
+func resourceKey(resourceType, owner, name, createdAt string) string {
+	return fmt.Sprintf("%s|%s|%s|%s", resourceType, owner, name, createdAt)
+}
+
+func itemKey(item *queue.Item) string {
+	return resourceKey(item.Type, item.Owner, nuke.ItemName(item), itemCreatedAt(item))
+}
+
+// itemCreatedAt returns the CreatedAt property of the resource of the item, empty when it has none
+func itemCreatedAt(item *queue.Item) string {
+	createdAt, err := item.GetProperty(nuke.CreatedAtProperty)
+	if err != nil {
+		return ""
+	}
+
+	return createdAt
+}
+
+// New returns a new, empty, plan for the project and organization
+func New(version, project, organization string) *Plan {
+	return &Plan{
+		FormatVersion: FormatVersion,
+		Version:       version,
+		CreatedAt:     time.Now().UTC(),
+		Project:       project,
+		Organization:  organization,
+		Resources:     make([]*Resource, 0),
+	}
+}
+
+// Load reads the plan from the file at the path
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plan{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("unable to parse plan %s: %w", path, err)
+	}
+
+	if p.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("plan %s has format version %d, only version %d is supported",
+			path, p.FormatVersion, FormatVersion)
+	}
+
+	if p.Project == "" && p.Organization == "" {
+		return nil, fmt.Errorf("plan %s has neither a project nor an organization", path)
+	}
+
+	return p, nil
+}
+
+// Write writes the plan to the file at the path, the resources are sorted by type, owner and name so that the plan
+// can be reviewed and compared easily
+func (p *Plan) Write(path string) error {
+	sort.SliceStable(p.Resources, func(i, j int) bool {
+		a, b := p.Resources[i], p.Resources[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Name < b.Name
+	})
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// AddItems adds the items of the queue that would be removed to the plan, filtered items are left out
+func (p *Plan) AddItems(items []*queue.Item) {
+	for _, item := range items {
+		if !wouldRemove(item) {
+			continue
+		}
+
+		p.Resources = append(p.Resources, &Resource{
+			Type:       item.Type,
+			Owner:      item.Owner,
+			Name:       nuke.ItemName(item),
+			CreatedAt:  itemCreatedAt(item),
+			Properties: nuke.ItemProperties(item),
+		})
+	}
+}
+
+// ResourceTypes returns the sorted, unique, resource types of the resources of the plan
+func (p *Plan) ResourceTypes() []string {
+	resourceTypes := make([]string, 0)
+	for _, r := range p.Resources {
+		resourceTypes = append(resourceTypes, r.Type)
+	}
+
+	sort.Strings(resourceTypes)
+
+	unique := resourceTypes[:0]
+	for i, resourceType := range resourceTypes {
+		if i == 0 || resourceType != resourceTypes[i-1] {
+			unique = append(unique, resourceType)
+		}
+	}
+
+	return unique
+}
+
+// Enforce filters every item of the queue that would be removed but is not part of the plan, e.g. a resource recreated
+// with the same name since the plan, these items are returned. It also returns the resources of the plan that were not
+// found in the queue, or that would not be removed anymore (e.g. because the configuration now filters them).
+func (p *Plan) Enforce(items []*queue.Item) (excluded []*queue.Item, missing []*Resource) {
+	planned := make(map[string]bool, len(p.Resources))
+	for _, r := range p.Resources {
+		planned[r.key()] = true
+	}
+
+	found := make(map[string]bool, len(p.Resources))
+	for _, item := range items {
+		if !wouldRemove(item) {
+			continue
+		}
+
+		key := itemKey(item)
+		if !planned[key] {
+			item.State = queue.ItemStateFiltered
+			item.Reason = ReasonNotInPlan
+			excluded = append(excluded, item)
+			continue
+		}
+
+		found[key] = true
+	}
+
+	for _, r := range p.Resources {
+		if !found[r.key()] {
+			missing = append(missing, r)
+		}
+	}
+
+	return excluded, missing
+}
+
+func wouldRemove(item *queue.Item) bool {
+	return item.GetState() == queue.ItemStateNew || item.GetState() == queue.ItemStateNewDependency
+}
@@ -0,0 +1,136 @@
+package plan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testResource struct {
+	name      string
+	createdAt string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func (r *testResource) Properties() types.Properties {
+	properties := types.NewProperties().Set("Name", r.name)
+	if r.createdAt != "" {
+		properties.Set("CreatedAt", r.createdAt)
+	}
+
+	return properties
+}
+
+func newItem(resourceType, owner, name string, state queue.ItemState) *queue.Item {
+	return &queue.Item{Resource: &testResource{name: name}, Type: resourceType, Owner: owner, State: state}
+}
+
+func TestPlanWriteLoad(t *testing.T) {
+	p := New("1.0.0", "test-project", "")
+	p.AddItems([]*queue.Item{
+		newItem("StorageBucket", "global", "bucket-b", queue.ItemStateNew),
+		newItem("ComputeDisk", "us-east1", "disk", queue.ItemStateNewDependency),
+		newItem("StorageBucket", "global", "bucket-a", queue.ItemStateNew),
+		newItem("StorageBucket", "global", "filtered", queue.ItemStateFiltered),
+	})
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, p.Write(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-project", loaded.Project)
+	assert.Equal(t, []*Resource{
+		{Type: "ComputeDisk", Owner: "us-east1", Name: "disk", Properties: map[string]string{"Name": "disk"}},
+		{Type: "StorageBucket", Owner: "global", Name: "bucket-a", Properties: map[string]string{"Name": "bucket-a"}},
+		{Type: "StorageBucket", Owner: "global", Name: "bucket-b", Properties: map[string]string{"Name": "bucket-b"}},
+	}, loaded.Resources)
+	assert.Equal(t, []string{"ComputeDisk", "StorageBucket"}, loaded.ResourceTypes())
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := map[string]string{
+		"not-json":       "plan",
+		"format-version": `{"formatVersion": 2, "project": "test-project"}`,
+		"no-target":      `{"formatVersion": 1}`,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".json")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	p := New("1.0.0", "test-project", "")
+	p.Resources = []*Resource{
+		{Type: "StorageBucket", Owner: "global", Name: "planned"},
+		{Type: "StorageBucket", Owner: "global", Name: "gone"},
+		{Type: "ComputeDisk", Owner: "us-east1", Name: "disk"},
+		{Type: "ComputeDisk", Owner: "us-east1", Name: "filtered-now"},
+	}
+
+	planned := newItem("StorageBucket", "global", "planned", queue.ItemStateNew)
+	created := newItem("StorageBucket", "global", "created-after-plan", queue.ItemStateNew)
+	otherRegion := newItem("ComputeDisk", "us-west1", "disk", queue.ItemStateNew)
+	disk := newItem("ComputeDisk", "us-east1", "disk", queue.ItemStateNewDependency)
+	filteredNow := newItem("ComputeDisk", "us-east1", "filtered-now", queue.ItemStateFiltered)
+
+	excluded, missing := p.Enforce([]*queue.Item{planned, created, otherRegion, disk, filteredNow})
+
+	assert.Equal(t, []*queue.Item{created, otherRegion}, excluded)
+	assert.Equal(t, queue.ItemStateFiltered, created.State)
+	assert.Equal(t, ReasonNotInPlan, created.Reason)
+	assert.Equal(t, queue.ItemStateFiltered, otherRegion.State)
+
+	assert.Equal(t, queue.ItemStateNew, planned.State)
+	assert.Equal(t, queue.ItemStateNewDependency, disk.State)
+
+	assert.Equal(t, []*Resource{p.Resources[1], p.Resources[3]}, missing)
+}
+
+func TestEnforceRecreated(t *testing.T) {
+	p := New("1.0.0", "test-project", "")
+	p.AddItems([]*queue.Item{
+		{
+			Resource: &testResource{name: "bucket", createdAt: "2024-06-01T10:00:00Z"},
+			Type:     "StorageBucket", Owner: "global", State: queue.ItemStateNew,
+		},
+	})
+	require.Len(t, p.Resources, 1)
+	assert.Equal(t, "2024-06-01T10:00:00Z", p.Resources[0].CreatedAt)
+
+	// Note: the bucket was removed and created again with the same name between the plan and the apply
+	recreated := &queue.Item{
+		Resource: &testResource{name: "bucket", createdAt: "2024-06-02T10:00:00Z"},
+		Type:     "StorageBucket", Owner: "global", State: queue.ItemStateNew,
+	}
+
+	excluded, missing := p.Enforce([]*queue.Item{recreated})
+
+	assert.Equal(t, []*queue.Item{recreated}, excluded)
+	assert.Equal(t, ReasonNotInPlan, recreated.Reason)
+	assert.Equal(t, p.Resources, missing)
+}
@@ -0,0 +1,141 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// encodeJUnit writes the report as JUnit XML, with a test suite per run and a test case per resource. Removed
+// resources and resources that would be removed pass, filtered resources are skipped, failed resources and resources
+// that were still waiting for removal when the run ended fail. A run that ended with an error has an additional test
+// case with the error, and so does every resource type that could not be listed completely.
+func (r *Report) encodeJUnit(w io.Writer) error {
+	suites := junitTestSuites{
+		Name: fmt.Sprintf("gcp-nuke %s", r.Version),
+		Time: fmt.Sprintf("%.3f", r.Duration),
+	}
+
+	for _, run := range r.Runs {
+		suite := junitTestSuite{
+			Name:      run.Name(),
+			Timestamp: r.StartedAt.Format("2006-01-02T15:04:05"),
+			Properties: []junitProperty{
+				{Name: "dry-run", Value: fmt.Sprintf("%t", r.DryRun)},
+			},
+		}
+
+		for _, region := range run.Regions {
+			suite.Properties = append(suite.Properties, junitProperty{Name: "region", Value: region})
+		}
+
+		if run.Skipped != "" {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      run.Name(),
+				ClassName: "run",
+				Skipped:   &junitMessage{Message: run.Skipped},
+			})
+			suite.Skipped++
+		}
+
+		if run.Error != "" {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      run.Name(),
+				ClassName: "run",
+				Error:     &junitMessage{Message: run.Error},
+			})
+			suite.Errors++
+		}
+
+		for _, listing := range run.IncompleteListings {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      fmt.Sprintf("%s - listing", listing.Type),
+				ClassName: listing.Owner,
+				Error:     &junitMessage{Message: fmt.Sprintf("incomplete listing (%s)", listing.Kind), Body: listing.Error},
+			})
+			suite.Errors++
+		}
+
+		for _, res := range run.Resources {
+			tc := junitTestCase{
+				Name:      fmt.Sprintf("%s - %s", res.Type, res.Name),
+				ClassName: res.Owner,
+			}
+
+			switch res.State {
+			case StateFiltered:
+				tc.Skipped = &junitMessage{Message: res.Reason}
+				suite.Skipped++
+			case StateFailed:
+				tc.Failure = &junitMessage{Message: "failed", Body: res.Reason}
+				suite.Failures++
+			case StateWaiting:
+				tc.Failure = &junitMessage{Message: "still waiting for removal when the run ended", Body: res.Reason}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+
+		suite.Tests = len(suite.TestCases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
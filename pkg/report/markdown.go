@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// encodeMarkdown writes the report as Markdown, meant to be posted as a comment, for example on a pull request. The
+// properties of the resources are left out to keep it readable, they are only part of the JSON report.
+func (r *Report) encodeMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	mode := "removal"
+	if r.DryRun {
+		mode = "dry run"
+	}
+
+	b.WriteString("# gcp-nuke report\n\n")
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Version | %s |\n", escapeMarkdown(r.Version))
+	fmt.Fprintf(&b, "| Run ID | %s |\n", escapeMarkdown(r.RunID))
+	fmt.Fprintf(&b, "| Mode | %s |\n", mode)
+	fmt.Fprintf(&b, "| Started | %s |\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "| Duration | %s |\n", time.Duration(r.Duration*float64(time.Second)).Round(time.Second))
+	fmt.Fprintf(&b, "| Throttled API calls | %d |\n", r.ThrottledTotal())
+
+	for _, run := range r.Runs {
+		fmt.Fprintf(&b, "\n## %s\n\n", escapeMarkdown(run.Name()))
+
+		if len(run.Regions) > 0 {
+			fmt.Fprintf(&b, "Regions: %s\n\n", escapeMarkdown(strings.Join(run.Regions, ", ")))
+		}
+
+		if run.Skipped != "" {
+			fmt.Fprintf(&b, "Skipped: %s\n", escapeMarkdown(run.Skipped))
+			continue
+		}
+
+		if run.Error != "" {
+			fmt.Fprintf(&b, "**Error:** %s\n\n", escapeMarkdown(run.Error))
+		}
+
+		if len(run.IncompleteListings) > 0 {
+			b.WriteString("**Incomplete listing**, the resources of these types may be missing:\n\n")
+			b.WriteString("| Type | Owner | Kind | Error |\n|---|---|---|---|\n")
+			for _, listing := range run.IncompleteListings {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+					escapeMarkdown(listing.Type), escapeMarkdown(listing.Owner), listing.Kind,
+					escapeMarkdown(listing.Error))
+			}
+			b.WriteString("\n")
+		}
+
+		counts := make([]string, 0, len(States))
+		for _, state := range States {
+			if count := run.Count(state); count > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", count, state))
+			}
+		}
+
+		if len(counts) == 0 {
+			b.WriteString("No resources found.\n")
+			continue
+		}
+
+		fmt.Fprintf(&b, "%d resource(s): %s\n\n", len(run.Resources), strings.Join(counts, ", "))
+
+		b.WriteString("| State | Type | Owner | Name | Reason |\n|---|---|---|---|---|\n")
+		for _, res := range run.Resources {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				res.State, escapeMarkdown(res.Type), escapeMarkdown(res.Owner),
+				escapeMarkdown(res.Name), escapeMarkdown(res.Reason))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeMarkdown escapes the characters that would break a table cell
+func escapeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
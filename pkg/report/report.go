@@ -0,0 +1,289 @@
+// Package report provides a machine-readable report of a run, with every resource that was discovered and the state
+// it ended up in. The report can be written as JSON, JUnit XML or Markdown.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// Format is the format the report is written in
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatJUnit    Format = "junit"
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats are all the supported formats
+var Formats = []Format{FormatJSON, FormatJUnit, FormatMarkdown}
+
+// ParseFormat returns the Format for the name, or an error if the format is not supported
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported report format %q, must be one of %v", name, Formats)
+}
+
+// State is the final state of a resource in the report
+type State string
+
+const (
+	StateFiltered    State = "filtered"
+	StateWouldRemove State = "would-remove"
+	StateRemoved     State = "removed"
+	StateFailed      State = "failed"
+	StateWaiting     State = "waiting"
+
+	// StateMarked is the state of the resources marked for removal by a run in mark mode, see nuke.ModeMark
+	StateMarked State = "marked"
+
+	// StateSuspended is the state of the resources suspended by a run in suspend mode, see nuke.ModeSuspend
+	StateSuspended State = "suspended"
+
+	// StateResumed is the state of the resources resumed by a run in resume mode, see nuke.ModeResume
+	StateResumed State = "resumed"
+)
+
+// States are all the states in the order they are summarized
+var States = []State{
+	StateWouldRemove, StateRemoved, StateMarked, StateSuspended, StateResumed, StateFiltered, StateWaiting, StateFailed,
+}
+
+// stateFromItem maps the state of an item of the libnuke queue to the state in the report
+func stateFromItem(state queue.ItemState) State {
+	switch state {
+	case queue.ItemStateFiltered:
+		return StateFiltered
+	case queue.ItemStateNew, queue.ItemStateNewDependency:
+		return StateWouldRemove
+	case queue.ItemStateFinished:
+		return StateRemoved
+	case queue.ItemStateFailed:
+		return StateFailed
+	default:
+		return StateWaiting
+	}
+}
+
+// Report is the outcome of a run of gcp-nuke, with a Run for every project or organization that was nuked
+type Report struct {
+	Version    string    `json:"version"`
+	RunID      string    `json:"runId"`
+	DryRun     bool      `json:"dryRun"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Duration   float64   `json:"durationSeconds"`
+	Runs       []*Run    `json:"runs"`
+
+	// Throttled is the number of API calls that were throttled by GCP, by API
+	Throttled map[string]int `json:"throttled,omitempty"`
+}
+
+// Run is the outcome of nuking a single project or organization
+type Run struct {
+	Project      string      `json:"project,omitempty"`
+	Organization string      `json:"organization,omitempty"`
+	Regions      []string    `json:"regions,omitempty"`
+	Skipped      string      `json:"skipped,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	Resources    []*Resource `json:"resources"`
+
+	// IncompleteListings are the resource types that could not be listed completely, their resources are missing
+	IncompleteListings []*IncompleteListing `json:"incompleteListings,omitempty"`
+}
+
+// IncompleteListing is a resource type that could not be listed completely in a region or an organization
+type IncompleteListing struct {
+	Type  string `json:"type"`
+	Owner string `json:"owner"`
+	Kind  string `json:"kind"`
+	Error string `json:"error"`
+}
+
+// Resource is a single resource that was discovered during the run
+type Resource struct {
+	Type       string            `json:"type"`
+	Owner      string            `json:"owner"`
+	Name       string            `json:"name,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	State      State             `json:"state"`
+	Reason     string            `json:"reason,omitempty"`
+}
+
+// New returns a new report for a run that starts now
+func New(version string, dryRun bool) *Report {
+	return &Report{
+		Version:   version,
+		RunID:     nuke.RunID(),
+		DryRun:    dryRun,
+		StartedAt: time.Now().UTC(),
+		Runs:      make([]*Run, 0),
+	}
+}
+
+// AddRun adds the outcome of a run to the report
+func (r *Report) AddRun(run *Run) {
+	if run.Resources == nil {
+		run.Resources = make([]*Resource, 0)
+	}
+
+	r.Runs = append(r.Runs, run)
+}
+
+// Finish records the end of the run, it must be called before the report is written. The end is only recorded once,
+// the later calls keep it.
+func (r *Report) Finish() {
+	if !r.FinishedAt.IsZero() {
+		return
+	}
+
+	r.FinishedAt = time.Now().UTC()
+	r.Duration = r.FinishedAt.Sub(r.StartedAt).Seconds()
+}
+
+// ThrottledTotal returns the number of API calls that were throttled by GCP for all APIs
+func (r *Report) ThrottledTotal() int {
+	total := 0
+	for _, count := range r.Throttled {
+		total += count
+	}
+
+	return total
+}
+
+// Name returns the project or organization the run was for
+func (r *Run) Name() string {
+	if r.Project != "" && r.Organization != "" {
+		return fmt.Sprintf("%s (organizations/%s)", r.Project, r.Organization)
+	}
+
+	if r.Organization != "" {
+		return fmt.Sprintf("organizations/%s", r.Organization)
+	}
+
+	return r.Project
+}
+
+// SetError records the error the run ended with, if any
+func (r *Run) SetError(err error) {
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// AddNuke adds the regions the project was scanned in and every item of the queue of the instance of libnuke
+func (r *Run) AddNuke(n *libnuke.Nuke, projectScope registry.Scope) {
+	for _, s := range n.Scanners[projectScope] {
+		r.Regions = append(r.Regions, s.Owner)
+	}
+
+	for _, item := range n.Queue.GetItems() {
+		r.Resources = append(r.Resources, newResource(item))
+	}
+}
+
+// SetRemovedState sets the state of the resources that would be reported as removed, for the runs in a mode that does
+// not remove them, e.g. the resources were marked by a run in mark mode
+func (r *Run) SetRemovedState(state State) {
+	for _, res := range r.Resources {
+		if res.State == StateRemoved {
+			res.State = state
+		}
+	}
+}
+
+// AddListingErrors adds the resource types that could not be listed completely
+func (r *Run) AddListingErrors(errs []*nuke.ListError) {
+	for _, err := range errs {
+		r.IncompleteListings = append(r.IncompleteListings, &IncompleteListing{
+			Type:  err.ResourceType,
+			Owner: err.Owner,
+			Kind:  string(err.Kind),
+			Error: err.Err.Error(),
+		})
+	}
+}
+
+// Count returns the number of resources in the state
+func (r *Run) Count(state State) int {
+	count := 0
+	for _, res := range r.Resources {
+		if res.State == state {
+			count++
+		}
+	}
+
+	return count
+}
+
+func newResource(item *queue.Item) *Resource {
+	return &Resource{
+		Type:       item.Type,
+		Owner:      item.Owner,
+		Name:       nuke.ItemName(item),
+		Properties: nuke.ItemProperties(item),
+		State:      stateFromItem(item.GetState()),
+		Reason:     item.GetReason(),
+	}
+}
+
+// Write writes the report to the file at the path in the format
+func (r *Report) Write(path string, format Format) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Encode(f, format); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Encode writes the report to w in the format, resources are sorted by type, owner and name so that reports of
+// multiple runs can be compared with each other
+func (r *Report) Encode(w io.Writer, format Format) error {
+	for _, run := range r.Runs {
+		sort.SliceStable(run.Resources, func(i, j int) bool {
+			a, b := run.Resources[i], run.Resources[j]
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+			if a.Owner != b.Owner {
+				return a.Owner < b.Owner
+			}
+			return a.Name < b.Name
+		})
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatJUnit:
+		return r.encodeJUnit(w)
+	case FormatMarkdown:
+		return r.encodeMarkdown(w)
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
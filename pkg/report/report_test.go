@@ -0,0 +1,177 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewProperties().Set("Name", r.name)
+}
+
+func newTestReport(t *testing.T) *Report {
+	t.Helper()
+
+	n := libnuke.New(&libnuke.Parameters{}, nil, nil)
+	for _, region := range []string{"global", "us-east1"} {
+		s, err := scanner.New(&scanner.Config{Owner: region, ResourceTypes: []string{"TestResource"}})
+		require.NoError(t, err)
+		require.NoError(t, n.RegisterScanner("project", s))
+	}
+
+	n.Queue.Items = []*queue.Item{
+		{Resource: &testResource{name: "b"}, Type: "TestResource", Owner: "us-east1", State: queue.ItemStateFinished},
+		{Resource: &testResource{name: "a"}, Type: "TestResource", Owner: "us-east1", State: queue.ItemStateFiltered,
+			Reason: "filtered by config"},
+		{Resource: &testResource{name: "c|d"}, Type: "TestResource", Owner: "global", State: queue.ItemStateFailed,
+			Reason: "permission denied"},
+		{Resource: &testResource{name: "e"}, Type: "TestResource", Owner: "global", State: queue.ItemStateWaiting},
+	}
+
+	rep := New("1.0.0", false)
+
+	run := &Run{Project: "test-project"}
+	run.AddNuke(n, "project")
+	run.SetError(errors.New("failed"))
+	run.AddListingErrors([]*nuke.ListError{{
+		ResourceType: "OtherResource",
+		Owner:        "us-east1",
+		Kind:         nuke.ListErrorPermissionDenied,
+		Err:          errors.New("permission denied"),
+	}})
+	rep.AddRun(run)
+
+	rep.AddRun(&Run{Project: "other-project", Skipped: "blocklisted"})
+	rep.Throttled = map[string]int{"compute.googleapis.com": 2, "storage.googleapis.com": 1}
+
+	rep.Finish()
+
+	return rep
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("junit")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJUnit, format)
+
+	_, err = ParseFormat("yaml")
+	assert.Error(t, err)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestReport(t).Encode(&buf, FormatJSON))
+
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+
+	assert.False(t, decoded.DryRun)
+	assert.Equal(t, "1.0.0", decoded.Version)
+	require.Len(t, decoded.Runs, 2)
+
+	run := decoded.Runs[0]
+	assert.Equal(t, "test-project", run.Project)
+	assert.Equal(t, []string{"global", "us-east1"}, run.Regions)
+	assert.Equal(t, "failed", run.Error)
+	require.Len(t, run.Resources, 4)
+
+	// Note: resources are sorted by type, owner and name
+	assert.Equal(t, &Resource{
+		Type:       "TestResource",
+		Owner:      "global",
+		Name:       "c|d",
+		Properties: map[string]string{"Name": "c|d"},
+		State:      StateFailed,
+		Reason:     "permission denied",
+	}, run.Resources[0])
+	assert.Equal(t, StateWaiting, run.Resources[1].State)
+	assert.Equal(t, StateFiltered, run.Resources[2].State)
+	assert.Equal(t, StateRemoved, run.Resources[3].State)
+
+	assert.Equal(t, []*IncompleteListing{
+		{Type: "OtherResource", Owner: "us-east1", Kind: "permission-denied", Error: "permission denied"},
+	}, run.IncompleteListings)
+
+	assert.Equal(t, "blocklisted", decoded.Runs[1].Skipped)
+	assert.Equal(t, 3, decoded.ThrottledTotal())
+}
+
+func TestEncodeJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestReport(t).Encode(&buf, FormatJUnit))
+
+	var decoded junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+
+	assert.Equal(t, 7, decoded.Tests)
+	assert.Equal(t, 2, decoded.Failures)
+	assert.Equal(t, 2, decoded.Errors)
+	assert.Equal(t, 2, decoded.Skipped)
+
+	require.Len(t, decoded.Suites, 2)
+	assert.Equal(t, "test-project", decoded.Suites[0].Name)
+	assert.Len(t, decoded.Suites[0].TestCases, 6)
+	assert.Equal(t, "OtherResource - listing", decoded.Suites[0].TestCases[1].Name)
+	assert.Equal(t, "incomplete listing (permission-denied)", decoded.Suites[0].TestCases[1].Error.Message)
+	assert.Equal(t, "TestResource - c|d", decoded.Suites[0].TestCases[2].Name)
+	assert.Equal(t, "permission denied", decoded.Suites[0].TestCases[2].Failure.Body)
+}
+
+func TestEncodeMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestReport(t).Encode(&buf, FormatMarkdown))
+
+	out := buf.String()
+	assert.Contains(t, out, "| Mode | removal |")
+	assert.Contains(t, out, "| Throttled API calls | 3 |")
+	assert.Contains(t, out, "## test-project")
+	assert.Contains(t, out, "Regions: global, us-east1")
+	assert.Contains(t, out, "**Error:** failed")
+	assert.Contains(t, out, "| OtherResource | us-east1 | permission-denied | permission denied |")
+	assert.Contains(t, out, "4 resource(s): 1 removed, 1 filtered, 1 waiting, 1 failed")
+	assert.Contains(t, out, `| failed | TestResource | global | c\|d | permission denied |`)
+	assert.Contains(t, out, "## other-project\n\nSkipped: blocklisted")
+}
+
+func TestSetRemovedState(t *testing.T) {
+	run := newTestReport(t).Runs[0]
+	run.SetRemovedState(StateMarked)
+
+	states := make(map[string]State)
+	for _, res := range run.Resources {
+		states[res.Name] = res.State
+	}
+
+	assert.Equal(t, map[string]State{
+		"a":   StateFiltered,
+		"b":   StateMarked,
+		"c|d": StateFailed,
+		"e":   StateWaiting,
+	}, states)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// runRequest is the body of POST /runs
+type runRequest struct {
+	Project string `json:"project"`
+	DryRun  bool   `json:"dryRun"`
+}
+
+// runDetail is the body of GET /runs/{id}, the run with its report
+type runDetail struct {
+	Run
+	Report *report.Report `json:"report,omitempty"`
+}
+
+// Handler returns the HTTP API of the daemon:
+//
+//   - GET /healthz: the daemon is up
+//   - GET /runs: the history of the runs, the most recent first
+//   - POST /runs: queues a run of a project, the body is {"project": "<id>", "dryRun": false}
+//   - GET /runs/{id}: a run with its report, ?format=markdown or ?format=junit returns the report only
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /runs", s.authorize(s.listRuns))
+	mux.HandleFunc("POST /runs", s.authorize(s.createRun))
+	mux.HandleFunc("GET /runs/{id}", s.authorize(s.getRun))
+
+	return mux
+}
+
+func (s *Server) healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.store.list())
+}
+
+func (s *Server) createRun(w http.ResponseWriter, r *http.Request) {
+	req := &runRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+
+	run, err := s.Trigger(req.Project, TriggerAPI, req.DryRun)
+	switch {
+	case errors.Is(err, ErrProjectRequired):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrAlreadyQueued):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrQueueFull):
+		writeError(w, http.StatusServiceUnavailable, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.Header().Set("Location", "/runs/"+run.ID)
+		writeJSON(w, http.StatusAccepted, run)
+	}
+}
+
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.store.get(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	rep, err := s.store.loadReport(run.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" || format == string(report.FormatJSON) {
+		writeJSON(w, http.StatusOK, &runDetail{Run: run, Report: rep})
+		return
+	}
+
+	if _, err := report.ParseFormat(format); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if rep == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %s has no report", run.ID))
+		return
+	}
+
+	if err := rep.Encode(w, report.Format(format)); err != nil {
+		s.logger.WithError(err).Errorf("unable to write report of run %s", run.ID)
+	}
+}
+
+// authorize requires the token of the daemon as a bearer token, when there is one
+func (s *Server) authorize(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
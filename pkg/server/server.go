@@ -0,0 +1,301 @@
+// Package server runs gcp-nuke as a daemon: the projects are nuked on the schedules of the config or on demand
+// through an HTTP API, one run at a time, and the history of the runs is kept on disk with their reports.
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/gcp-nuke/pkg/config"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// DefaultHistoryLimit is the number of finished runs kept in the history when no limit is given
+const DefaultHistoryLimit = 100
+
+// queueSize is the number of runs that can wait for the run in progress, a project is queued at most once
+const queueSize = 64
+
+// ErrQueueFull is returned when a run is requested while too many runs are waiting already
+var ErrQueueFull = errors.New("too many runs are queued")
+
+// ErrProjectRequired is returned when a run is requested without a project
+var ErrProjectRequired = errors.New("a project is required")
+
+// Status is the status of a run
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Finished returns true when the run is over, successfully or not
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// Trigger is what started a run
+type Trigger string
+
+const (
+	TriggerSchedule Trigger = "schedule"
+	TriggerAPI      Trigger = "api"
+)
+
+// Run is a run of the daemon against a project, its ID is the run ID the backups created during the run are
+// labelled with
+type Run struct {
+	ID         string     `json:"id"`
+	Project    string     `json:"project"`
+	Trigger    Trigger    `json:"trigger"`
+	DryRun     bool       `json:"dryRun"`
+	Status     Status     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	QueuedAt   time.Time  `json:"queuedAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// Summary is the number of resources of the report in each state
+	Summary map[report.State]int `json:"summary,omitempty"`
+}
+
+func (r *Run) start(now time.Time) {
+	now = now.UTC()
+	r.Status = StatusRunning
+	r.StartedAt = &now
+}
+
+func (r *Run) finish(now time.Time, err error) {
+	now = now.UTC()
+	r.FinishedAt = &now
+	r.Status = StatusSucceeded
+
+	if err != nil {
+		r.Status = StatusFailed
+		r.Error = err.Error()
+	}
+}
+
+// summarize counts the resources of the report in each state
+func (r *Run) summarize(rep *report.Report) {
+	r.Summary = make(map[report.State]int)
+	for _, run := range rep.Runs {
+		for _, state := range report.States {
+			if count := run.Count(state); count > 0 {
+				r.Summary[state] += count
+			}
+		}
+	}
+}
+
+// RunFunc nukes the project of the run and returns its report. The error of the run fails it, the report is kept
+// either way.
+type RunFunc func(ctx context.Context, run Run) (*report.Report, error)
+
+// Options are the options of the daemon
+type Options struct {
+	// Dir is the directory the history of the runs is kept in
+	Dir string
+
+	// HistoryLimit is the number of finished runs kept in the history, the oldest are removed first
+	HistoryLimit int
+
+	// DryRun makes every run a dry run, otherwise a run is a dry run only when it is requested as one
+	DryRun bool
+
+	// Schedules are the schedules of the projects, see config.Schedules
+	Schedules []*config.Schedule
+
+	// Run nukes a project
+	Run RunFunc
+
+	// Token is required as a bearer token by every endpoint but /healthz, when it is set
+	Token string
+
+	Logger *logrus.Logger
+}
+
+// Server runs the projects on their schedules and on demand, one run at a time
+type Server struct {
+	opts   Options
+	store  *store
+	queue  chan string
+	logger *logrus.Entry
+	wg     sync.WaitGroup
+}
+
+// New returns a daemon with the history of the runs loaded from Options.Dir
+func New(opts Options) (*Server, error) {
+	if opts.HistoryLimit == 0 {
+		opts.HistoryLimit = DefaultHistoryLimit
+	}
+
+	if opts.Logger == nil {
+		opts.Logger = logrus.StandardLogger()
+	}
+
+	st, err := openStore(opts.Dir, opts.HistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		opts:   opts,
+		store:  st,
+		queue:  make(chan string, queueSize),
+		logger: opts.Logger.WithField("component", "server"),
+	}, nil
+}
+
+// Start starts running the queued runs and the schedules, until the context is canceled. A run in progress is
+// canceled with the context, Wait waits for it to be recorded.
+func (s *Server) Start(ctx context.Context) {
+	s.wg.Add(2)
+
+	go func() {
+		defer s.wg.Done()
+		s.work(ctx)
+	}()
+
+	go func() {
+		defer s.wg.Done()
+		s.schedule(ctx)
+	}()
+}
+
+// Wait waits for the daemon to stop after the context given to Start was canceled
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// Trigger queues a run of the project. A project has at most one run queued or running, ErrAlreadyQueued is returned
+// otherwise.
+func (s *Server) Trigger(project string, trigger Trigger, dryRun bool) (Run, error) {
+	if project == "" {
+		return Run{}, ErrProjectRequired
+	}
+
+	run, err := s.store.add(project, trigger, s.opts.DryRun || dryRun, time.Now())
+	if err != nil {
+		return Run{}, err
+	}
+
+	select {
+	case s.queue <- run.ID:
+	default:
+		_, _ = s.store.update(run.ID, func(r *Run) { r.finish(time.Now(), ErrQueueFull) })
+		return Run{}, ErrQueueFull
+	}
+
+	s.logger.Infof("run %s of project %s queued by %s", run.ID, run.Project, run.Trigger)
+
+	return run, nil
+}
+
+// work runs the queued runs one after the other, the listers and the run ID are shared by the whole process
+func (s *Server) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			s.execute(ctx, id)
+		}
+	}
+}
+
+func (s *Server) execute(ctx context.Context, id string) {
+	logger := s.logger.WithField("run", id)
+
+	run, err := s.store.update(id, func(r *Run) { r.start(time.Now()) })
+	if err != nil {
+		logger.WithError(err).Error("unable to start run")
+		return
+	}
+
+	logger.Infof("run of project %s started", run.Project)
+
+	rep, runErr := s.opts.Run(ctx, run)
+
+	if rep != nil {
+		rep.Finish()
+		if err := s.store.saveReport(id, rep); err != nil {
+			logger.WithError(err).Error("unable to save report")
+		}
+	}
+
+	run, err = s.store.update(id, func(r *Run) {
+		if rep != nil {
+			r.summarize(rep)
+		}
+		r.finish(time.Now(), runErr)
+	})
+	if err != nil {
+		logger.WithError(err).Error("unable to save run")
+	}
+
+	if runErr != nil {
+		logger.WithError(runErr).Errorf("run of project %s failed", run.Project)
+	} else {
+		logger.Infof("run of project %s succeeded", run.Project)
+	}
+
+	if err := s.store.prune(); err != nil {
+		logger.WithError(err).Warn("unable to prune the history of the runs")
+	}
+}
+
+// schedule queues the runs of the projects when their schedule is due, a run that is due while the previous run of
+// the project is still queued or running is skipped
+func (s *Server) schedule(ctx context.Context) {
+	for {
+		next, projects := nextDue(s.opts.Schedules, time.Now())
+		if len(projects) == 0 {
+			return
+		}
+
+		s.logger.Debugf("next scheduled run at %s for %v", next.Format(time.RFC3339), projects)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for _, project := range projects {
+			if _, err := s.Trigger(project, TriggerSchedule, false); err != nil {
+				s.logger.WithError(err).Warnf("scheduled run of project %s skipped", project)
+			}
+		}
+	}
+}
+
+// nextDue returns the next time after now a schedule is due and the projects that are due at that time
+func nextDue(schedules []*config.Schedule, now time.Time) (time.Time, []string) {
+	var next time.Time
+	var projects []string
+
+	for _, schedule := range schedules {
+		due := schedule.Cron.Next(now)
+		switch {
+		case due.IsZero():
+			continue
+		case next.IsZero() || due.Before(next):
+			next = due
+			projects = []string{schedule.Project}
+		case due.Equal(next):
+			projects = append(projects, schedule.Project)
+		}
+	}
+
+	return next, projects
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/gcp-nuke/pkg/config"
+	"github.com/ekristen/gcp-nuke/pkg/cron"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// testRunner is a RunFunc that waits to be released, so that the state of a run in progress can be checked
+type testRunner struct {
+	release chan error
+	runs    chan Run
+}
+
+func newTestRunner() *testRunner {
+	return &testRunner{release: make(chan error), runs: make(chan Run, 10)}
+}
+
+func (r *testRunner) run(ctx context.Context, run Run) (*report.Report, error) {
+	r.runs <- run
+
+	rep := report.New("test", run.DryRun)
+	rep.AddRun(&report.Run{Project: run.Project, Resources: []*report.Resource{
+		{Type: "StorageBucket", Owner: "us", Name: "kept", State: report.StateFiltered},
+		{Type: "StorageBucket", Owner: "us", Name: "removed", State: report.StateRemoved},
+		{Type: "ComputeDisk", Owner: "us-east1", Name: "removed", State: report.StateRemoved},
+	}})
+
+	select {
+	case <-ctx.Done():
+		return rep, ctx.Err()
+	case err := <-r.release:
+		return rep, err
+	}
+}
+
+func newTestServer(t *testing.T, opts Options) (*Server, *httptest.Server, *testRunner) {
+	t.Helper()
+
+	runner := newTestRunner()
+
+	if opts.Dir == "" {
+		opts.Dir = t.TempDir()
+	}
+	opts.Run = runner.run
+
+	s, err := New(opts)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		s.Wait()
+	})
+
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+
+	return s, ts, runner
+}
+
+func request(t *testing.T, method, url, token, body string, out any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+// waitForStatus waits for the run to have the status, through the API
+func waitForStatus(t *testing.T, url, id string, status Status) *runDetail {
+	t.Helper()
+
+	detail := &runDetail{}
+	require.Eventually(t, func() bool {
+		request(t, http.MethodGet, url+"/runs/"+id, "", "", detail)
+		return detail.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return detail
+}
+
+func TestHealthz(t *testing.T) {
+	_, ts, _ := newTestServer(t, Options{Token: "secret"})
+
+	body := map[string]string{}
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, ts.URL+"/healthz", "", "", &body))
+	assert.Equal(t, "ok", body["status"])
+}
+
+func TestRunOnDemand(t *testing.T) {
+	_, ts, runner := newTestServer(t, Options{})
+
+	run := Run{}
+	status := request(t, http.MethodPost, ts.URL+"/runs", "", `{"project": "my-project"}`, &run)
+	require.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, "my-project", run.Project)
+	assert.Equal(t, TriggerAPI, run.Trigger)
+	assert.False(t, run.DryRun)
+
+	started := <-runner.runs
+	assert.Equal(t, run.ID, started.ID)
+	waitForStatus(t, ts.URL, run.ID, StatusRunning)
+
+	// Note: a project is queued at most once
+	errBody := map[string]string{}
+	status = request(t, http.MethodPost, ts.URL+"/runs", "", `{"project": "my-project"}`, &errBody)
+	assert.Equal(t, http.StatusConflict, status)
+	assert.Equal(t, ErrAlreadyQueued.Error(), errBody["error"])
+
+	runner.release <- nil
+
+	detail := waitForStatus(t, ts.URL, run.ID, StatusSucceeded)
+	assert.NotNil(t, detail.StartedAt)
+	assert.NotNil(t, detail.FinishedAt)
+	assert.Equal(t, map[report.State]int{report.StateFiltered: 1, report.StateRemoved: 2}, detail.Summary)
+	require.NotNil(t, detail.Report)
+	require.Len(t, detail.Report.Runs, 1)
+	assert.Len(t, detail.Report.Runs[0].Resources, 3)
+
+	runs := []Run{}
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, ts.URL+"/runs", "", "", &runs))
+	require.Len(t, runs, 1)
+	assert.Equal(t, run.ID, runs[0].ID)
+}
+
+func TestRunFailed(t *testing.T) {
+	_, ts, runner := newTestServer(t, Options{DryRun: true})
+
+	run := Run{}
+	require.Equal(t, http.StatusAccepted,
+		request(t, http.MethodPost, ts.URL+"/runs", "", `{"project": "my-project"}`, &run))
+	assert.True(t, run.DryRun)
+
+	<-runner.runs
+	runner.release <- errors.New("listing failed")
+
+	detail := waitForStatus(t, ts.URL, run.ID, StatusFailed)
+	assert.Equal(t, "listing failed", detail.Error)
+	assert.NotNil(t, detail.Report)
+}
+
+func TestRunReportFormat(t *testing.T) {
+	_, ts, runner := newTestServer(t, Options{})
+
+	run := Run{}
+	request(t, http.MethodPost, ts.URL+"/runs", "", `{"project": "my-project"}`, &run)
+	<-runner.runs
+	runner.release <- nil
+	waitForStatus(t, ts.URL, run.ID, StatusSucceeded)
+
+	resp, err := http.Get(ts.URL + "/runs/" + run.ID + "?format=markdown")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "my-project")
+
+	assert.Equal(t, http.StatusBadRequest,
+		request(t, http.MethodGet, ts.URL+"/runs/"+run.ID+"?format=yaml", "", "", nil))
+}
+
+func TestRunErrors(t *testing.T) {
+	_, ts, _ := newTestServer(t, Options{})
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{name: "unknown run", method: http.MethodGet, path: "/runs/20240601-120000", want: http.StatusNotFound},
+		{name: "invalid body", method: http.MethodPost, path: "/runs", body: "{", want: http.StatusBadRequest},
+		{name: "no project", method: http.MethodPost, path: "/runs", body: "{}", want: http.StatusBadRequest},
+		{name: "method", method: http.MethodDelete, path: "/runs", want: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, request(t, tc.method, ts.URL+tc.path, "", tc.body, nil))
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	_, ts, _ := newTestServer(t, Options{Token: "secret"})
+
+	assert.Equal(t, http.StatusUnauthorized, request(t, http.MethodGet, ts.URL+"/runs", "", "", nil))
+	assert.Equal(t, http.StatusUnauthorized, request(t, http.MethodGet, ts.URL+"/runs", "wrong", "", nil))
+	assert.Equal(t, http.StatusUnauthorized,
+		request(t, http.MethodPost, ts.URL+"/runs", "", `{"project": "my-project"}`, nil))
+	assert.Equal(t, http.StatusOK, request(t, http.MethodGet, ts.URL+"/runs", "secret", "", nil))
+}
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := New(Options{Dir: dir, HistoryLimit: 2, Run: func(_ context.Context, run Run) (*report.Report, error) {
+		return report.New("test", run.DryRun), nil
+	}})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	for _, project := range []string{"project-a", "project-b", "project-c"} {
+		run, err := s.Trigger(project, TriggerAPI, false)
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			run, err := s.store.get(run.ID)
+			return err != nil || run.Status.Finished()
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Note: a run that was queued when the daemon stopped is failed when the history is loaded
+	_, err = s.store.add("project-d", TriggerSchedule, false, time.Now())
+	require.NoError(t, err)
+
+	cancel()
+	s.Wait()
+
+	reloaded, err := New(Options{Dir: dir, HistoryLimit: 2})
+	require.NoError(t, err)
+
+	runs := reloaded.store.list()
+	require.Len(t, runs, 3)
+	assert.Equal(t, "project-d", runs[0].Project)
+	assert.Equal(t, StatusFailed, runs[0].Status)
+	assert.Contains(t, runs[0].Error, "interrupted")
+
+	// Note: the oldest run is pruned beyond the limit of the history
+	for i, project := range []string{"project-c", "project-b"} {
+		assert.Equal(t, project, runs[i+1].Project)
+		assert.Equal(t, StatusSucceeded, runs[i+1].Status)
+	}
+}
+
+func TestNextDue(t *testing.T) {
+	schedule := func(project, expression string) *config.Schedule {
+		c, err := cron.Parse(expression)
+		require.NoError(t, err)
+		return &config.Schedule{Project: project, Cron: c}
+	}
+
+	now := time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC)
+
+	next, projects := nextDue([]*config.Schedule{
+		schedule("nightly", "0 2 * * *"),
+		schedule("hourly-a", "@hourly"),
+		schedule("hourly-b", "0 * * * *"),
+		schedule("never", "0 0 30 feb *"),
+	}, now)
+	assert.Equal(t, time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, []string{"hourly-a", "hourly-b"}, projects)
+
+	next, projects = nextDue(nil, now)
+	assert.True(t, next.IsZero())
+	assert.Empty(t, projects)
+}
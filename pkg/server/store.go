@@ -0,0 +1,233 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/report"
+)
+
+// ErrRunNotFound is returned for a run that is not part of the history
+var ErrRunNotFound = errors.New("run not found")
+
+// ErrAlreadyQueued is returned when a run is requested for a project that already has a run queued or running
+var ErrAlreadyQueued = errors.New("a run of the project is already queued or running")
+
+// store is the history of the runs, kept on disk in a directory with a file for every run and for its report:
+// <id>.json and <id>.report.json
+type store struct {
+	dir   string
+	limit int
+
+	mu   sync.Mutex
+	runs map[string]*Run
+}
+
+// openStore loads the history of the runs from dir, it is created when it does not exist. The runs that were queued
+// or running when the daemon stopped are failed.
+func openStore(dir string, limit int) (*store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	s := &store{dir: dir, limit: limit, runs: make(map[string]*Run)}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".report.json") {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		run := &Run{}
+		if err := json.Unmarshal(data, run); err != nil {
+			return nil, fmt.Errorf("unable to load run %s: %w", path, err)
+		}
+
+		if !run.Status.Finished() {
+			run.finish(time.Now(), errors.New("interrupted, the daemon stopped during the run"))
+			if err := s.write(run); err != nil {
+				return nil, err
+			}
+		}
+
+		s.runs[run.ID] = run
+	}
+
+	return s, nil
+}
+
+// add queues a run for the project, its ID is the run ID of gcp-nuke for the time it is queued at
+func (s *store) add(project string, trigger Trigger, dryRun bool, now time.Time) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, run := range s.runs {
+		if run.Project == project && !run.Status.Finished() {
+			return Run{}, ErrAlreadyQueued
+		}
+	}
+
+	id := nuke.NewRunID(now)
+	for i := 2; s.runs[id] != nil; i++ {
+		id = fmt.Sprintf("%s-%d", nuke.NewRunID(now), i)
+	}
+
+	run := &Run{
+		ID:       id,
+		Project:  project,
+		Trigger:  trigger,
+		DryRun:   dryRun,
+		Status:   StatusQueued,
+		QueuedAt: now.UTC(),
+	}
+	if err := s.write(run); err != nil {
+		return Run{}, err
+	}
+
+	s.runs[id] = run
+
+	return *run, nil
+}
+
+// update changes the run and writes it to disk, the updated run is returned
+func (s *store) update(id string, fn func(run *Run)) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok {
+		return Run{}, ErrRunNotFound
+	}
+
+	fn(run)
+
+	return *run, s.write(run)
+}
+
+// get returns the run with the ID
+func (s *store) get(id string) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.runs[id]
+	if !ok {
+		return Run{}, ErrRunNotFound
+	}
+
+	return *run, nil
+}
+
+// list returns the runs, the most recent first
+func (s *store) list() []Run {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	runs := make([]Run, 0, len(s.runs))
+	for _, run := range s.runs {
+		runs = append(runs, *run)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].QueuedAt.Equal(runs[j].QueuedAt) {
+			return runs[i].QueuedAt.After(runs[j].QueuedAt)
+		}
+		return runs[i].ID > runs[j].ID
+	})
+
+	return runs
+}
+
+// saveReport writes the report of the run
+func (s *store) saveReport(id string, rep *report.Report) error {
+	return rep.Write(s.reportPath(id), report.FormatJSON)
+}
+
+// loadReport reads the report of the run, nil is returned when the run has no report
+func (s *store) loadReport(id string) (*report.Report, error) {
+	data, err := os.ReadFile(s.reportPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rep := &report.Report{}
+	if err := json.Unmarshal(data, rep); err != nil {
+		return nil, err
+	}
+
+	return rep, nil
+}
+
+// prune removes the oldest finished runs, and their reports, beyond the limit of the history
+func (s *store) prune() error {
+	if s.limit <= 0 {
+		return nil
+	}
+
+	kept := 0
+	var errs []error
+	for _, run := range s.list() {
+		if !run.Status.Finished() {
+			continue
+		}
+
+		kept++
+		if kept <= s.limit {
+			continue
+		}
+
+		s.mu.Lock()
+		delete(s.runs, run.ID)
+		s.mu.Unlock()
+
+		for _, path := range []string{s.runPath(run.ID), s.reportPath(run.ID)} {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// write writes the run to disk, through a temporary file so that a run is never left half written
+func (s *store) write(run *Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.runPath(run.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.runPath(run.ID))
+}
+
+func (s *store) runPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *store) reportPath(id string) string {
+	return filepath.Join(s.dir, id+".report.json")
+}
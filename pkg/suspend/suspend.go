@@ -0,0 +1,269 @@
+// Package suspend provides the state file of the suspended resources. A run in suspend mode suspends the resources
+// instead of removing them and records what they looked like before, a later run in resume mode restores them from
+// the state file and removes them from it.
+package suspend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// FormatVersion is the version of the format of the state file, it is bumped on incompatible changes
+const FormatVersion = 1
+
+const (
+	// ReasonNotSuspendable is the reason given to the resources whose type cannot be suspended
+	ReasonNotSuspendable = "the resource type cannot be suspended"
+
+	// ReasonNotSuspended is the reason given to the resources that are not in the state file, there is nothing to
+	// resume
+	ReasonNotSuspended = "not suspended"
+)
+
+// State is the state file, the resources that were suspended and are not resumed yet
+type State struct {
+	FormatVersion int         `json:"formatVersion"`
+	Version       string      `json:"version"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+	Resources     []*Resource `json:"resources"`
+}
+
+// Resource is a suspended resource, it is identified by its project, type, owner and name. The state is what the
+// resource looked like before it was suspended, see nuke.Suspender.
+type Resource struct {
+	Project     string              `json:"project,omitempty"`
+	Type        string              `json:"type"`
+	Owner       string              `json:"owner"`
+	Name        string              `json:"name"`
+	SuspendedAt time.Time           `json:"suspendedAt"`
+	State       nuke.SuspendedState `json:"state"`
+}
+
+// String returns the resource in the form "project - type - owner - name"
+func (r *Resource) String() string {
+	return fmt.Sprintf("%s - %s - %s - %s", r.Project, r.Type, r.Owner, r.Name)
+}
+
+func resourceKey(project, resourceType, owner, name string) string {
+	return fmt.Sprintf("%s|%s|%s|%s", project, resourceType, owner, name)
+}
+
+func (r *Resource) key() string {
+	return resourceKey(r.Project, r.Type, r.Owner, r.Name)
+}
+
+// New returns a new, empty, state
+func New(version string) *State {
+	return &State{
+		FormatVersion: FormatVersion,
+		Version:       version,
+		Resources:     make([]*Resource, 0),
+	}
+}
+
+// Load reads the state from the file at the path, the state is empty when the file does not exist yet
+func Load(path, version string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(version), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &State{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("unable to parse state file %s: %w", path, err)
+	}
+
+	if s.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("state file %s has format version %d, only version %d is supported",
+			path, s.FormatVersion, FormatVersion)
+	}
+
+	s.Version = version
+
+	return s, nil
+}
+
+// Write writes the state to the file at the path, through a temporary file so that the state is never left half
+// written. The resources are sorted by project, type, owner and name.
+func (s *State) Write(path string) error {
+	sort.SliceStable(s.Resources, func(i, j int) bool {
+		return s.Resources[i].key() < s.Resources[j].key()
+	})
+
+	s.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// get returns the suspended resource of the item of the project, nil when it is not suspended
+func (s *State) get(project string, item *queue.Item) *Resource {
+	key := resourceKey(project, item.Type, item.Owner, nuke.ItemName(item))
+	for _, r := range s.Resources {
+		if r.key() == key {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// remove removes the suspended resource from the state
+func (s *State) remove(resource *Resource) {
+	for i, r := range s.Resources {
+		if r == resource {
+			s.Resources = append(s.Resources[:i], s.Resources[i+1:]...)
+			return
+		}
+	}
+}
+
+// Prepare filters the items of the project that would be removed but cannot be suspended, or resumed when resume is
+// true, these items are returned. The resources whose type is not a nuke.Suspender cannot be suspended nor resumed,
+// and the resources that are not in the state cannot be resumed.
+func (s *State) Prepare(project string, items []*queue.Item, resume bool) []*queue.Item {
+	excluded := make([]*queue.Item, 0)
+	for _, item := range items {
+		if !wouldRemove(item) {
+			continue
+		}
+
+		switch _, ok := nuke.UnwrapResource(item.Resource).(nuke.Suspender); {
+		case !ok:
+			item.State, item.Reason = queue.ItemStateFiltered, ReasonNotSuspendable
+		case resume && s.get(project, item) == nil:
+			item.State, item.Reason = queue.ItemStateFiltered, ReasonNotSuspended
+		default:
+			continue
+		}
+
+		excluded = append(excluded, item)
+	}
+
+	return excluded
+}
+
+// Suspend suspends the resources of the items of the project that would be removed and adds them to the state. The
+// state of each of these items is set to the outcome: finished when the resource is suspended, failed when it could
+// not be suspended and filtered when there was nothing to suspend. A resource that was only partly suspended is added to
+// the state as well, so that it can be resumed. The resources that are already in the state are not suspended again,
+// so that their state before the first suspension is kept. The items are returned.
+func (s *State) Suspend(ctx context.Context, project string, items []*queue.Item, suspendedAt time.Time) []*queue.Item {
+	suspended := make([]*queue.Item, 0)
+	for _, item := range items {
+		if !wouldRemove(item) {
+			continue
+		}
+
+		suspended = append(suspended, item)
+
+		suspender, ok := nuke.UnwrapResource(item.Resource).(nuke.Suspender)
+		if !ok {
+			item.State, item.Reason = queue.ItemStateFiltered, ReasonNotSuspendable
+			continue
+		}
+
+		if existing := s.get(project, item); existing != nil {
+			item.State = queue.ItemStateFinished
+			item.Reason = fmt.Sprintf("already suspended at %s", existing.SuspendedAt.Format(time.RFC3339))
+			continue
+		}
+
+		state, err := suspender.Suspend(ctx)
+		if reason, ok := nuke.IsNotSuspended(err); ok {
+			item.State, item.Reason = queue.ItemStateFiltered, fmt.Sprintf("nothing to suspend: %s", reason)
+			continue
+		}
+		if err != nil && state == nil {
+			item.State, item.Reason = queue.ItemStateFailed, fmt.Sprintf("unable to suspend: %s", err)
+			continue
+		}
+
+		s.Resources = append(s.Resources, &Resource{
+			Project:     project,
+			Type:        item.Type,
+			Owner:       item.Owner,
+			Name:        nuke.ItemName(item),
+			SuspendedAt: suspendedAt.UTC(),
+			State:       state,
+		})
+
+		if err != nil {
+			item.State, item.Reason = queue.ItemStateFailed, fmt.Sprintf("only partly suspended: %s", err)
+			continue
+		}
+
+		item.State = queue.ItemStateFinished
+		item.Reason = fmt.Sprintf("suspended at %s", suspendedAt.UTC().Format(time.RFC3339))
+	}
+
+	return suspended
+}
+
+// Resume resumes the resources of the items of the project that would be removed and are in the state, they are
+// removed from the state once resumed. The state of each of these items is set to the outcome: finished when the
+// resource is resumed and failed when it could not be resumed, it stays in the state then. The items are returned,
+// along with the resources of the project in the state that were not found, or are filtered now.
+func (s *State) Resume(ctx context.Context, project string, items []*queue.Item) (
+	resumed []*queue.Item, missing []*Resource) {
+	found := make(map[*Resource]bool)
+	for _, item := range items {
+		if !wouldRemove(item) {
+			continue
+		}
+
+		resumed = append(resumed, item)
+
+		suspender, ok := nuke.UnwrapResource(item.Resource).(nuke.Suspender)
+		r := s.get(project, item)
+		if !ok || r == nil {
+			item.State, item.Reason = queue.ItemStateFiltered, ReasonNotSuspended
+			continue
+		}
+
+		found[r] = true
+
+		if err := suspender.Resume(ctx, r.State); err != nil {
+			item.State, item.Reason = queue.ItemStateFailed, fmt.Sprintf("unable to resume: %s", err)
+			continue
+		}
+
+		s.remove(r)
+
+		item.State = queue.ItemStateFinished
+		item.Reason = fmt.Sprintf("resumed, it was suspended at %s", r.SuspendedAt.Format(time.RFC3339))
+	}
+
+	for _, r := range s.Resources {
+		if r.Project == project && !found[r] {
+			missing = append(missing, r)
+		}
+	}
+
+	return resumed, missing
+}
+
+func wouldRemove(item *queue.Item) bool {
+	return item.GetState() == queue.ItemStateNew || item.GetState() == queue.ItemStateNewDependency
+}
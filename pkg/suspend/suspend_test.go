@@ -0,0 +1,262 @@
+package suspend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+type testState struct {
+	Size int `json:"size"`
+}
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) String() string {
+	return r.name
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewProperties().Set("Name", r.name)
+}
+
+type testSuspendedResource struct {
+	testResource
+
+	size       int
+	suspendErr error
+	resumeErr  error
+	partial    bool
+}
+
+func (r *testSuspendedResource) Suspend(_ context.Context) (nuke.SuspendedState, error) {
+	if r.size == 0 {
+		return nil, nuke.NotSuspended("the resource is empty")
+	}
+
+	if r.suspendErr != nil && !r.partial {
+		return nil, r.suspendErr
+	}
+
+	state, err := nuke.NewSuspendedState(&testState{Size: r.size})
+	if err != nil {
+		return nil, err
+	}
+
+	r.size = 0
+
+	return state, r.suspendErr
+}
+
+func (r *testSuspendedResource) Resume(_ context.Context, state nuke.SuspendedState) error {
+	if r.resumeErr != nil {
+		return r.resumeErr
+	}
+
+	suspended := &testState{}
+	if err := state.Decode(suspended); err != nil {
+		return err
+	}
+
+	r.size = suspended.Size
+
+	return nil
+}
+
+func newItem(resourceType, owner string, r resource.Resource, state queue.ItemState) *queue.Item {
+	return &queue.Item{Resource: r, Type: resourceType, Owner: owner, State: state}
+}
+
+func newSuspended(name string, size int) *testSuspendedResource {
+	return &testSuspendedResource{testResource: testResource{name: name}, size: size}
+}
+
+func TestSuspendResume(t *testing.T) {
+	suspendedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := Load(path, "1.0.0")
+	require.NoError(t, err)
+	assert.Empty(t, state.Resources)
+
+	pool := newSuspended("pool", 3)
+	empty := newSuspended("empty", 0)
+	failing := newSuspended("failing", 2)
+	failing.suspendErr = errors.New("permission denied")
+	partial := newSuspended("partial", 4)
+	partial.suspendErr, partial.partial = errors.New("quota exceeded"), true
+
+	items := []*queue.Item{
+		newItem("TestResource", "us-east1", pool, queue.ItemStateNew),
+		newItem("TestResource", "us-east1", empty, queue.ItemStateNew),
+		newItem("TestResource", "us-east1", failing, queue.ItemStateNew),
+		newItem("TestResource", "us-east1", partial, queue.ItemStateNewDependency),
+		newItem("OtherResource", "global", &testResource{name: "plain"}, queue.ItemStateNew),
+		newItem("TestResource", "us-east1", newSuspended("filtered", 1), queue.ItemStateFiltered),
+	}
+
+	assert.Len(t, state.Suspend(context.TODO(), "test-project", items, suspendedAt), 5)
+
+	assert.Equal(t, queue.ItemStateFinished, items[0].State)
+	assert.Equal(t, "suspended at 2024-06-01T12:00:00Z", items[0].Reason)
+	assert.Equal(t, 0, pool.size)
+	assert.Equal(t, queue.ItemStateFiltered, items[1].State)
+	assert.Equal(t, "nothing to suspend: the resource is empty", items[1].Reason)
+	assert.Equal(t, queue.ItemStateFailed, items[2].State)
+	assert.Equal(t, "unable to suspend: permission denied", items[2].Reason)
+	assert.Equal(t, queue.ItemStateFailed, items[3].State)
+	assert.Equal(t, "only partly suspended: quota exceeded", items[3].Reason)
+	assert.Equal(t, queue.ItemStateFiltered, items[4].State)
+	assert.Equal(t, ReasonNotSuspendable, items[4].Reason)
+	assert.Equal(t, queue.ItemStateFiltered, items[5].State)
+
+	require.NoError(t, state.Write(path))
+
+	loaded, err := Load(path, "1.0.0")
+	require.NoError(t, err)
+	require.Len(t, loaded.Resources, 2)
+	assert.Equal(t, "test-project - TestResource - us-east1 - partial", loaded.Resources[0].String())
+	assert.Equal(t, suspendedAt, loaded.Resources[0].SuspendedAt)
+	assert.JSONEq(t, `{"size": 4}`, string(loaded.Resources[0].State))
+	assert.Equal(t, "pool", loaded.Resources[1].Name)
+
+	// Note: the resources already in the state are not suspended again, their state is kept
+	pool.size = 1
+	again := []*queue.Item{newItem("TestResource", "us-east1", pool, queue.ItemStateNew)}
+	loaded.Suspend(context.TODO(), "test-project", again, suspendedAt.Add(time.Hour))
+	assert.Equal(t, "already suspended at 2024-06-01T12:00:00Z", again[0].Reason)
+	assert.Equal(t, 1, pool.size)
+
+	partial.resumeErr = errors.New("permission denied")
+	resumeItems := []*queue.Item{
+		newItem("TestResource", "us-east1", pool, queue.ItemStateNew),
+		newItem("TestResource", "us-east1", partial, queue.ItemStateNew),
+		newItem("TestResource", "us-east1", newSuspended("other", 1), queue.ItemStateNew),
+	}
+
+	resumed, missing := loaded.Resume(context.TODO(), "test-project", resumeItems)
+	assert.Len(t, resumed, 3)
+	assert.Empty(t, missing)
+
+	assert.Equal(t, queue.ItemStateFinished, resumeItems[0].State)
+	assert.Equal(t, "resumed, it was suspended at 2024-06-01T12:00:00Z", resumeItems[0].Reason)
+	assert.Equal(t, 3, pool.size)
+	assert.Equal(t, queue.ItemStateFailed, resumeItems[1].State)
+	assert.Equal(t, "unable to resume: permission denied", resumeItems[1].Reason)
+	assert.Equal(t, queue.ItemStateFiltered, resumeItems[2].State)
+	assert.Equal(t, ReasonNotSuspended, resumeItems[2].Reason)
+
+	// Note: the resources that failed to resume stay in the state
+	require.Len(t, loaded.Resources, 1)
+	assert.Equal(t, "partial", loaded.Resources[0].Name)
+}
+
+func TestPrepare(t *testing.T) {
+	state := New("1.0.0")
+	state.Resources = []*Resource{
+		{Project: "test-project", Type: "TestResource", Owner: "us-east1", Name: "suspended"},
+		{Project: "other-project", Type: "TestResource", Owner: "us-east1", Name: "other-project"},
+	}
+
+	suspended := newItem("TestResource", "us-east1", newSuspended("suspended", 0), queue.ItemStateNew)
+	notSuspended := newItem("TestResource", "us-east1", newSuspended("not-suspended", 1), queue.ItemStateNew)
+	otherProject := newItem("TestResource", "us-east1", newSuspended("other-project", 1), queue.ItemStateNew)
+	plain := newItem("OtherResource", "global", &testResource{name: "plain"}, queue.ItemStateNew)
+
+	items := []*queue.Item{suspended, notSuspended, otherProject, plain}
+
+	assert.Equal(t, []*queue.Item{plain}, state.Prepare("test-project", items, false))
+	assert.Equal(t, ReasonNotSuspendable, plain.Reason)
+
+	assert.Equal(t, []*queue.Item{notSuspended, otherProject}, state.Prepare("test-project", items, true))
+	assert.Equal(t, ReasonNotSuspended, notSuspended.Reason)
+	assert.Equal(t, queue.ItemStateNew, suspended.State)
+
+	_, missing := state.Resume(context.TODO(), "other-project", nil)
+	assert.Equal(t, []*Resource{state.Resources[1]}, missing)
+}
+
+type testSuspendedLister struct {
+	resources []resource.Resource
+}
+
+func (l *testSuspendedLister) List(_ context.Context, _ interface{}) ([]resource.Resource, error) {
+	return l.resources, nil
+}
+
+func TestSuspendResumeInstrumented(t *testing.T) {
+	suspendedAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Note: with the telemetry enabled, the listers wrap the resources they list
+	pool := newSuspended("pool", 3)
+	registry.Register(&registry.Registration{
+		Name:   "TestSuspendedResource",
+		Scope:  nuke.Project,
+		Lister: &testSuspendedLister{resources: []resource.Resource{pool}},
+	})
+	nuke.InstrumentListers()
+
+	resources, err := registry.GetLister("TestSuspendedResource").List(context.TODO(), &nuke.ListerOpts{
+		Project: ptr.String("test-project"),
+		Region:  ptr.String("us-east1"),
+	})
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.NotSame(t, pool, resources[0])
+
+	item := newItem("TestSuspendedResource", "us-east1", resources[0], queue.ItemStateNew)
+
+	state := New("1.0.0")
+	assert.Empty(t, state.Prepare("test-project", []*queue.Item{item}, false))
+
+	state.Suspend(context.TODO(), "test-project", []*queue.Item{item}, suspendedAt)
+	assert.Equal(t, queue.ItemStateFinished, item.State)
+	assert.Equal(t, 0, pool.size)
+	require.Len(t, state.Resources, 1)
+
+	item.State = queue.ItemStateNew
+	assert.Empty(t, state.Prepare("test-project", []*queue.Item{item}, true))
+
+	state.Resume(context.TODO(), "test-project", []*queue.Item{item})
+	assert.Equal(t, queue.ItemStateFinished, item.State)
+	assert.Equal(t, 3, pool.size)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := map[string]string{
+		"not-json":       "state",
+		"format-version": `{"formatVersion": 2}`,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".json")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+			_, err := Load(path, "1.0.0")
+			assert.Error(t, err)
+		})
+	}
+}
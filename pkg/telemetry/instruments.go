@@ -0,0 +1,258 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+)
+
+// instrumentationName is the name of the tracer and the meter of gcp-nuke
+const instrumentationName = "github.com/ekristen/gcp-nuke"
+
+// The attributes of the spans and the metrics, the same names are used for both so that they can be correlated
+const (
+	AttributeResourceType = attribute.Key("resource_type")
+	AttributeOwner        = attribute.Key("owner")
+	AttributeProject      = attribute.Key("project")
+	AttributeOrganization = attribute.Key("organization")
+	AttributeResourceName = attribute.Key("resource_name")
+	AttributeResult       = attribute.Key("result")
+	AttributeAPI          = attribute.Key("api")
+	AttributeMethod       = attribute.Key("method")
+	AttributeCode         = attribute.Key("code")
+	AttributeCount        = attribute.Key("count")
+)
+
+// The results of a listing, a removal or a wait
+const (
+	ResultOK      = "ok"
+	ResultError   = "error"
+	ResultSkipped = "skipped"
+	ResultHold    = "hold"
+	ResultWaiting = "waiting"
+)
+
+// durationBuckets are the boundaries, in seconds, of the histograms of the durations. A listing or an API call usually
+// takes less than a second, a removal that waits for its operation can take minutes.
+var durationBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// instruments are the tracer and the metrics of gcp-nuke
+type instruments struct {
+	tracer trace.Tracer
+
+	resourcesFound   metric.Int64Counter
+	resourcesRemoved metric.Int64Counter
+	resourcesFailed  metric.Int64Counter
+
+	listDuration   metric.Float64Histogram
+	removeDuration metric.Float64Histogram
+	apiDuration    metric.Float64Histogram
+}
+
+// current are the instruments of the telemetry that is set up, the ones that do nothing otherwise
+var current atomic.Pointer[instruments]
+
+func init() {
+	current.Store(disabled)
+}
+
+func newInstruments(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*instruments, error) {
+	meter := meterProvider.Meter(instrumentationName)
+
+	inst := &instruments{tracer: tracerProvider.Tracer(instrumentationName)}
+
+	var errs [6]error
+	inst.resourcesFound, errs[0] = meter.Int64Counter("gcp_nuke.resources.found",
+		metric.WithDescription("Number of resources found by the runs"))
+	inst.resourcesRemoved, errs[1] = meter.Int64Counter("gcp_nuke.resources.removed",
+		metric.WithDescription("Number of resources removed by the runs"))
+	inst.resourcesFailed, errs[2] = meter.Int64Counter("gcp_nuke.resources.failed",
+		metric.WithDescription("Number of resources the runs failed to remove"))
+	inst.listDuration, errs[3] = meter.Float64Histogram("gcp_nuke.list.duration",
+		metric.WithDescription("Duration of the listings of a resource type in an owner"),
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...))
+	inst.removeDuration, errs[4] = meter.Float64Histogram("gcp_nuke.remove.duration",
+		metric.WithDescription("Duration of the removal requests of the resources"),
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...))
+	inst.apiDuration, errs[5] = meter.Float64Histogram("gcp_nuke.api.request.duration",
+		metric.WithDescription("Duration of the API calls, every attempt of a throttled call is a call"),
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...))
+
+	if err := errors.Join(errs[:]...); err != nil {
+		return nil, err
+	}
+
+	return inst, nil
+}
+
+// Start starts a span, the returned function ends it with the error of the operation, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(err error)) {
+	ctx, span := current.Load().tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+
+	return ctx, func(err error) {
+		endSpan(span, err)
+	}
+}
+
+// StartList starts the listing of a resource type in an owner, a region or an organization. The returned function ends
+// it with the number of resources listed and the error of the listing, if any.
+func StartList(
+	ctx context.Context, resourceType, owner, project string,
+) (context.Context, func(count int, err error)) {
+	inst := current.Load()
+	started := time.Now()
+
+	ctx, span := inst.tracer.Start(ctx, "list "+resourceType, trace.WithAttributes(
+		AttributeResourceType.String(resourceType),
+		AttributeOwner.String(owner),
+		AttributeProject.String(project),
+	))
+
+	return ctx, func(count int, err error) {
+		result := ResultOK
+		if err != nil {
+			result = ResultError
+		}
+
+		// Note: libnuke asks every lister in every region, the listers of the other regions skip the request
+		var skipErr liberrors.ErrSkipRequest
+		var endpointErr liberrors.ErrUnknownEndpoint
+		if errors.As(err, &skipErr) || errors.As(err, &endpointErr) {
+			result, err = ResultSkipped, nil
+		}
+
+		inst.listDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(
+			AttributeResourceType.String(resourceType),
+			AttributeOwner.String(owner),
+			AttributeResult.String(result),
+		))
+
+		span.SetAttributes(AttributeCount.Int(count), AttributeResult.String(result))
+		endSpan(span, err)
+	}
+}
+
+// StartRemove starts the removal of a resource, the returned function ends it with the error of the removal, if any
+func StartRemove(ctx context.Context, resourceType, owner, name string) (context.Context, func(err error)) {
+	inst := current.Load()
+	started := time.Now()
+
+	ctx, span := inst.tracer.Start(ctx, "remove "+resourceType, trace.WithAttributes(
+		AttributeResourceType.String(resourceType),
+		AttributeOwner.String(owner),
+		AttributeResourceName.String(name),
+	))
+
+	return ctx, func(err error) {
+		result := ResultOK
+		if err != nil {
+			result = ResultError
+		}
+
+		// Note: a resource on hold is removed later, once what holds it is gone
+		var holdErr liberrors.ErrHoldResource
+		if errors.As(err, &holdErr) {
+			result, err = ResultHold, nil
+		}
+
+		inst.removeDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(
+			AttributeResourceType.String(resourceType),
+			AttributeOwner.String(owner),
+			AttributeResult.String(result),
+		))
+
+		span.SetAttributes(AttributeResult.String(result))
+		endSpan(span, err)
+	}
+}
+
+// StartWait starts a check of whether the removal of a resource is done, the returned function ends it with the error
+// of the check, if any
+func StartWait(ctx context.Context, resourceType, owner, name string) (context.Context, func(err error)) {
+	ctx, span := current.Load().tracer.Start(ctx, "wait "+resourceType, trace.WithAttributes(
+		AttributeResourceType.String(resourceType),
+		AttributeOwner.String(owner),
+		AttributeResourceName.String(name),
+	))
+
+	return ctx, func(err error) {
+		result := ResultOK
+		if err != nil {
+			result = ResultError
+		}
+
+		var waitErr liberrors.ErrWaitResource
+		if errors.As(err, &waitErr) {
+			result, err = ResultWaiting, nil
+		}
+
+		span.SetAttributes(AttributeResult.String(result))
+		endSpan(span, err)
+	}
+}
+
+// StartAPIRequest starts an API call, method is the HTTP method or the gRPC method. The returned function ends it with
+// the code of the response, the HTTP status or the gRPC code, and the error of the call, if any.
+func StartAPIRequest(ctx context.Context, api, method string) func(code string, err error) {
+	inst := current.Load()
+	started := time.Now()
+
+	_, span := inst.tracer.Start(ctx, method+" "+api, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		AttributeAPI.String(api),
+		AttributeMethod.String(method),
+	))
+
+	return func(code string, err error) {
+		inst.apiDuration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(
+			AttributeAPI.String(api),
+			AttributeMethod.String(method),
+			AttributeCode.String(code),
+		))
+
+		span.SetAttributes(AttributeCode.String(code))
+		endSpan(span, err)
+	}
+}
+
+// Outcome is what a run did with a resource it found
+type Outcome int
+
+const (
+	// OutcomeKept is a resource that was filtered, left in place by a dry run or still waiting to be removed
+	OutcomeKept Outcome = iota
+	OutcomeRemoved
+	OutcomeFailed
+)
+
+// RecordResource counts a resource found by a run and, when it was removed or its removal failed, its outcome
+func RecordResource(ctx context.Context, resourceType, owner string, outcome Outcome) {
+	inst := current.Load()
+
+	attrs := metric.WithAttributes(AttributeResourceType.String(resourceType), AttributeOwner.String(owner))
+
+	inst.resourcesFound.Add(ctx, 1, attrs)
+
+	switch outcome {
+	case OutcomeRemoved:
+		inst.resourcesRemoved.Add(ctx, 1, attrs)
+	case OutcomeFailed:
+		inst.resourcesFailed.Add(ctx, 1, attrs)
+	}
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,242 @@
+// Package telemetry instruments the runs with OpenTelemetry: a span for the listing, the removal and the wait of every
+// resource type and for every API call, and metrics for the resources found, removed and failed and for the duration
+// of the listings, the removals and the API calls. The traces and the metrics are exported to an OTLP endpoint, the
+// metrics can also be scraped by Prometheus or pushed to a Prometheus push gateway.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// DefaultPushJob is the job the metrics are pushed as to the push gateway when no job is given
+const DefaultPushJob = "gcp-nuke"
+
+// serviceName is the name of the service of the traces and the metrics
+const serviceName = "gcp-nuke"
+
+// Options are the options of the telemetry, the telemetry is disabled when no exporter is given
+type Options struct {
+	// OTLPEndpoint is the base URL of the OTLP/HTTP endpoint the traces and the metrics are exported to, e.g.
+	// http://localhost:4318, the same as OTEL_EXPORTER_OTLP_ENDPOINT
+	OTLPEndpoint string
+
+	// MetricsListen is the address the Prometheus metrics are served on, on /metrics
+	MetricsListen string
+
+	// PushGateway is the URL of the Prometheus push gateway the metrics are pushed to, see Telemetry.Push
+	PushGateway string
+
+	// PushJob is the job the metrics are pushed as, DefaultPushJob when empty
+	PushJob string
+
+	// Version is the version of gcp-nuke, the service version of the traces and the metrics
+	Version string
+
+	Logger *logrus.Logger
+}
+
+// Enabled returns true when the traces or the metrics are exported
+func (o *Options) Enabled() bool {
+	return o.OTLPEndpoint != "" || o.MetricsListen != "" || o.PushGateway != ""
+}
+
+// Telemetry exports the traces and the metrics of the process, until it is shut down
+type Telemetry struct {
+	opts Options
+
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+
+	// registry is the registry of the Prometheus metrics, it is nil unless the metrics are served or pushed
+	registry *prometheus.Registry
+	listener net.Listener
+	server   *http.Server
+}
+
+// Setup starts exporting the traces and the metrics with the exporters of the options. When no exporter is given the
+// telemetry is disabled and the instrumentation does nothing.
+func Setup(ctx context.Context, opts Options) (*Telemetry, error) {
+	t := &Telemetry{opts: opts}
+	if !opts.Enabled() {
+		return t, nil
+	}
+
+	if t.opts.PushJob == "" {
+		t.opts.PushJob = DefaultPushJob
+	}
+	if t.opts.Logger == nil {
+		t.opts.Logger = logrus.StandardLogger()
+	}
+
+	if err := t.setup(ctx); err != nil {
+		return nil, errors.Join(err, t.shutdown(ctx))
+	}
+
+	return t, nil
+}
+
+func (t *Telemetry) setup(ctx context.Context) error {
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(t.opts.Version),
+	)
+
+	var tracerProvider trace.TracerProvider = tracenoop.NewTracerProvider()
+	metricOptions := []sdkmetric.Option{sdkmetric.WithResource(res)}
+
+	if t.opts.OTLPEndpoint != "" {
+		// Note: the endpoint is a base URL, the same as OTEL_EXPORTER_OTLP_ENDPOINT, the other OTEL_EXPORTER_OTLP_*
+		// environment variables, e.g. the headers, still apply
+		endpoint := strings.TrimSuffix(t.opts.OTLPEndpoint, "/")
+
+		traceExporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"))
+		if err != nil {
+			return fmt.Errorf("unable to create the OTLP trace exporter: %w", err)
+		}
+
+		metricExporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(endpoint+"/v1/metrics"))
+		if err != nil {
+			return fmt.Errorf("unable to create the OTLP metric exporter: %w", err)
+		}
+
+		t.tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(traceExporter), sdktrace.WithResource(res))
+		tracerProvider = t.tracerProvider
+
+		// Note: the GCP client libraries that are instrumented trace their own calls with the global provider
+		otel.SetTracerProvider(t.tracerProvider)
+
+		metricOptions = append(metricOptions, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)))
+	}
+
+	if t.opts.MetricsListen != "" || t.opts.PushGateway != "" {
+		t.registry = prometheus.NewRegistry()
+
+		// Note: the metrics have a single scope, its labels would only add noise to every series
+		exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(t.registry), otelprometheus.WithoutScopeInfo())
+		if err != nil {
+			return fmt.Errorf("unable to create the Prometheus exporter: %w", err)
+		}
+
+		metricOptions = append(metricOptions, sdkmetric.WithReader(exporter))
+	}
+
+	t.meterProvider = sdkmetric.NewMeterProvider(metricOptions...)
+
+	inst, err := newInstruments(tracerProvider, t.meterProvider)
+	if err != nil {
+		return err
+	}
+
+	if t.opts.MetricsListen != "" {
+		if err := t.serve(); err != nil {
+			return err
+		}
+	}
+
+	current.Store(inst)
+
+	return nil
+}
+
+// serve serves the Prometheus metrics on /metrics of Options.MetricsListen
+func (t *Telemetry) serve() error {
+	listener, err := net.Listen("tcp", t.opts.MetricsListen)
+	if err != nil {
+		return fmt.Errorf("unable to serve the metrics on %s: %w", t.opts.MetricsListen, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(t.registry, promhttp.HandlerOpts{}))
+
+	t.listener = listener
+	t.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := t.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.opts.Logger.WithError(err).Error("unable to serve the metrics")
+		}
+	}()
+
+	t.opts.Logger.Infof("serving the metrics on %s/metrics", listener.Addr())
+
+	return nil
+}
+
+// Push pushes the metrics to the push gateway, when there is one. The metrics of the job are replaced, so the
+// gateway always has the metrics of the whole process.
+func (t *Telemetry) Push(ctx context.Context) error {
+	if t.opts.PushGateway == "" || t.registry == nil {
+		return nil
+	}
+
+	if err := push.New(t.opts.PushGateway, t.opts.PushJob).Gatherer(t.registry).PushContext(ctx); err != nil {
+		return fmt.Errorf("unable to push the metrics to %s: %w", t.opts.PushGateway, err)
+	}
+
+	return nil
+}
+
+// Shutdown pushes the metrics a last time, flushes the traces and the metrics that were not exported yet and stops
+// serving the metrics. The instrumentation does nothing afterward.
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	if !t.opts.Enabled() {
+		return nil
+	}
+
+	return errors.Join(t.Push(ctx), t.shutdown(ctx))
+}
+
+func (t *Telemetry) shutdown(ctx context.Context) error {
+	current.Store(disabled)
+
+	var errs []error
+
+	if t.server != nil {
+		errs = append(errs, t.server.Shutdown(ctx))
+	}
+
+	if t.meterProvider != nil {
+		errs = append(errs, t.meterProvider.Shutdown(ctx))
+	}
+
+	if t.tracerProvider != nil {
+		errs = append(errs, t.tracerProvider.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+// disabled are the instruments that do nothing, used while the telemetry is not set up
+var disabled = func() *instruments {
+	inst, err := newInstruments(tracenoop.NewTracerProvider(), metricnoop.NewMeterProvider())
+	if err != nil {
+		panic(err)
+	}
+
+	return inst
+}()
@@ -0,0 +1,160 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+)
+
+// record records a listing, a removal and an API call, the same as a run would
+func record() {
+	ctx, end := Start(context.Background(), "nuke", AttributeProject.String("my-project"))
+
+	listCtx, endList := StartList(ctx, "StorageBucket", "us", "my-project")
+	StartAPIRequest(listCtx, "storage.googleapis.com", "GET")("200", nil)
+	endList(2, nil)
+
+	_, endSkipped := StartList(ctx, "StorageBucket", "us-east1", "my-project")
+	endSkipped(0, liberrors.ErrSkipRequest("not a multi-region"))
+
+	_, endRemove := StartRemove(ctx, "StorageBucket", "us", "my-bucket")
+	endRemove(errors.New("permission denied"))
+
+	_, endWait := StartWait(ctx, "StorageBucket", "us", "my-bucket")
+	endWait(liberrors.ErrWaitResource("still there"))
+
+	RecordResource(ctx, "StorageBucket", "us", OutcomeRemoved)
+	RecordResource(ctx, "StorageBucket", "us", OutcomeFailed)
+	RecordResource(ctx, "StorageBucket", "us", OutcomeKept)
+
+	end(nil)
+}
+
+func TestDisabled(t *testing.T) {
+	tel, err := Setup(context.Background(), Options{})
+	require.NoError(t, err)
+	assert.Same(t, disabled, current.Load())
+
+	record()
+
+	assert.NoError(t, tel.Push(context.Background()))
+	assert.NoError(t, tel.Shutdown(context.Background()))
+}
+
+func TestMetricsListen(t *testing.T) {
+	tel, err := Setup(context.Background(), Options{MetricsListen: "127.0.0.1:0", Version: "test"})
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, tel.Shutdown(context.Background())) })
+
+	record()
+
+	resp, err := http.Get("http://" + tel.listener.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	metrics := string(body)
+	for _, expected := range []string{
+		`gcp_nuke_resources_found_total{owner="us",resource_type="StorageBucket"} 3`,
+		`gcp_nuke_resources_removed_total{owner="us",resource_type="StorageBucket"} 1`,
+		`gcp_nuke_resources_failed_total{owner="us",resource_type="StorageBucket"} 1`,
+		`gcp_nuke_list_duration_seconds_count{owner="us",resource_type="StorageBucket",result="ok"} 1`,
+		`gcp_nuke_list_duration_seconds_count{owner="us-east1",resource_type="StorageBucket",result="skipped"} 1`,
+		`gcp_nuke_remove_duration_seconds_count{owner="us",resource_type="StorageBucket",result="error"} 1`,
+		`gcp_nuke_api_request_duration_seconds_count{api="storage.googleapis.com",code="200",method="GET"} 1`,
+		`target_info{service_name="gcp-nuke",service_version="test"} 1`,
+	} {
+		assert.Contains(t, metrics, expected)
+	}
+}
+
+func TestPushGateway(t *testing.T) {
+	var mu sync.Mutex
+	var pushes []string
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		pushes = append(pushes, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		assert.NotEmpty(t, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	tel, err := Setup(context.Background(), Options{PushGateway: gateway.URL, PushJob: "nightly"})
+	require.NoError(t, err)
+
+	record()
+
+	require.NoError(t, tel.Push(context.Background()))
+	require.NoError(t, tel.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"PUT /metrics/job/nightly", "PUT /metrics/job/nightly"}, pushes)
+	assert.Same(t, disabled, current.Load())
+}
+
+func TestPushGatewayError(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer gateway.Close()
+
+	tel, err := Setup(context.Background(), Options{PushGateway: gateway.URL})
+	require.NoError(t, err)
+
+	record()
+
+	err = tel.Shutdown(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to push the metrics")
+}
+
+func TestOTLP(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]int{}
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	tel, err := Setup(context.Background(), Options{OTLPEndpoint: collector.URL + "/"})
+	require.NoError(t, err)
+
+	record()
+
+	// Note: the traces and the metrics are flushed on shutdown
+	require.NoError(t, tel.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Positive(t, paths["/v1/traces"])
+	assert.Positive(t, paths["/v1/metrics"])
+}
+
+func TestMetricsListenError(t *testing.T) {
+	_, err := Setup(context.Background(), Options{MetricsListen: "127.0.0.1:-1"})
+	require.Error(t, err)
+	assert.Same(t, disabled, current.Load())
+}
@@ -0,0 +1,52 @@
+package testutil
+
+import (
+	"fmt"
+)
+
+// ComputeProjectPath returns the path of a compute collection that is directly under the project, e.g. regions
+func ComputeProjectPath(project, kind string) string {
+	return fmt.Sprintf("/compute/v1/projects/%s/%s", project, kind)
+}
+
+// ComputeGlobalPath returns the path of a global compute collection, e.g. networks
+func ComputeGlobalPath(project, kind string) string {
+	return fmt.Sprintf("/compute/v1/projects/%s/global/%s", project, kind)
+}
+
+// ComputeRegionalPath returns the path of a regional compute collection, e.g. subnetworks
+func ComputeRegionalPath(project, region, kind string) string {
+	return fmt.Sprintf("/compute/v1/projects/%s/regions/%s/%s", project, region, kind)
+}
+
+// ComputeZonalPath returns the path of a zonal compute collection, e.g. instances
+func ComputeZonalPath(project, zone, kind string) string {
+	return fmt.Sprintf("/compute/v1/projects/%s/zones/%s/%s", project, zone, kind)
+}
+
+// AddCompute adds items to a compute collection, the selfLink of the items is set when it is missing
+func (s *Server) AddCompute(collectionPath string, items ...Item) {
+	for _, item := range items {
+		if _, ok := item["selfLink"]; !ok {
+			item["selfLink"] = fmt.Sprintf("https://www.googleapis.com%s/%s", collectionPath, item.Name())
+		}
+	}
+
+	s.Add(collectionPath, "items", items...)
+}
+
+// AddComputeRegions adds the regions of the project along with their zones
+func (s *Server) AddComputeRegions(project string, regions map[string][]string) {
+	for region, zones := range regions {
+		zoneLinks := make([]string, 0, len(zones))
+		for _, zone := range zones {
+			zoneLinks = append(zoneLinks,
+				fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s", project, zone))
+		}
+
+		s.AddCompute(ComputeProjectPath(project, "regions"), Item{
+			"name":  region,
+			"zones": zoneLinks,
+		})
+	}
+}
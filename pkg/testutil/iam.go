@@ -0,0 +1,202 @@
+package testutil
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"cloud.google.com/go/iam/admin/apiv1/adminpb"
+)
+
+// IAMServer is a fake of the IAM admin gRPC API, it supports listing and deleting service accounts, service account
+// keys and custom roles.
+type IAMServer struct {
+	adminpb.UnimplementedIAMServer
+
+	conn *grpc.ClientConn
+
+	mu              sync.Mutex
+	serviceAccounts []*adminpb.ServiceAccount
+	keys            map[string][]*adminpb.ServiceAccountKey
+	roles           []*adminpb.Role
+	deleted         []string
+}
+
+// NewIAMServer starts a new fake IAM admin server, it is stopped automatically when the test ends
+func NewIAMServer(t testing.TB) *IAMServer {
+	t.Helper()
+
+	s := &IAMServer{
+		keys: make(map[string][]*adminpb.ServiceAccountKey),
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	server := grpc.NewServer()
+	adminpb.RegisterIAMServer(server, s)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	s.conn, err = grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unable to connect to the fake iam server: %v", err)
+	}
+	t.Cleanup(func() { _ = s.conn.Close() })
+
+	return s
+}
+
+// ClientOptions returns the client options to use the fake server with the gRPC IAM admin client
+func (s *IAMServer) ClientOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithGRPCConn(s.conn),
+	}
+}
+
+// AddServiceAccounts adds service accounts, the name must be in the form projects/{project}/serviceAccounts/{email}
+func (s *IAMServer) AddServiceAccounts(serviceAccounts ...*adminpb.ServiceAccount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.serviceAccounts = append(s.serviceAccounts, serviceAccounts...)
+}
+
+// AddServiceAccountKeys adds keys to the service account with the given name
+func (s *IAMServer) AddServiceAccountKeys(serviceAccount string, keys ...*adminpb.ServiceAccountKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[serviceAccount] = append(s.keys[serviceAccount], keys...)
+}
+
+// AddRoles adds custom roles, the name must be in the form projects/{project}/roles/{role} or
+// organizations/{organization}/roles/{role}
+func (s *IAMServer) AddRoles(roles ...*adminpb.Role) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.roles = append(s.roles, roles...)
+}
+
+// Deleted returns the names of everything that was deleted, in order
+func (s *IAMServer) Deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := make([]string, len(s.deleted))
+	copy(deleted, s.deleted)
+
+	return deleted
+}
+
+func (s *IAMServer) ListServiceAccounts(
+	_ context.Context, req *adminpb.ListServiceAccountsRequest,
+) (*adminpb.ListServiceAccountsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &adminpb.ListServiceAccountsResponse{}
+	for _, sa := range s.serviceAccounts {
+		if strings.HasPrefix(sa.GetName(), req.GetName()+"/") {
+			resp.Accounts = append(resp.Accounts, proto.Clone(sa).(*adminpb.ServiceAccount))
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *IAMServer) DeleteServiceAccount(
+	_ context.Context, req *adminpb.DeleteServiceAccountRequest,
+) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, sa := range s.serviceAccounts {
+		if sa.GetName() == req.GetName() {
+			s.serviceAccounts = append(s.serviceAccounts[:i], s.serviceAccounts[i+1:]...)
+			s.deleted = append(s.deleted, req.GetName())
+			return &emptypb.Empty{}, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "service account %s not found", req.GetName())
+}
+
+func (s *IAMServer) ListServiceAccountKeys(
+	_ context.Context, req *adminpb.ListServiceAccountKeysRequest,
+) (*adminpb.ListServiceAccountKeysResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &adminpb.ListServiceAccountKeysResponse{}
+	for _, key := range s.keys[req.GetName()] {
+		resp.Keys = append(resp.Keys, proto.Clone(key).(*adminpb.ServiceAccountKey))
+	}
+
+	return resp, nil
+}
+
+func (s *IAMServer) DeleteServiceAccountKey(
+	_ context.Context, req *adminpb.DeleteServiceAccountKeyRequest,
+) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for serviceAccount, keys := range s.keys {
+		for i, key := range keys {
+			if key.GetName() == req.GetName() {
+				s.keys[serviceAccount] = append(keys[:i], keys[i+1:]...)
+				s.deleted = append(s.deleted, req.GetName())
+				return &emptypb.Empty{}, nil
+			}
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "key %s not found", req.GetName())
+}
+
+func (s *IAMServer) ListRoles(_ context.Context, req *adminpb.ListRolesRequest) (*adminpb.ListRolesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &adminpb.ListRolesResponse{}
+	for _, role := range s.roles {
+		if !strings.HasPrefix(role.GetName(), req.GetParent()+"/") {
+			continue
+		}
+		if role.GetDeleted() && !req.GetShowDeleted() {
+			continue
+		}
+		resp.Roles = append(resp.Roles, proto.Clone(role).(*adminpb.Role))
+	}
+
+	return resp, nil
+}
+
+func (s *IAMServer) DeleteRole(_ context.Context, req *adminpb.DeleteRoleRequest) (*adminpb.Role, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, role := range s.roles {
+		if role.GetName() == req.GetName() {
+			role.Deleted = true
+			s.deleted = append(s.deleted, req.GetName())
+			return proto.Clone(role).(*adminpb.Role), nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "role %s not found", req.GetName())
+}
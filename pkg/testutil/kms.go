@@ -0,0 +1,54 @@
+package testutil
+
+import (
+	"fmt"
+)
+
+// AddKMSKey adds a crypto key, along with its key ring and its primary version in the given state
+func (s *Server) AddKMSKey(project, location, keyRing, key, state string) {
+	keyRingsPath := fmt.Sprintf("/v1/projects/%s/locations/%s/keyRings", project, location)
+	keyRingName := fmt.Sprintf("projects/%s/locations/%s/keyRings/%s", project, location, keyRing)
+	keyName := fmt.Sprintf("%s/cryptoKeys/%s", keyRingName, key)
+	versionName := fmt.Sprintf("%s/cryptoKeyVersions/1", keyName)
+
+	if !containsItem(s.Items(keyRingsPath), keyRingName) {
+		s.Add(keyRingsPath, "keyRings", Item{
+			"name": keyRingName,
+		})
+	}
+
+	s.Add(fmt.Sprintf("/v1/%s/cryptoKeys", keyRingName), "cryptoKeys", Item{
+		"name":    keyName,
+		"purpose": "ENCRYPT_DECRYPT",
+		"primary": Item{
+			"name":  versionName,
+			"state": state,
+		},
+	})
+
+	s.Add(fmt.Sprintf("/v1/%s/cryptoKeyVersions", keyName), "cryptoKeyVersions", Item{
+		"name":  versionName,
+		"state": state,
+	})
+}
+
+// destroyCryptoKeyVersion schedules the destruction of a crypto key version, like the real API does
+func destroyCryptoKeyVersion(_ string, item Item, _ []byte) any {
+	if item == nil {
+		return nil
+	}
+
+	item["state"] = "DESTROY_SCHEDULED"
+
+	return item
+}
+
+func containsItem(items []Item, name string) bool {
+	for _, item := range items {
+		if item["name"] == name {
+			return true
+		}
+	}
+
+	return false
+}
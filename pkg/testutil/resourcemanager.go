@@ -0,0 +1,82 @@
+package testutil
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// AddOrganizations adds organizations, the name must be in the form organizations/{id}
+func (s *Server) AddOrganizations(organizations ...Item) {
+	s.Add("/v3/organizations", "organizations", organizations...)
+}
+
+// AddFolders adds folders, the name must be in the form folders/{id} and the parent is the folder or organization
+// they belong to
+func (s *Server) AddFolders(folders ...Item) {
+	s.Add("/v3/folders", "folders", folders...)
+}
+
+// AddProjects adds projects, the name must be in the form projects/{number} and the parent is the folder or
+// organization they belong to
+func (s *Server) AddProjects(projects ...Item) {
+	s.Add("/v3/projects", "projects", projects...)
+}
+
+// AddTagKeys adds tag keys, the name must be in the form tagKeys/{id}
+func (s *Server) AddTagKeys(tagKeys ...Item) {
+	s.Add("/v3/tagKeys", "tagKeys", tagKeys...)
+}
+
+// AddTagValues adds tag values, the name must be in the form tagValues/{id} and the parent is the tag key
+func (s *Server) AddTagValues(tagValues ...Item) {
+	s.Add("/v3/tagValues", "tagValues", tagValues...)
+}
+
+// SetIAMPolicy sets the IAM policy of a resource, e.g. "projects/my-project" or "organizations/123"
+func (s *Server) SetIAMPolicy(resource string, policy Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policies[iamPolicyKey(resource)] = policy
+}
+
+// IAMPolicy returns the current IAM policy of a resource
+func (s *Server) IAMPolicy(resource string) Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.policies[iamPolicyKey(resource)]
+}
+
+func (s *Server) getIAMPolicy(itemPath string, _ Item, _ []byte) any {
+	if policy, ok := s.policies[iamPolicyKey(itemPath)]; ok {
+		return policy
+	}
+
+	return Item{}
+}
+
+func (s *Server) setIAMPolicy(itemPath string, _ Item, body []byte) any {
+	var req struct {
+		Policy Item `json:"policy"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return Item{}
+	}
+
+	s.policies[iamPolicyKey(itemPath)] = req.Policy
+
+	return req.Policy
+}
+
+// iamPolicyKey strips the version of the API from the path of the resource, e.g. "/v3/projects/p" -> "projects/p"
+func iamPolicyKey(resource string) string {
+	resource = strings.TrimPrefix(resource, "/")
+	if strings.HasPrefix(resource, "v") {
+		if _, rest, ok := strings.Cut(resource, "/"); ok {
+			return rest
+		}
+	}
+
+	return resource
+}
@@ -0,0 +1,336 @@
+// Package testutil provides in-process fakes of the GCP APIs used by the listers, so that they can be tested without
+// a live project. The fakes are injected through nuke.ListerOpts.ClientOptions.
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+// Item is a single resource as returned by the REST API, using the JSON field names of the API
+type Item map[string]any
+
+// Name returns the last segment of the name of the item, which is how the item is addressed in a URL
+func (i Item) Name() string {
+	name, _ := i["name"].(string)
+	return path.Base(name)
+}
+
+// Request is a request that was received by the Server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// String returns the request in the form "METHOD /path"
+func (r Request) String() string {
+	return fmt.Sprintf("%s %s", r.Method, r.Path)
+}
+
+// ActionFunc handles a custom method (e.g. ":destroy") on an item. It receives the path of the item, the item (which
+// can be modified in place) and the body of the request, and returns the body of the response.
+type ActionFunc func(itemPath string, item Item, body []byte) any
+
+type collection struct {
+	key   string
+	items []Item
+}
+
+type apiError struct {
+	code    int
+	message string
+}
+
+// Server is a fake of the GCP REST APIs. It is a generic in-memory store of collections of items addressed by their
+// URL path, which is enough to fake the List, Get and Delete methods of most APIs:
+//
+//   - GET on a collection path returns all the items of the collection under the list key of the collection, when
+//     the request has a "parent" query parameter only the items with that parent are returned
+//   - GET on an item path returns the item
+//   - POST on a collection path inserts the item of the body, the response depends on the API (see operationResponse)
+//   - DELETE on an item path removes the item, the response depends on the API (see operationResponse)
+//   - a request with a custom method (e.g. "/v1/.../cryptoKeyVersions/1:destroy") calls the ActionFunc registered for
+//     the method, or returns the item if there is none
+//   - any other request on an item path returns the item
+//
+// Every request is recorded and can be asserted with Requests and HasRequest.
+type Server struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	actions     map[string]ActionFunc
+	errors      map[string]apiError
+	policies    map[string]Item
+	requests    []Request
+	operations  int
+}
+
+// NewServer starts a new fake server, it is stopped automatically when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		collections: make(map[string]*collection),
+		actions:     make(map[string]ActionFunc),
+		errors:      make(map[string]apiError),
+		policies:    make(map[string]Item),
+	}
+
+	s.HandleAction("destroy", destroyCryptoKeyVersion)
+	s.HandleAction("getIamPolicy", s.getIAMPolicy)
+	s.HandleAction("setIamPolicy", s.setIAMPolicy)
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// ClientOptions returns the client options to use the fake server with the REST clients of the GCP libraries
+func (s *Server) ClientOptions() []option.ClientOption {
+	return s.ClientOptionsWithBasePath("/")
+}
+
+// ClientOptionsWithBasePath returns the client options for the clients that expect the endpoint to include the path
+// of the API, for example storage expects "/storage/v1/"
+func (s *Server) ClientOptionsWithBasePath(basePath string) []option.ClientOption {
+	return []option.ClientOption{
+		option.WithEndpoint(s.server.URL + basePath),
+		option.WithoutAuthentication(),
+		option.WithHTTPClient(s.server.Client()),
+	}
+}
+
+// Add adds items to the collection at the path, key is the JSON field the items are returned under when the
+// collection is listed (e.g. "items" for compute or "keyRings" for KMS)
+func (s *Server) Add(collectionPath, key string, items ...Item) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionPath]
+	if !ok {
+		c = &collection{key: key}
+		s.collections[collectionPath] = c
+	}
+
+	c.items = append(c.items, items...)
+}
+
+// Items returns the items that are currently in the collection at the path
+func (s *Server) Items(collectionPath string) []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionPath]
+	if !ok {
+		return nil
+	}
+
+	items := make([]Item, len(c.items))
+	copy(items, c.items)
+
+	return items
+}
+
+// HandleAction registers the function that handles a custom method (e.g. "destroy") for all items
+func (s *Server) HandleAction(action string, fn ActionFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.actions[action] = fn
+}
+
+// SetError makes the server respond with an API error to all the requests with the method and path
+func (s *Server) SetError(method, requestPath string, code int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors[method+" "+requestPath] = apiError{code: code, message: message}
+}
+
+// Requests returns all the requests received by the server so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]Request, len(s.requests))
+	copy(requests, s.requests)
+
+	return requests
+}
+
+// HasRequest returns true if the server received a request with the method and path
+func (s *Server) HasRequest(method, requestPath string) bool {
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == requestPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Note: some clients put a path prefix on the endpoint (e.g. "/storage/v1/"), which leads to double slashes
+	requestPath := path.Clean(r.URL.Path)
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   requestPath,
+		Query:  r.URL.RawQuery,
+		Body:   string(body),
+	})
+
+	if e, ok := s.errors[r.Method+" "+requestPath]; ok {
+		writeError(w, e.code, e.message)
+		return
+	}
+
+	if c, ok := s.collections[requestPath]; ok && r.Method == http.MethodGet {
+		// Note: APIs that list by parent pass it as a query parameter, items with a parent are filtered on it
+		parent := r.URL.Query().Get("parent")
+
+		items := make([]Item, 0, len(c.items))
+		for _, item := range c.items {
+			if itemParent, ok := item["parent"].(string); ok && parent != "" && itemParent != parent {
+				continue
+			}
+			items = append(items, item)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]any{c.key: items})
+		return
+	}
+
+	if c, ok := s.collections[requestPath]; ok && r.Method == http.MethodPost {
+		var item Item
+		if err := json.Unmarshal(body, &item); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		c.items = append(c.items, item)
+		s.operationResponse(w, requestPath, item, "insert")
+		return
+	}
+
+	itemPath, action, _ := strings.Cut(requestPath, ":")
+
+	// Note: some APIs list with a custom method on the collection, e.g. "/v3/projects:search"
+	if c, ok := s.collections[itemPath]; ok && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, map[string]any{c.key: c.items})
+		return
+	}
+
+	collectionPath, name := path.Split(itemPath)
+	collectionPath = strings.TrimSuffix(collectionPath, "/")
+
+	var item Item
+	idx := -1
+	if c, ok := s.collections[collectionPath]; ok {
+		for i := range c.items {
+			if c.items[i].Name() == name {
+				idx, item = i, c.items[i]
+				break
+			}
+		}
+	}
+
+	// Note: custom methods are called even if the item does not exist, e.g. getIamPolicy on a project
+	if fn, ok := s.actions[action]; ok && action != "" {
+		writeJSON(w, http.StatusOK, fn(itemPath, item, body))
+		return
+	}
+
+	if item == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", requestPath))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		c := s.collections[collectionPath]
+		c.items = append(c.items[:idx], c.items[idx+1:]...)
+		s.operationResponse(w, collectionPath, item, "delete")
+	default:
+		writeJSON(w, http.StatusOK, item)
+	}
+}
+
+// operationResponse writes the response of an insert or a delete, it depends on the API:
+//   - compute returns an operation, the operation is stored as done so that polling it completes
+//   - storage returns no content
+//   - everything else returns a long-running operation that is already done
+func (s *Server) operationResponse(w http.ResponseWriter, collectionPath string, item Item, operationType string) {
+	s.operations++
+	operationName := fmt.Sprintf("operation-%d", s.operations)
+
+	switch {
+	case strings.HasPrefix(collectionPath, "/compute/"):
+		operationsPath := path.Join(path.Dir(collectionPath), "operations")
+
+		operation := Item{
+			"name":          operationName,
+			"operationType": operationType,
+			"targetLink":    item["selfLink"],
+			"status":        "DONE",
+			"progress":      100,
+		}
+		if _, ok := s.collections[operationsPath]; !ok {
+			s.collections[operationsPath] = &collection{key: "items"}
+		}
+		s.collections[operationsPath].items = append(s.collections[operationsPath].items, operation)
+
+		writeJSON(w, http.StatusOK, Item{
+			"name":          operationName,
+			"operationType": operationType,
+			"targetLink":    item["selfLink"],
+			"status":        "RUNNING",
+		})
+	case strings.HasPrefix(collectionPath, "/storage/"):
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeJSON(w, http.StatusOK, Item{
+			"name": fmt.Sprintf("operations/%s", operationName),
+			"done": true,
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"status":  http.StatusText(code),
+		},
+	})
+}
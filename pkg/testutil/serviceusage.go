@@ -0,0 +1,19 @@
+package testutil
+
+import (
+	"fmt"
+)
+
+// EnableServices adds the services as enabled on the project, e.g. "compute.googleapis.com"
+func (s *Server) EnableServices(project string, services ...string) {
+	for _, service := range services {
+		s.Add(fmt.Sprintf("/v1/projects/%s/services", project), "services", Item{
+			"name":   fmt.Sprintf("projects/%s/services/%s", project, service),
+			"parent": fmt.Sprintf("projects/%s", project),
+			"state":  "ENABLED",
+			"config": Item{
+				"name": service,
+			},
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package testutil
+
+import (
+	"fmt"
+
+	"google.golang.org/api/option"
+)
+
+// StorageClientOptions returns the client options to use the fake server with the storage client, which expects the
+// path of the JSON API to be part of the endpoint
+func (s *Server) StorageClientOptions() []option.ClientOption {
+	return s.ClientOptionsWithBasePath("/storage/v1/")
+}
+
+// AddBuckets adds empty storage buckets, the items need at least a name and a location
+func (s *Server) AddBuckets(buckets ...Item) {
+	s.Add("/storage/v1/b", "items", buckets...)
+
+	for _, bucket := range buckets {
+		s.Add(fmt.Sprintf("/storage/v1/b/%s/o", bucket.Name()), "items")
+	}
+}
+
+// AddObjects adds objects to the bucket
+func (s *Server) AddObjects(bucket string, objects ...Item) {
+	for _, object := range objects {
+		object["bucket"] = bucket
+	}
+
+	s.Add(fmt.Sprintf("/storage/v1/b/%s/o", bucket), "items", objects...)
+}
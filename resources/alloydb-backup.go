@@ -0,0 +1,150 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gotidy/ptr"
+
+	alloydb "cloud.google.com/go/alloydb/apiv1"
+	"cloud.google.com/go/alloydb/apiv1/alloydbpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const AlloyDBBackupResource = "AlloyDBBackup"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     AlloyDBBackupResource,
+		Scope:    nuke.Project,
+		Resource: &AlloyDBBackup{},
+		Lister:   &AlloyDBBackupLister{},
+	})
+}
+
+type AlloyDBBackupLister struct {
+	svc *alloydb.AlloyDBAdminClient
+}
+
+func (l *AlloyDBBackupLister) Close() {
+	if l.svc != nil {
+		_ = l.svc.Close()
+	}
+}
+
+func (l *AlloyDBBackupLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Regional, "alloydb.googleapis.com", AlloyDBBackupResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = alloydb.NewAlloyDBAdminClient(ctx, opts.GRPCClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := &alloydbpb.ListBackupsRequest{
+		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
+	}
+
+	backups, err := nuke.ListAll(ctx, opts, AlloyDBBackupResource, func() nuke.Iterator[*alloydbpb.Backup] {
+		return l.svc.ListBackups(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backup := range backups {
+		nameParts := strings.Split(backup.Name, "/")
+		name := nameParts[len(nameParts)-1]
+
+		resources = append(resources, &AlloyDBBackup{
+			svc:       l.svc,
+			Project:   opts.Project,
+			Region:    opts.Region,
+			FullName:  ptr.String(backup.Name),
+			Name:      ptr.String(name),
+			Cluster:   ptr.String(backup.ClusterName),
+			Type:      ptr.String(backup.Type.String()),
+			State:     ptr.String(backup.State.String()),
+			Labels:    backup.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(backup.CreateTime),
+
+			removeWait: opts.OperationTracker(),
+		})
+	}
+
+	return resources, nil
+}
+
+type AlloyDBBackup struct {
+	svc        *alloydb.AlloyDBAdminClient
+	removeOp   *alloydb.DeleteBackupOperation
+	removeWait nuke.OperationTracker
+	Project    *string
+	Region     *string
+	FullName   *string
+	Name       *string           `description:"The name of the AlloyDB backup"`
+	Cluster    *string           `description:"The full name of the cluster the backup was created from"`
+	Type       *string           `description:"The type of the backup, e.g. ON_DEMAND or AUTOMATED"`
+	State      *string           `description:"The current state of the backup"`
+	CreatedAt  *time.Time        `description:"The time the backup was created"`
+	Labels     map[string]string `property:"tagPrefix=label" description:"Labels associated with the backup"`
+}
+
+// Filter keeps the backups created by gcp-nuke before the removal of a cluster until their retention expires
+func (r *AlloyDBBackup) Filter() error {
+	return nuke.FilterRetainedBackup(r.Labels)
+}
+
+func (r *AlloyDBBackup) Remove(ctx context.Context) (err error) {
+	r.removeOp, err = r.svc.DeleteBackup(ctx, &alloydbpb.DeleteBackupRequest{
+		Name: *r.FullName,
+	})
+	return err
+}
+
+func (r *AlloyDBBackup) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *AlloyDBBackup) String() string {
+	return *r.Name
+}
+
+// Mark adds the mark to the labels of the backup, see nuke.Marker
+func (r *AlloyDBBackup) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateBackup(ctx, &alloydbpb.UpdateBackupRequest{
+		Backup: &alloydbpb.Backup{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *AlloyDBBackup) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
+	}
+
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
+}
@@ -2,19 +2,20 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	alloydb "cloud.google.com/go/alloydb/apiv1"
 	"cloud.google.com/go/alloydb/apiv1/alloydbpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
@@ -29,6 +30,10 @@ func init() {
 		Resource:  &AlloyDBCluster{},
 		Lister:    &AlloyDBClusterLister{},
 		DependsOn: []string{AlloyDBInstanceResource},
+		Settings: []string{
+			nuke.BackupBeforeDeleteSetting,
+			nuke.BackupRetentionSetting,
+		},
 	})
 }
 
@@ -52,7 +57,7 @@ func (l *AlloyDBClusterLister) List(ctx context.Context, o interface{}) ([]resou
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = alloydb.NewAlloyDBAdminClient(ctx, opts.ClientOptions...)
+		l.svc, err = alloydb.NewAlloyDBAdminClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -62,28 +67,29 @@ func (l *AlloyDBClusterLister) List(ctx context.Context, o interface{}) ([]resou
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	it := l.svc.ListClusters(ctx, req)
-	for {
-		cluster, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate alloydb clusters")
-			break
-		}
+	clusters, err := nuke.ListAll(ctx, opts, AlloyDBClusterResource, func() nuke.Iterator[*alloydbpb.Cluster] {
+		return l.svc.ListClusters(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, cluster := range clusters {
 		nameParts := strings.Split(cluster.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &AlloyDBCluster{
-			svc:      l.svc,
-			Project:  opts.Project,
-			Region:   opts.Region,
-			FullName: ptr.String(cluster.Name),
-			Name:     ptr.String(name),
-			State:    ptr.String(cluster.State.String()),
-			Labels:   cluster.Labels,
+			svc:       l.svc,
+			Project:   opts.Project,
+			Region:    opts.Region,
+			FullName:  ptr.String(cluster.Name),
+			Name:      ptr.String(name),
+			State:     ptr.String(cluster.State.String()),
+			Labels:    cluster.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(cluster.CreateTime),
+
+			removeWait:  opts.OperationTracker(),
+			waitTimeout: opts.WaitTimeout,
 		})
 	}
 
@@ -91,17 +97,30 @@ func (l *AlloyDBClusterLister) List(ctx context.Context, o interface{}) ([]resou
 }
 
 type AlloyDBCluster struct {
-	svc      *alloydb.AlloyDBAdminClient
-	removeOp *alloydb.DeleteClusterOperation
-	Project  *string
-	Region   *string
-	FullName *string
-	Name     *string           `description:"The name of the AlloyDB cluster"`
-	State    *string           `description:"The current state of the cluster"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"Labels associated with the cluster"`
+	svc         *alloydb.AlloyDBAdminClient
+	removeOp    *alloydb.DeleteClusterOperation
+	removeWait  nuke.OperationTracker
+	waitTimeout time.Duration
+	settings    *settings.Setting
+	backedUp    bool
+	Project     *string
+	Region      *string
+	FullName    *string
+	Name        *string           `description:"The name of the AlloyDB cluster"`
+	State       *string           `description:"The current state of the cluster"`
+	CreatedAt   *time.Time        `description:"The time the cluster was created"`
+	Labels      map[string]string `property:"tagPrefix=label" description:"Labels associated with the cluster"`
+}
+
+func (r *AlloyDBCluster) Settings(setting *settings.Setting) {
+	r.settings = setting
 }
 
 func (r *AlloyDBCluster) Remove(ctx context.Context) (err error) {
+	if err := r.backup(ctx); err != nil {
+		return err
+	}
+
 	r.removeOp, err = r.svc.DeleteCluster(ctx, &alloydbpb.DeleteClusterRequest{
 		Name:  *r.FullName,
 		Force: true,
@@ -117,19 +136,61 @@ func (r *AlloyDBCluster) String() string {
 	return *r.Name
 }
 
+// Mark adds the mark to the labels of the cluster, see nuke.Marker
+func (r *AlloyDBCluster) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateCluster(ctx, &alloydbpb.UpdateClusterRequest{
+		Cluster: &alloydbpb.Cluster{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = op.Wait(ctx)
+	return err
+}
+
 func (r *AlloyDBCluster) HandleWait(ctx context.Context) error {
 	if r.removeOp == nil {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("alloydb cluster remove op polling encountered error")
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
+}
+
+// backup creates an on-demand backup of the cluster, when the BackupBeforeDelete setting is enabled, and waits for it.
+// On-demand backups do not expire, they are removed as AlloyDBBackup once their retention expired.
+func (r *AlloyDBCluster) backup(ctx context.Context) error {
+	policy, err := nuke.NewBackupPolicy(r.settings)
+	if err != nil || !policy.Enabled || r.backedUp {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	backupID := nuke.BackupID(*r.Name, 63)
+	logrus.WithField("backup", backupID).Debug("creating backup before removing alloydb cluster")
+
+	op, err := r.svc.CreateBackup(ctx, &alloydbpb.CreateBackupRequest{
+		Parent:   fmt.Sprintf("projects/%s/locations/%s", *r.Project, *r.Region),
+		BackupId: backupID,
+		Backup: &alloydbpb.Backup{
+			Description: fmt.Sprintf("Backup of %s before its removal by gcp-nuke", *r.Name),
+			Type:        alloydbpb.Backup_ON_DEMAND,
+			ClusterName: *r.FullName,
+			Labels:      policy.Labels(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
 	}
 
+	if err := nuke.WaitOperation(ctx, r.waitTimeout, nuke.LROResultOperation[*alloydbpb.Backup](op)); err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	r.backedUp = true
+
 	return nil
 }
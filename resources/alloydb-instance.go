@@ -2,17 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	alloydb "cloud.google.com/go/alloydb/apiv1"
 	"cloud.google.com/go/alloydb/apiv1/alloydbpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -51,7 +49,7 @@ func (l *AlloyDBInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = alloydb.NewAlloyDBAdminClient(ctx, opts.ClientOptions...)
+		l.svc, err = alloydb.NewAlloyDBAdminClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,32 +59,26 @@ func (l *AlloyDBInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	clusterIt := l.svc.ListClusters(ctx, clusterReq)
-	for {
-		cluster, err := clusterIt.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate alloydb clusters")
-			break
-		}
+	clusters, err := nuke.ListAll(ctx, opts, AlloyDBInstanceResource, func() nuke.Iterator[*alloydbpb.Cluster] {
+		return l.svc.ListClusters(ctx, clusterReq)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, cluster := range clusters {
 		instanceReq := &alloydbpb.ListInstancesRequest{
 			Parent: cluster.Name,
 		}
 
-		instanceIt := l.svc.ListInstances(ctx, instanceReq)
-		for {
-			instance, err := instanceIt.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate alloydb instances")
-				break
-			}
+		instances, err := nuke.ListAll(ctx, opts, AlloyDBInstanceResource, func() nuke.Iterator[*alloydbpb.Instance] {
+			return l.svc.ListInstances(ctx, instanceReq)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, instance := range instances {
 			nameParts := strings.Split(instance.Name, "/")
 			name := nameParts[len(nameParts)-1]
 
@@ -103,6 +95,9 @@ func (l *AlloyDBInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 				State:        ptr.String(instance.State.String()),
 				InstanceType: ptr.String(instance.InstanceType.String()),
 				Labels:       instance.Labels,
+				CreatedAt:    nuke.CreatedAtFromProto(instance.CreateTime),
+
+				removeWait: opts.OperationTracker(),
 			})
 		}
 	}
@@ -113,6 +108,7 @@ func (l *AlloyDBInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 type AlloyDBInstance struct {
 	svc          *alloydb.AlloyDBAdminClient
 	removeOp     *alloydb.DeleteInstanceOperation
+	removeWait   nuke.OperationTracker
 	Project      *string
 	Region       *string
 	FullName     *string
@@ -120,6 +116,7 @@ type AlloyDBInstance struct {
 	Cluster      *string           `description:"The cluster this instance belongs to"`
 	State        *string           `description:"The current state of the instance"`
 	InstanceType *string           `description:"The type of the instance (PRIMARY, READ_POOL)"`
+	CreatedAt    *time.Time        `description:"The time the instance was created"`
 	Labels       map[string]string `property:"tagPrefix=label" description:"Labels associated with the instance"`
 }
 
@@ -138,19 +135,27 @@ func (r *AlloyDBInstance) String() string {
 	return *r.Name
 }
 
-func (r *AlloyDBInstance) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("alloydb instance remove op polling encountered error")
+// Mark adds the mark to the labels of the instance, see nuke.Marker
+func (r *AlloyDBInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateInstance(ctx, &alloydbpb.UpdateInstanceRequest{
+		Instance: &alloydbpb.Instance{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *AlloyDBInstance) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
@@ -2,18 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	artifactregistry "cloud.google.com/go/artifactregistry/apiv1"
 	"cloud.google.com/go/artifactregistry/apiv1/artifactregistrypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -40,43 +36,43 @@ func (l *ArtifactRegistryRepositoryLister) List(ctx context.Context, o interface
 	var resources []resource.Resource
 	opts := o.(*nuke.ListerOpts)
 
-	if err := opts.BeforeList(nuke.Regional, "artifactregistry.googleapis.com", ArtifactRegistryRepositoryResource); err != nil {
+	if err := opts.BeforeList(nuke.MultiRegional, "artifactregistry.googleapis.com", ArtifactRegistryRepositoryResource); err != nil {
 		return resources, nil
 	}
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = artifactregistry.NewClient(ctx, opts.ClientOptions...)
+		l.svc, err = artifactregistry.NewClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	req := &artifactregistrypb.ListRepositoriesRequest{
-		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
+		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, opts.Location("artifactregistry.googleapis.com")),
+	}
+	items, err := nuke.ListAll(ctx, opts, ArtifactRegistryRepositoryResource, func() nuke.Iterator[*artifactregistrypb.Repository] {
+		return l.svc.ListRepositories(ctx, req)
+	})
+	if err != nil {
+		return nil, err
 	}
-	it := l.svc.ListRepositories(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate artifact registry repositories")
-			break
-		}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &ArtifactRegistryRepository{
-			svc:      l.svc,
-			project:  opts.Project,
-			region:   opts.Region,
-			Name:     &name,
-			FullName: &resp.Name,
-			Format:   resp.Format.String(),
-			Labels:   resp.Labels,
+			svc:       l.svc,
+			project:   opts.Project,
+			region:    opts.Region,
+			Name:      &name,
+			FullName:  &resp.Name,
+			Format:    resp.Format.String(),
+			Labels:    resp.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -90,14 +86,16 @@ func (l *ArtifactRegistryRepositoryLister) Close() {
 }
 
 type ArtifactRegistryRepository struct {
-	svc      *artifactregistry.Client
-	removeOp *artifactregistry.DeleteRepositoryOperation
-	project  *string
-	region   *string
-	Name     *string
-	FullName *string
-	Format   string
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc        *artifactregistry.Client
+	removeOp   *artifactregistry.DeleteRepositoryOperation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	Name       *string
+	FullName   *string
+	Format     string
+	CreatedAt  *time.Time
+	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *ArtifactRegistryRepository) Remove(ctx context.Context) (err error) {
@@ -115,19 +113,22 @@ func (r *ArtifactRegistryRepository) String() string {
 	return *r.Name
 }
 
+// Mark adds the mark to the labels of the repository, see nuke.Marker
+func (r *ArtifactRegistryRepository) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.UpdateRepository(ctx, &artifactregistrypb.UpdateRepositoryRequest{
+		Repository: &artifactregistrypb.Repository{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
+
 func (r *ArtifactRegistryRepository) HandleWait(ctx context.Context) error {
 	if r.removeOp == nil {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
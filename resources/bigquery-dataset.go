@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
@@ -15,6 +16,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
 )
 
@@ -42,7 +44,7 @@ func (l *BigQueryDatasetLister) Close() {
 func (l *BigQueryDatasetLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
 	opts := o.(*nuke.ListerOpts)
 	var resources []resource.Resource
-	if err := opts.BeforeList(nuke.Regional, "bigquery.googleapis.com", BigQueryDatasetResource); err != nil {
+	if err := opts.BeforeList(nuke.MultiRegional, "bigquery.googleapis.com", BigQueryDatasetResource); err != nil {
 		return resources, err
 	}
 
@@ -54,49 +56,77 @@ func (l *BigQueryDatasetLister) List(ctx context.Context, o interface{}) ([]reso
 		}
 	}
 
-	it := l.svc.Datasets(ctx)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate bigquery datasets")
-			break
-		}
-
-		meta, err := resp.Metadata(ctx)
-		if err != nil {
-			logrus.WithError(err).Error("unable to get dataset metadata")
-			continue
-		}
+	datasets, err := l.listDatasets(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
 
-		if meta.Location != ptr.ToString(opts.Region) {
+	for _, d := range datasets {
+		// Note: the multi-region datasets, e.g. "US", are owned by their pseudo-region, e.g. "multi-region:us"
+		if gcputil.LocationRegion(d.meta.Location) != *opts.Region {
 			continue
 		}
 
 		resources = append(resources, &BigQueryDataset{
-			svc:      l.svc,
-			project:  opts.Project,
-			region:   opts.Region,
-			dataset:  resp,
-			Name:     ptr.String(resp.DatasetID),
-			Location: ptr.String(meta.Location),
-			Labels:   meta.Labels,
+			svc:       l.svc,
+			project:   opts.Project,
+			region:    opts.Region,
+			dataset:   d.dataset,
+			Name:      ptr.String(d.dataset.DatasetID),
+			Location:  ptr.String(d.meta.Location),
+			Labels:    d.meta.Labels,
+			CreatedAt: nuke.CreatedAtFromTime(d.meta.CreationTime),
 		})
 	}
 
 	return resources, nil
 }
 
+type bigQueryDatasetWithMetadata struct {
+	dataset *bigquery.Dataset
+	meta    *bigquery.DatasetMetadata
+}
+
+// listDatasets returns every dataset of the project with its metadata, the datasets are not regional so they are
+// listed once per run and partitioned by location by every region
+func (l *BigQueryDatasetLister) listDatasets(
+	ctx context.Context, opts *nuke.ListerOpts,
+) ([]bigQueryDatasetWithMetadata, error) {
+	return nuke.Cached(ctx, opts, BigQueryDatasetResource, "bigquery.googleapis.com", "datasets",
+		func() ([]bigQueryDatasetWithMetadata, error) {
+			// Note: the client is cached and bound to the first project it was created for, so the project must be set
+			datasets, err := nuke.ListAll(ctx, opts, BigQueryDatasetResource, func() nuke.Iterator[*bigquery.Dataset] {
+				it := l.svc.Datasets(ctx)
+				it.ProjectID = *opts.Project
+				return it
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			var result []bigQueryDatasetWithMetadata
+			for _, dataset := range datasets {
+				meta, err := dataset.Metadata(ctx)
+				if err != nil {
+					return nil, opts.ListError(BigQueryDatasetResource, err)
+				}
+
+				result = append(result, bigQueryDatasetWithMetadata{dataset: dataset, meta: meta})
+			}
+
+			return result, nil
+		})
+}
+
 type BigQueryDataset struct {
-	svc      *bigquery.Client
-	project  *string
-	region   *string
-	dataset  *bigquery.Dataset
-	Name     *string           `description:"The name of the dataset"`
-	Location *string           `description:"The location of the dataset"`
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc       *bigquery.Client
+	project   *string
+	region    *string
+	dataset   *bigquery.Dataset
+	Name      *string           `description:"The name of the dataset"`
+	Location  *string           `description:"The location of the dataset"`
+	CreatedAt *time.Time        `description:"The time the dataset was created"`
+	Labels    map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *BigQueryDataset) Remove(ctx context.Context) error {
@@ -134,3 +164,12 @@ func (r *BigQueryDataset) Properties() types.Properties {
 func (r *BigQueryDataset) String() string {
 	return *r.Name
 }
+
+// Mark adds the mark to the labels of the dataset, see nuke.Marker
+func (r *BigQueryDataset) Mark(ctx context.Context, markedAt time.Time) error {
+	update := bigquery.DatasetMetadataToUpdate{}
+	update.SetLabel(nuke.MarkedLabel, nuke.MarkValue(markedAt))
+
+	_, err := r.dataset.Update(ctx, update, "")
+	return err
+}
@@ -3,8 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
-
-	"github.com/sirupsen/logrus"
+	"time"
 
 	"cloud.google.com/go/bigtable"
 
@@ -30,13 +29,11 @@ func init() {
 }
 
 type BigtableInstanceLister struct {
-	svc *bigtable.InstanceAdminClient
+	svc bigtableInstanceAdminClients
 }
 
 func (l *BigtableInstanceLister) Close() {
-	if l.svc != nil {
-		_ = l.svc.Close()
-	}
+	l.svc.Close()
 }
 
 func (l *BigtableInstanceLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
@@ -48,22 +45,22 @@ func (l *BigtableInstanceLister) List(ctx context.Context, o interface{}) ([]res
 	}
 
 	if l.svc == nil {
-		var err error
-		l.svc, err = bigtable.NewInstanceAdminClient(ctx, *opts.Project, opts.ClientOptions...)
-		if err != nil {
-			return nil, err
-		}
+		l.svc = make(bigtableInstanceAdminClients)
 	}
 
-	instances, err := l.svc.Instances(ctx)
+	svc, err := l.svc.get(ctx, opts)
 	if err != nil {
-		logrus.WithError(err).Error("unable to list bigtable instances")
-		return resources, nil
+		return nil, err
+	}
+
+	instances, err := svc.Instances(ctx)
+	if err != nil {
+		return nil, opts.ListError(BigtableInstanceResource, err)
 	}
 
 	for _, inst := range instances {
 		resources = append(resources, &BigtableInstance{
-			svc:         l.svc,
+			svc:         svc,
 			project:     opts.Project,
 			Name:        inst.Name,
 			DisplayName: inst.DisplayName,
@@ -75,6 +72,35 @@ func (l *BigtableInstanceLister) List(ctx context.Context, o interface{}) ([]res
 	return resources, nil
 }
 
+// bigtableInstanceAdminClients are the instance admin clients by project. A client is bound to the project it was
+// created for, and a lister lists every project of the run.
+type bigtableInstanceAdminClients map[string]*bigtable.InstanceAdminClient
+
+// get returns the client of the project of the options, it is created on first use
+func (c bigtableInstanceAdminClients) get(
+	ctx context.Context, opts *nuke.ListerOpts,
+) (*bigtable.InstanceAdminClient, error) {
+	if svc, ok := c[*opts.Project]; ok {
+		return svc, nil
+	}
+
+	svc, err := bigtable.NewInstanceAdminClient(ctx, *opts.Project,
+		opts.GRPCClientOptionsFor("bigtable.googleapis.com")...)
+	if err != nil {
+		return nil, err
+	}
+
+	c[*opts.Project] = svc
+
+	return svc, nil
+}
+
+func (c bigtableInstanceAdminClients) Close() {
+	for _, svc := range c {
+		_ = svc.Close()
+	}
+}
+
 type BigtableInstance struct {
 	svc         *bigtable.InstanceAdminClient
 	project     *string
@@ -95,3 +121,12 @@ func (r *BigtableInstance) Properties() types.Properties {
 func (r *BigtableInstance) String() string {
 	return r.Name
 }
+
+// Mark adds the mark to the labels of the instance, see nuke.Marker. Only the labels are updated, the clusters are
+// left as they are.
+func (r *BigtableInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	return r.svc.UpdateInstanceWithClusters(ctx, &bigtable.InstanceWithClustersConfig{
+		InstanceID: r.Name,
+		Labels:     nuke.WithMark(r.Labels, markedAt),
+	})
+}
@@ -2,6 +2,9 @@ package resources
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -9,6 +12,7 @@ import (
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
@@ -22,17 +26,19 @@ func init() {
 		Scope:    nuke.Project,
 		Resource: &BigtableTable{},
 		Lister:   &BigtableTableLister{},
+		Settings: []string{
+			nuke.BackupBeforeDeleteSetting,
+			nuke.BackupRetentionSetting,
+		},
 	})
 }
 
 type BigtableTableLister struct {
-	instanceSvc *bigtable.InstanceAdminClient
+	instanceSvc bigtableInstanceAdminClients
 }
 
 func (l *BigtableTableLister) Close() {
-	if l.instanceSvc != nil {
-		_ = l.instanceSvc.Close()
-	}
+	l.instanceSvc.Close()
 }
 
 func (l *BigtableTableLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
@@ -43,22 +49,24 @@ func (l *BigtableTableLister) List(ctx context.Context, o interface{}) ([]resour
 		return resources, nil
 	}
 
+	clientOptions := opts.GRPCClientOptionsFor("bigtable.googleapis.com")
+
 	if l.instanceSvc == nil {
-		var err error
-		l.instanceSvc, err = bigtable.NewInstanceAdminClient(ctx, *opts.Project, opts.ClientOptions...)
-		if err != nil {
-			return nil, err
-		}
+		l.instanceSvc = make(bigtableInstanceAdminClients)
 	}
 
-	instances, err := l.instanceSvc.Instances(ctx)
+	instanceSvc, err := l.instanceSvc.get(ctx, opts)
 	if err != nil {
-		logrus.WithError(err).Error("unable to list bigtable instances")
-		return resources, nil
+		return nil, err
+	}
+
+	instances, err := instanceSvc.Instances(ctx)
+	if err != nil {
+		return nil, opts.ListError(BigtableTableResource, err)
 	}
 
 	for _, inst := range instances {
-		adminClient, err := bigtable.NewAdminClient(ctx, *opts.Project, inst.Name, opts.ClientOptions...)
+		adminClient, err := bigtable.NewAdminClient(ctx, *opts.Project, inst.Name, clientOptions...)
 		if err != nil {
 			logrus.WithError(err).Errorf("unable to create admin client for instance %s", inst.Name)
 			continue
@@ -66,17 +74,17 @@ func (l *BigtableTableLister) List(ctx context.Context, o interface{}) ([]resour
 
 		tables, err := adminClient.Tables(ctx)
 		if err != nil {
-			logrus.WithError(err).Errorf("unable to list tables for instance %s", inst.Name)
 			_ = adminClient.Close()
-			continue
+			return nil, opts.ListError(BigtableTableResource, err)
 		}
 
 		for _, tableName := range tables {
 			resources = append(resources, &BigtableTable{
-				svc:      adminClient,
-				project:  opts.Project,
-				Instance: inst.Name,
-				Name:     tableName,
+				svc:         adminClient,
+				instanceSvc: instanceSvc,
+				project:     opts.Project,
+				Instance:    inst.Name,
+				Name:        tableName,
 			})
 		}
 	}
@@ -85,13 +93,24 @@ func (l *BigtableTableLister) List(ctx context.Context, o interface{}) ([]resour
 }
 
 type BigtableTable struct {
-	svc      *bigtable.AdminClient
-	project  *string
-	Instance string
-	Name     string
+	svc         *bigtable.AdminClient
+	instanceSvc *bigtable.InstanceAdminClient
+	settings    *settings.Setting
+	backedUp    bool
+	project     *string
+	Instance    string
+	Name        string
+}
+
+func (r *BigtableTable) Settings(setting *settings.Setting) {
+	r.settings = setting
 }
 
 func (r *BigtableTable) Remove(ctx context.Context) error {
+	if err := r.backup(ctx); err != nil {
+		return err
+	}
+
 	return r.svc.DeleteTable(ctx, r.Name)
 }
 
@@ -102,3 +121,39 @@ func (r *BigtableTable) Properties() types.Properties {
 func (r *BigtableTable) String() string {
 	return r.Instance + "/" + r.Name
 }
+
+// backup creates a backup of the table in the first cluster of the instance, when the BackupBeforeDelete setting is
+// enabled. Bigtable backups have no labels, the run ID is part of the name of the backup and Bigtable removes it when
+// it expires.
+func (r *BigtableTable) backup(ctx context.Context) error {
+	policy, err := nuke.NewBackupPolicy(r.settings)
+	if err != nil || !policy.Enabled || r.backedUp {
+		return err
+	}
+
+	clusters, err := r.instanceSvc.Clusters(ctx, r.Instance)
+	if err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("unable to create backup: instance %s has no cluster", r.Instance)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	backupID := nuke.BackupID(r.Name, 50)
+	logrus.WithField("backup", backupID).Debug("creating backup before removing bigtable table")
+
+	// Note: the backup is created synchronously, Bigtable requires the expire time to be at least 6 hours and at most
+	// 90 days away
+	if err := r.svc.CreateBackup(ctx, r.Name, clusters[0].Name, backupID,
+		policy.ExpireTimeWithin(6*time.Hour, 90*24*time.Hour)); err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	r.backedUp = true
+
+	return nil
+}
@@ -2,18 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	certificatemanager "cloud.google.com/go/certificatemanager/apiv1"
 	"cloud.google.com/go/certificatemanager/apiv1/certificatemanagerpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -46,7 +42,7 @@ func (l *CertificateManagerCertificateMapEntryLister) List(ctx context.Context,
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = certificatemanager.NewClient(ctx, opts.ClientOptions...)
+		l.svc, err = certificatemanager.NewClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -55,31 +51,25 @@ func (l *CertificateManagerCertificateMapEntryLister) List(ctx context.Context,
 	mapsReq := &certificatemanagerpb.ListCertificateMapsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/global", *opts.Project),
 	}
-	mapsIt := l.svc.ListCertificateMaps(ctx, mapsReq)
-	for {
-		certMap, err := mapsIt.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate certificate maps")
-			break
-		}
+	certMaps, err := nuke.ListAll(ctx, opts, CertificateManagerCertificateMapEntryResource, func() nuke.Iterator[*certificatemanagerpb.CertificateMap] {
+		return l.svc.ListCertificateMaps(ctx, mapsReq)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, certMap := range certMaps {
 		entriesReq := &certificatemanagerpb.ListCertificateMapEntriesRequest{
 			Parent: certMap.Name,
 		}
-		entriesIt := l.svc.ListCertificateMapEntries(ctx, entriesReq)
-		for {
-			entry, err := entriesIt.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate certificate map entries")
-				break
-			}
+		entries, err := nuke.ListAll(ctx, opts, CertificateManagerCertificateMapEntryResource, func() nuke.Iterator[*certificatemanagerpb.CertificateMapEntry] {
+			return l.svc.ListCertificateMapEntries(ctx, entriesReq)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, entry := range entries {
 			nameParts := strings.Split(entry.Name, "/")
 			name := nameParts[len(nameParts)-1]
 
@@ -88,13 +78,16 @@ func (l *CertificateManagerCertificateMapEntryLister) List(ctx context.Context,
 
 			hostname := entry.GetHostname()
 			resources = append(resources, &CertificateManagerCertificateMapEntry{
-				svc:      l.svc,
-				project:  opts.Project,
-				Name:     &name,
-				FullName: &entry.Name,
-				MapName:  &mapName,
-				Hostname: &hostname,
-				Labels:   entry.Labels,
+				svc:       l.svc,
+				project:   opts.Project,
+				Name:      &name,
+				FullName:  &entry.Name,
+				MapName:   &mapName,
+				Hostname:  &hostname,
+				Labels:    entry.Labels,
+				CreatedAt: nuke.CreatedAtFromProto(entry.CreateTime),
+
+				removeWait: opts.OperationTracker(),
 			})
 		}
 	}
@@ -109,14 +102,16 @@ func (l *CertificateManagerCertificateMapEntryLister) Close() {
 }
 
 type CertificateManagerCertificateMapEntry struct {
-	svc      *certificatemanager.Client
-	removeOp *certificatemanager.DeleteCertificateMapEntryOperation
-	project  *string
-	Name     *string
-	FullName *string
-	MapName  *string
-	Hostname *string
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc        *certificatemanager.Client
+	removeOp   *certificatemanager.DeleteCertificateMapEntryOperation
+	removeWait nuke.OperationTracker
+	project    *string
+	Name       *string
+	FullName   *string
+	MapName    *string
+	Hostname   *string
+	CreatedAt  *time.Time
+	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *CertificateManagerCertificateMapEntry) Remove(ctx context.Context) (err error) {
@@ -134,19 +129,27 @@ func (r *CertificateManagerCertificateMapEntry) String() string {
 	return *r.Name
 }
 
-func (r *CertificateManagerCertificateMapEntry) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the certificate map entry, see nuke.Marker
+func (r *CertificateManagerCertificateMapEntry) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateCertificateMapEntry(ctx, &certificatemanagerpb.UpdateCertificateMapEntryRequest{
+		CertificateMapEntry: &certificatemanagerpb.CertificateMapEntry{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *CertificateManagerCertificateMapEntry) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
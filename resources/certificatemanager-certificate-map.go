@@ -2,18 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	certificatemanager "cloud.google.com/go/certificatemanager/apiv1"
 	"cloud.google.com/go/certificatemanager/apiv1/certificatemanagerpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -47,7 +43,7 @@ func (l *CertificateManagerCertificateMapLister) List(ctx context.Context, o int
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = certificatemanager.NewClient(ctx, opts.ClientOptions...)
+		l.svc, err = certificatemanager.NewClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -56,26 +52,26 @@ func (l *CertificateManagerCertificateMapLister) List(ctx context.Context, o int
 	req := &certificatemanagerpb.ListCertificateMapsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/global", *opts.Project),
 	}
-	it := l.svc.ListCertificateMaps(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate certificate maps")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, CertificateManagerCertificateMapResource, func() nuke.Iterator[*certificatemanagerpb.CertificateMap] {
+		return l.svc.ListCertificateMaps(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &CertificateManagerCertificateMap{
-			svc:      l.svc,
-			project:  opts.Project,
-			Name:     &name,
-			FullName: &resp.Name,
-			Labels:   resp.Labels,
+			svc:       l.svc,
+			project:   opts.Project,
+			Name:      &name,
+			FullName:  &resp.Name,
+			Labels:    resp.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -89,12 +85,14 @@ func (l *CertificateManagerCertificateMapLister) Close() {
 }
 
 type CertificateManagerCertificateMap struct {
-	svc      *certificatemanager.Client
-	removeOp *certificatemanager.DeleteCertificateMapOperation
-	project  *string
-	Name     *string
-	FullName *string
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc        *certificatemanager.Client
+	removeOp   *certificatemanager.DeleteCertificateMapOperation
+	removeWait nuke.OperationTracker
+	project    *string
+	Name       *string
+	FullName   *string
+	CreatedAt  *time.Time
+	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *CertificateManagerCertificateMap) Remove(ctx context.Context) (err error) {
@@ -112,19 +110,27 @@ func (r *CertificateManagerCertificateMap) String() string {
 	return *r.Name
 }
 
-func (r *CertificateManagerCertificateMap) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the certificate map, see nuke.Marker
+func (r *CertificateManagerCertificateMap) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateCertificateMap(ctx, &certificatemanagerpb.UpdateCertificateMapRequest{
+		CertificateMap: &certificatemanagerpb.CertificateMap{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *CertificateManagerCertificateMap) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
@@ -2,18 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	certificatemanager "cloud.google.com/go/certificatemanager/apiv1"
 	"cloud.google.com/go/certificatemanager/apiv1/certificatemanagerpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -42,7 +38,7 @@ func (l *CertificateManagerDNSAuthorizationLister) List(ctx context.Context, o i
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = certificatemanager.NewClient(ctx, opts.ClientOptions...)
+		l.svc, err = certificatemanager.NewClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -51,19 +47,17 @@ func (l *CertificateManagerDNSAuthorizationLister) List(ctx context.Context, o i
 	if err := opts.BeforeList(nuke.Global, "certificatemanager.googleapis.com", CertificateManagerDNSAuthorizationResource); err == nil {
 		globalResources, err := l.listLocation(ctx, opts, "global")
 		if err != nil {
-			logrus.WithError(err).Error("unable to list global certificate manager DNS authorizations")
-		} else {
-			resources = append(resources, globalResources...)
+			return nil, opts.ListError(CertificateManagerDNSAuthorizationResource, err)
 		}
+		resources = append(resources, globalResources...)
 	}
 
 	if err := opts.BeforeList(nuke.Regional, "certificatemanager.googleapis.com", CertificateManagerDNSAuthorizationResource); err == nil {
 		regionalResources, err := l.listLocation(ctx, opts, *opts.Region)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list regional certificate manager DNS authorizations")
-		} else {
-			resources = append(resources, regionalResources...)
+			return nil, opts.ListError(CertificateManagerDNSAuthorizationResource, err)
 		}
+		resources = append(resources, regionalResources...)
 	}
 
 	return resources, nil
@@ -75,28 +69,28 @@ func (l *CertificateManagerDNSAuthorizationLister) listLocation(ctx context.Cont
 	req := &certificatemanagerpb.ListDnsAuthorizationsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, location),
 	}
-	it := l.svc.ListDnsAuthorizations(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate certificate manager DNS authorizations")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, CertificateManagerDNSAuthorizationResource, func() nuke.Iterator[*certificatemanagerpb.DnsAuthorization] {
+		return l.svc.ListDnsAuthorizations(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &CertificateManagerDNSAuthorization{
-			svc:      l.svc,
-			project:  opts.Project,
-			Location: &location,
-			Name:     &name,
-			FullName: &resp.Name,
-			Domain:   &resp.Domain,
-			Labels:   resp.Labels,
+			svc:       l.svc,
+			project:   opts.Project,
+			Location:  &location,
+			Name:      &name,
+			FullName:  &resp.Name,
+			Domain:    &resp.Domain,
+			Labels:    resp.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -110,14 +104,16 @@ func (l *CertificateManagerDNSAuthorizationLister) Close() {
 }
 
 type CertificateManagerDNSAuthorization struct {
-	svc      *certificatemanager.Client
-	removeOp *certificatemanager.DeleteDnsAuthorizationOperation
-	project  *string
-	Location *string
-	Name     *string
-	FullName *string
-	Domain   *string
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc        *certificatemanager.Client
+	removeOp   *certificatemanager.DeleteDnsAuthorizationOperation
+	removeWait nuke.OperationTracker
+	project    *string
+	Location   *string
+	Name       *string
+	FullName   *string
+	Domain     *string
+	CreatedAt  *time.Time
+	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *CertificateManagerDNSAuthorization) Remove(ctx context.Context) (err error) {
@@ -135,19 +131,27 @@ func (r *CertificateManagerDNSAuthorization) String() string {
 	return *r.Name
 }
 
-func (r *CertificateManagerDNSAuthorization) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the DNS authorization, see nuke.Marker
+func (r *CertificateManagerDNSAuthorization) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateDnsAuthorization(ctx, &certificatemanagerpb.UpdateDnsAuthorizationRequest{
+		DnsAuthorization: &certificatemanagerpb.DnsAuthorization{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *CertificateManagerDNSAuthorization) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
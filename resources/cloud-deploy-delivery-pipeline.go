@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -55,29 +56,31 @@ func (l *CloudDeployDeliveryPipelineLister) List(ctx context.Context, o interfac
 			name := nameParts[len(nameParts)-1]
 
 			resources = append(resources, &CloudDeployDeliveryPipeline{
-				svc:      l.svc,
-				FullName: ptr.String(pipeline.Name),
-				Name:     ptr.String(name),
-				Project:  opts.Project,
-				Region:   opts.Region,
-				Labels:   pipeline.Labels,
+				svc:       l.svc,
+				FullName:  ptr.String(pipeline.Name),
+				Name:      ptr.String(name),
+				Project:   opts.Project,
+				Region:    opts.Region,
+				Labels:    pipeline.Labels,
+				CreatedAt: nuke.ParseCreatedAt(pipeline.CreateTime),
 			})
 		}
 		return nil
 	}); err != nil {
-		return resources, err
+		return nil, opts.ListError(CloudDeployDeliveryPipelineResource, err)
 	}
 
 	return resources, nil
 }
 
 type CloudDeployDeliveryPipeline struct {
-	svc      *clouddeploy.Service
-	Project  *string
-	Region   *string
-	FullName *string
-	Name     *string           `description:"The name of the delivery pipeline"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"The labels associated with the delivery pipeline"`
+	svc       *clouddeploy.Service
+	Project   *string
+	Region    *string
+	FullName  *string
+	Name      *string           `description:"The name of the delivery pipeline"`
+	CreatedAt *time.Time        `description:"The time the delivery pipeline was created"`
+	Labels    map[string]string `property:"tagPrefix=label" description:"The labels associated with the delivery pipeline"`
 }
 
 func (r *CloudDeployDeliveryPipeline) Remove(ctx context.Context) error {
@@ -92,3 +95,11 @@ func (r *CloudDeployDeliveryPipeline) Properties() types.Properties {
 func (r *CloudDeployDeliveryPipeline) String() string {
 	return *r.Name
 }
+
+// Mark adds the mark to the labels of the delivery pipeline, see nuke.Marker
+func (r *CloudDeployDeliveryPipeline) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.Projects.Locations.DeliveryPipelines.Patch(*r.FullName, &clouddeploy.DeliveryPipeline{
+		Labels: nuke.WithMark(r.Labels, markedAt),
+	}).UpdateMask("labels").Context(ctx).Do()
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -55,29 +56,31 @@ func (l *CloudDeployTargetLister) List(ctx context.Context, o interface{}) ([]re
 			name := nameParts[len(nameParts)-1]
 
 			resources = append(resources, &CloudDeployTarget{
-				svc:      l.svc,
-				FullName: ptr.String(target.Name),
-				Name:     ptr.String(name),
-				Project:  opts.Project,
-				Region:   opts.Region,
-				Labels:   target.Labels,
+				svc:       l.svc,
+				FullName:  ptr.String(target.Name),
+				Name:      ptr.String(name),
+				Project:   opts.Project,
+				Region:    opts.Region,
+				Labels:    target.Labels,
+				CreatedAt: nuke.ParseCreatedAt(target.CreateTime),
 			})
 		}
 		return nil
 	}); err != nil {
-		return resources, err
+		return nil, opts.ListError(CloudDeployTargetResource, err)
 	}
 
 	return resources, nil
 }
 
 type CloudDeployTarget struct {
-	svc      *clouddeploy.Service
-	Project  *string
-	Region   *string
-	FullName *string
-	Name     *string           `description:"The name of the target"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"The labels associated with the target"`
+	svc       *clouddeploy.Service
+	Project   *string
+	Region    *string
+	FullName  *string
+	Name      *string           `description:"The name of the target"`
+	CreatedAt *time.Time        `description:"The time the target was created"`
+	Labels    map[string]string `property:"tagPrefix=label" description:"The labels associated with the target"`
 }
 
 func (r *CloudDeployTarget) Remove(ctx context.Context) error {
@@ -92,3 +95,11 @@ func (r *CloudDeployTarget) Properties() types.Properties {
 func (r *CloudDeployTarget) String() string {
 	return *r.Name
 }
+
+// Mark adds the mark to the labels of the target, see nuke.Marker
+func (r *CloudDeployTarget) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.Projects.Locations.Targets.Patch(*r.FullName, &clouddeploy.Target{
+		Labels: nuke.WithMark(r.Labels, markedAt),
+	}).UpdateMask("labels").Context(ctx).Do()
+	return err
+}
@@ -2,7 +2,6 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -12,8 +11,6 @@ import (
 
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	"cloud.google.com/go/functions/apiv1"
 	"cloud.google.com/go/functions/apiv1/functionspb"
 
@@ -62,18 +59,16 @@ func (l *CloudFunctionLister) List(ctx context.Context, o interface{}) ([]resour
 			return nil, err
 		}
 
-		it := l.svc.ListLocations(ctx, &location.ListLocationsRequest{
-			Name: fmt.Sprintf("projects/%s", *opts.Project),
+		items, err := nuke.ListAll(ctx, opts, CloudFunctionResource, func() nuke.Iterator[*location.Location] {
+			return l.svc.ListLocations(ctx, &location.ListLocationsRequest{
+				Name: fmt.Sprintf("projects/%s", *opts.Project),
+			})
 		})
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				return nil, err
-			}
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range items {
 			l.locations = append(l.locations, resp.Name)
 		}
 	}
@@ -87,17 +82,14 @@ func (l *CloudFunctionLister) List(ctx context.Context, o interface{}) ([]resour
 	req := &functionspb.ListFunctionsRequest{
 		Parent: parent,
 	}
-	it := l.svc.ListFunctions(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate cloud functions")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, CloudFunctionResource, func() nuke.Iterator[*functionspb.CloudFunction] {
+		return l.svc.ListFunctions(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -109,6 +101,8 @@ func (l *CloudFunctionLister) List(ctx context.Context, o interface{}) ([]resour
 			Name:     ptr.String(name),
 			Labels:   resp.Labels,
 			Status:   ptr.String(resp.Status.String()),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -116,14 +110,15 @@ func (l *CloudFunctionLister) List(ctx context.Context, o interface{}) ([]resour
 }
 
 type CloudFunction struct {
-	svc      *functions.CloudFunctionsClient
-	removeOp *functions.DeleteFunctionOperation
-	project  *string
-	region   *string
-	fullName *string
-	Name     *string
-	Status   *string
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc        *functions.CloudFunctionsClient
+	removeOp   *functions.DeleteFunctionOperation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	fullName   *string
+	Name       *string
+	Status     *string
+	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *CloudFunction) Remove(ctx context.Context) (err error) {
@@ -152,17 +147,5 @@ func (r *CloudFunction) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.
-			WithField("resource", CloudFunctionResource).
-			WithError(err).
-			Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return fmt.Errorf("operation still in progress")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
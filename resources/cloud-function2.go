@@ -2,16 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	"cloud.google.com/go/functions/apiv2"
 	"cloud.google.com/go/functions/apiv2/functionspb"
 
@@ -62,28 +60,28 @@ func (l *CloudFunction2Lister) List(ctx context.Context, o interface{}) ([]resou
 	req := &functionspb.ListFunctionsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListFunctions(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate cloud functions")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, CloudFunction2Resource, func() nuke.Iterator[*functionspb.Function] {
+		return l.svc.ListFunctions(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &CloudFunction2{
-			svc:      l.svc,
-			FullName: ptr.String(resp.Name),
-			Name:     ptr.String(name),
-			Project:  opts.Project,
-			Region:   opts.Region,
-			Labels:   resp.Labels,
-			State:    ptr.String(resp.State.String()),
+			svc:       l.svc,
+			FullName:  ptr.String(resp.Name),
+			Name:      ptr.String(name),
+			Project:   opts.Project,
+			Region:    opts.Region,
+			Labels:    resp.Labels,
+			State:     ptr.String(resp.State.String()),
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -91,14 +89,16 @@ func (l *CloudFunction2Lister) List(ctx context.Context, o interface{}) ([]resou
 }
 
 type CloudFunction2 struct {
-	svc      *functions.FunctionClient
-	removeOp *functions.DeleteFunctionOperation
-	Project  *string
-	Region   *string
-	FullName *string           `property:"-"`
-	Name     *string           `property:"Name"`
-	Labels   map[string]string `property:"tagPrefix=label"`
-	State    *string
+	svc        *functions.FunctionClient
+	removeOp   *functions.DeleteFunctionOperation
+	removeWait nuke.OperationTracker
+	Project    *string
+	Region     *string
+	FullName   *string           `property:"-"`
+	Name       *string           `property:"Name"`
+	Labels     map[string]string `property:"tagPrefix=label"`
+	State      *string
+	CreatedAt  *time.Time
 }
 
 func (r *CloudFunction2) Remove(ctx context.Context) (err error) {
@@ -127,22 +127,5 @@ func (r *CloudFunction2) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		if strings.Contains(err.Error(), "proto") && strings.Contains(err.Error(), "missing") {
-			err = nil
-		}
-		if err != nil {
-			logrus.
-				WithField("resource", CloudFunction2Resource).
-				WithError(err).
-				Trace("remove op polling encountered error")
-			return err
-		}
-	}
-
-	if !r.removeOp.Done() {
-		return fmt.Errorf("operation still in progress")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
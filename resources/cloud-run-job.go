@@ -2,14 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	"cloud.google.com/go/run/apiv2"
 	"cloud.google.com/go/run/apiv2/runpb"
@@ -52,7 +49,7 @@ func (l *CloudRunJobLister) List(ctx context.Context, o interface{}) ([]resource
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = run.NewJobsClient(ctx, opts.ClientOptions...)
+		l.svc, err = run.NewJobsClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,27 +58,27 @@ func (l *CloudRunJobLister) List(ctx context.Context, o interface{}) ([]resource
 	req := &runpb.ListJobsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListJobs(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate cloud run jobs")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, CloudRunJobResource, func() nuke.Iterator[*runpb.Job] {
+		return l.svc.ListJobs(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &CloudRunJob{
-			svc:      l.svc,
-			FullName: ptr.String(resp.Name),
-			Name:     ptr.String(name),
-			Project:  opts.Project,
-			Region:   opts.Region,
-			Labels:   resp.Labels,
+			svc:       l.svc,
+			FullName:  ptr.String(resp.Name),
+			Name:      ptr.String(name),
+			Project:   opts.Project,
+			Region:    opts.Region,
+			Labels:    resp.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -89,13 +86,15 @@ func (l *CloudRunJobLister) List(ctx context.Context, o interface{}) ([]resource
 }
 
 type CloudRunJob struct {
-	svc      *run.JobsClient
-	removeOp *run.DeleteJobOperation
-	Project  *string
-	Region   *string
-	FullName *string
-	Name     *string           `description:"The name of the cloud run job"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"The labels associated with the cloud run job"`
+	svc        *run.JobsClient
+	removeOp   *run.DeleteJobOperation
+	removeWait nuke.OperationTracker
+	Project    *string
+	Region     *string
+	FullName   *string
+	Name       *string           `description:"The name of the cloud run job"`
+	CreatedAt  *time.Time        `description:"The time the job was created"`
+	Labels     map[string]string `property:"tagPrefix=label" description:"The labels associated with the cloud run job"`
 }
 
 func (r *CloudRunJob) Remove(ctx context.Context) (err error) {
@@ -113,15 +112,28 @@ func (r *CloudRunJob) String() string {
 	return *r.Name
 }
 
-func (r *CloudRunJob) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
+// Mark adds the mark to the labels of the job, see nuke.Marker. The job is updated as a whole.
+func (r *CloudRunJob) Mark(ctx context.Context, markedAt time.Time) error {
+	job, err := r.svc.GetJob(ctx, &runpb.GetJobRequest{Name: *r.FullName})
+	if err != nil {
+		return err
 	}
 
-	if _, err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("cloud run job remove op polling encountered error")
+	job.Labels = nuke.WithMark(job.Labels, markedAt)
+
+	op, err := r.svc.UpdateJob(ctx, &runpb.UpdateJobRequest{Job: job})
+	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *CloudRunJob) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
+	}
+
+	return r.removeWait.Wait(ctx, nuke.LROResultOperation[*runpb.Job](r.removeOp))
 }
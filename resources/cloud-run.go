@@ -5,13 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
-
 	"cloud.google.com/go/run/apiv2"
 	"cloud.google.com/go/run/apiv2/runpb"
 
@@ -53,7 +50,7 @@ func (l *CloudRunLister) List(ctx context.Context, o interface{}) ([]resource.Re
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = run.NewServicesClient(ctx, opts.ClientOptions...)
+		l.svc, err = run.NewServicesClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -62,27 +59,27 @@ func (l *CloudRunLister) List(ctx context.Context, o interface{}) ([]resource.Re
 	req := &runpb.ListServicesRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListServices(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, CloudRunResource, func() nuke.Iterator[*runpb.Service] {
+		return l.svc.ListServices(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &CloudRun{
-			svc:      l.svc,
-			FullName: ptr.String(resp.Name),
-			Name:     ptr.String(name),
-			Project:  opts.Project,
-			Region:   opts.Region,
-			Labels:   resp.Labels,
+			svc:       l.svc,
+			FullName:  ptr.String(resp.Name),
+			Name:      ptr.String(name),
+			Project:   opts.Project,
+			Region:    opts.Region,
+			Labels:    resp.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -90,13 +87,15 @@ func (l *CloudRunLister) List(ctx context.Context, o interface{}) ([]resource.Re
 }
 
 type CloudRun struct {
-	svc      *run.ServicesClient
-	removeOp *run.DeleteServiceOperation
-	Project  *string
-	Region   *string
-	FullName *string
-	Name     *string           `description:"The name of the cloud run"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"The labels associated with the cloud run"`
+	svc        *run.ServicesClient
+	removeOp   *run.DeleteServiceOperation
+	removeWait nuke.OperationTracker
+	Project    *string
+	Region     *string
+	FullName   *string
+	Name       *string           `description:"The name of the cloud run"`
+	CreatedAt  *time.Time        `description:"The time the service was created"`
+	Labels     map[string]string `property:"tagPrefix=label" description:"The labels associated with the cloud run"`
 }
 
 func (r *CloudRun) Filter() error {
@@ -122,15 +121,29 @@ func (r *CloudRun) String() string {
 	return *r.Name
 }
 
-func (r *CloudRun) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
+// Mark adds the mark to the labels of the service, see nuke.Marker. The service is updated as a whole, its template
+// is unchanged so no revision is created.
+func (r *CloudRun) Mark(ctx context.Context, markedAt time.Time) error {
+	service, err := r.svc.GetService(ctx, &runpb.GetServiceRequest{Name: *r.FullName})
+	if err != nil {
+		return err
 	}
 
-	if _, err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("network remove op polling encountered error")
+	service.Labels = nuke.WithMark(service.Labels, markedAt)
+
+	op, err := r.svc.UpdateService(ctx, &runpb.UpdateServiceRequest{Service: service})
+	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *CloudRun) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
+	}
+
+	return r.removeWait.Wait(ctx, nuke.LROResultOperation[*runpb.Service](r.removeOp))
 }
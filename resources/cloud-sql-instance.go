@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/gotidy/ptr"
@@ -26,6 +27,8 @@ func init() {
 		Lister:   &CloudSQLInstanceLister{},
 		Settings: []string{
 			"DisableDeletionProtection",
+			nuke.BackupBeforeDeleteSetting,
+			nuke.BackupRetentionSetting,
 		},
 	})
 }
@@ -50,12 +53,21 @@ func (l *CloudSQLInstanceLister) List(ctx context.Context, o interface{}) ([]res
 		}
 	}
 
-	resp, err := l.svc.Instances.List(*opts.Project).Context(ctx).Do()
+	// Note: the instances of every region are listed at once, they are listed once per run
+	instances, err := nuke.Cached(ctx, opts, CloudSQLInstanceResource, "sqladmin.googleapis.com", "instances",
+		func() ([]*sqladmin.DatabaseInstance, error) {
+			resp, err := l.svc.Instances.List(*opts.Project).Context(ctx).Do()
+			if err != nil {
+				return nil, opts.ListError(CloudSQLInstanceResource, err)
+			}
+
+			return resp.Items, nil
+		})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, instance := range resp.Items {
+	for _, instance := range instances {
 		if instance.Region != *opts.Region {
 			continue
 		}
@@ -69,7 +81,16 @@ func (l *CloudSQLInstanceLister) List(ctx context.Context, o interface{}) ([]res
 			Labels:           instance.Settings.UserLabels,
 			CreationDate:     ptr.String(instance.CreateTime),
 			DatabaseVersion:  ptr.String(instance.DatabaseVersion),
+			Tier:             ptr.String(instance.Settings.Tier),
+			AvailabilityType: ptr.String(instance.Settings.AvailabilityType),
+			ActivationPolicy: ptr.String(instance.Settings.ActivationPolicy),
+			DataDiskType:     ptr.String(instance.Settings.DataDiskType),
+			DataDiskSizeGb:   ptr.Int64(instance.Settings.DataDiskSizeGb),
 			instanceSettings: instance.Settings,
+			CreatedAt:        nuke.ParseCreatedAt(instance.CreateTime),
+
+			removeWait:  opts.OperationTracker(),
+			waitTimeout: opts.WaitTimeout,
 		})
 	}
 
@@ -77,17 +98,25 @@ func (l *CloudSQLInstanceLister) List(ctx context.Context, o interface{}) ([]res
 }
 
 type CloudSQLInstance struct {
-	svc      *sqladmin.Service
-	deleteOp *sqladmin.Operation
-	settings *settings.Setting
-
-	project         *string
-	region          *string
-	Name            *string           `description:"Name of the Cloud SQL instance"`
-	State           *string           `description:"The current serving state of the Cloud SQL instance"`
-	Labels          map[string]string `property:"tagPrefix=label" description:"The user-defined labels associated with this Cloud SQL instance"`
-	CreationDate    *string           `description:"The time when the instance was created"`
-	DatabaseVersion *string           `description:"The database engine type and version"`
+	svc         *sqladmin.Service
+	deleteOp    *sqladmin.Operation
+	removeWait  nuke.OperationTracker
+	waitTimeout time.Duration
+	settings    *settings.Setting
+
+	project          *string
+	region           *string
+	Name             *string           `description:"Name of the Cloud SQL instance"`
+	State            *string           `description:"The current serving state of the Cloud SQL instance"`
+	Labels           map[string]string `property:"tagPrefix=label" description:"The user-defined labels associated with this Cloud SQL instance"`
+	CreationDate     *string           `description:"The time when the instance was created"`
+	DatabaseVersion  *string           `description:"The database engine type and version"`
+	Tier             *string           `description:"The machine type of the instance, e.g. db-custom-2-7680"`
+	AvailabilityType *string           `description:"Whether the instance is zonal (ZONAL) or highly available (REGIONAL)"`
+	ActivationPolicy *string           `description:"Whether the instance is running (ALWAYS) or stopped (NEVER)"`
+	DataDiskType     *string           `description:"The type of the data disk, e.g. PD_SSD"`
+	DataDiskSizeGb   *int64            `description:"The size of the data disk in GB"`
+	CreatedAt        *time.Time        `description:"The time the instance was created"`
 
 	instanceSettings *sqladmin.Settings
 }
@@ -101,10 +130,66 @@ func (r *CloudSQLInstance) Remove(ctx context.Context) (err error) {
 		return disableErr
 	}
 
-	r.deleteOp, err = r.svc.Instances.Delete(*r.project, *r.Name).Context(ctx).Do()
+	policy, err := nuke.NewBackupPolicy(r.settings)
+	if err != nil {
+		return err
+	}
+
+	call := r.svc.Instances.Delete(*r.project, *r.Name)
+	if policy.Enabled {
+		// Note: the final backup is taken by the delete operation itself and is kept after the instance is gone, Cloud
+		// SQL removes it when it expires
+		call = call.EnableFinalBackup(true).
+			FinalBackupExpiryTime(policy.ExpireTimeWithin(24*time.Hour, 365*24*time.Hour).Format(time.RFC3339)).
+			FinalBackupDescription(fmt.Sprintf("Final backup of %s before its removal by gcp-nuke run %s",
+				*r.Name, nuke.RunID()))
+	}
+
+	r.deleteOp, err = call.Context(ctx).Do()
 	return err
 }
 
+// cloudSQLInstanceState is the state of a suspended instance, see nuke.Suspender
+type cloudSQLInstanceState struct {
+	ActivationPolicy string `json:"activationPolicy"`
+}
+
+// Suspend stops the instance, its activation policy is set to NEVER. See nuke.Suspender.
+func (r *CloudSQLInstance) Suspend(ctx context.Context) (nuke.SuspendedState, error) {
+	policy := r.instanceSettings.ActivationPolicy
+	if policy == "NEVER" {
+		return nil, nuke.NotSuspended("the instance is already stopped")
+	}
+
+	if err := r.setActivationPolicy(ctx, "NEVER"); err != nil {
+		return nil, err
+	}
+
+	return nuke.NewSuspendedState(&cloudSQLInstanceState{ActivationPolicy: policy})
+}
+
+// Resume sets the activation policy of the instance back, which starts it, see nuke.Suspender
+func (r *CloudSQLInstance) Resume(ctx context.Context, state nuke.SuspendedState) error {
+	suspended := &cloudSQLInstanceState{}
+	if err := state.Decode(suspended); err != nil {
+		return err
+	}
+
+	return r.setActivationPolicy(ctx, suspended.ActivationPolicy)
+}
+
+// setActivationPolicy sets the activation policy of the instance and waits for the operation
+func (r *CloudSQLInstance) setActivationPolicy(ctx context.Context, policy string) error {
+	op, err := r.svc.Instances.Patch(*r.project, *r.Name, &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{ActivationPolicy: policy},
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.SQLAdminOperation(r.svc, *r.project, op))
+}
+
 func (r *CloudSQLInstance) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -113,22 +198,24 @@ func (r *CloudSQLInstance) String() string {
 	return *r.Name
 }
 
+// Mark adds the mark to the user labels of the instance, see nuke.Marker
+func (r *CloudSQLInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.Instances.Patch(*r.project, *r.Name, &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{UserLabels: nuke.WithMark(r.Labels, markedAt)},
+	}).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.SQLAdminOperation(r.svc, *r.project, op))
+}
+
 func (r *CloudSQLInstance) HandleWait(ctx context.Context) error {
 	if r.deleteOp == nil {
 		return nil
 	}
 
-	if op, err := r.svc.Operations.Get(*r.project, r.deleteOp.Name).Context(ctx).Do(); err == nil {
-		if op.Status == "DONE" {
-			if op.Error != nil {
-				return fmt.Errorf("delete error on '%s': %s", op.TargetLink, op.Error.Errors[0].Message)
-			}
-		}
-	} else {
-		return err
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.SQLAdminOperation(r.svc, *r.project, r.deleteOp))
 }
 
 func (r *CloudSQLInstance) disableDeletionProtection(ctx context.Context) error {
@@ -143,14 +230,9 @@ func (r *CloudSQLInstance) disableDeletionProtection(ctx context.Context) error
 			return err
 		}
 
-		for {
-			op, err = r.svc.Operations.Get(*r.project, op.Name).Context(ctx).Do()
-			if err != nil {
-				return err
-			}
-			if op.Status == "DONE" {
-				break
-			}
+		// Note: the instance cannot be deleted until the update is done
+		if err := nuke.WaitOperation(ctx, r.waitTimeout, nuke.SQLAdminOperation(r.svc, *r.project, op)); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -2,14 +2,10 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	scheduler "cloud.google.com/go/scheduler/apiv1"
 	"cloud.google.com/go/scheduler/apiv1/schedulerpb"
@@ -61,19 +57,18 @@ func (l *CloudSchedulerJobLister) List(ctx context.Context, o interface{}) ([]re
 	req := &schedulerpb.ListJobsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListJobs(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			if strings.Contains(err.Error(), "not a valid location") {
-				return resources, nil
-			}
-			logrus.WithError(err).Error("unable to iterate cloud scheduler jobs")
-			break
+	jobs, err := nuke.ListAll(ctx, opts, CloudSchedulerJobResource, func() nuke.Iterator[*schedulerpb.Job] {
+		return l.svc.ListJobs(ctx, req)
+	})
+	if err != nil {
+		// Note: cloud scheduler is not available in every region, this is not a listing error
+		if strings.Contains(err.Error(), "not a valid location") {
+			return resources, nil
 		}
+		return nil, err
+	}
+
+	for _, resp := range jobs {
 
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
@@ -106,6 +101,25 @@ func (r *CloudSchedulerJob) Remove(ctx context.Context) error {
 	})
 }
 
+// Suspend pauses the enabled job, see nuke.Suspender
+func (r *CloudSchedulerJob) Suspend(ctx context.Context) (nuke.SuspendedState, error) {
+	if state := ptr.ToString(r.State); state != schedulerpb.Job_ENABLED.String() {
+		return nil, nuke.NotSuspended(fmt.Sprintf("the job is %s", state))
+	}
+
+	if _, err := r.svc.PauseJob(ctx, &schedulerpb.PauseJobRequest{Name: *r.FullName}); err != nil {
+		return nil, err
+	}
+
+	return nuke.NewSuspendedState(struct{}{})
+}
+
+// Resume resumes the paused job, see nuke.Suspender
+func (r *CloudSchedulerJob) Resume(ctx context.Context, _ nuke.SuspendedState) error {
+	_, err := r.svc.ResumeJob(ctx, &schedulerpb.ResumeJobRequest{Name: *r.FullName})
+	return err
+}
+
 func (r *CloudSchedulerJob) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
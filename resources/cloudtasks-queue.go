@@ -2,15 +2,12 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
 	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
-	"google.golang.org/api/iterator"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -50,7 +47,7 @@ func (l *CloudTasksQueueLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = cloudtasks.NewClient(ctx, opts.ClientOptions...)
+		l.svc, err = cloudtasks.NewClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -60,17 +57,14 @@ func (l *CloudTasksQueueLister) List(ctx context.Context, o interface{}) ([]reso
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	it := l.svc.ListQueues(ctx, req)
-	for {
-		queue, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate cloud tasks queues")
-			break
-		}
+	queues, err := nuke.ListAll(ctx, opts, CloudTasksQueueResource, func() nuke.Iterator[*cloudtaskspb.Queue] {
+		return l.svc.ListQueues(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, queue := range queues {
 		nameParts := strings.Split(queue.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
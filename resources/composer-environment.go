@@ -2,17 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	composer "cloud.google.com/go/orchestration/airflow/service/apiv1"
 	"cloud.google.com/go/orchestration/airflow/service/apiv1/servicepb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -51,7 +49,7 @@ func (l *ComposerEnvironmentLister) List(ctx context.Context, o interface{}) ([]
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = composer.NewEnvironmentsClient(ctx, opts.ClientOptions...)
+		l.svc, err = composer.NewEnvironmentsClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,28 +59,28 @@ func (l *ComposerEnvironmentLister) List(ctx context.Context, o interface{}) ([]
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	it := l.svc.ListEnvironments(ctx, req)
-	for {
-		env, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate composer environments")
-			break
-		}
+	envs, err := nuke.ListAll(ctx, opts, ComposerEnvironmentResource, func() nuke.Iterator[*servicepb.Environment] {
+		return l.svc.ListEnvironments(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, env := range envs {
 		nameParts := strings.Split(env.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &ComposerEnvironment{
-			svc:      l.svc,
-			Project:  opts.Project,
-			Region:   opts.Region,
-			FullName: ptr.String(env.Name),
-			Name:     ptr.String(name),
-			State:    ptr.String(env.State.String()),
-			Labels:   env.Labels,
+			svc:       l.svc,
+			Project:   opts.Project,
+			Region:    opts.Region,
+			FullName:  ptr.String(env.Name),
+			Name:      ptr.String(name),
+			State:     ptr.String(env.State.String()),
+			Labels:    env.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(env.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -90,14 +88,16 @@ func (l *ComposerEnvironmentLister) List(ctx context.Context, o interface{}) ([]
 }
 
 type ComposerEnvironment struct {
-	svc      *composer.EnvironmentsClient
-	removeOp *composer.DeleteEnvironmentOperation
-	Project  *string
-	Region   *string
-	FullName *string
-	Name     *string           `description:"The name of the Composer environment"`
-	State    *string           `description:"The current state of the environment"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"Labels associated with the environment"`
+	svc        *composer.EnvironmentsClient
+	removeOp   *composer.DeleteEnvironmentOperation
+	removeWait nuke.OperationTracker
+	Project    *string
+	Region     *string
+	FullName   *string
+	Name       *string           `description:"The name of the Composer environment"`
+	State      *string           `description:"The current state of the environment"`
+	CreatedAt  *time.Time        `description:"The time the environment was created"`
+	Labels     map[string]string `property:"tagPrefix=label" description:"Labels associated with the environment"`
 }
 
 func (r *ComposerEnvironment) Remove(ctx context.Context) (err error) {
@@ -115,19 +115,27 @@ func (r *ComposerEnvironment) String() string {
 	return *r.Name
 }
 
-func (r *ComposerEnvironment) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("composer environment remove op polling encountered error")
+// Mark adds the mark to the labels of the environment, see nuke.Marker
+func (r *ComposerEnvironment) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateEnvironment(ctx, &servicepb.UpdateEnvironmentRequest{
+		Name: *r.FullName,
+		Environment: &servicepb.Environment{
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *ComposerEnvironment) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
@@ -3,10 +3,8 @@ package resources
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -61,7 +59,7 @@ func (l *ComputeCommonInstanceMetadataLister) List(ctx context.Context, o interf
 
 	proj, err := l.svc.Get(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(ComputeCommonInstanceMetadataResource, err)
 	}
 
 	resources = append(resources, &ComputeCommonInstanceMetadata{
@@ -69,6 +67,8 @@ func (l *ComputeCommonInstanceMetadataLister) List(ctx context.Context, o interf
 		project:     proj.Name,
 		Fingerprint: proj.CommonInstanceMetadata.Fingerprint,
 		Items:       proj.CommonInstanceMetadata.Items,
+
+		removeWait: opts.OperationTracker(),
 	})
 
 	return resources, nil
@@ -77,6 +77,7 @@ func (l *ComputeCommonInstanceMetadataLister) List(ctx context.Context, o interf
 type ComputeCommonInstanceMetadata struct {
 	svc         *compute.ProjectsClient
 	removeOp    *compute.Operation
+	removeWait  nuke.OperationTracker
 	project     *string
 	Fingerprint *string
 	Items       []*computepb.Items `property:"tagPrefix=item"`
@@ -121,22 +122,5 @@ func (r *ComputeCommonInstanceMetadata) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		if strings.Contains(err.Error(), "proto") && strings.Contains(err.Error(), "missing") {
-			err = nil
-		}
-		if err != nil {
-			logrus.
-				WithField("resource", ComputeCommonInstanceMetadataResource).
-				WithError(err).
-				Trace("remove op polling encountered error")
-			return err
-		}
-	}
-
-	if !r.removeOp.Done() {
-		return fmt.Errorf("operation still in progress")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeMetadataOperation(r.removeOp))
 }
@@ -2,20 +2,19 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
@@ -29,17 +28,25 @@ func init() {
 		Scope:    nuke.Project,
 		Resource: &ComputeDisk{},
 		Lister:   &ComputeDiskLister{},
+		Settings: []string{
+			nuke.BackupBeforeDeleteSetting,
+			nuke.BackupRetentionSetting,
+		},
 	})
 }
 
 type ComputeDiskLister struct {
-	svc *compute.DisksClient
+	svc         *compute.DisksClient
+	snapshotSvc *compute.SnapshotsClient
 }
 
 func (l *ComputeDiskLister) Close() {
 	if l.svc != nil {
 		_ = l.svc.Close()
 	}
+	if l.snapshotSvc != nil {
+		_ = l.snapshotSvc.Close()
+	}
 }
 
 func (l *ComputeDiskLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
@@ -58,37 +65,46 @@ func (l *ComputeDiskLister) List(ctx context.Context, o interface{}) ([]resource
 		}
 	}
 
+	if l.snapshotSvc == nil {
+		var err error
+		l.snapshotSvc, err = compute.NewSnapshotsRESTClient(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, zone := range opts.Zones {
 		req := &computepb.ListDisksRequest{
 			Project: *opts.Project,
 			Zone:    zone,
 		}
 
-		it := l.svc.List(ctx, req)
-
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate compute disks")
-				break
-			}
+		items, err := nuke.ListAll(ctx, opts, ComputeDiskResource, func() nuke.Iterator[*computepb.Disk] {
+			return l.svc.List(ctx, req)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range items {
 			typeParts := strings.Split(resp.GetType(), "/")
 			typeName := typeParts[len(typeParts)-1]
 
 			resources = append(resources, &ComputeDisk{
-				svc:     l.svc,
-				project: opts.Project,
-				region:  opts.Region,
-				Name:    resp.Name,
-				Zone:    ptr.String(zone),
-				Arch:    resp.Architecture,
-				Size:    resp.SizeGb,
-				Type:    ptr.String(typeName),
-				Labels:  resp.Labels,
+				svc:              l.svc,
+				snapshotSvc:      l.snapshotSvc,
+				project:          opts.Project,
+				region:           opts.Region,
+				Name:             resp.Name,
+				Zone:             ptr.String(zone),
+				Arch:             resp.Architecture,
+				Size:             resp.SizeGb,
+				Type:             ptr.String(typeName),
+				Labels:           resp.Labels,
+				CreatedAt:        nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+				labelFingerprint: resp.LabelFingerprint,
+
+				waitTimeout: opts.WaitTimeout,
 			})
 		}
 	}
@@ -97,18 +113,32 @@ func (l *ComputeDiskLister) List(ctx context.Context, o interface{}) ([]resource
 }
 
 type ComputeDisk struct {
-	svc     *compute.DisksClient
-	project *string
-	region  *string
-	Name    *string
-	Zone    *string
-	Arch    *string
-	Size    *int64
-	Type    *string
-	Labels  map[string]string `property:"tagPrefix=label"`
+	svc              *compute.DisksClient
+	waitTimeout      time.Duration
+	snapshotSvc      *compute.SnapshotsClient
+	settings         *settings.Setting
+	backedUp         bool
+	labelFingerprint *string
+	project          *string
+	region           *string
+	Name             *string
+	Zone             *string
+	Arch             *string
+	Size             *int64
+	Type             *string
+	CreatedAt        *time.Time
+	Labels           map[string]string `property:"tagPrefix=label"`
+}
+
+func (r *ComputeDisk) Settings(setting *settings.Setting) {
+	r.settings = setting
 }
 
 func (r *ComputeDisk) Remove(ctx context.Context) error {
+	if err := r.snapshot(ctx); err != nil {
+		return err
+	}
+
 	_, err := r.svc.Delete(ctx, &computepb.DeleteDiskRequest{
 		Project: *r.project,
 		Zone:    *r.Zone,
@@ -117,6 +147,24 @@ func (r *ComputeDisk) Remove(ctx context.Context) error {
 	return err
 }
 
+// Mark adds the mark to the labels of the disk, see nuke.Marker
+func (r *ComputeDisk) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &computepb.SetLabelsDiskRequest{
+		Project:  *r.project,
+		Zone:     *r.Zone,
+		Resource: *r.Name,
+		ZoneSetLabelsRequestResource: &computepb.ZoneSetLabelsRequest{
+			Labels:           nuke.WithMark(r.Labels, markedAt),
+			LabelFingerprint: r.labelFingerprint,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
+
 func (r *ComputeDisk) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -124,3 +172,64 @@ func (r *ComputeDisk) Properties() types.Properties {
 func (r *ComputeDisk) String() string {
 	return *r.Name
 }
+
+// snapshot creates a snapshot of the disk, when the BackupBeforeDelete setting is enabled, and waits for it
+func (r *ComputeDisk) snapshot(ctx context.Context) error {
+	policy, err := nuke.NewBackupPolicy(r.settings)
+	if err != nil || !policy.Enabled || r.backedUp {
+		return err
+	}
+
+	// Note: the snapshots are global, the hash of the disk keeps the snapshots of the disks of other zones apart
+	sourceDisk := fmt.Sprintf("projects/%s/zones/%s/disks/%s", *r.project, *r.Zone, *r.Name)
+	name := nuke.SourceBackupID(*r.Name, sourceDisk, 63)
+	logrus.WithField("snapshot", name).Debug("creating snapshot before removing disk")
+
+	op, err := r.snapshotSvc.Insert(ctx, &computepb.InsertSnapshotRequest{
+		Project: *r.project,
+		SnapshotResource: &computepb.Snapshot{
+			Name:        ptr.String(name),
+			Description: ptr.String(fmt.Sprintf("Snapshot of disk %s before its removal by gcp-nuke", *r.Name)),
+			SourceDisk:  ptr.String(sourceDisk),
+			Labels:      policy.Labels(),
+		},
+	})
+	if nuke.IsAlreadyExists(err) {
+		return r.existingSnapshot(ctx, name)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create snapshot: %w", err)
+	}
+
+	if err := nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op)); err != nil {
+		return fmt.Errorf("unable to create snapshot: %w", err)
+	}
+
+	r.backedUp = true
+
+	return nil
+}
+
+// existingSnapshot checks the snapshot of the disk that already exists, it was created by a previous attempt of this
+// run when it has the label of the run. The disk is backed up once that snapshot is ready.
+func (r *ComputeDisk) existingSnapshot(ctx context.Context, name string) error {
+	snapshot, err := r.snapshotSvc.Get(ctx, &computepb.GetSnapshotRequest{
+		Project:  *r.project,
+		Snapshot: name,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create snapshot: %w", err)
+	}
+
+	if snapshot.GetLabels()[nuke.BackupRunLabel] != nuke.RunID() {
+		return fmt.Errorf("unable to create snapshot: snapshot %s already exists", name)
+	}
+
+	if status := snapshot.GetStatus(); status != computepb.Snapshot_READY.String() {
+		return fmt.Errorf("snapshot %s is not ready yet, its status is %s", name, status)
+	}
+
+	r.backedUp = true
+
+	return nil
+}
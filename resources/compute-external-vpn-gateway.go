@@ -2,17 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -63,23 +57,25 @@ func (l *ComputeExternalVpnGatewayLister) List(ctx context.Context, o interface{
 	req := &computepb.ListExternalVpnGatewaysRequest{
 		Project: *opts.Project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate external vpn gateways")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeExternalVpnGatewayResource, func() nuke.Iterator[*computepb.ExternalVpnGateway] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeExternalVpnGateway{
 			svc:            l.svc,
 			project:        opts.Project,
 			Name:           resp.Name,
 			RedundancyType: resp.RedundancyType,
 			Labels:         resp.Labels,
+			CreatedAt:      nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			labelFingerprint: resp.LabelFingerprint,
+			removeWait:       opts.OperationTracker(),
+			waitTimeout:      opts.WaitTimeout,
 		})
 	}
 
@@ -87,12 +83,16 @@ func (l *ComputeExternalVpnGatewayLister) List(ctx context.Context, o interface{
 }
 
 type ComputeExternalVpnGateway struct {
-	svc            *compute.ExternalVpnGatewaysClient
-	removeOp       *compute.Operation
-	project        *string
-	Name           *string
-	RedundancyType *string
-	Labels         map[string]string `property:"tagPrefix=label"`
+	svc              *compute.ExternalVpnGatewaysClient
+	removeOp         *compute.Operation
+	removeWait       nuke.OperationTracker
+	project          *string
+	labelFingerprint *string
+	waitTimeout      time.Duration
+	Name             *string
+	RedundancyType   *string
+	CreatedAt        *time.Time
+	Labels           map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *ComputeExternalVpnGateway) Remove(ctx context.Context) (err error) {
@@ -111,27 +111,27 @@ func (r *ComputeExternalVpnGateway) String() string {
 	return *r.Name
 }
 
-func (r *ComputeExternalVpnGateway) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the VPN gateway, see nuke.Marker
+func (r *ComputeExternalVpnGateway) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &computepb.SetLabelsExternalVpnGatewayRequest{
+		Project:  *r.project,
+		Resource: *r.Name,
+		GlobalSetLabelsRequestResource: &computepb.GlobalSetLabelsRequest{
+			Labels:           nuke.WithMark(r.Labels, markedAt),
+			LabelFingerprint: r.labelFingerprint,
+		},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
 
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeExternalVpnGatewayResource)
-			return removeErr
-		}
+func (r *ComputeExternalVpnGateway) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
@@ -2,11 +2,7 @@ package resources
 
 import (
 	"context"
-	"errors"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -58,21 +54,19 @@ func (l *ComputeFirewallLister) List(ctx context.Context, o interface{}) ([]reso
 	req := &computepb.ListFirewallsRequest{
 		Project: *opts.Project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeFirewallResource, func() nuke.Iterator[*computepb.Firewall] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeFirewall{
-			svc:     l.svc,
-			Name:    resp.Name,
-			Project: opts.Project,
+			svc:       l.svc,
+			Name:      resp.Name,
+			Project:   opts.Project,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -80,10 +74,11 @@ func (l *ComputeFirewallLister) List(ctx context.Context, o interface{}) ([]reso
 }
 
 type ComputeFirewall struct {
-	svc     *compute.FirewallsClient
-	Project *string
-	Region  *string
-	Name    *string
+	svc       *compute.FirewallsClient
+	Project   *string
+	Region    *string
+	Name      *string
+	CreatedAt *time.Time
 }
 
 func (r *ComputeFirewall) Remove(ctx context.Context) error {
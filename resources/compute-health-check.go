@@ -2,11 +2,7 @@ package resources
 
 import (
 	"context"
-	"errors"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -59,24 +55,21 @@ func (l *ComputeHealthCheckLister) List(ctx context.Context, o interface{}) ([]r
 		Project: *opts.Project,
 	}
 
-	it := l.svc.List(ctx, req)
-
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate compute health checks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeHealthCheckResource, func() nuke.Iterator[*computepb.HealthCheck] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeHealthCheck{
 			svc:               l.svc,
 			Name:              resp.Name,
 			Project:           opts.Project,
 			CreationTimestamp: resp.CreationTimestamp,
 			Type:              resp.Type,
+			CreatedAt:         nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -89,6 +82,7 @@ type ComputeHealthCheck struct {
 	Name              *string
 	CreationTimestamp *string
 	Type              *string
+	CreatedAt         *time.Time
 }
 
 func (r *ComputeHealthCheck) Remove(ctx context.Context) error {
@@ -0,0 +1,245 @@
+package resources
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gotidy/ptr"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const ComputeInstanceGroupManagerResource = "ComputeInstanceGroupManager"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ComputeInstanceGroupManagerResource,
+		Scope:    nuke.Project,
+		Resource: &ComputeInstanceGroupManager{},
+		Lister:   &ComputeInstanceGroupManagerLister{},
+	})
+}
+
+type ComputeInstanceGroupManagerLister struct {
+	svc         *compute.InstanceGroupManagersClient
+	autoscalers *compute.AutoscalersClient
+}
+
+func (l *ComputeInstanceGroupManagerLister) Close() {
+	if l.svc != nil {
+		_ = l.svc.Close()
+	}
+	if l.autoscalers != nil {
+		_ = l.autoscalers.Close()
+	}
+}
+
+func (l *ComputeInstanceGroupManagerLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Regional, "compute.googleapis.com", ComputeInstanceGroupManagerResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = compute.NewInstanceGroupManagersRESTClient(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if l.autoscalers == nil {
+		var err error
+		l.autoscalers, err = compute.NewAutoscalersRESTClient(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, zone := range opts.Zones {
+		req := &computepb.ListInstanceGroupManagersRequest{
+			Project: *opts.Project,
+			Zone:    zone,
+		}
+
+		items, err := nuke.ListAll(ctx, opts, ComputeInstanceGroupManagerResource,
+			func() nuke.Iterator[*computepb.InstanceGroupManager] {
+				return l.svc.List(ctx, req)
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resp := range items {
+			resources = append(resources, &ComputeInstanceGroupManager{
+				svc:               l.svc,
+				autoscalers:       l.autoscalers,
+				Name:              resp.Name,
+				Project:           opts.Project,
+				Zone:              ptr.String(zone),
+				TargetSize:        resp.TargetSize,
+				Autoscaler:        lastPathSegment(resp.GetStatus().GetAutoscaler()),
+				CreationTimestamp: resp.CreationTimestamp,
+				CreatedAt:         nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+				waitTimeout: opts.WaitTimeout,
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+type ComputeInstanceGroupManager struct {
+	svc               *compute.InstanceGroupManagersClient
+	waitTimeout       time.Duration
+	autoscalers       *compute.AutoscalersClient
+	Project           *string
+	Zone              *string
+	Name              *string    `description:"The name of the managed instance group"`
+	TargetSize        *int32     `description:"The number of instances the group is meant to run"`
+	Autoscaler        *string    `description:"The name of the autoscaler of the group, if any"`
+	CreationTimestamp *string    `description:"The time the group was created"`
+	CreatedAt         *time.Time `description:"The time the group was created"`
+}
+
+// computeInstanceGroupManagerState is the state of a suspended group, see nuke.Suspender
+type computeInstanceGroupManagerState struct {
+	TargetSize     int32  `json:"targetSize"`
+	AutoscalerMode string `json:"autoscalerMode,omitempty"`
+}
+
+func (r *ComputeInstanceGroupManager) Remove(ctx context.Context) error {
+	_, err := r.svc.Delete(ctx, &computepb.DeleteInstanceGroupManagerRequest{
+		Project:              *r.Project,
+		Zone:                 *r.Zone,
+		InstanceGroupManager: *r.Name,
+	})
+	return err
+}
+
+// Suspend resizes the group to zero instances, its autoscaler is turned off first so that it stays empty. The groups
+// of the node pools of the GKE clusters are left to GKE, see GKECluster. See nuke.Suspender.
+func (r *ComputeInstanceGroupManager) Suspend(ctx context.Context) (nuke.SuspendedState, error) {
+	if strings.HasPrefix(*r.Name, "gke-") && strings.HasSuffix(*r.Name, "-grp") {
+		return nil, nuke.NotSuspended("the group belongs to a node pool of a GKE cluster")
+	}
+
+	state := &computeInstanceGroupManagerState{TargetSize: ptr.ToInt32(r.TargetSize)}
+
+	if r.Autoscaler != nil {
+		autoscaler, err := r.autoscalers.Get(ctx, &computepb.GetAutoscalerRequest{
+			Project:    *r.Project,
+			Zone:       *r.Zone,
+			Autoscaler: *r.Autoscaler,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if mode := autoscaler.GetAutoscalingPolicy().GetMode(); mode != "OFF" {
+			state.AutoscalerMode = mode
+		}
+	}
+
+	if state.TargetSize == 0 && state.AutoscalerMode == "" {
+		return nil, nuke.NotSuspended("the group has no instances")
+	}
+
+	suspended, err := nuke.NewSuspendedState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.AutoscalerMode != "" {
+		if err := r.setAutoscalerMode(ctx, "OFF"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Note: the autoscaler is off by now, the state is returned along with the error so that it is turned back on
+	if err := r.resize(ctx, 0); err != nil {
+		return suspended, err
+	}
+
+	return suspended, nil
+}
+
+// Resume resizes the group back to its size and turns its autoscaler back on, see nuke.Suspender
+func (r *ComputeInstanceGroupManager) Resume(ctx context.Context, state nuke.SuspendedState) error {
+	suspended := &computeInstanceGroupManagerState{}
+	if err := state.Decode(suspended); err != nil {
+		return err
+	}
+
+	if err := r.resize(ctx, suspended.TargetSize); err != nil {
+		return err
+	}
+
+	if suspended.AutoscalerMode == "" || r.Autoscaler == nil {
+		return nil
+	}
+
+	return r.setAutoscalerMode(ctx, suspended.AutoscalerMode)
+}
+
+// resize sets the number of instances of the group and waits for the operation
+func (r *ComputeInstanceGroupManager) resize(ctx context.Context, size int32) error {
+	op, err := r.svc.Resize(ctx, &computepb.ResizeInstanceGroupManagerRequest{
+		Project:              *r.Project,
+		Zone:                 *r.Zone,
+		InstanceGroupManager: *r.Name,
+		Size:                 size,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
+
+// setAutoscalerMode sets the mode of the autoscaler of the group and waits for the operation, e.g. "OFF"
+func (r *ComputeInstanceGroupManager) setAutoscalerMode(ctx context.Context, mode string) error {
+	op, err := r.autoscalers.Patch(ctx, &computepb.PatchAutoscalerRequest{
+		Project:    *r.Project,
+		Zone:       *r.Zone,
+		Autoscaler: r.Autoscaler,
+		AutoscalerResource: &computepb.Autoscaler{
+			Name:              r.Autoscaler,
+			AutoscalingPolicy: &computepb.AutoscalingPolicy{Mode: ptr.String(mode)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
+
+func (r *ComputeInstanceGroupManager) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ComputeInstanceGroupManager) String() string {
+	return *r.Name
+}
+
+// lastPathSegment returns the last segment of the URL of a resource, e.g. its name, nil when the URL is empty
+func lastPathSegment(url string) *string {
+	if url == "" {
+		return nil
+	}
+
+	parts := strings.Split(url, "/")
+	return ptr.String(parts[len(parts)-1])
+}
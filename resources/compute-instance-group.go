@@ -2,12 +2,9 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -62,24 +59,21 @@ func (l *ComputeInstanceGroupLister) List(ctx context.Context, o interface{}) ([
 			Zone:    zone,
 		}
 
-		it := l.svc.List(ctx, req)
-
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate compute instance groups")
-				break
-			}
+		items, err := nuke.ListAll(ctx, opts, ComputeInstanceGroupResource, func() nuke.Iterator[*computepb.InstanceGroup] {
+			return l.svc.List(ctx, req)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range items {
 			resources = append(resources, &ComputeInstanceGroup{
 				svc:               l.svc,
 				Name:              resp.Name,
 				Project:           opts.Project,
 				Zone:              ptr.String(zone),
 				CreationTimestamp: resp.CreationTimestamp,
+				CreatedAt:         nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 			})
 		}
 	}
@@ -93,6 +87,7 @@ type ComputeInstanceGroup struct {
 	Zone              *string
 	Name              *string
 	CreationTimestamp *string
+	CreatedAt         *time.Time
 }
 
 func (r *ComputeInstanceGroup) Remove(ctx context.Context) error {
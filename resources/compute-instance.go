@@ -2,12 +2,10 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -62,25 +60,28 @@ func (l *ComputeInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 			Zone:    zone,
 		}
 
-		it := l.svc.List(ctx, req)
-
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate compute instances")
-				break
-			}
+		items, err := nuke.ListAll(ctx, opts, ComputeInstanceResource, func() nuke.Iterator[*computepb.Instance] {
+			return l.svc.List(ctx, req)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range items {
 			resources = append(resources, &ComputeInstance{
 				svc:               l.svc,
 				Name:              resp.Name,
 				Project:           opts.Project,
 				Zone:              ptr.String(zone),
 				CreationTimestamp: resp.CreationTimestamp,
+				Status:            resp.Status,
+				MachineType:       lastPathSegment(resp.GetMachineType()),
 				Labels:            resp.Labels,
+				CreatedAt:         nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+				labelFingerprint:  resp.LabelFingerprint,
+				createdBy:         instanceCreatedBy(resp),
+
+				waitTimeout: opts.WaitTimeout,
 			})
 		}
 	}
@@ -90,14 +91,25 @@ func (l *ComputeInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 
 type ComputeInstance struct {
 	svc               *compute.InstancesClient
+	waitTimeout       time.Duration
+	labelFingerprint  *string
+	createdBy         string
 	Project           *string
 	Region            *string
 	Name              *string
 	Zone              *string
+	Status            *string
+	MachineType       *string
 	CreationTimestamp *string
+	CreatedAt         *time.Time
 	Labels            map[string]string `property:"tagPrefix=label"`
 }
 
+// computeInstanceState is the state of a suspended instance, see nuke.Suspender
+type computeInstanceState struct {
+	Status string `json:"status"`
+}
+
 func (r *ComputeInstance) Remove(ctx context.Context) error {
 	_, err := r.svc.Delete(ctx, &computepb.DeleteInstanceRequest{
 		Project:  *r.Project,
@@ -107,6 +119,85 @@ func (r *ComputeInstance) Remove(ctx context.Context) error {
 	return err
 }
 
+// Mark adds the mark to the labels of the instance, see nuke.Marker
+func (r *ComputeInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &computepb.SetLabelsInstanceRequest{
+		Project:  *r.Project,
+		Zone:     *r.Zone,
+		Instance: *r.Name,
+		InstancesSetLabelsRequestResource: &computepb.InstancesSetLabelsRequest{
+			Labels:           nuke.WithMark(r.Labels, markedAt),
+			LabelFingerprint: r.labelFingerprint,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
+
+// Stop stops the instance, its disks are kept until it is removed, see nuke.Stopper
+func (r *ComputeInstance) Stop(ctx context.Context) error {
+	_, err := r.svc.Stop(ctx, &computepb.StopInstanceRequest{
+		Project:  *r.Project,
+		Zone:     *r.Zone,
+		Instance: *r.Name,
+	})
+	return err
+}
+
+// Suspend stops the running instance, the instances of a managed instance group are left to the group, see
+// nuke.Suspender
+func (r *ComputeInstance) Suspend(ctx context.Context) (nuke.SuspendedState, error) {
+	if r.createdBy != "" {
+		return nil, nuke.NotSuspended(fmt.Sprintf("the instance is managed by %s", r.createdBy))
+	}
+
+	status := ptr.ToString(r.Status)
+	if status != "RUNNING" {
+		return nil, nuke.NotSuspended(fmt.Sprintf("the instance is %s", status))
+	}
+
+	op, err := r.svc.Stop(ctx, &computepb.StopInstanceRequest{
+		Project:  *r.Project,
+		Zone:     *r.Zone,
+		Instance: *r.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op)); err != nil {
+		return nil, err
+	}
+
+	return nuke.NewSuspendedState(&computeInstanceState{Status: status})
+}
+
+// Resume starts the instance again, see nuke.Suspender
+func (r *ComputeInstance) Resume(ctx context.Context, state nuke.SuspendedState) error {
+	suspended := &computeInstanceState{}
+	if err := state.Decode(suspended); err != nil {
+		return err
+	}
+
+	if suspended.Status != "RUNNING" {
+		return nil
+	}
+
+	op, err := r.svc.Start(ctx, &computepb.StartInstanceRequest{
+		Project:  *r.Project,
+		Zone:     *r.Zone,
+		Instance: *r.Name,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
+
 func (r *ComputeInstance) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -114,3 +205,14 @@ func (r *ComputeInstance) Properties() types.Properties {
 func (r *ComputeInstance) String() string {
 	return *r.Name
 }
+
+// instanceCreatedBy returns the managed instance group that created the instance, empty when it was created on its own
+func instanceCreatedBy(instance *computepb.Instance) string {
+	for _, item := range instance.GetMetadata().GetItems() {
+		if item.GetKey() == "created-by" {
+			return item.GetValue()
+		}
+	}
+
+	return ""
+}
@@ -3,16 +3,11 @@ package resources
 import (
 	"context"
 	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -56,26 +51,23 @@ func (l *ComputeNetworkEndpointGroupLister) List(ctx context.Context, o interfac
 	if err := opts.BeforeList(nuke.Global, "compute.googleapis.com", ComputeNetworkEndpointGroupResource); err == nil {
 		globalResources, err := l.listGlobal(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list global network endpoint groups")
-		} else {
-			resources = append(resources, globalResources...)
+			return nil, opts.ListError(ComputeNetworkEndpointGroupResource, err)
 		}
+		resources = append(resources, globalResources...)
 	}
 
 	if err := opts.BeforeList(nuke.Regional, "compute.googleapis.com", ComputeNetworkEndpointGroupResource); err == nil {
 		regionalResources, err := l.listRegional(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list regional network endpoint groups")
-		} else {
-			resources = append(resources, regionalResources...)
+			return nil, opts.ListError(ComputeNetworkEndpointGroupResource, err)
 		}
+		resources = append(resources, regionalResources...)
 
 		zonalResources, err := l.listZonal(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list zonal network endpoint groups")
-		} else {
-			resources = append(resources, zonalResources...)
+			return nil, opts.ListError(ComputeNetworkEndpointGroupResource, err)
 		}
+		resources = append(resources, zonalResources...)
 	}
 
 	return resources, nil
@@ -95,23 +87,22 @@ func (l *ComputeNetworkEndpointGroupLister) listGlobal(ctx context.Context, opts
 	req := &computepb.ListGlobalNetworkEndpointGroupsRequest{
 		Project: *opts.Project,
 	}
-	it := l.globalSvc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate global network endpoint groups")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeNetworkEndpointGroupResource, func() nuke.Iterator[*computepb.NetworkEndpointGroup] {
+		return l.globalSvc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeNetworkEndpointGroup{
 			globalSvc:   l.globalSvc,
 			project:     opts.Project,
 			Name:        resp.Name,
 			NetworkType: resp.NetworkEndpointType,
-			CreatedAt:   resp.CreationTimestamp,
+			CreatedAt:   nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -133,24 +124,23 @@ func (l *ComputeNetworkEndpointGroupLister) listRegional(ctx context.Context, op
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.regionalSvc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate regional network endpoint groups")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeNetworkEndpointGroupResource, func() nuke.Iterator[*computepb.NetworkEndpointGroup] {
+		return l.regionalSvc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeNetworkEndpointGroup{
 			regionalSvc: l.regionalSvc,
 			project:     opts.Project,
 			region:      opts.Region,
 			Name:        resp.Name,
 			NetworkType: resp.NetworkEndpointType,
-			CreatedAt:   resp.CreationTimestamp,
+			CreatedAt:   nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -173,17 +163,14 @@ func (l *ComputeNetworkEndpointGroupLister) listZonal(ctx context.Context, opts
 			Project: *opts.Project,
 			Zone:    zone,
 		}
-		it := l.zonalSvc.List(ctx, req)
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate zonal network endpoint groups")
-				break
-			}
+		items, err := nuke.ListAll(ctx, opts, ComputeNetworkEndpointGroupResource, func() nuke.Iterator[*computepb.NetworkEndpointGroup] {
+			return l.zonalSvc.List(ctx, req)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range items {
 			zoneCopy := zone
 			resources = append(resources, &ComputeNetworkEndpointGroup{
 				zonalSvc:    l.zonalSvc,
@@ -191,7 +178,9 @@ func (l *ComputeNetworkEndpointGroupLister) listZonal(ctx context.Context, opts
 				zone:        &zoneCopy,
 				Name:        resp.Name,
 				NetworkType: resp.NetworkEndpointType,
-				CreatedAt:   resp.CreationTimestamp,
+				CreatedAt:   nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+				removeWait: opts.OperationTracker(),
 			})
 		}
 	}
@@ -204,12 +193,13 @@ type ComputeNetworkEndpointGroup struct {
 	regionalSvc *compute.RegionNetworkEndpointGroupsClient
 	globalSvc   *compute.GlobalNetworkEndpointGroupsClient
 	removeOp    *compute.Operation
+	removeWait  nuke.OperationTracker
 	project     *string
 	region      *string
 	zone        *string
 	Name        *string
 	NetworkType *string
-	CreatedAt   *string
+	CreatedAt   *time.Time
 }
 
 func (r *ComputeNetworkEndpointGroup) Remove(ctx context.Context) error {
@@ -263,22 +253,5 @@ func (r *ComputeNetworkEndpointGroup) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeNetworkEndpointGroupResource)
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
@@ -2,11 +2,7 @@ package resources
 
 import (
 	"context"
-	"errors"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -59,22 +55,20 @@ func (l *ComputePacketMirroringLister) List(ctx context.Context, o interface{})
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputePacketMirroringResource, func() nuke.Iterator[*computepb.PacketMirroring] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputePacketMirroring{
-			svc:     l.svc,
-			Name:    resp.Name,
-			project: opts.Project,
-			region:  opts.Region,
+			svc:       l.svc,
+			Name:      resp.Name,
+			project:   opts.Project,
+			region:    opts.Region,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -82,10 +76,11 @@ func (l *ComputePacketMirroringLister) List(ctx context.Context, o interface{})
 }
 
 type ComputePacketMirroring struct {
-	svc     *compute.PacketMirroringsClient
-	project *string
-	region  *string
-	Name    *string `description:"Name of the packet mirroring configuration."`
+	svc       *compute.PacketMirroringsClient
+	project   *string
+	region    *string
+	Name      *string    `description:"Name of the packet mirroring configuration."`
+	CreatedAt *time.Time `description:"The time the packet mirroring was created"`
 }
 
 func (r *ComputePacketMirroring) Remove(ctx context.Context) error {
@@ -3,16 +3,13 @@ package resources
 import (
 	"context"
 	"errors"
-	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -52,19 +49,17 @@ func (l *ComputeSecurityPolicyLister) List(ctx context.Context, o interface{}) (
 	if err := opts.BeforeList(nuke.Global, "compute.googleapis.com", ComputeSecurityPolicyResource); err == nil {
 		globalResources, err := l.listGlobal(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list global security policies")
-		} else {
-			resources = append(resources, globalResources...)
+			return nil, opts.ListError(ComputeSecurityPolicyResource, err)
 		}
+		resources = append(resources, globalResources...)
 	}
 
 	if err := opts.BeforeList(nuke.Regional, "compute.googleapis.com", ComputeSecurityPolicyResource); err == nil {
 		regionalResources, err := l.listRegional(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list regional security policies")
-		} else {
-			resources = append(resources, regionalResources...)
+			return nil, opts.ListError(ComputeSecurityPolicyResource, err)
 		}
+		resources = append(resources, regionalResources...)
 	}
 
 	return resources, nil
@@ -89,21 +84,24 @@ func (l *ComputeSecurityPolicyLister) listGlobal(ctx context.Context, opts *nuke
 	req := &computepb.ListSecurityPoliciesRequest{
 		Project: *opts.Project,
 	}
-	it := l.globalSvc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeSecurityPolicyResource, func() nuke.Iterator[*computepb.SecurityPolicy] {
+		return l.globalSvc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeSecurityPolicy{
 			globalSvc: l.globalSvc,
 			Name:      resp.Name,
 			project:   opts.Project,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+			Labels:    resp.Labels,
+
+			labelFingerprint: resp.LabelFingerprint,
+			removeWait:       opts.OperationTracker(),
+			waitTimeout:      opts.WaitTimeout,
 		})
 	}
 
@@ -127,24 +125,25 @@ func (l *ComputeSecurityPolicyLister) listRegional(ctx context.Context, opts *nu
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeSecurityPolicyResource, func() nuke.Iterator[*computepb.SecurityPolicy] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeSecurityPolicy{
 			svc:       l.svc,
 			Name:      resp.Name,
 			project:   opts.Project,
 			region:    opts.Region,
-			CreatedAt: resp.CreationTimestamp,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 			Labels:    resp.Labels,
+
+			labelFingerprint: resp.LabelFingerprint,
+			removeWait:       opts.OperationTracker(),
+			waitTimeout:      opts.WaitTimeout,
 		})
 	}
 
@@ -152,14 +151,17 @@ func (l *ComputeSecurityPolicyLister) listRegional(ctx context.Context, opts *nu
 }
 
 type ComputeSecurityPolicy struct {
-	svc       *compute.RegionSecurityPoliciesClient
-	globalSvc *compute.SecurityPoliciesClient
-	removeOp  *compute.Operation
-	project   *string
-	region    *string
-	Name      *string
-	CreatedAt *string
-	Labels    map[string]string `property:"tagPrefix=label"`
+	svc              *compute.RegionSecurityPoliciesClient
+	globalSvc        *compute.SecurityPoliciesClient
+	removeOp         *compute.Operation
+	removeWait       nuke.OperationTracker
+	project          *string
+	region           *string
+	labelFingerprint *string
+	waitTimeout      time.Duration
+	Name             *string
+	CreatedAt        *time.Time
+	Labels           map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *ComputeSecurityPolicy) Remove(ctx context.Context) error {
@@ -197,27 +199,42 @@ func (r *ComputeSecurityPolicy) String() string {
 	return *r.Name
 }
 
-func (r *ComputeSecurityPolicy) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
+// Mark adds the mark to the labels of the security policy, see nuke.Marker
+func (r *ComputeSecurityPolicy) Mark(ctx context.Context, markedAt time.Time) (err error) {
+	labels := nuke.WithMark(r.Labels, markedAt)
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+	var op *compute.Operation
+	if r.svc != nil {
+		op, err = r.svc.SetLabels(ctx, &computepb.SetLabelsRegionSecurityPolicyRequest{
+			Project:  *r.project,
+			Region:   *r.region,
+			Resource: *r.Name,
+			RegionSetLabelsRequestResource: &computepb.RegionSetLabelsRequest{
+				Labels:           labels,
+				LabelFingerprint: r.labelFingerprint,
+			},
+		})
+	} else {
+		op, err = r.globalSvc.SetLabels(ctx, &computepb.SetLabelsSecurityPolicyRequest{
+			Project:  *r.project,
+			Resource: *r.Name,
+			GlobalSetLabelsRequestResource: &computepb.GlobalSetLabelsRequest{
+				Labels:           labels,
+				LabelFingerprint: r.labelFingerprint,
+			},
+		})
+	}
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
 
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Error("unable to delete network")
-			return removeErr
-		}
+func (r *ComputeSecurityPolicy) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
@@ -0,0 +1,141 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const ComputeSnapshotResource = "ComputeSnapshot"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ComputeSnapshotResource,
+		Scope:    nuke.Project,
+		Resource: &ComputeSnapshot{},
+		Lister:   &ComputeSnapshotLister{},
+	})
+}
+
+type ComputeSnapshotLister struct {
+	svc *compute.SnapshotsClient
+}
+
+func (l *ComputeSnapshotLister) Close() {
+	if l.svc != nil {
+		_ = l.svc.Close()
+	}
+}
+
+func (l *ComputeSnapshotLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+	opts := o.(*nuke.ListerOpts)
+
+	if err := opts.BeforeList(nuke.Global, "compute.googleapis.com", ComputeSnapshotResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = compute.NewSnapshotsRESTClient(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := &computepb.ListSnapshotsRequest{
+		Project: *opts.Project,
+	}
+	items, err := nuke.ListAll(ctx, opts, ComputeSnapshotResource, func() nuke.Iterator[*computepb.Snapshot] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resp := range items {
+		resources = append(resources, &ComputeSnapshot{
+			svc:        l.svc,
+			project:    opts.Project,
+			Name:       resp.Name,
+			SourceDisk: resp.SourceDisk,
+			Status:     resp.Status,
+			CreatedAt:  nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+			Labels:     resp.Labels,
+
+			labelFingerprint: resp.LabelFingerprint,
+			removeWait:       opts.OperationTracker(),
+			waitTimeout:      opts.WaitTimeout,
+		})
+	}
+
+	return resources, nil
+}
+
+type ComputeSnapshot struct {
+	svc              *compute.SnapshotsClient
+	removeOp         *compute.Operation
+	removeWait       nuke.OperationTracker
+	project          *string
+	labelFingerprint *string
+	waitTimeout      time.Duration
+	Name             *string           `description:"The name of the snapshot"`
+	SourceDisk       *string           `description:"The URL of the disk the snapshot was created from"`
+	Status           *string           `description:"The status of the snapshot"`
+	CreatedAt        *time.Time        `description:"The time the snapshot was created"`
+	Labels           map[string]string `property:"tagPrefix=label" description:"The labels of the snapshot"`
+}
+
+// Filter keeps the snapshots created by gcp-nuke before the removal of a disk until their retention expires
+func (r *ComputeSnapshot) Filter() error {
+	return nuke.FilterRetainedBackup(r.Labels)
+}
+
+func (r *ComputeSnapshot) Remove(ctx context.Context) (err error) {
+	r.removeOp, err = r.svc.Delete(ctx, &computepb.DeleteSnapshotRequest{
+		Project:  *r.project,
+		Snapshot: *r.Name,
+	})
+	return err
+}
+
+func (r *ComputeSnapshot) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ComputeSnapshot) String() string {
+	return *r.Name
+}
+
+// Mark adds the mark to the labels of the snapshot, see nuke.Marker
+func (r *ComputeSnapshot) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &computepb.SetLabelsSnapshotRequest{
+		Project:  *r.project,
+		Resource: *r.Name,
+		GlobalSetLabelsRequestResource: &computepb.GlobalSetLabelsRequest{
+			Labels:           nuke.WithMark(r.Labels, markedAt),
+			LabelFingerprint: r.labelFingerprint,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
+
+func (r *ComputeSnapshot) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
+	}
+
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
+}
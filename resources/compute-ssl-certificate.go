@@ -4,11 +4,9 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -52,19 +50,17 @@ func (l *ComputeSSLCertificateLister) List(ctx context.Context, o interface{}) (
 	if err := opts.BeforeList(nuke.Global, "compute.googleapis.com", ComputeSSLCertificateResource); err == nil {
 		globalResources, err := l.listGlobal(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list global ssl certificates")
-		} else {
-			resources = append(resources, globalResources...)
+			return nil, opts.ListError(ComputeSSLCertificateResource, err)
 		}
+		resources = append(resources, globalResources...)
 	}
 
 	if err := opts.BeforeList(nuke.Regional, "compute.googleapis.com", ComputeSSLCertificateResource); err == nil {
 		regionalResources, err := l.listRegional(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list regional ssl certificates")
-		} else {
-			resources = append(resources, regionalResources...)
+			return nil, opts.ListError(ComputeSSLCertificateResource, err)
 		}
+		resources = append(resources, regionalResources...)
 	}
 
 	return resources, nil
@@ -84,21 +80,19 @@ func (l *ComputeSSLCertificateLister) listGlobal(ctx context.Context, opts *nuke
 	req := &computepb.ListSslCertificatesRequest{
 		Project: *opts.Project,
 	}
-	it := l.globalSvc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeSSLCertificateResource, func() nuke.Iterator[*computepb.SslCertificate] {
+		return l.globalSvc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeSSLCertificate{
 			globalSvc: l.globalSvc,
 			Name:      resp.Name,
 			project:   opts.Project,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -120,17 +114,14 @@ func (l *ComputeSSLCertificateLister) listRegional(ctx context.Context, opts *nu
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeSSLCertificateResource, func() nuke.Iterator[*computepb.SslCertificate] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		certResource := &ComputeSSLCertificate{
 			svc:       l.svc,
 			project:   opts.Project,
@@ -139,6 +130,7 @@ func (l *ComputeSSLCertificateLister) listRegional(ctx context.Context, opts *nu
 			Type:      resp.Type,
 			Domain:    ptr.String(strings.Join(resp.GetSubjectAlternativeNames(), ",")),
 			ExpiresAt: resp.ExpireTime,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		}
 
 		resources = append(resources, certResource)
@@ -157,6 +149,7 @@ type ComputeSSLCertificate struct {
 	Type      *string
 	Domain    *string
 	ExpiresAt *string
+	CreatedAt *time.Time
 }
 
 func (r *ComputeSSLCertificate) Remove(ctx context.Context) error {
@@ -2,17 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -60,22 +54,21 @@ func (l *ComputeTargetGRPCProxyLister) List(ctx context.Context, o interface{})
 	req := &computepb.ListTargetGrpcProxiesRequest{
 		Project: *opts.Project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate target grpc proxies")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetGRPCProxyResource, func() nuke.Iterator[*computepb.TargetGrpcProxy] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeTargetGRPCProxy{
 			svc:       l.svc,
 			project:   opts.Project,
 			Name:      resp.Name,
-			CreatedAt: resp.CreationTimestamp,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -83,11 +76,12 @@ func (l *ComputeTargetGRPCProxyLister) List(ctx context.Context, o interface{})
 }
 
 type ComputeTargetGRPCProxy struct {
-	svc       *compute.TargetGrpcProxiesClient
-	removeOp  *compute.Operation
-	project   *string
-	Name      *string
-	CreatedAt *string
+	svc        *compute.TargetGrpcProxiesClient
+	removeOp   *compute.Operation
+	removeWait nuke.OperationTracker
+	project    *string
+	Name       *string
+	CreatedAt  *time.Time
 }
 
 func (r *ComputeTargetGRPCProxy) Remove(ctx context.Context) (err error) {
@@ -111,22 +105,5 @@ func (r *ComputeTargetGRPCProxy) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeTargetGRPCProxyResource)
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
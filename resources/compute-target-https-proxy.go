@@ -3,16 +3,11 @@ package resources
 import (
 	"context"
 	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -52,19 +47,17 @@ func (l *ComputeTargetHTTPSProxyLister) List(ctx context.Context, o interface{})
 	if err := opts.BeforeList(nuke.Global, "compute.googleapis.com", ComputeTargetHTTPSProxyResource); err == nil {
 		globalResources, err := l.listGlobal(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list global target proxies")
-		} else {
-			resources = append(resources, globalResources...)
+			return nil, opts.ListError(ComputeTargetHTTPSProxyResource, err)
 		}
+		resources = append(resources, globalResources...)
 	}
 
 	if err := opts.BeforeList(nuke.Regional, "compute.googleapis.com", ComputeTargetHTTPSProxyResource); err == nil {
 		regionalResources, err := l.listRegional(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list regional target proxies")
-		} else {
-			resources = append(resources, regionalResources...)
+			return nil, opts.ListError(ComputeTargetHTTPSProxyResource, err)
 		}
+		resources = append(resources, regionalResources...)
 	}
 
 	return resources, nil
@@ -84,21 +77,21 @@ func (l *ComputeTargetHTTPSProxyLister) listGlobal(ctx context.Context, opts *nu
 	req := &computepb.ListTargetHttpsProxiesRequest{
 		Project: *opts.Project,
 	}
-	it := l.globalSvc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetHTTPSProxyResource, func() nuke.Iterator[*computepb.TargetHttpsProxy] {
+		return l.globalSvc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeTargetHTTPSProxy{
 			globalSvc: l.globalSvc,
 			project:   opts.Project,
 			Name:      resp.Name,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -120,22 +113,22 @@ func (l *ComputeTargetHTTPSProxyLister) listRegional(ctx context.Context, opts *
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetHTTPSProxyResource, func() nuke.Iterator[*computepb.TargetHttpsProxy] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		certResource := &ComputeTargetHTTPSProxy{
-			svc:     l.svc,
-			project: opts.Project,
-			region:  opts.Region,
-			Name:    resp.Name,
+			svc:       l.svc,
+			project:   opts.Project,
+			region:    opts.Region,
+			Name:      resp.Name,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		}
 
 		resources = append(resources, certResource)
@@ -145,15 +138,17 @@ func (l *ComputeTargetHTTPSProxyLister) listRegional(ctx context.Context, opts *
 }
 
 type ComputeTargetHTTPSProxy struct {
-	svc       *compute.RegionTargetHttpsProxiesClient
-	globalSvc *compute.TargetHttpsProxiesClient
-	removeOp  *compute.Operation
-	project   *string
-	region    *string
-	Name      *string
-	Type      *string
-	Domain    *string
-	ExpiresAt *string
+	svc        *compute.RegionTargetHttpsProxiesClient
+	globalSvc  *compute.TargetHttpsProxiesClient
+	removeOp   *compute.Operation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	Name       *string
+	Type       *string
+	Domain     *string
+	ExpiresAt  *string
+	CreatedAt  *time.Time
 }
 
 func (r *ComputeTargetHTTPSProxy) Remove(ctx context.Context) error {
@@ -196,22 +191,5 @@ func (r *ComputeTargetHTTPSProxy) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeTargetHTTPSProxyResource)
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
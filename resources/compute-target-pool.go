@@ -2,17 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -61,23 +55,22 @@ func (l *ComputeTargetPoolLister) List(ctx context.Context, o interface{}) ([]re
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate target pools")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetPoolResource, func() nuke.Iterator[*computepb.TargetPool] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeTargetPool{
 			svc:       l.svc,
 			project:   opts.Project,
 			region:    opts.Region,
 			Name:      resp.Name,
-			CreatedAt: resp.CreationTimestamp,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -85,12 +78,13 @@ func (l *ComputeTargetPoolLister) List(ctx context.Context, o interface{}) ([]re
 }
 
 type ComputeTargetPool struct {
-	svc       *compute.TargetPoolsClient
-	removeOp  *compute.Operation
-	project   *string
-	region    *string
-	Name      *string
-	CreatedAt *string
+	svc        *compute.TargetPoolsClient
+	removeOp   *compute.Operation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	Name       *string
+	CreatedAt  *time.Time
 }
 
 func (r *ComputeTargetPool) Remove(ctx context.Context) (err error) {
@@ -115,22 +109,5 @@ func (r *ComputeTargetPool) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeTargetPoolResource)
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
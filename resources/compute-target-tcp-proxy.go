@@ -3,16 +3,11 @@ package resources
 import (
 	"context"
 	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -52,19 +47,17 @@ func (l *ComputeTargetTCPProxyLister) List(ctx context.Context, o interface{}) (
 	if err := opts.BeforeList(nuke.Global, "compute.googleapis.com", ComputeTargetTCPProxyResource); err == nil {
 		globalResources, err := l.listGlobal(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list global target tcp proxies")
-		} else {
-			resources = append(resources, globalResources...)
+			return nil, opts.ListError(ComputeTargetTCPProxyResource, err)
 		}
+		resources = append(resources, globalResources...)
 	}
 
 	if err := opts.BeforeList(nuke.Regional, "compute.googleapis.com", ComputeTargetTCPProxyResource); err == nil {
 		regionalResources, err := l.listRegional(ctx, opts)
 		if err != nil {
-			logrus.WithError(err).Error("unable to list regional target tcp proxies")
-		} else {
-			resources = append(resources, regionalResources...)
+			return nil, opts.ListError(ComputeTargetTCPProxyResource, err)
 		}
+		resources = append(resources, regionalResources...)
 	}
 
 	return resources, nil
@@ -84,22 +77,21 @@ func (l *ComputeTargetTCPProxyLister) listGlobal(ctx context.Context, opts *nuke
 	req := &computepb.ListTargetTcpProxiesRequest{
 		Project: *opts.Project,
 	}
-	it := l.globalSvc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate target tcp proxies")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetTCPProxyResource, func() nuke.Iterator[*computepb.TargetTcpProxy] {
+		return l.globalSvc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeTargetTCPProxy{
 			globalSvc: l.globalSvc,
 			project:   opts.Project,
 			Name:      resp.Name,
-			CreatedAt: resp.CreationTimestamp,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -121,23 +113,22 @@ func (l *ComputeTargetTCPProxyLister) listRegional(ctx context.Context, opts *nu
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate regional target tcp proxies")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetTCPProxyResource, func() nuke.Iterator[*computepb.TargetTcpProxy] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeTargetTCPProxy{
 			svc:       l.svc,
 			project:   opts.Project,
 			region:    opts.Region,
 			Name:      resp.Name,
-			CreatedAt: resp.CreationTimestamp,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -145,13 +136,14 @@ func (l *ComputeTargetTCPProxyLister) listRegional(ctx context.Context, opts *nu
 }
 
 type ComputeTargetTCPProxy struct {
-	svc       *compute.RegionTargetTcpProxiesClient
-	globalSvc *compute.TargetTcpProxiesClient
-	removeOp  *compute.Operation
-	project   *string
-	region    *string
-	Name      *string
-	CreatedAt *string
+	svc        *compute.RegionTargetTcpProxiesClient
+	globalSvc  *compute.TargetTcpProxiesClient
+	removeOp   *compute.Operation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	Name       *string
+	CreatedAt  *time.Time
 }
 
 func (r *ComputeTargetTCPProxy) Remove(ctx context.Context) error {
@@ -194,22 +186,5 @@ func (r *ComputeTargetTCPProxy) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeTargetTCPProxyResource)
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
@@ -2,17 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -64,24 +58,24 @@ func (l *ComputeTargetVpnGatewayLister) List(ctx context.Context, o interface{})
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate target vpn gateways")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeTargetVpnGatewayResource, func() nuke.Iterator[*computepb.TargetVpnGateway] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeTargetVpnGateway{
-			svc:     l.svc,
-			project: opts.Project,
-			region:  opts.Region,
-			Name:    resp.Name,
-			Network: resp.Network,
-			Status:  resp.Status,
+			svc:       l.svc,
+			project:   opts.Project,
+			region:    opts.Region,
+			Name:      resp.Name,
+			Network:   resp.Network,
+			Status:    resp.Status,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -89,13 +83,15 @@ func (l *ComputeTargetVpnGatewayLister) List(ctx context.Context, o interface{})
 }
 
 type ComputeTargetVpnGateway struct {
-	svc      *compute.TargetVpnGatewaysClient
-	removeOp *compute.Operation
-	project  *string
-	region   *string
-	Name     *string
-	Network  *string
-	Status   *string
+	svc        *compute.TargetVpnGatewaysClient
+	removeOp   *compute.Operation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	Name       *string
+	Network    *string
+	Status     *string
+	CreatedAt  *time.Time
 }
 
 func (r *ComputeTargetVpnGateway) Remove(ctx context.Context) (err error) {
@@ -120,22 +116,5 @@ func (r *ComputeTargetVpnGateway) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeTargetVpnGatewayResource)
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
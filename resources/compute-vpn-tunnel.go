@@ -2,17 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -61,17 +55,14 @@ func (l *ComputeVpnTunnelLister) List(ctx context.Context, o interface{}) ([]res
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate vpn tunnels")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ComputeVpnTunnelResource, func() nuke.Iterator[*computepb.VpnTunnel] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &ComputeVpnTunnel{
 			svc:        l.svc,
 			project:    opts.Project,
@@ -81,6 +72,11 @@ func (l *ComputeVpnTunnelLister) List(ctx context.Context, o interface{}) ([]res
 			PeerIp:     resp.PeerIp,
 			Status:     resp.Status,
 			Labels:     resp.Labels,
+			CreatedAt:  nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			labelFingerprint: resp.LabelFingerprint,
+			removeWait:       opts.OperationTracker(),
+			waitTimeout:      opts.WaitTimeout,
 		})
 	}
 
@@ -88,15 +84,19 @@ func (l *ComputeVpnTunnelLister) List(ctx context.Context, o interface{}) ([]res
 }
 
 type ComputeVpnTunnel struct {
-	svc        *compute.VpnTunnelsClient
-	removeOp   *compute.Operation
-	project    *string
-	region     *string
-	Name       *string
-	VpnGateway *string
-	PeerIp     *string
-	Status     *string
-	Labels     map[string]string `property:"tagPrefix=label"`
+	svc              *compute.VpnTunnelsClient
+	removeOp         *compute.Operation
+	removeWait       nuke.OperationTracker
+	project          *string
+	region           *string
+	labelFingerprint *string
+	waitTimeout      time.Duration
+	Name             *string
+	VpnGateway       *string
+	PeerIp           *string
+	Status           *string
+	CreatedAt        *time.Time
+	Labels           map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *ComputeVpnTunnel) Remove(ctx context.Context) (err error) {
@@ -116,27 +116,28 @@ func (r *ComputeVpnTunnel) String() string {
 	return *r.Name
 }
 
-func (r *ComputeVpnTunnel) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the VPN tunnel, see nuke.Marker
+func (r *ComputeVpnTunnel) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &computepb.SetLabelsVpnTunnelRequest{
+		Project:  *r.project,
+		Region:   *r.region,
+		Resource: *r.Name,
+		RegionSetLabelsRequestResource: &computepb.RegionSetLabelsRequest{
+			Labels:           nuke.WithMark(r.Labels, markedAt),
+			LabelFingerprint: r.labelFingerprint,
+		},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
 
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Errorf("unable to delete %s", ComputeVpnTunnelResource)
-			return removeErr
-		}
+func (r *ComputeVpnTunnel) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
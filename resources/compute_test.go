@@ -0,0 +1,303 @@
+package resources
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+// computeListerTest is the most common case for compute, a single item that is listed and deleted from a collection
+func computeListerTest(name string, lister registry.Lister, region, collectionPath string) listerTest {
+	return listerTest{
+		name:   name,
+		lister: lister,
+		region: region,
+		setup: func(fake *testutil.Server) {
+			fake.AddCompute(collectionPath, testutil.Item{"name": "test-1"})
+		},
+		want:    []string{"test-1"},
+		removed: []string{"DELETE " + collectionPath + "/test-1"},
+	}
+}
+
+// testDiskPath is the full name of the disk "test-1" in the test zone, the source of its snapshot
+const testDiskPath = "projects/" + testProject + "/zones/" + testZone + "/disks/test-1"
+
+func TestComputeListers(t *testing.T) {
+	global := func(kind string) string {
+		return testutil.ComputeGlobalPath(testProject, kind)
+	}
+	regional := func(kind string) string {
+		return testutil.ComputeRegionalPath(testProject, testRegion, kind)
+	}
+	zonal := func(kind string) string {
+		return testutil.ComputeZonalPath(testProject, testZone, kind)
+	}
+
+	runListerTests(t, []listerTest{
+		computeListerTest("backend-bucket", &ComputeBackendBucketLister{},
+			"global", global("backendBuckets")),
+		computeListerTest("backend-service-global", &ComputeBackendServiceLister{},
+			"global", global("backendServices")),
+		computeListerTest("backend-service-regional", &ComputeBackendServiceLister{},
+			testRegion, regional("backendServices")),
+		computeListerTest("disk", &ComputeDiskLister{},
+			testRegion, zonal("disks")),
+		computeListerTest("external-vpn-gateway", &ComputeExternalVpnGatewayLister{},
+			"global", global("externalVpnGateways")),
+		computeListerTest("firewall", &ComputeFirewallLister{},
+			"global", global("firewalls")),
+		computeListerTest("forwarding-rule-global", &ComputeForwardingRuleLister{},
+			"global", global("forwardingRules")),
+		computeListerTest("forwarding-rule-regional", &ComputeForwardingRuleLister{},
+			testRegion, regional("forwardingRules")),
+		computeListerTest("health-check", &ComputeHealthCheckLister{},
+			"global", global("healthChecks")),
+		computeListerTest("instance", &ComputeInstanceLister{},
+			testRegion, zonal("instances")),
+		computeListerTest("instance-group", &ComputeInstanceGroupLister{},
+			testRegion, zonal("instanceGroups")),
+		computeListerTest("network-endpoint-group-global", &ComputeNetworkEndpointGroupLister{},
+			"global", global("networkEndpointGroups")),
+		computeListerTest("packet-mirroring", &ComputePacketMirroringLister{},
+			testRegion, regional("packetMirrorings")),
+		computeListerTest("security-policy-global", &ComputeSecurityPolicyLister{},
+			"global", global("securityPolicies")),
+		computeListerTest("security-policy-regional", &ComputeSecurityPolicyLister{},
+			testRegion, regional("securityPolicies")),
+		computeListerTest("ssl-certificate-global", &ComputeSSLCertificateLister{},
+			"global", global("sslCertificates")),
+		computeListerTest("ssl-certificate-regional", &ComputeSSLCertificateLister{},
+			testRegion, regional("sslCertificates")),
+		computeListerTest("target-grpc-proxy", &ComputeTargetGRPCProxyLister{},
+			"global", global("targetGrpcProxies")),
+		computeListerTest("target-http-proxy-global", &ComputeTargetHTTPProxyLister{},
+			"global", global("targetHttpProxies")),
+		computeListerTest("target-http-proxy-regional", &ComputeTargetHTTPProxyLister{},
+			testRegion, regional("targetHttpProxies")),
+		computeListerTest("target-https-proxy-global", &ComputeTargetHTTPSProxyLister{},
+			"global", global("targetHttpsProxies")),
+		computeListerTest("target-https-proxy-regional", &ComputeTargetHTTPSProxyLister{},
+			testRegion, regional("targetHttpsProxies")),
+		computeListerTest("target-pool", &ComputeTargetPoolLister{},
+			testRegion, regional("targetPools")),
+		computeListerTest("target-ssl-proxy", &ComputeTargetSSLProxyLister{},
+			"global", global("targetSslProxies")),
+		computeListerTest("target-tcp-proxy-global", &ComputeTargetTCPProxyLister{},
+			"global", global("targetTcpProxies")),
+		computeListerTest("target-tcp-proxy-regional", &ComputeTargetTCPProxyLister{},
+			testRegion, regional("targetTcpProxies")),
+		computeListerTest("target-vpn-gateway", &ComputeTargetVpnGatewayLister{},
+			testRegion, regional("targetVpnGateways")),
+		computeListerTest("url-map-global", &ComputeURLMapLister{},
+			"global", global("urlMaps")),
+		computeListerTest("url-map-regional", &ComputeURLMapLister{},
+			testRegion, regional("urlMaps")),
+		computeListerTest("vpn-gateway", &ComputeVpnGatewayLister{},
+			testRegion, regional("vpnGateways")),
+		computeListerTest("vpn-tunnel", &ComputeVpnTunnelLister{},
+			testRegion, regional("vpnTunnels")),
+		{
+			name:    "global-resource-skipped-in-region",
+			lister:  &ComputeFirewallLister{},
+			region:  testRegion,
+			skipped: true,
+			setup: func(fake *testutil.Server) {
+				fake.AddCompute(global("firewalls"), testutil.Item{"name": "test-1"})
+			},
+		},
+		{
+			name:     "disk-backup-before-delete",
+			lister:   &ComputeDiskLister{},
+			region:   testRegion,
+			settings: settings.Setting{nuke.BackupBeforeDeleteSetting: true, nuke.BackupRetentionSetting: "2d"},
+			setup: func(fake *testutil.Server) {
+				fake.AddCompute(zonal("disks"), testutil.Item{"name": "test-1"})
+				fake.AddCompute(global("snapshots"))
+			},
+			want: []string{"test-1"},
+			removed: []string{
+				"POST " + global("snapshots"),
+				"DELETE " + zonal("disks") + "/test-1",
+			},
+			verify: func(t *testing.T, fake *testutil.Server) {
+				snapshots := fake.Items(global("snapshots"))
+				require.Len(t, snapshots, 1)
+				assert.Equal(t, nuke.SourceBackupID("test-1", testDiskPath, 63), snapshots[0]["name"])
+				assert.Equal(t, testDiskPath, snapshots[0]["sourceDisk"])
+
+				labels := snapshots[0]["labels"].(map[string]any)
+				assert.Equal(t, nuke.RunID(), labels[nuke.BackupRunLabel])
+				assert.NotEmpty(t, labels[nuke.BackupExpiresLabel])
+			},
+		},
+		{
+			// Note: the snapshot of a previous attempt of the run is the backup of the disk
+			name:     "disk-backup-before-delete-already-exists",
+			lister:   &ComputeDiskLister{},
+			region:   testRegion,
+			settings: settings.Setting{nuke.BackupBeforeDeleteSetting: true},
+			setup: func(fake *testutil.Server) {
+				fake.AddCompute(zonal("disks"), testutil.Item{"name": "test-1"})
+				fake.AddCompute(global("snapshots"), testutil.Item{
+					"name":   nuke.SourceBackupID("test-1", testDiskPath, 63),
+					"status": "READY",
+					"labels": map[string]any{nuke.BackupRunLabel: nuke.RunID()},
+				})
+				fake.SetError(http.MethodPost, global("snapshots"), http.StatusConflict, "already exists")
+			},
+			want:    []string{"test-1"},
+			removed: []string{"DELETE " + zonal("disks") + "/test-1"},
+		},
+		{
+			name:   "snapshot-retained-by-backup-before-delete",
+			lister: &ComputeSnapshotLister{},
+			region: "global",
+			setup: func(fake *testutil.Server) {
+				retained := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+				expired := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+				fake.AddCompute(global("snapshots"),
+					testutil.Item{"name": "retained", "labels": map[string]any{
+						nuke.BackupRunLabel: "20240601-120000", nuke.BackupExpiresLabel: retained,
+					}},
+					testutil.Item{"name": "expired", "labels": map[string]any{
+						nuke.BackupRunLabel: "20240601-120000", nuke.BackupExpiresLabel: expired,
+					}},
+					testutil.Item{"name": "manual", "labels": map[string]any{nuke.BackupExpiresLabel: retained}})
+			},
+			want:     []string{"retained", "expired", "manual"},
+			filtered: []string{"retained"},
+			removed: []string{
+				"DELETE " + global("snapshots") + "/expired",
+				"DELETE " + global("snapshots") + "/manual",
+			},
+		},
+		{
+			name:   "zonal-resource-in-other-zone",
+			lister: &ComputeDiskLister{},
+			region: testRegion,
+			setup: func(fake *testutil.Server) {
+				fake.AddCompute(testutil.ComputeZonalPath(testProject, testZone, "disks"))
+				fake.AddCompute(testutil.ComputeZonalPath(testProject, "us-east1-c", "disks"),
+					testutil.Item{"name": "test-1"})
+			},
+		},
+	})
+}
+
+func TestVPCListers(t *testing.T) {
+	global := func(kind string) string {
+		return testutil.ComputeGlobalPath(testProject, kind)
+	}
+	regional := func(kind string) string {
+		return testutil.ComputeRegionalPath(testProject, testRegion, kind)
+	}
+
+	runListerTests(t, []listerTest{
+		computeListerTest("global-ip-address", &VPCGlobalIPAddressLister{},
+			"global", global("addresses")),
+		computeListerTest("ip-address", &VPCIPAddressLister{},
+			testRegion, regional("addresses")),
+		computeListerTest("network", &VPCNetworkLister{},
+			"global", global("networks")),
+		computeListerTest("router", &VPCRouterLister{},
+			testRegion, regional("routers")),
+		{
+			name:   "route",
+			lister: &VPCRouteLister{},
+			region: "global",
+			setup: func(fake *testutil.Server) {
+				fake.AddCompute(global("routes"),
+					testutil.Item{
+						"name":        "custom-route",
+						"network":     "global/networks/test-network",
+						"description": "",
+					},
+					testutil.Item{
+						"name":        "default-route-abc",
+						"network":     "global/networks/test-network",
+						"description": "Default local route to the subnetwork 10.0.0.0/24.",
+					})
+			},
+			want:     []string{"custom-route", "default-route-abc"},
+			filtered: []string{"default-route-abc"},
+			removed:  []string{"DELETE " + global("routes") + "/custom-route"},
+		},
+		{
+			name:   "subnet",
+			lister: &VPCSubnetLister{},
+			region: testRegion,
+			setup: func(fake *testutil.Server) {
+				fake.AddCompute(global("networks"),
+					testutil.Item{"name": "auto-network", "autoCreateSubnetworks": true},
+					testutil.Item{"name": "custom-network", "autoCreateSubnetworks": false})
+				fake.AddCompute(regional("subnetworks"),
+					testutil.Item{"name": "auto-subnet", "network": global("networks") + "/auto-network"},
+					testutil.Item{"name": "custom-subnet", "network": global("networks") + "/custom-network"})
+			},
+			want:     []string{"auto-subnet", "custom-subnet"},
+			filtered: []string{"auto-subnet"},
+			removed:  []string{"DELETE " + regional("subnetworks") + "/custom-subnet"},
+		},
+	})
+}
+
+func TestComputeCreatedAt(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddCompute(testutil.ComputeZonalPath(testProject, testZone, "instances"),
+		testutil.Item{"name": "with-timestamp", "creationTimestamp": "2024-06-01T03:00:00.000-07:00"},
+		testutil.Item{"name": "without-timestamp"})
+
+	resources := listResources(t, &ComputeInstanceLister{}, newTestListerOpts(testRegion, fake.ClientOptions()),
+		[]string{"with-timestamp", "without-timestamp"})
+
+	createdAt := map[string]string{}
+	for _, r := range resources {
+		props := r.(resource.PropertyGetter).Properties()
+		createdAt[props.Get("Name")] = props.Get(nuke.CreatedAtProperty)
+	}
+
+	assert.Equal(t, map[string]string{
+		"with-timestamp":    "2024-06-01T10:00:00Z",
+		"without-timestamp": "",
+	}, createdAt)
+}
+
+// TestVPCSubnetListerProjects lists two projects with the same lister, like a run of every project under a folder
+func TestVPCSubnetListerProjects(t *testing.T) {
+	const otherProject = "other-project"
+
+	fake := testutil.NewServer(t)
+	for project, autoCreate := range map[string]bool{testProject: true, otherProject: false} {
+		fake.AddCompute(testutil.ComputeGlobalPath(project, "networks"),
+			testutil.Item{"name": "default", "autoCreateSubnetworks": autoCreate})
+		fake.AddCompute(testutil.ComputeRegionalPath(project, testRegion, "subnetworks"),
+			testutil.Item{"name": "subnet", "network": testutil.ComputeGlobalPath(project, "networks") + "/default"})
+	}
+
+	lister := &VPCSubnetLister{}
+
+	autoCreated := map[string]string{}
+	for _, project := range []string{testProject, otherProject} {
+		opts := newTestListerOpts(testRegion, fake.ClientOptions())
+		opts.Project = ptr.String(project)
+
+		resources := listResources(t, lister, opts, []string{"subnet"})
+		autoCreated[project] = resources[0].(resource.PropertyGetter).Properties().Get("AutoCreated")
+	}
+
+	assert.Equal(t, map[string]string{testProject: "true", otherProject: "false"}, autoCreated)
+}
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
@@ -48,7 +49,7 @@ func (l *DataflowJobLister) List(ctx context.Context, o interface{}) ([]resource
 
 	resp, err := l.svc.Projects.Locations.Jobs.List(*opts.Project, *opts.Region).Context(ctx).Do()
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(DataflowJobResource, err)
 	}
 
 	for _, job := range resp.Jobs {
@@ -69,6 +70,7 @@ func (l *DataflowJobLister) List(ctx context.Context, o interface{}) ([]resource
 			CurrentState: ptr.String(job.CurrentState),
 			CreateTime:   ptr.String(job.CreateTime),
 			Labels:       job.Labels,
+			CreatedAt:    nuke.ParseCreatedAt(job.CreateTime),
 		})
 	}
 
@@ -84,6 +86,7 @@ type DataflowJob struct {
 	Type         *string           `description:"The type of the job (batch or streaming)"`
 	CurrentState *string           `description:"The current state of the job"`
 	CreateTime   *string           `description:"The time the job was created"`
+	CreatedAt    *time.Time        `description:"The time the job was created"`
 	Labels       map[string]string `property:"tagPrefix=label" description:"Labels associated with the job"`
 }
 
@@ -2,16 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	dataproc "cloud.google.com/go/dataproc/v2/apiv1"
 	"cloud.google.com/go/dataproc/v2/apiv1/dataprocpb"
-	"google.golang.org/api/iterator"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -50,7 +49,7 @@ func (l *DataprocClusterLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = dataproc.NewClusterControllerClient(ctx, opts.ClientOptions...)
+		l.svc, err = dataproc.NewClusterControllerClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,24 +60,24 @@ func (l *DataprocClusterLister) List(ctx context.Context, o interface{}) ([]reso
 		Region:    *opts.Region,
 	}
 
-	it := l.svc.ListClusters(ctx, req)
-	for {
-		cluster, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate dataproc clusters")
-			break
-		}
+	clusters, err := nuke.ListAll(ctx, opts, DataprocClusterResource, func() nuke.Iterator[*dataprocpb.Cluster] {
+		return l.svc.ListClusters(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, cluster := range clusters {
 		resources = append(resources, &DataprocCluster{
-			svc:     l.svc,
-			Project: opts.Project,
-			Region:  opts.Region,
-			Name:    ptr.String(cluster.ClusterName),
-			State:   ptr.String(cluster.Status.State.String()),
-			Labels:  cluster.Labels,
+			svc:       l.svc,
+			Project:   opts.Project,
+			Region:    opts.Region,
+			Name:      ptr.String(cluster.ClusterName),
+			State:     ptr.String(cluster.Status.State.String()),
+			Labels:    cluster.Labels,
+			CreatedAt: dataprocCreatedAt(cluster.Status, cluster.StatusHistory),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -86,13 +85,15 @@ func (l *DataprocClusterLister) List(ctx context.Context, o interface{}) ([]reso
 }
 
 type DataprocCluster struct {
-	svc      *dataproc.ClusterControllerClient
-	removeOp *dataproc.DeleteClusterOperation
-	Project  *string
-	Region   *string
-	Name     *string           `description:"The name of the Dataproc cluster"`
-	State    *string           `description:"The current state of the cluster"`
-	Labels   map[string]string `property:"tagPrefix=label" description:"Labels associated with the cluster"`
+	svc        *dataproc.ClusterControllerClient
+	removeOp   *dataproc.DeleteClusterOperation
+	removeWait nuke.OperationTracker
+	Project    *string
+	Region     *string
+	Name       *string           `description:"The name of the Dataproc cluster"`
+	State      *string           `description:"The current state of the cluster"`
+	CreatedAt  *time.Time        `description:"The time the cluster was created"`
+	Labels     map[string]string `property:"tagPrefix=label" description:"Labels associated with the cluster"`
 }
 
 func (r *DataprocCluster) Remove(ctx context.Context) (err error) {
@@ -112,19 +113,44 @@ func (r *DataprocCluster) String() string {
 	return *r.Name
 }
 
+// Mark adds the mark to the labels of the cluster, see nuke.Marker
+func (r *DataprocCluster) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateCluster(ctx, &dataprocpb.UpdateClusterRequest{
+		ProjectId:   *r.Project,
+		Region:      *r.Region,
+		ClusterName: *r.Name,
+		Cluster: &dataprocpb.Cluster{
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = op.Wait(ctx)
+	return err
+}
+
 func (r *DataprocCluster) HandleWait(ctx context.Context) error {
 	if r.removeOp == nil {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("dataproc cluster remove op polling encountered error")
-		return err
-	}
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
+}
+
+// dataprocStatus is the status of a Dataproc cluster or job
+type dataprocStatus interface {
+	GetStateStartTime() *timestamppb.Timestamp
+}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+// dataprocCreatedAt returns the start time of the first status of a Dataproc cluster or job, the API does not return
+// their creation time
+func dataprocCreatedAt[T dataprocStatus](status T, history []T) *time.Time {
+	if len(history) > 0 {
+		status = history[0]
 	}
 
-	return nil
+	return nuke.CreatedAtFromProto(status.GetStateStartTime())
 }
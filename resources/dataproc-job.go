@@ -2,14 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	dataproc "cloud.google.com/go/dataproc/v2/apiv1"
 	"cloud.google.com/go/dataproc/v2/apiv1/dataprocpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -49,7 +49,7 @@ func (l *DataprocJobLister) List(ctx context.Context, o interface{}) ([]resource
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = dataproc.NewJobControllerClient(ctx, opts.ClientOptions...)
+		l.svc, err = dataproc.NewJobControllerClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -60,29 +60,27 @@ func (l *DataprocJobLister) List(ctx context.Context, o interface{}) ([]resource
 		Region:    *opts.Region,
 	}
 
-	it := l.svc.ListJobs(ctx, req)
-	for {
-		job, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate dataproc jobs")
-			break
-		}
+	jobs, err := nuke.ListAll(ctx, opts, DataprocJobResource, func() nuke.Iterator[*dataprocpb.Job] {
+		return l.svc.ListJobs(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, job := range jobs {
 		state := job.Status.State.String()
 		if state == "DONE" || state == "CANCELLED" || state == "ERROR" {
 			continue
 		}
 
 		resources = append(resources, &DataprocJob{
-			svc:     l.svc,
-			Project: opts.Project,
-			Region:  opts.Region,
-			ID:      ptr.String(job.Reference.JobId),
-			State:   ptr.String(state),
-			Labels:  job.Labels,
+			svc:       l.svc,
+			Project:   opts.Project,
+			Region:    opts.Region,
+			ID:        ptr.String(job.Reference.JobId),
+			State:     ptr.String(state),
+			Labels:    job.Labels,
+			CreatedAt: dataprocCreatedAt(job.Status, job.StatusHistory),
 		})
 	}
 
@@ -90,12 +88,13 @@ func (l *DataprocJobLister) List(ctx context.Context, o interface{}) ([]resource
 }
 
 type DataprocJob struct {
-	svc     *dataproc.JobControllerClient
-	Project *string
-	Region  *string
-	ID      *string           `description:"The ID of the Dataproc job"`
-	State   *string           `description:"The current state of the job"`
-	Labels  map[string]string `property:"tagPrefix=label" description:"Labels associated with the job"`
+	svc       *dataproc.JobControllerClient
+	Project   *string
+	Region    *string
+	ID        *string           `description:"The ID of the Dataproc job"`
+	State     *string           `description:"The current state of the job"`
+	CreatedAt *time.Time        `description:"The time the job was created"`
+	Labels    map[string]string `property:"tagPrefix=label" description:"Labels associated with the job"`
 }
 
 func (r *DataprocJob) Remove(ctx context.Context) error {
@@ -122,3 +121,17 @@ func (r *DataprocJob) Properties() types.Properties {
 func (r *DataprocJob) String() string {
 	return *r.ID
 }
+
+// Mark adds the mark to the labels of the job, see nuke.Marker
+func (r *DataprocJob) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.UpdateJob(ctx, &dataprocpb.UpdateJobRequest{
+		ProjectId: *r.Project,
+		Region:    *r.Region,
+		JobId:     *r.ID,
+		Job: &dataprocpb.Job{
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -55,11 +56,13 @@ func (l *DNSManagedZoneLister) List(ctx context.Context, o interface{}) ([]resou
 				DNSName:      ptr.String(zone.DnsName),
 				Visibility:   ptr.String(zone.Visibility),
 				CreationTime: ptr.String(zone.CreationTime),
+				CreatedAt:    nuke.ParseCreatedAt(zone.CreationTime),
+				Labels:       zone.Labels,
 			})
 		}
 		return nil
 	}); err != nil {
-		return resources, err
+		return nil, opts.ListError(DNSManagedZoneResource, err)
 	}
 
 	return resources, nil
@@ -72,6 +75,7 @@ type DNSManagedZone struct {
 	DNSName      *string `description:"DNS name of the managed zone"`
 	CreationTime *string `description:"Creation time of the managed zone"`
 	Visibility   *string
+	CreatedAt    *time.Time        `description:"The time the managed zone was created"`
 	Labels       map[string]string `property:"tagPrefix=label" description:"Labels of the managed zone"`
 }
 
@@ -118,3 +122,11 @@ func (r *DNSManagedZone) Properties() types.Properties {
 func (r *DNSManagedZone) String() string {
 	return *r.Name
 }
+
+// Mark adds the mark to the labels of the managed zone, see nuke.Marker
+func (r *DNSManagedZone) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.ManagedZones.Patch(*r.project, *r.Name, &dns.ManagedZone{
+		Labels: nuke.WithMark(r.Labels, markedAt),
+	}).Context(ctx).Do()
+	return err
+}
@@ -49,17 +49,17 @@ func (l *DNSPolicyLister) List(ctx context.Context, o interface{}) ([]resource.R
 	if err := req.Pages(ctx, func(page *dns.PoliciesListResponse) error {
 		for _, policy := range page.Policies {
 			resources = append(resources, &DNSPolicy{
-				svc:                      l.svc,
-				project:                  opts.Project,
-				Name:                     ptr.String(policy.Name),
-				Description:              ptr.String(policy.Description),
-				EnableInboundForwarding:  ptr.Bool(policy.EnableInboundForwarding),
-				EnableLogging:            ptr.Bool(policy.EnableLogging),
+				svc:                     l.svc,
+				project:                 opts.Project,
+				Name:                    ptr.String(policy.Name),
+				Description:             ptr.String(policy.Description),
+				EnableInboundForwarding: ptr.Bool(policy.EnableInboundForwarding),
+				EnableLogging:           ptr.Bool(policy.EnableLogging),
 			})
 		}
 		return nil
 	}); err != nil {
-		return resources, err
+		return nil, opts.ListError(DNSPolicyResource, err)
 	}
 
 	return resources, nil
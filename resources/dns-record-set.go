@@ -70,7 +70,7 @@ func (l *DNSRecordSetLister) List(ctx context.Context, o interface{}) ([]resourc
 		}
 		return nil
 	}); err != nil {
-		return resources, err
+		return nil, opts.ListError(DNSRecordSetResource, err)
 	}
 
 	return resources, nil
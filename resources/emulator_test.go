@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cloud.google.com/go/pubsub/v2"
+	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
+	"cloud.google.com/go/pubsub/v2/pstest"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+// newEmulatorListerOpts returns the lister options of a run on the emulators, the same as the run command would
+func newEmulatorListerOpts(t *testing.T, emulators *gcputil.Emulators) *nuke.ListerOpts {
+	t.Helper()
+
+	gcp, err := gcputil.NewEmulated(testProject, emulators, nil)
+	require.NoError(t, err)
+
+	return &nuke.ListerOpts{
+		Project:       ptr.String(testProject),
+		Region:        ptr.String("global"),
+		EnabledAPIs:   gcp.GetEnabledAPIs(),
+		ClientOptions: gcp.GetClientOptions(),
+
+		GRPCClientOptions:     gcp.GetGRPCClientOptions(),
+		EmulatorClientOptions: gcp.GetEmulatorClientOptions(),
+	}
+}
+
+func TestPubSubEmulator(t *testing.T) {
+	srv := pstest.NewServer()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	ctx := context.TODO()
+	opts := newEmulatorListerOpts(t, &gcputil.Emulators{PubSub: srv.Addr})
+
+	client, err := pubsub.NewClient(ctx, testProject, opts.GRPCClientOptionsFor("pubsub.googleapis.com")...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	topic, err := client.TopicAdminClient.CreateTopic(ctx, &pubsubpb.Topic{
+		Name: "projects/" + testProject + "/topics/events",
+	})
+	require.NoError(t, err)
+
+	_, err = client.SubscriptionAdminClient.CreateSubscription(ctx, &pubsubpb.Subscription{
+		Name:  "projects/" + testProject + "/subscriptions/events-worker",
+		Topic: topic.Name,
+	})
+	require.NoError(t, err)
+
+	// Note: the subscriptions are removed before the topics, the same as libnuke would with the dependencies
+	removeResources(t, listResources(t, &PubSubSubscriptionLister{}, opts, []string{"events-worker"}), nil)
+	removeResources(t, listResources(t, &PubSubTopicLister{}, opts, []string{"events"}), nil)
+
+	listResources(t, &PubSubSubscriptionLister{}, opts, nil)
+	listResources(t, &PubSubTopicLister{}, opts, nil)
+
+	// Note: the APIs without an emulator are disabled, nothing is ever called on GCP
+	_, err = (&SpannerInstanceLister{}).List(ctx, opts)
+	var skipErr liberror.ErrSkipRequest
+	assert.ErrorAs(t, err, &skipErr)
+}
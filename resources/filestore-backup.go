@@ -2,16 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	filestore "cloud.google.com/go/filestore/apiv1"
 	"cloud.google.com/go/filestore/apiv1/filestorepb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -46,7 +45,7 @@ func (l *FilestoreBackupLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = filestore.NewCloudFilestoreManagerClient(ctx, opts.ClientOptions...)
+		l.svc, err = filestore.NewCloudFilestoreManagerClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -55,17 +54,14 @@ func (l *FilestoreBackupLister) List(ctx context.Context, o interface{}) ([]reso
 	req := &filestorepb.ListBackupsRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListBackups(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate filestore backups")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, FilestoreBackupResource, func() nuke.Iterator[*filestorepb.Backup] {
+		return l.svc.ListBackups(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -78,6 +74,9 @@ func (l *FilestoreBackupLister) List(ctx context.Context, o interface{}) ([]reso
 			State:          resp.State.String(),
 			SourceInstance: &resp.SourceInstance,
 			Labels:         resp.Labels,
+			CreatedAt:      nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -93,15 +92,22 @@ func (l *FilestoreBackupLister) Close() {
 type FilestoreBackup struct {
 	svc            *filestore.CloudFilestoreManagerClient
 	removeOp       *filestore.DeleteBackupOperation
+	removeWait     nuke.OperationTracker
 	project        *string
 	region         *string
 	Name           *string
 	FullName       *string
 	State          string
 	SourceInstance *string
+	CreatedAt      *time.Time
 	Labels         map[string]string `property:"tagPrefix=label"`
 }
 
+// Filter keeps the backups created by gcp-nuke before the removal of an instance until their retention expires
+func (r *FilestoreBackup) Filter() error {
+	return nuke.FilterRetainedBackup(r.Labels)
+}
+
 func (r *FilestoreBackup) Remove(ctx context.Context) (err error) {
 	r.removeOp, err = r.svc.DeleteBackup(ctx, &filestorepb.DeleteBackupRequest{
 		Name: *r.FullName,
@@ -121,19 +127,27 @@ func (r *FilestoreBackup) String() string {
 	return *r.Name
 }
 
-func (r *FilestoreBackup) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the backup, see nuke.Marker
+func (r *FilestoreBackup) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateBackup(ctx, &filestorepb.UpdateBackupRequest{
+		Backup: &filestorepb.Backup{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *FilestoreBackup) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
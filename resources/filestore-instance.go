@@ -2,20 +2,20 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	filestore "cloud.google.com/go/filestore/apiv1"
 	"cloud.google.com/go/filestore/apiv1/filestorepb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
@@ -29,6 +29,10 @@ func init() {
 		Scope:    nuke.Project,
 		Resource: &FilestoreInstance{},
 		Lister:   &FilestoreInstanceLister{},
+		Settings: []string{
+			nuke.BackupBeforeDeleteSetting,
+			nuke.BackupRetentionSetting,
+		},
 	})
 }
 
@@ -46,7 +50,7 @@ func (l *FilestoreInstanceLister) List(ctx context.Context, o interface{}) ([]re
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = filestore.NewCloudFilestoreManagerClient(ctx, opts.ClientOptions...)
+		l.svc, err = filestore.NewCloudFilestoreManagerClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -56,30 +60,41 @@ func (l *FilestoreInstanceLister) List(ctx context.Context, o interface{}) ([]re
 		req := &filestorepb.ListInstancesRequest{
 			Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, zone),
 		}
-		it := l.svc.ListInstances(ctx, req)
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate filestore instances")
-				break
-			}
+		items, err := nuke.ListAll(ctx, opts, FilestoreInstanceResource, func() nuke.Iterator[*filestorepb.Instance] {
+			return l.svc.ListInstances(ctx, req)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range items {
 			nameParts := strings.Split(resp.Name, "/")
 			name := nameParts[len(nameParts)-1]
 
+			var fileShare string
+			var capacityGb int64
+			if len(resp.FileShares) > 0 {
+				fileShare = resp.FileShares[0].Name
+				capacityGb = resp.FileShares[0].CapacityGb
+			}
+
 			zoneCopy := zone
 			resources = append(resources, &FilestoreInstance{
-				svc:      l.svc,
-				project:  opts.Project,
-				zone:     &zoneCopy,
-				Name:     &name,
-				FullName: &resp.Name,
-				Tier:     resp.Tier.String(),
-				State:    resp.State.String(),
-				Labels:   resp.Labels,
+				svc:        l.svc,
+				project:    opts.Project,
+				region:     opts.Region,
+				zone:       &zoneCopy,
+				fileShare:  fileShare,
+				Name:       &name,
+				FullName:   &resp.Name,
+				Tier:       resp.Tier.String(),
+				CapacityGb: capacityGb,
+				State:      resp.State.String(),
+				Labels:     resp.Labels,
+				CreatedAt:  nuke.CreatedAtFromProto(resp.CreateTime),
+
+				removeWait:  opts.OperationTracker(),
+				waitTimeout: opts.WaitTimeout,
 			})
 		}
 	}
@@ -94,18 +109,34 @@ func (l *FilestoreInstanceLister) Close() {
 }
 
 type FilestoreInstance struct {
-	svc      *filestore.CloudFilestoreManagerClient
-	removeOp *filestore.DeleteInstanceOperation
-	project  *string
-	zone     *string
-	Name     *string
-	FullName *string
-	Tier     string
-	State    string
-	Labels   map[string]string `property:"tagPrefix=label"`
+	svc         *filestore.CloudFilestoreManagerClient
+	removeOp    *filestore.DeleteInstanceOperation
+	removeWait  nuke.OperationTracker
+	waitTimeout time.Duration
+	settings    *settings.Setting
+	backedUp    bool
+	project     *string
+	region      *string
+	zone        *string
+	fileShare   string
+	Name        *string
+	FullName    *string
+	Tier        string
+	CapacityGb  int64
+	State       string
+	CreatedAt   *time.Time
+	Labels      map[string]string `property:"tagPrefix=label"`
+}
+
+func (r *FilestoreInstance) Settings(setting *settings.Setting) {
+	r.settings = setting
 }
 
 func (r *FilestoreInstance) Remove(ctx context.Context) (err error) {
+	if err := r.backup(ctx); err != nil {
+		return err
+	}
+
 	r.removeOp, err = r.svc.DeleteInstance(ctx, &filestorepb.DeleteInstanceRequest{
 		Name:  *r.FullName,
 		Force: true,
@@ -125,19 +156,91 @@ func (r *FilestoreInstance) String() string {
 	return *r.Name
 }
 
+// Mark adds the mark to the labels of the instance, see nuke.Marker
+func (r *FilestoreInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateInstance(ctx, &filestorepb.UpdateInstanceRequest{
+		Instance: &filestorepb.Instance{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = op.Wait(ctx)
+	return err
+}
+
 func (r *FilestoreInstance) HandleWait(ctx context.Context) error {
 	if r.removeOp == nil {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return liberror.ErrWaitResource(fmt.Sprintf("poll failed: %v", err))
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
+}
+
+// backup creates a backup of the file share of the instance, when the BackupBeforeDelete setting is enabled, and waits
+// for it. The backup is created in the region of the instance.
+func (r *FilestoreInstance) backup(ctx context.Context) error {
+	policy, err := nuke.NewBackupPolicy(r.settings)
+	if err != nil || !policy.Enabled || r.backedUp {
+		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	if r.fileShare == "" {
+		return fmt.Errorf("unable to create backup: instance %s has no file share", *r.Name)
 	}
 
+	// Note: the backups are regional, the hash of the instance keeps the backups of the instances of other zones apart
+	parent := fmt.Sprintf("projects/%s/locations/%s", *r.project, *r.region)
+	backupID := nuke.SourceBackupID(*r.Name, *r.FullName, 63)
+	logrus.WithField("backup", backupID).Debug("creating backup before removing filestore instance")
+
+	op, err := r.svc.CreateBackup(ctx, &filestorepb.CreateBackupRequest{
+		Parent:   parent,
+		BackupId: backupID,
+		Backup: &filestorepb.Backup{
+			Description:     fmt.Sprintf("Backup of %s before its removal by gcp-nuke", *r.Name),
+			SourceInstance:  *r.FullName,
+			SourceFileShare: r.fileShare,
+			Labels:          policy.Labels(),
+		},
+	})
+	if nuke.IsAlreadyExists(err) {
+		return r.existingBackup(ctx, parent+"/backups/"+backupID)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	if err := nuke.WaitOperation(ctx, r.waitTimeout, nuke.LROResultOperation[*filestorepb.Backup](op)); err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	r.backedUp = true
+
+	return nil
+}
+
+// existingBackup checks the backup of the instance that already exists, it was created by a previous attempt of this
+// run when it has the label of the run. The instance is backed up once that backup is ready.
+func (r *FilestoreInstance) existingBackup(ctx context.Context, name string) error {
+	backup, err := r.svc.GetBackup(ctx, &filestorepb.GetBackupRequest{Name: name})
+	if err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	if backup.GetLabels()[nuke.BackupRunLabel] != nuke.RunID() {
+		return fmt.Errorf("unable to create backup: backup %s already exists", name)
+	}
+
+	if state := backup.GetState(); state != filestorepb.Backup_READY {
+		return fmt.Errorf("backup %s is not ready yet, its state is %s", name, state)
+	}
+
+	r.backedUp = true
+
 	return nil
 }
@@ -46,7 +46,7 @@ func (l *FirebaseAuthProviderLister) List(ctx context.Context, o interface{}) ([
 
 	cfg, err := l.svc.GetProjectConfig(ctx, *opts.Project)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(FirebaseAuthProviderResource, err)
 	}
 
 	if cfg.SignIn == nil {
@@ -47,7 +47,7 @@ func (l *FirebaseOAuthProviderLister) List(ctx context.Context, o interface{}) (
 
 	builtinProviders, err := l.svc.ListDefaultSupportedOAuthIdpConfigs(ctx, *opts.Project)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(FirebaseOAuthProviderResource, err)
 	}
 
 	for _, provider := range builtinProviders.DefaultSupportedIdpConfigs {
@@ -64,7 +64,7 @@ func (l *FirebaseOAuthProviderLister) List(ctx context.Context, o interface{}) (
 
 	customProviders, err := l.svc.ListOAuthIdpConfigs(ctx, *opts.Project)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(FirebaseOAuthProviderResource, err)
 	}
 
 	for _, provider := range customProviders.OAuthIdpConfigs {
@@ -65,7 +65,7 @@ func (l *FirebaseRealtimeDatabaseLister) List(ctx context.Context, o interface{}
 
 	resp, err := l.svc.ListDatabaseInstances(ctx, fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region))
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(FirebaseRealtimeDatabaseResource, err)
 	}
 
 	for _, instance := range resp {
@@ -47,7 +47,7 @@ func (l *FirebaseWebAppLister) List(ctx context.Context, o interface{}) ([]resou
 
 	resp, err := l.svc.Projects.WebApps.List(fmt.Sprintf("projects/%s", *opts.Project)).Context(ctx).Do()
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(FirebaseWebAppResource, err)
 	}
 
 	for _, app := range resp.Apps {
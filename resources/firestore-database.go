@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -47,7 +48,7 @@ func (l *FirestoreDatabaseLister) List(ctx context.Context, o interface{}) ([]re
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = admin.NewFirestoreAdminClient(ctx, opts.ClientOptions...)
+		l.svc, err = admin.NewFirestoreAdminClient(ctx, opts.GRPCClientOptionsFor("firestore.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -59,18 +60,19 @@ func (l *FirestoreDatabaseLister) List(ctx context.Context, o interface{}) ([]re
 
 	resp, err := l.svc.ListDatabases(ctx, req)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(FirestoreDatabaseResource, err)
 	}
 
 	for _, db := range resp.Databases {
 		nameParts := strings.Split(db.Name, "/")
 
 		resources = append(resources, &FirestoreDatabase{
-			svc:      l.svc,
-			project:  opts.Project,
-			fullName: ptr.String(db.Name),
-			Name:     ptr.String(nameParts[len(nameParts)-1]),
-			Location: ptr.String(db.LocationId),
+			svc:       l.svc,
+			project:   opts.Project,
+			fullName:  ptr.String(db.Name),
+			Name:      ptr.String(nameParts[len(nameParts)-1]),
+			Location:  ptr.String(db.LocationId),
+			CreatedAt: nuke.CreatedAtFromProto(db.CreateTime),
 		})
 	}
 
@@ -78,11 +80,12 @@ func (l *FirestoreDatabaseLister) List(ctx context.Context, o interface{}) ([]re
 }
 
 type FirestoreDatabase struct {
-	svc      *admin.FirestoreAdminClient
-	project  *string
-	fullName *string
-	Name     *string
-	Location *string
+	svc       *admin.FirestoreAdminClient
+	project   *string
+	fullName  *string
+	Name      *string
+	Location  *string
+	CreatedAt *time.Time
 }
 
 func (r *FirestoreDatabase) Remove(ctx context.Context) error {
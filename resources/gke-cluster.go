@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
 	"cloud.google.com/go/container/apiv1"
 	"cloud.google.com/go/container/apiv1/containerpb"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
 	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -34,24 +35,32 @@ func init() {
 
 type GKEClusterLister struct {
 	svc *container.ClusterManagerClient
+	igm *compute.InstanceGroupManagersClient
 }
 
 func (l *GKEClusterLister) Close() {
 	if l.svc != nil {
 		_ = l.svc.Close()
 	}
+	if l.igm != nil {
+		_ = l.igm.Close()
+	}
 }
 
-func (l *GKEClusterLister) ListClusters(ctx context.Context, project, location string) ([]resource.Resource, error) {
+func (l *GKEClusterLister) ListClusters(
+	ctx context.Context, opts *nuke.ListerOpts, location string,
+) ([]resource.Resource, error) {
 	var resources []resource.Resource
 
+	project := *opts.Project
+
 	req := &containerpb.ListClustersRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", project, location),
 	}
 
 	resp, err := l.svc.ListClusters(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list GKE clusters: %v", err)
+		return nil, fmt.Errorf("failed to list GKE clusters: %w", err)
 	}
 
 	for _, cluster := range resp.Clusters {
@@ -64,13 +73,22 @@ func (l *GKEClusterLister) ListClusters(ctx context.Context, project, location s
 
 		resources = append(resources, &GKECluster{
 			svc:               l.svc,
+			igm:               l.igm,
 			Project:           ptr.String(project),
 			Region:            ptr.String(region),
 			Name:              ptr.String(cluster.Name),
 			Zone:              ptr.String(zone),
 			Status:            ptr.String(cluster.Status.String()),
+			NodeCount:         cluster.CurrentNodeCount,
+			NodeMachineType:   nodeMachineType(cluster),
 			CreationTimestamp: ptr.String(cluster.CreateTime),
 			Labels:            cluster.ResourceLabels,
+			CreatedAt:         nuke.ParseCreatedAt(cluster.CreateTime),
+			labelFingerprint:  cluster.LabelFingerprint,
+			autopilot:         cluster.GetAutopilot().GetEnabled(),
+
+			removeWait:  opts.OperationTracker(),
+			waitTimeout: opts.WaitTimeout,
 		})
 	}
 
@@ -87,7 +105,16 @@ func (l *GKEClusterLister) List(ctx context.Context, o interface{}) ([]resource.
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = container.NewClusterManagerClient(ctx, opts.ClientOptions...)
+		l.svc, err = container.NewClusterManagerClient(ctx, opts.GRPCClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Note: the sizes of the node pools are the sizes of their managed instance groups, GKE does not report them
+	if l.igm == nil {
+		var err error
+		l.igm, err = compute.NewInstanceGroupManagersRESTClient(ctx, opts.ClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -97,9 +124,9 @@ func (l *GKEClusterLister) List(ctx context.Context, o interface{}) ([]resource.
 	locations = append(locations, opts.Zones...)
 
 	for _, loc := range locations {
-		clusters, err := l.ListClusters(ctx, *opts.Project, loc)
+		clusters, err := l.ListClusters(ctx, opts, loc)
 		if err != nil {
-			return nil, err
+			return nil, opts.ListError(GKEClusterResource, err)
 		}
 		resources = append(resources, clusters...)
 	}
@@ -109,25 +136,39 @@ func (l *GKEClusterLister) List(ctx context.Context, o interface{}) ([]resource.
 
 type GKECluster struct {
 	svc               *container.ClusterManagerClient
+	igm               *compute.InstanceGroupManagersClient
 	removeOp          *containerpb.Operation
+	removeWait        nuke.OperationTracker
+	waitTimeout       time.Duration
+	labelFingerprint  string
+	autopilot         bool
 	Project           *string
 	Region            *string
 	Name              *string
 	Zone              *string
 	Status            *string
+	NodeCount         int32
+	NodeMachineType   *string
 	CreationTimestamp *string
+	CreatedAt         *time.Time
 	Labels            map[string]string `property:"tagPrefix=label"`
 }
 
-func (r *GKECluster) Remove(ctx context.Context) error {
-	var err error
-	location := r.Region
-	if *r.Zone != "" {
-		location = r.Zone
+// nodeMachineType returns the machine type of the nodes of the first node pool of the cluster, nil when it has none
+func nodeMachineType(cluster *containerpb.Cluster) *string {
+	for _, pool := range cluster.NodePools {
+		if machineType := pool.GetConfig().GetMachineType(); machineType != "" {
+			return ptr.String(machineType)
+		}
 	}
 
+	return nil
+}
+
+func (r *GKECluster) Remove(ctx context.Context) error {
+	var err error
 	r.removeOp, err = r.svc.DeleteCluster(ctx, &containerpb.DeleteClusterRequest{
-		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", *r.Project, *location, *r.Name),
+		Name: r.fullName(),
 	})
 	if err != nil {
 		logrus.WithError(err).WithField("cluster", *r.Name).Trace("gke cluster delete error")
@@ -136,39 +177,238 @@ func (r *GKECluster) Remove(ctx context.Context) error {
 	return nil
 }
 
-func (r *GKECluster) Properties() types.Properties {
-	return types.NewPropertiesFromStruct(r)
-}
+// Mark adds the mark to the labels of the cluster, see nuke.Marker
+func (r *GKECluster) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &containerpb.SetLabelsRequest{
+		Name:             r.fullName(),
+		ResourceLabels:   nuke.WithMark(r.Labels, markedAt),
+		LabelFingerprint: r.labelFingerprint,
+	})
+	if err != nil {
+		return err
+	}
 
-func (r *GKECluster) String() string {
-	return *r.Name
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ContainerOperation(r.svc, *r.Project, op))
 }
 
-func (r *GKECluster) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
+// Stop scales every node pool of the cluster to zero nodes, the autoscaling of the node pools is disabled first so
+// that they stay empty. The nodes of an Autopilot cluster are managed by GKE, there is nothing to stop. See
+// nuke.Stopper.
+func (r *GKECluster) Stop(ctx context.Context) error {
+	if r.autopilot {
 		return nil
 	}
 
-	var err error
-	r.removeOp, err = r.svc.GetOperation(ctx, &containerpb.GetOperationRequest{
-		Name: r.removeOp.Name,
-	})
+	resp, err := r.svc.ListNodePools(ctx, &containerpb.ListNodePoolsRequest{Parent: r.fullName()})
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			logrus.WithField("cluster", *r.Name).Trace("operation not found, assuming completed")
-			return nil
+		return err
+	}
+
+	for _, pool := range resp.NodePools {
+		if err := r.scaleDown(ctx, pool); err != nil {
+			return err
 		}
-		logrus.WithError(err).WithField("cluster", *r.Name).Trace("failed to get operation status")
-		return liberror.ErrWaitResource(fmt.Sprintf("poll failed: %v", err))
 	}
 
-	if r.removeOp.Status != containerpb.Operation_DONE {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	return nil
+}
+
+// gkeNodePoolState is the state of a node pool of a suspended cluster, the autoscaling is nil when it was disabled
+type gkeNodePoolState struct {
+	Name        string               `json:"name"`
+	NodeCount   int32                `json:"nodeCount"`
+	Autoscaling *gkeAutoscalingState `json:"autoscaling,omitempty"`
+}
+
+// gkeAutoscalingState is the autoscaling of a node pool of a suspended cluster
+type gkeAutoscalingState struct {
+	MinNodeCount      int32 `json:"minNodeCount,omitempty"`
+	MaxNodeCount      int32 `json:"maxNodeCount,omitempty"`
+	TotalMinNodeCount int32 `json:"totalMinNodeCount,omitempty"`
+	TotalMaxNodeCount int32 `json:"totalMaxNodeCount,omitempty"`
+	LocationPolicy    int32 `json:"locationPolicy,omitempty"`
+}
+
+// Suspend scales every node pool of the cluster to zero nodes, like Stop, and returns their sizes and autoscaling. See
+// nuke.Suspender.
+func (r *GKECluster) Suspend(ctx context.Context) (nuke.SuspendedState, error) {
+	if r.autopilot {
+		return nil, nuke.NotSuspended("the nodes of an Autopilot cluster are managed by GKE")
 	}
 
-	if r.removeOp.GetError() != nil {
-		return fmt.Errorf("operation failed: %v", r.removeOp.GetError())
+	resp, err := r.svc.ListNodePools(ctx, &containerpb.ListNodePoolsRequest{Parent: r.fullName()})
+	if err != nil {
+		return nil, err
+	}
+
+	pools := make([]*gkeNodePoolState, 0, len(resp.NodePools))
+	suspend := false
+	for _, pool := range resp.NodePools {
+		state := &gkeNodePoolState{Name: pool.Name}
+
+		state.NodeCount, err = r.nodeCount(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+
+		if autoscaling := pool.GetAutoscaling(); autoscaling.GetEnabled() {
+			state.Autoscaling = &gkeAutoscalingState{
+				MinNodeCount:      autoscaling.MinNodeCount,
+				MaxNodeCount:      autoscaling.MaxNodeCount,
+				TotalMinNodeCount: autoscaling.TotalMinNodeCount,
+				TotalMaxNodeCount: autoscaling.TotalMaxNodeCount,
+				LocationPolicy:    int32(autoscaling.LocationPolicy),
+			}
+		}
+
+		suspend = suspend || state.NodeCount > 0 || state.Autoscaling != nil
+		pools = append(pools, state)
+	}
+
+	if !suspend {
+		return nil, nuke.NotSuspended("the node pools have no nodes")
+	}
+
+	state, err := nuke.NewSuspendedState(pools)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: the state is returned along with the error when a node pool could not be scaled down, the node pools that
+	// were are resumed from it
+	for _, pool := range resp.NodePools {
+		if err := r.scaleDown(ctx, pool); err != nil {
+			return state, fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+	}
+
+	return state, nil
+}
+
+// Resume scales every node pool of the cluster back to its size and enables its autoscaling again, see nuke.Suspender
+func (r *GKECluster) Resume(ctx context.Context, state nuke.SuspendedState) error {
+	pools := make([]*gkeNodePoolState, 0)
+	if err := state.Decode(&pools); err != nil {
+		return err
+	}
+
+	for _, pool := range pools {
+		if err := r.setNodePoolSize(ctx, pool.Name, pool.NodeCount); err != nil {
+			return fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
+
+		if pool.Autoscaling == nil {
+			continue
+		}
+
+		if err := r.setNodePoolAutoscaling(ctx, pool.Name, &containerpb.NodePoolAutoscaling{
+			Enabled:           true,
+			MinNodeCount:      pool.Autoscaling.MinNodeCount,
+			MaxNodeCount:      pool.Autoscaling.MaxNodeCount,
+			TotalMinNodeCount: pool.Autoscaling.TotalMinNodeCount,
+			TotalMaxNodeCount: pool.Autoscaling.TotalMaxNodeCount,
+			LocationPolicy:    containerpb.NodePoolAutoscaling_LocationPolicy(pool.Autoscaling.LocationPolicy),
+		}); err != nil {
+			return fmt.Errorf("node pool %s: %w", pool.Name, err)
+		}
 	}
 
 	return nil
 }
+
+// scaleDown scales the node pool to zero nodes, its autoscaling is disabled first so that it stays empty
+func (r *GKECluster) scaleDown(ctx context.Context, pool *containerpb.NodePool) error {
+	if pool.GetAutoscaling().GetEnabled() {
+		err := r.setNodePoolAutoscaling(ctx, pool.Name, &containerpb.NodePoolAutoscaling{Enabled: false})
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.setNodePoolSize(ctx, pool.Name, 0)
+}
+
+// setNodePoolSize sets the number of nodes per zone of the node pool. GKE runs a single operation at a time on a
+// cluster, so the operation is waited for.
+func (r *GKECluster) setNodePoolSize(ctx context.Context, pool string, nodeCount int32) error {
+	op, err := r.svc.SetNodePoolSize(ctx, &containerpb.SetNodePoolSizeRequest{
+		Name:      fmt.Sprintf("%s/nodePools/%s", r.fullName(), pool),
+		NodeCount: nodeCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ContainerOperation(r.svc, *r.Project, op))
+}
+
+// setNodePoolAutoscaling sets the autoscaling of the node pool and waits for the operation, see setNodePoolSize
+func (r *GKECluster) setNodePoolAutoscaling(
+	ctx context.Context, pool string, autoscaling *containerpb.NodePoolAutoscaling,
+) error {
+	op, err := r.svc.SetNodePoolAutoscaling(ctx, &containerpb.SetNodePoolAutoscalingRequest{
+		Name:        fmt.Sprintf("%s/nodePools/%s", r.fullName(), pool),
+		Autoscaling: autoscaling,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ContainerOperation(r.svc, *r.Project, op))
+}
+
+// nodeCount returns the number of nodes per zone of the node pool, the largest target size of its managed instance
+// groups, one per zone
+func (r *GKECluster) nodeCount(ctx context.Context, pool *containerpb.NodePool) (int32, error) {
+	if len(pool.InstanceGroupUrls) == 0 {
+		return pool.InitialNodeCount, nil
+	}
+
+	nodeCount := int32(0)
+	for _, url := range pool.InstanceGroupUrls {
+		// Note: the URL ends with projects/<project>/zones/<zone>/instanceGroupManagers/<name>
+		parts := strings.Split(url, "/")
+		if len(parts) < 6 {
+			return 0, fmt.Errorf("unexpected URL of the instance group of node pool %s: %s", pool.Name, url)
+		}
+
+		group, err := r.igm.Get(ctx, &computepb.GetInstanceGroupManagerRequest{
+			Project:              parts[len(parts)-5],
+			Zone:                 parts[len(parts)-3],
+			InstanceGroupManager: parts[len(parts)-1],
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		nodeCount = max(nodeCount, group.GetTargetSize())
+	}
+
+	return nodeCount, nil
+}
+
+// fullName returns the resource name of the cluster, in its zone for the zonal clusters
+func (r *GKECluster) fullName() string {
+	location := r.Region
+	if *r.Zone != "" {
+		location = r.Zone
+	}
+
+	return fmt.Sprintf("projects/%s/locations/%s/clusters/%s", *r.Project, *location, *r.Name)
+}
+
+func (r *GKECluster) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *GKECluster) String() string {
+	return *r.Name
+}
+
+func (r *GKECluster) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
+	}
+
+	return r.removeWait.Wait(ctx, nuke.ContainerOperation(r.svc, *r.Project, r.removeOp))
+}
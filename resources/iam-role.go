@@ -48,7 +48,7 @@ func (l *IAMRoleLister) List(ctx context.Context, o interface{}) ([]resource.Res
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = iamadmin.NewIamClient(ctx, opts.ClientOptions...)
+		l.svc, err = iamadmin.NewIamClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -64,7 +64,7 @@ func (l *IAMRoleLister) List(ctx context.Context, o interface{}) ([]resource.Res
 
 		resp, err := l.svc.ListRoles(ctx, req)
 		if err != nil {
-			return nil, err
+			return nil, opts.ListError(IAMRoleResource, err)
 		}
 
 		for _, role := range resp.GetRoles() {
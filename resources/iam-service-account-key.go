@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -48,7 +49,7 @@ func (l *IAMServiceAccountKeyLister) List(ctx context.Context, o interface{}) ([
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = iamadmin.NewIamClient(ctx, opts.ClientOptions...)
+		l.svc, err = iamadmin.NewIamClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -58,7 +59,7 @@ func (l *IAMServiceAccountKeyLister) List(ctx context.Context, o interface{}) ([
 		svc: l.svc,
 	}
 
-	sas, err := saLister.ListServiceAccounts(ctx, opts)
+	sas, err := saLister.ListServiceAccounts(ctx, opts, IAMServiceAccountKeyResource)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +87,7 @@ func (l *IAMServiceAccountKeyLister) List(ctx context.Context, o interface{}) ([
 				ServiceAccountID:    ptr.String(sa.UniqueId),
 				ServiceAccountEmail: ptr.String(sa.Email),
 				Disabled:            ptr.Bool(key.Disabled),
+				CreatedAt:           nuke.CreatedAtFromProto(key.ValidAfterTime),
 			})
 		}
 	}
@@ -104,6 +106,7 @@ type IAMServiceAccountKey struct {
 	ServiceAccountID    *string
 	ServiceAccountEmail *string
 	Disabled            *bool
+	CreatedAt           *time.Time
 }
 
 func (r *IAMServiceAccountKey) Filter() error {
@@ -2,19 +2,16 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/gotidy/ptr"
 
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
-
 	iamadmin "cloud.google.com/go/iam/admin/apiv1"
 	"cloud.google.com/go/iam/admin/apiv1/adminpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -51,37 +48,28 @@ func (l *IAMServiceAccountLister) Close() {
 	}
 }
 
+// ListServiceAccounts returns every service account of the project, a listing error is reported for the resourceType
+// of the calling lister. The service accounts are listed once per run.
 func (l *IAMServiceAccountLister) ListServiceAccounts(
-	ctx context.Context, opts *nuke.ListerOpts,
+	ctx context.Context, opts *nuke.ListerOpts, resourceType string,
 ) ([]*adminpb.ServiceAccount, error) {
 	if l.svc == nil {
 		var err error
-		l.svc, err = iamadmin.NewIamClient(ctx, opts.ClientOptions...)
+		l.svc, err = iamadmin.NewIamClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	var serviceAccounts []*adminpb.ServiceAccount
-
 	req := &adminpb.ListServiceAccountsRequest{
 		Name: fmt.Sprintf("projects/%s", *opts.Project),
 	}
-	it := l.svc.ListServiceAccounts(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate service accounts")
-			break
-		}
-
-		serviceAccounts = append(serviceAccounts, resp)
-	}
-
-	return serviceAccounts, nil
+	return nuke.Cached(ctx, opts, resourceType, "iam.googleapis.com", "serviceAccounts",
+		func() ([]*adminpb.ServiceAccount, error) {
+			return nuke.ListAll(ctx, opts, resourceType, func() nuke.Iterator[*adminpb.ServiceAccount] {
+				return l.svc.ListServiceAccounts(ctx, req)
+			})
+		})
 }
 
 func (l *IAMServiceAccountLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
@@ -92,7 +80,7 @@ func (l *IAMServiceAccountLister) List(ctx context.Context, o interface{}) ([]re
 		return resources, err
 	}
 
-	serviceAccounts, err := l.ListServiceAccounts(ctx, opts)
+	serviceAccounts, err := l.ListServiceAccounts(ctx, opts, IAMServiceAccountResource)
 	if err != nil {
 		return resources, err
 	}
@@ -108,6 +96,7 @@ func (l *IAMServiceAccountLister) List(ctx context.Context, o interface{}) ([]re
 			ID:          ptr.String(serviceAccount.UniqueId),
 			Name:        ptr.String(name),
 			Description: ptr.String(serviceAccount.Description),
+			Marked:      nuke.MarkFromDescription(serviceAccount.Description),
 		})
 	}
 
@@ -122,6 +111,7 @@ type IAMServiceAccount struct {
 	ID          *string
 	Name        *string
 	Description *string
+	Marked      *string `property:"name=label:gcp-nuke-marked" description:"The mark for removal in the description, service accounts have no labels"`
 }
 
 func (r *IAMServiceAccount) Filter() error {
@@ -151,6 +141,18 @@ func (r *IAMServiceAccount) Remove(ctx context.Context) error {
 	})
 }
 
+// Mark adds the mark to the description of the service account, it has no labels, see nuke.Marker
+func (r *IAMServiceAccount) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.PatchServiceAccount(ctx, &adminpb.PatchServiceAccountRequest{
+		ServiceAccount: &adminpb.ServiceAccount{
+			Name:        *r.fullName,
+			Description: nuke.DescriptionWithMark(ptr.ToString(r.Description), markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+	})
+	return err
+}
+
 func (r *IAMServiceAccount) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -44,9 +44,9 @@ func (l *IAMWorkloadIdentityPoolProviderLister) List(ctx context.Context, o inte
 	}
 
 	workloadIdentityPoolLister := &IAMWorkloadIdentityPoolLister{}
-	workloadIdentityPools, err := workloadIdentityPoolLister.ListPools(ctx, opts)
+	workloadIdentityPools, err := workloadIdentityPoolLister.ListPools(ctx, opts, IAMWorkloadIdentityPoolProviderProviderResource)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(IAMWorkloadIdentityPoolProviderProviderResource, err)
 	}
 
 	for _, workloadIdentityPool := range workloadIdentityPools {
@@ -60,7 +60,7 @@ func (l *IAMWorkloadIdentityPoolProviderLister) List(ctx context.Context, o inte
 
 			resp, err := call.Context(ctx).Do()
 			if err != nil {
-				return nil, err
+				return nil, opts.ListError(IAMWorkloadIdentityPoolProviderProviderResource, err)
 			}
 
 			for _, provider := range resp.WorkloadIdentityPoolProviders {
@@ -28,7 +28,11 @@ type IAMWorkloadIdentityPoolLister struct {
 	svc *iam.Service
 }
 
-func (l *IAMWorkloadIdentityPoolLister) ListPools(ctx context.Context, opts *nuke.ListerOpts) ([]*iam.WorkloadIdentityPool, error) {
+// ListPools returns every workload identity pool of the project, they are listed once per run. The resourceType is the
+// one of the calling lister.
+func (l *IAMWorkloadIdentityPoolLister) ListPools(
+	ctx context.Context, opts *nuke.ListerOpts, resourceType string,
+) ([]*iam.WorkloadIdentityPool, error) {
 	if l.svc == nil {
 		var err error
 		l.svc, err = iam.NewService(ctx, opts.ClientOptions...)
@@ -37,31 +41,34 @@ func (l *IAMWorkloadIdentityPoolLister) ListPools(ctx context.Context, opts *nuk
 		}
 	}
 
-	resourceName := fmt.Sprintf("projects/%s/locations/global", *opts.Project)
-	var nextPageToken string
+	return nuke.Cached(ctx, opts, resourceType, "iam.googleapis.com", "workloadIdentityPools",
+		func() ([]*iam.WorkloadIdentityPool, error) {
+			resourceName := fmt.Sprintf("projects/%s/locations/global", *opts.Project)
+			var nextPageToken string
 
-	var allPools []*iam.WorkloadIdentityPool
+			var allPools []*iam.WorkloadIdentityPool
 
-	for {
-		call := l.svc.Projects.Locations.WorkloadIdentityPools.List(resourceName)
-		if nextPageToken != "" {
-			call.PageToken(nextPageToken)
-		}
+			for {
+				call := l.svc.Projects.Locations.WorkloadIdentityPools.List(resourceName)
+				if nextPageToken != "" {
+					call.PageToken(nextPageToken)
+				}
 
-		resp, err := call.Context(ctx).Do()
-		if err != nil {
-			return nil, err
-		}
+				resp, err := call.Context(ctx).Do()
+				if err != nil {
+					return nil, err
+				}
 
-		allPools = append(allPools, resp.WorkloadIdentityPools...)
+				allPools = append(allPools, resp.WorkloadIdentityPools...)
 
-		nextPageToken = resp.NextPageToken
-		if nextPageToken == "" {
-			break
-		}
-	}
+				nextPageToken = resp.NextPageToken
+				if nextPageToken == "" {
+					break
+				}
+			}
 
-	return allPools, nil
+			return allPools, nil
+		})
 }
 
 func (l *IAMWorkloadIdentityPoolLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
@@ -72,9 +79,9 @@ func (l *IAMWorkloadIdentityPoolLister) List(ctx context.Context, o interface{})
 		return resources, err
 	}
 
-	workloadIdentityPools, err := l.ListPools(ctx, opts)
+	workloadIdentityPools, err := l.ListPools(ctx, opts, IAMWorkloadIdentityPoolResource)
 	if err != nil {
-		return nil, err
+		return nil, opts.ListError(IAMWorkloadIdentityPoolResource, err)
 	}
 
 	for _, pool := range workloadIdentityPools {
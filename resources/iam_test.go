@@ -0,0 +1,198 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cloud.google.com/go/iam/admin/apiv1/adminpb"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+func TestIAMPolicyBindingLister(t *testing.T) {
+	policy := func() testutil.Item {
+		return testutil.Item{
+			"bindings": []testutil.Item{
+				{
+					"role": "roles/editor",
+					"members": []string{
+						"user:someone@example.com",
+						"serviceAccount:app@test-project.iam.gserviceaccount.com",
+					},
+				},
+				{
+					"role": "roles/editor",
+					"members": []string{
+						"serviceAccount:123@cloudservices.gserviceaccount.com",
+					},
+				},
+			},
+		}
+	}
+
+	runListerTests(t, []listerTest{
+		{
+			name:   "binding",
+			lister: &IAMPolicyBindingLister{},
+			region: "global",
+			setup: func(fake *testutil.Server) {
+				fake.SetIAMPolicy("projects/"+testProject, policy())
+			},
+			want: []string{
+				"user:someone@example.com -> roles/editor",
+				"serviceAccount:app@test-project.iam.gserviceaccount.com -> roles/editor",
+				"serviceAccount:123@cloudservices.gserviceaccount.com -> roles/editor",
+			},
+			filtered: []string{
+				"serviceAccount:123@cloudservices.gserviceaccount.com -> roles/editor",
+			},
+			removed: []string{
+				"POST /v3/projects/test-project:setIamPolicy",
+			},
+			verify: func(t *testing.T, fake *testutil.Server) {
+				bindings := fake.IAMPolicy("projects/" + testProject)["bindings"].([]any)
+				require.Len(t, bindings, 2)
+				assert.Empty(t, bindings[0].(map[string]any)["members"])
+				assert.Equal(t, []any{"serviceAccount:123@cloudservices.gserviceaccount.com"},
+					bindings[1].(map[string]any)["members"])
+			},
+		},
+		{
+			name:     "binding-delete-google-managed",
+			lister:   &IAMPolicyBindingLister{},
+			region:   "global",
+			settings: settings.Setting{"DeleteGoogleManaged": true},
+			setup: func(fake *testutil.Server) {
+				fake.SetIAMPolicy("projects/"+testProject, policy())
+			},
+			want: []string{
+				"user:someone@example.com -> roles/editor",
+				"serviceAccount:app@test-project.iam.gserviceaccount.com -> roles/editor",
+				"serviceAccount:123@cloudservices.gserviceaccount.com -> roles/editor",
+			},
+		},
+		{
+			name:   "workload-identity-pool",
+			lister: &IAMWorkloadIdentityPoolLister{},
+			region: "global",
+			setup: func(fake *testutil.Server) {
+				fake.Add("/v1/projects/test-project/locations/global/workloadIdentityPools", "workloadIdentityPools",
+					testutil.Item{"name": "projects/test-project/locations/global/workloadIdentityPools/pool-1"})
+			},
+			want: []string{"pool-1"},
+			removed: []string{
+				"DELETE /v1/projects/test-project/locations/global/workloadIdentityPools/pool-1",
+			},
+		},
+	})
+}
+
+// iamAdminTest is a single case of a lister test that runs against the fake IAM admin gRPC server
+type iamAdminTest struct {
+	name     string
+	lister   registry.Lister
+	setup    func(fake *testutil.IAMServer)
+	settings settings.Setting
+	want     []string
+	filtered []string
+	deleted  []string
+}
+
+func TestIAMAdminListers(t *testing.T) {
+	serviceAccount := func(email string) *adminpb.ServiceAccount {
+		return &adminpb.ServiceAccount{
+			Name:     "projects/test-project/serviceAccounts/" + email,
+			Email:    email,
+			UniqueId: "1" + email[:1],
+		}
+	}
+
+	tests := []iamAdminTest{
+		{
+			name:   "service-account",
+			lister: &IAMServiceAccountLister{},
+			setup: func(fake *testutil.IAMServer) {
+				fake.AddServiceAccounts(
+					serviceAccount("app@test-project.iam.gserviceaccount.com"),
+					serviceAccount("123-compute@developer.gserviceaccount.com"),
+				)
+				fake.AddServiceAccounts(&adminpb.ServiceAccount{
+					Name: "projects/other-project/serviceAccounts/other@other-project.iam.gserviceaccount.com",
+				})
+			},
+			want: []string{
+				"app@test-project.iam.gserviceaccount.com",
+				"123-compute@developer.gserviceaccount.com",
+			},
+			filtered: []string{"123-compute@developer.gserviceaccount.com"},
+			deleted:  []string{"projects/test-project/serviceAccounts/app@test-project.iam.gserviceaccount.com"},
+		},
+		{
+			name:     "service-account-delete-default",
+			lister:   &IAMServiceAccountLister{},
+			settings: settings.Setting{"DeleteDefaultServiceAccounts": true},
+			setup: func(fake *testutil.IAMServer) {
+				fake.AddServiceAccounts(serviceAccount("123-compute@developer.gserviceaccount.com"))
+			},
+			want:    []string{"123-compute@developer.gserviceaccount.com"},
+			deleted: []string{"projects/test-project/serviceAccounts/123-compute@developer.gserviceaccount.com"},
+		},
+		{
+			name:   "service-account-key",
+			lister: &IAMServiceAccountKeyLister{},
+			setup: func(fake *testutil.IAMServer) {
+				sa := serviceAccount("app@test-project.iam.gserviceaccount.com")
+				fake.AddServiceAccounts(sa)
+				fake.AddServiceAccountKeys(sa.Name,
+					&adminpb.ServiceAccountKey{
+						Name:    sa.Name + "/keys/user-key",
+						KeyType: adminpb.ListServiceAccountKeysRequest_USER_MANAGED,
+					},
+					&adminpb.ServiceAccountKey{
+						Name:    sa.Name + "/keys/system-key",
+						KeyType: adminpb.ListServiceAccountKeysRequest_SYSTEM_MANAGED,
+					})
+			},
+			want: []string{
+				"app@test-project.iam.gserviceaccount.com -> user-key",
+				"app@test-project.iam.gserviceaccount.com -> system-key",
+			},
+			filtered: []string{"app@test-project.iam.gserviceaccount.com -> system-key"},
+			deleted: []string{
+				"projects/test-project/serviceAccounts/app@test-project.iam.gserviceaccount.com/keys/user-key",
+			},
+		},
+		{
+			name:   "role",
+			lister: &IAMRoleLister{},
+			setup: func(fake *testutil.IAMServer) {
+				fake.AddRoles(
+					&adminpb.Role{Name: "projects/test-project/roles/custom"},
+					&adminpb.Role{Name: "organizations/123456789012/roles/org-custom"},
+				)
+			},
+			want:    []string{"custom"},
+			deleted: []string{"projects/test-project/roles/custom"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := testutil.NewIAMServer(t)
+			tc.setup(fake)
+
+			opts := newTestListerOpts("global", fake.ClientOptions())
+
+			resources := listResources(t, tc.lister, opts, tc.want)
+			filtered := removeResources(t, resources, tc.settings)
+
+			assert.ElementsMatch(t, tc.filtered, filtered)
+			assert.ElementsMatch(t, tc.deleted, fake.Deleted())
+		})
+	}
+}
@@ -2,15 +2,13 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	kms "cloud.google.com/go/kms/apiv1"
 	"cloud.google.com/go/kms/apiv1/kmspb"
 
@@ -60,7 +58,7 @@ func (l *KMSKeyLister) List(ctx context.Context, o interface{}) ([]resource.Reso
 	var resources []resource.Resource
 
 	opts := o.(*nuke.ListerOpts)
-	if err := opts.BeforeList(nuke.Regional, "cloudkms.googleapis.com", KMSKeyResource); err != nil {
+	if err := opts.BeforeList(nuke.MultiRegional, "cloudkms.googleapis.com", KMSKeyResource); err != nil {
 		return resources, err
 	}
 
@@ -73,55 +71,55 @@ func (l *KMSKeyLister) List(ctx context.Context, o interface{}) ([]resource.Reso
 	}
 
 	req := &kmspb.ListKeyRingsRequest{
-		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
+		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, opts.Location("cloudkms.googleapis.com")),
+	}
+	keyRings, err := nuke.ListAll(ctx, opts, KMSKeyResource, func() nuke.Iterator[*kmspb.KeyRing] {
+		return l.svc.ListKeyRings(ctx, req)
+	})
+	if err != nil {
+		return nil, err
 	}
-	it := l.svc.ListKeyRings(ctx, req)
-	for {
-		keyRing, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate kms-key")
-			break
-		}
 
+	for _, keyRing := range keyRings {
 		reqKeys := &kmspb.ListCryptoKeysRequest{
 			Parent: keyRing.Name,
 		}
-		itKeys := l.svc.ListCryptoKeys(ctx, reqKeys)
-		for {
-			cryptoKey, err := itKeys.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate kms-key")
-				break
-			}
+		cryptoKeys, err := nuke.ListAll(ctx, opts, KMSKeyResource, func() nuke.Iterator[*kmspb.CryptoKey] {
+			return l.svc.ListCryptoKeys(ctx, reqKeys)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, cryptoKey := range cryptoKeys {
 			nameParts := strings.Split(cryptoKey.Name, "/")
 			name := nameParts[len(nameParts)-1]
 
 			keyringNameParts := strings.Split(keyRing.Name, "/")
 			keyringName := keyringNameParts[len(keyringNameParts)-1]
 
+			// Note: only the symmetric keys have a primary version, the versions of the other keys are not removed
+			if cryptoKey.Primary == nil {
+				logrus.WithField("key", cryptoKey.Name).Trace("skipping key without primary version")
+				continue
+			}
+
 			reqPrimaryVersion := &kmspb.GetCryptoKeyVersionRequest{
 				Name: cryptoKey.Primary.Name,
 			}
 			keyVersion, err := l.svc.GetCryptoKeyVersion(ctx, reqPrimaryVersion)
 			if err != nil {
-				logrus.WithError(err).Error("unable to get primary key version")
-				break
+				return nil, opts.ListError(KMSKeyResource, err)
 			}
 
 			resources = append(resources, &KMSKey{
-				svc:      l.svc,
-				project:  opts.Project,
-				fullName: ptr.String(keyVersion.Name),
-				Name:     ptr.String(name),
-				Keyring:  ptr.String(keyringName),
-				State:    ptr.String(keyVersion.State.String()),
+				svc:       l.svc,
+				project:   opts.Project,
+				fullName:  ptr.String(keyVersion.Name),
+				Name:      ptr.String(name),
+				Keyring:   ptr.String(keyringName),
+				State:     ptr.String(keyVersion.State.String()),
+				CreatedAt: nuke.CreatedAtFromProto(keyVersion.CreateTime),
 			})
 		}
 	}
@@ -130,12 +128,13 @@ func (l *KMSKeyLister) List(ctx context.Context, o interface{}) ([]resource.Reso
 }
 
 type KMSKey struct {
-	svc      *kms.KeyManagementClient
-	project  *string
-	fullName *string
-	Name     *string
-	Keyring  *string
-	State    *string
+	svc       *kms.KeyManagementClient
+	project   *string
+	fullName  *string
+	Name      *string
+	Keyring   *string
+	State     *string
+	CreatedAt *time.Time
 }
 
 func (r *KMSKey) Remove(ctx context.Context) error {
@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+func TestKMSKeyLister(t *testing.T) {
+	runListerTests(t, []listerTest{
+		{
+			name:   "key",
+			lister: &KMSKeyLister{},
+			region: testRegion,
+			setup: func(fake *testutil.Server) {
+				fake.AddKMSKey(testProject, testRegion, "ring", "enabled-key", "ENABLED")
+				fake.AddKMSKey(testProject, testRegion, "ring", "scheduled-key", "DESTROY_SCHEDULED")
+				fake.AddKMSKey(testProject, testRegion, "ring", "destroyed-key", "DESTROYED")
+			},
+			want:     []string{"enabled-key", "scheduled-key", "destroyed-key"},
+			filtered: []string{"scheduled-key", "destroyed-key"},
+			removed: []string{
+				"POST /v1/projects/test-project/locations/us-east1/keyRings/ring/cryptoKeys/enabled-key/" +
+					"cryptoKeyVersions/1:destroy",
+			},
+		},
+		{
+			name:   "key-multi-region",
+			lister: &KMSKeyLister{},
+			region: "multi-region:eu",
+			setup: func(fake *testutil.Server) {
+				fake.AddKMSKey(testProject, "europe", "ring", "europe-key", "ENABLED")
+				fake.AddKMSKey(testProject, testRegion, "ring", "regional-key", "ENABLED")
+			},
+			want: []string{"europe-key"},
+			removed: []string{
+				"POST /v1/projects/test-project/locations/europe/keyRings/ring/cryptoKeys/europe-key/" +
+					"cryptoKeyVersions/1:destroy",
+			},
+		},
+	})
+}
+
+func TestKMSKeyDestroyIsScheduled(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddKMSKey(testProject, testRegion, "ring", "key", "ENABLED")
+
+	opts := newTestListerOpts(testRegion, fake.ClientOptions())
+
+	removeResources(t, listResources(t, &KMSKeyLister{}, opts, []string{"key"}), nil)
+
+	// Note: listing again must now filter the key, as its only version is scheduled for destruction
+	filtered := removeResources(t, listResources(t, &KMSKeyLister{}, opts, []string{"key"}), nil)
+	assert.Equal(t, []string{"key"}, filtered)
+}
+
+func TestKMSKeyListerWithoutPrimary(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddKMSKey(testProject, testRegion, "ring", "key", "ENABLED")
+
+	keyRingName := "projects/test-project/locations/us-east1/keyRings/ring"
+	fake.Add("/v1/"+keyRingName+"/cryptoKeys", "cryptoKeys", testutil.Item{
+		"name":    keyRingName + "/cryptoKeys/signing-key",
+		"purpose": "ASYMMETRIC_SIGN",
+	})
+
+	listResources(t, &KMSKeyLister{}, newTestListerOpts(testRegion, fake.ClientOptions()), []string{"key"})
+}
+
+func TestKMSKeyListerVersionError(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddKMSKey(testProject, testRegion, "ring", "key", "ENABLED")
+	fake.AddKMSKey(testProject, testRegion, "ring", "other-key", "ENABLED")
+	fake.SetError("GET", "/v1/projects/test-project/locations/us-east1/keyRings/ring/cryptoKeys/key/"+
+		"cryptoKeyVersions/1", http.StatusForbidden, "permission denied")
+
+	opts := newTestListerOpts(testRegion, fake.ClientOptions())
+	opts.ListingErrors = &nuke.ListingErrors{}
+
+	lister := &KMSKeyLister{}
+	t.Cleanup(lister.Close)
+
+	resources, err := lister.List(context.TODO(), opts)
+	assert.Nil(t, resources)
+
+	var listErr *nuke.ListError
+	require.ErrorAs(t, err, &listErr)
+	assert.Equal(t, KMSKeyResource, listErr.ResourceType)
+	assert.Equal(t, nuke.ListErrorPermissionDenied, listErr.Kind)
+	assert.Equal(t, []*nuke.ListError{listErr}, opts.ListingErrors.Errors())
+}
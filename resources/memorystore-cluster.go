@@ -2,18 +2,13 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	cluster "cloud.google.com/go/redis/cluster/apiv1"
 	"cloud.google.com/go/redis/cluster/apiv1/clusterpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -46,7 +41,7 @@ func (l *MemorystoreClusterLister) List(ctx context.Context, o interface{}) ([]r
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = cluster.NewCloudRedisClusterClient(ctx, opts.ClientOptions...)
+		l.svc, err = cluster.NewCloudRedisClusterClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -55,17 +50,14 @@ func (l *MemorystoreClusterLister) List(ctx context.Context, o interface{}) ([]r
 	req := &clusterpb.ListClustersRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListClusters(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate memorystore clusters")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, MemorystoreClusterResource, func() nuke.Iterator[*clusterpb.Cluster] {
+		return l.svc.ListClusters(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -77,6 +69,9 @@ func (l *MemorystoreClusterLister) List(ctx context.Context, o interface{}) ([]r
 			FullName:   &resp.Name,
 			State:      resp.State.String(),
 			ShardCount: resp.ShardCount,
+			CreatedAt:  nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -92,12 +87,14 @@ func (l *MemorystoreClusterLister) Close() {
 type MemorystoreCluster struct {
 	svc        *cluster.CloudRedisClusterClient
 	removeOp   *cluster.DeleteClusterOperation
+	removeWait nuke.OperationTracker
 	project    *string
 	region     *string
 	Name       *string
 	FullName   *string
 	State      string
 	ShardCount *int32
+	CreatedAt  *time.Time
 }
 
 func (r *MemorystoreCluster) Remove(ctx context.Context) (err error) {
@@ -120,14 +117,5 @@ func (r *MemorystoreCluster) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
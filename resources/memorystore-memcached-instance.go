@@ -2,18 +2,13 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	memcache "cloud.google.com/go/memcache/apiv1"
 	"cloud.google.com/go/memcache/apiv1/memcachepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -46,7 +41,7 @@ func (l *MemorystoreMemcachedInstanceLister) List(ctx context.Context, o interfa
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = memcache.NewCloudMemcacheClient(ctx, opts.ClientOptions...)
+		l.svc, err = memcache.NewCloudMemcacheClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -55,17 +50,14 @@ func (l *MemorystoreMemcachedInstanceLister) List(ctx context.Context, o interfa
 	req := &memcachepb.ListInstancesRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListInstances(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate memorystore memcached instances")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, MemorystoreMemcachedInstanceResource, func() nuke.Iterator[*memcachepb.Instance] {
+		return l.svc.ListInstances(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -78,6 +70,9 @@ func (l *MemorystoreMemcachedInstanceLister) List(ctx context.Context, o interfa
 			State:     resp.State.String(),
 			NodeCount: resp.NodeCount,
 			Labels:    resp.Labels,
+			CreatedAt: nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -91,15 +86,17 @@ func (l *MemorystoreMemcachedInstanceLister) Close() {
 }
 
 type MemorystoreMemcachedInstance struct {
-	svc       *memcache.CloudMemcacheClient
-	removeOp  *memcache.DeleteInstanceOperation
-	project   *string
-	region    *string
-	Name      *string
-	FullName  *string
-	State     string
-	NodeCount int32
-	Labels    map[string]string `property:"tagPrefix=label"`
+	svc        *memcache.CloudMemcacheClient
+	removeOp   *memcache.DeleteInstanceOperation
+	removeWait nuke.OperationTracker
+	project    *string
+	region     *string
+	Name       *string
+	FullName   *string
+	State      string
+	NodeCount  int32
+	CreatedAt  *time.Time
+	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *MemorystoreMemcachedInstance) Remove(ctx context.Context) (err error) {
@@ -122,14 +119,5 @@ func (r *MemorystoreMemcachedInstance) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
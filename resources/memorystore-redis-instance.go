@@ -2,18 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	redis "cloud.google.com/go/redis/apiv1"
 	"cloud.google.com/go/redis/apiv1/redispb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -46,7 +42,7 @@ func (l *MemorystoreRedisInstanceLister) List(ctx context.Context, o interface{}
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = redis.NewCloudRedisClient(ctx, opts.ClientOptions...)
+		l.svc, err = redis.NewCloudRedisClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -55,17 +51,14 @@ func (l *MemorystoreRedisInstanceLister) List(ctx context.Context, o interface{}
 	req := &redispb.ListInstancesRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListInstances(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate memorystore redis instances")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, MemorystoreRedisInstanceResource, func() nuke.Iterator[*redispb.Instance] {
+		return l.svc.ListInstances(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -76,9 +69,13 @@ func (l *MemorystoreRedisInstanceLister) List(ctx context.Context, o interface{}
 			Name:         &name,
 			FullName:     &resp.Name,
 			Tier:         resp.Tier.String(),
+			MemorySizeGb: resp.MemorySizeGb,
 			State:        resp.State.String(),
 			RedisVersion: &resp.RedisVersion,
 			Labels:       resp.Labels,
+			CreatedAt:    nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -94,13 +91,16 @@ func (l *MemorystoreRedisInstanceLister) Close() {
 type MemorystoreRedisInstance struct {
 	svc          *redis.CloudRedisClient
 	removeOp     *redis.DeleteInstanceOperation
+	removeWait   nuke.OperationTracker
 	project      *string
 	region       *string
 	Name         *string
 	FullName     *string
 	Tier         string
+	MemorySizeGb int32
 	State        string
 	RedisVersion *string
+	CreatedAt    *time.Time
 	Labels       map[string]string `property:"tagPrefix=label"`
 }
 
@@ -119,19 +119,27 @@ func (r *MemorystoreRedisInstance) String() string {
 	return *r.Name
 }
 
-func (r *MemorystoreRedisInstance) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the instance, see nuke.Marker
+func (r *MemorystoreRedisInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateInstance(ctx, &redispb.UpdateInstanceRequest{
+		Instance: &redispb.Instance{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *MemorystoreRedisInstance) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
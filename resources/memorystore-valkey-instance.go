@@ -2,18 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	memorystore "cloud.google.com/go/memorystore/apiv1"
 	"cloud.google.com/go/memorystore/apiv1/memorystorepb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -55,17 +51,14 @@ func (l *MemorystoreValkeyInstanceLister) List(ctx context.Context, o interface{
 	req := &memorystorepb.ListInstancesRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListInstances(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate memorystore valkey instances")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, MemorystoreValkeyInstanceResource, func() nuke.Iterator[*memorystorepb.Instance] {
+		return l.svc.ListInstances(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -78,6 +71,9 @@ func (l *MemorystoreValkeyInstanceLister) List(ctx context.Context, o interface{
 			State:      resp.State.String(),
 			ShardCount: resp.ShardCount,
 			Labels:     resp.Labels,
+			CreatedAt:  nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -93,12 +89,14 @@ func (l *MemorystoreValkeyInstanceLister) Close() {
 type MemorystoreValkeyInstance struct {
 	svc        *memorystore.Client
 	removeOp   *memorystore.DeleteInstanceOperation
+	removeWait nuke.OperationTracker
 	project    *string
 	region     *string
 	Name       *string
 	FullName   *string
 	State      string
 	ShardCount int32
+	CreatedAt  *time.Time
 	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
@@ -117,19 +115,27 @@ func (r *MemorystoreValkeyInstance) String() string {
 	return *r.Name
 }
 
-func (r *MemorystoreValkeyInstance) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("remove op polling encountered error")
+// Mark adds the mark to the labels of the instance, see nuke.Marker
+func (r *MemorystoreValkeyInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateInstance(ctx, &memorystorepb.UpdateInstanceRequest{
+		Instance: &memorystorepb.Instance{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *MemorystoreValkeyInstance) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
@@ -2,19 +2,16 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	networkconnectivity "cloud.google.com/go/networkconnectivity/apiv1"
 	"cloud.google.com/go/networkconnectivity/apiv1/networkconnectivitypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -53,7 +50,7 @@ func (l *ServiceConnectionPolicyLister) List(ctx context.Context, o interface{})
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = networkconnectivity.NewCrossNetworkAutomationClient(ctx, opts.ClientOptions...)
+		l.svc, err = networkconnectivity.NewCrossNetworkAutomationClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -62,17 +59,14 @@ func (l *ServiceConnectionPolicyLister) List(ctx context.Context, o interface{})
 	req := &networkconnectivitypb.ListServiceConnectionPoliciesRequest{
 		Parent: fmt.Sprintf("projects/%s/locations/%s", *opts.Project, *opts.Region),
 	}
-	it := l.svc.ListServiceConnectionPolicies(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate service connection policies")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, ServiceConnectionPolicyResource, func() nuke.Iterator[*networkconnectivitypb.ServiceConnectionPolicy] {
+		return l.svc.ListServiceConnectionPolicies(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -85,6 +79,9 @@ func (l *ServiceConnectionPolicyLister) List(ctx context.Context, o interface{})
 			ServiceClass: ptr.String(resp.ServiceClass),
 			Network:      ptr.String(resp.Network),
 			Labels:       resp.Labels,
+			CreatedAt:    nuke.CreatedAtFromProto(resp.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -94,12 +91,14 @@ func (l *ServiceConnectionPolicyLister) List(ctx context.Context, o interface{})
 type ServiceConnectionPolicy struct {
 	svc          *networkconnectivity.CrossNetworkAutomationClient
 	removeOp     *networkconnectivity.DeleteServiceConnectionPolicyOperation
+	removeWait   nuke.OperationTracker
 	Project      *string
 	Region       *string
 	FullName     *string
 	Name         *string           `description:"The name of the service connection policy"`
 	ServiceClass *string           `description:"The service class (e.g., gcp-cloud-sql, gcp-memorystore-redis)"`
 	Network      *string           `description:"The network this policy applies to"`
+	CreatedAt    *time.Time        `description:"The time the policy was created"`
 	Labels       map[string]string `property:"tagPrefix=label"`
 }
 
@@ -118,19 +117,27 @@ func (r *ServiceConnectionPolicy) String() string {
 	return *r.Name
 }
 
-func (r *ServiceConnectionPolicy) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("service connection policy remove op polling encountered error")
+// Mark adds the mark to the labels of the policy, see nuke.Marker
+func (r *ServiceConnectionPolicy) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateServiceConnectionPolicy(ctx, &networkconnectivitypb.UpdateServiceConnectionPolicyRequest{
+		ServiceConnectionPolicy: &networkconnectivitypb.ServiceConnectionPolicy{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
+	_, err = op.Wait(ctx)
+	return err
+}
+
+func (r *ServiceConnectionPolicy) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
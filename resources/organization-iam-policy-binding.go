@@ -0,0 +1,160 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/cloudresourcemanager/v3"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const OrganizationIAMPolicyBindingResource = "OrganizationIAMPolicyBinding"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     OrganizationIAMPolicyBindingResource,
+		Scope:    nuke.Organization,
+		Resource: &OrganizationIAMPolicyBinding{},
+		Lister:   &OrganizationIAMPolicyBindingLister{},
+		Settings: []string{
+			"DeleteGoogleManaged",
+		},
+	})
+}
+
+type OrganizationIAMPolicyBindingLister struct {
+	svc *cloudresourcemanager.Service
+}
+
+func (l *OrganizationIAMPolicyBindingLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Global, "cloudresourcemanager.googleapis.com",
+		OrganizationIAMPolicyBindingResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = cloudresourcemanager.NewService(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := l.svc.Organizations.
+		GetIamPolicy(fmt.Sprintf("organizations/%s", *opts.Organization), &cloudresourcemanager.GetIamPolicyRequest{}).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, binding := range resp.Bindings {
+		for _, member := range binding.Members {
+			iamPolicyBinding := &OrganizationIAMPolicyBinding{
+				svc:          l.svc,
+				Organization: *opts.Organization,
+				Role:         binding.Role,
+				Member:       member,
+			}
+
+			// Note: service agents live in google owned projects, i.e. service-org-123@gcp-sa-logging.iam.gserviceaccount.com
+			parts := strings.Split(member, "@")
+			if len(parts) > 1 && strings.HasSuffix(parts[1], ".gserviceaccount.com") &&
+				(strings.HasPrefix(parts[1], "gcp-sa-") || strings.HasPrefix(parts[1], "system.")) {
+				iamPolicyBinding.GoogleManaged = true
+			}
+
+			if strings.HasPrefix(iamPolicyBinding.Member, "deleted:") {
+				iamPolicyBinding.IsDeleted = true
+			}
+
+			memberType, _, found := strings.Cut(strings.TrimPrefix(member, "deleted:"), ":")
+			if !found {
+				memberType = "unknown"
+			}
+			iamPolicyBinding.MemberType = memberType
+
+			resources = append(resources, iamPolicyBinding)
+		}
+	}
+
+	return resources, nil
+}
+
+type OrganizationIAMPolicyBinding struct {
+	svc           *cloudresourcemanager.Service
+	settings      *settings.Setting
+	Organization  string `description:"The ID of the organization the binding is set on"`
+	Role          string `description:"The role that is bound"`
+	Member        string `description:"The member the role is bound to"`
+	MemberType    string `description:"The type of the member, i.e. user, group, serviceAccount, domain"`
+	IsDeleted     bool   `description:"Whether the member has been deleted"`
+	GoogleManaged bool   `description:"Whether the member is a Google managed service agent"`
+}
+
+func (r *OrganizationIAMPolicyBinding) Filter() error {
+	if r.GoogleManaged && !r.settings.GetBool("DeleteGoogleManaged") {
+		return fmt.Errorf("binding is managed by Google")
+	}
+
+	return nil
+}
+
+func (r *OrganizationIAMPolicyBinding) Remove(ctx context.Context) error {
+	resourceName := fmt.Sprintf("organizations/%s", r.Organization)
+
+	policy, err := r.svc.Organizations.
+		GetIamPolicy(resourceName, &cloudresourcemanager.GetIamPolicyRequest{}).
+		Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	for _, binding := range policy.Bindings {
+		if binding.Role != r.Role {
+			continue
+		}
+
+		members := binding.Members[:0]
+		for _, member := range binding.Members {
+			if member == r.Member || member == fmt.Sprintf("deleted:%s", r.Member) {
+				continue
+			}
+			members = append(members, member)
+		}
+		binding.Members = members
+	}
+
+	_, err = r.svc.Organizations.SetIamPolicy(resourceName, &cloudresourcemanager.SetIamPolicyRequest{
+		Policy: policy,
+	}).Context(ctx).Do()
+	if err != nil {
+		logrus.Errorf("error removing organization IAM policy binding: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *OrganizationIAMPolicyBinding) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *OrganizationIAMPolicyBinding) String() string {
+	return fmt.Sprintf("%s -> %s", r.Member, r.Role)
+}
+
+func (r *OrganizationIAMPolicyBinding) Settings(setting *settings.Setting) {
+	r.settings = setting
+}
@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+
+	iamadmin "cloud.google.com/go/iam/admin/apiv1"
+	"cloud.google.com/go/iam/admin/apiv1/adminpb"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const OrganizationIAMRoleResource = "OrganizationIAMRole"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:      OrganizationIAMRoleResource,
+		Scope:     nuke.Organization,
+		Resource:  &OrganizationIAMRole{},
+		Lister:    &OrganizationIAMRoleLister{},
+		DependsOn: []string{OrganizationIAMPolicyBindingResource},
+	})
+}
+
+type OrganizationIAMRoleLister struct {
+	svc *iamadmin.IamClient
+}
+
+func (l *OrganizationIAMRoleLister) Close() {
+	if l.svc != nil {
+		_ = l.svc.Close()
+	}
+}
+
+func (l *OrganizationIAMRoleLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Global, "iam.googleapis.com", OrganizationIAMRoleResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = iamadmin.NewIamClient(ctx, opts.GRPCClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var nextPageToken string
+
+	for {
+		req := &adminpb.ListRolesRequest{
+			Parent:      fmt.Sprintf("organizations/%s", *opts.Organization),
+			PageToken:   nextPageToken,
+			ShowDeleted: true,
+		}
+
+		resp, err := l.svc.ListRoles(ctx, req)
+		if err != nil {
+			return nil, opts.ListError(OrganizationIAMRoleResource, err)
+		}
+
+		for _, role := range resp.GetRoles() {
+			roleParts := strings.Split(role.GetName(), "/")
+			roleName := roleParts[len(roleParts)-1]
+			resources = append(resources, &OrganizationIAMRole{
+				svc:          l.svc,
+				Organization: opts.Organization,
+				Name:         ptr.String(roleName),
+				Title:        ptr.String(role.GetTitle()),
+				Etag:         role.Etag,
+				Stage:        ptr.String(role.GetStage().String()),
+				Deleted:      ptr.Bool(role.Deleted),
+			})
+		}
+
+		if resp.GetNextPageToken() == "" {
+			break
+		}
+
+		nextPageToken = resp.GetNextPageToken()
+	}
+
+	return resources, nil
+}
+
+type OrganizationIAMRole struct {
+	svc          *iamadmin.IamClient
+	Organization *string `description:"The ID of the organization the role belongs to"`
+	Name         *string `description:"The ID of the custom role"`
+	Title        *string `description:"The title of the custom role"`
+	Stage        *string `description:"The launch stage of the custom role"`
+	Etag         []byte
+	Deleted      *bool `description:"Whether the role has already been soft deleted"`
+}
+
+func (r *OrganizationIAMRole) Filter() error {
+	if ptr.ToBool(r.Deleted) {
+		return fmt.Errorf("role already deleted")
+	}
+
+	return nil
+}
+
+func (r *OrganizationIAMRole) Remove(ctx context.Context) error {
+	_, err := r.svc.DeleteRole(ctx, &adminpb.DeleteRoleRequest{
+		Name: fmt.Sprintf("organizations/%s/roles/%s", *r.Organization, *r.Name),
+		Etag: r.Etag,
+	})
+	return err
+}
+
+func (r *OrganizationIAMRole) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *OrganizationIAMRole) String() string {
+	return *r.Name
+}
@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gotidy/ptr"
+
+	"google.golang.org/api/logging/v2"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const OrganizationLoggingSinkResource = "OrganizationLoggingSink"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     OrganizationLoggingSinkResource,
+		Scope:    nuke.Organization,
+		Resource: &OrganizationLoggingSink{},
+		Lister:   &OrganizationLoggingSinkLister{},
+	})
+}
+
+type OrganizationLoggingSinkLister struct {
+	svc *logging.Service
+}
+
+func (l *OrganizationLoggingSinkLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Global, "logging.googleapis.com", OrganizationLoggingSinkResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = logging.NewService(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.svc.Organizations.Sinks.
+		List(fmt.Sprintf("organizations/%s", *opts.Organization)).
+		Pages(ctx, func(page *logging.ListSinksResponse) error {
+			for _, sink := range page.Sinks {
+				resources = append(resources, &OrganizationLoggingSink{
+					svc:             l.svc,
+					Organization:    opts.Organization,
+					Name:            ptr.String(sink.Name),
+					Destination:     ptr.String(sink.Destination),
+					IncludeChildren: ptr.Bool(sink.IncludeChildren),
+					Disabled:        ptr.Bool(sink.Disabled),
+					CreateTime:      ptr.String(sink.CreateTime),
+					CreatedAt:       nuke.ParseCreatedAt(sink.CreateTime),
+				})
+			}
+			return nil
+		}); err != nil {
+		return nil, opts.ListError(OrganizationLoggingSinkResource, err)
+	}
+
+	return resources, nil
+}
+
+type OrganizationLoggingSink struct {
+	svc             *logging.Service
+	Organization    *string    `description:"The ID of the organization the sink belongs to"`
+	Name            *string    `description:"The name of the sink"`
+	Destination     *string    `description:"The export destination of the sink"`
+	IncludeChildren *bool      `description:"Whether logs from child folders and projects are exported"`
+	Disabled        *bool      `description:"Whether the sink is disabled"`
+	CreateTime      *string    `description:"The time the sink was created"`
+	CreatedAt       *time.Time `description:"The time the sink was created"`
+}
+
+func (r *OrganizationLoggingSink) Filter() error {
+	// Note: _Required and _Default are created by Google for every organization, _Required cannot be deleted
+	if strings.HasPrefix(*r.Name, "_") {
+		return fmt.Errorf("cannot delete built-in sink")
+	}
+
+	return nil
+}
+
+func (r *OrganizationLoggingSink) Remove(ctx context.Context) error {
+	_, err := r.svc.Organizations.Sinks.
+		Delete(fmt.Sprintf("organizations/%s/sinks/%s", *r.Organization, *r.Name)).
+		Context(ctx).Do()
+	return err
+}
+
+func (r *OrganizationLoggingSink) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *OrganizationLoggingSink) String() string {
+	return *r.Name
+}
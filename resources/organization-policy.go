@@ -0,0 +1,92 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+
+	"google.golang.org/api/orgpolicy/v2"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const OrganizationPolicyResource = "OrganizationPolicy"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     OrganizationPolicyResource,
+		Scope:    nuke.Organization,
+		Resource: &OrganizationPolicy{},
+		Lister:   &OrganizationPolicyLister{},
+	})
+}
+
+type OrganizationPolicyLister struct {
+	svc *orgpolicy.Service
+}
+
+func (l *OrganizationPolicyLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Global, "orgpolicy.googleapis.com", OrganizationPolicyResource); err != nil {
+		return resources, err
+	}
+
+	if l.svc == nil {
+		var err error
+		l.svc, err = orgpolicy.NewService(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.svc.Organizations.Policies.
+		List(fmt.Sprintf("organizations/%s", *opts.Organization)).
+		Pages(ctx, func(page *orgpolicy.GoogleCloudOrgpolicyV2ListPoliciesResponse) error {
+			for _, policy := range page.Policies {
+				nameParts := strings.Split(policy.Name, "/")
+				name := nameParts[len(nameParts)-1]
+
+				resources = append(resources, &OrganizationPolicy{
+					svc:          l.svc,
+					fullName:     ptr.String(policy.Name),
+					Organization: opts.Organization,
+					Name:         ptr.String(name),
+					DryRun:       ptr.Bool(policy.DryRunSpec != nil && policy.Spec == nil),
+				})
+			}
+			return nil
+		}); err != nil {
+		return nil, opts.ListError(OrganizationPolicyResource, err)
+	}
+
+	return resources, nil
+}
+
+type OrganizationPolicy struct {
+	svc          *orgpolicy.Service
+	fullName     *string
+	Organization *string `description:"The ID of the organization the policy is set on"`
+	Name         *string `description:"The name of the constraint the policy is configuring"`
+	DryRun       *bool   `description:"Whether the policy only has a dry run spec set"`
+}
+
+func (r *OrganizationPolicy) Remove(ctx context.Context) error {
+	_, err := r.svc.Organizations.Policies.Delete(*r.fullName).Context(ctx).Do()
+	return err
+}
+
+func (r *OrganizationPolicy) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *OrganizationPolicy) String() string {
+	return *r.Name
+}
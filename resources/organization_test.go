@@ -0,0 +1,141 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cloud.google.com/go/iam/admin/apiv1/adminpb"
+
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+func TestOrganizationListers(t *testing.T) {
+	runListerTests(t, []listerTest{
+		{
+			name:         "iam-policy-binding",
+			lister:       &OrganizationIAMPolicyBindingLister{},
+			region:       "global",
+			organization: true,
+			setup: func(fake *testutil.Server) {
+				fake.SetIAMPolicy("organizations/"+testOrganization, testutil.Item{
+					"bindings": []testutil.Item{
+						{
+							"role": "roles/viewer",
+							"members": []string{
+								"user:someone@example.com",
+								"serviceAccount:service-123@gcp-sa-logging.iam.gserviceaccount.com",
+							},
+						},
+					},
+				})
+			},
+			want: []string{
+				"user:someone@example.com -> roles/viewer",
+				"serviceAccount:service-123@gcp-sa-logging.iam.gserviceaccount.com -> roles/viewer",
+			},
+			filtered: []string{
+				"serviceAccount:service-123@gcp-sa-logging.iam.gserviceaccount.com -> roles/viewer",
+			},
+			removed: []string{"POST /v3/organizations/" + testOrganization + ":setIamPolicy"},
+		},
+		{
+			name:         "logging-sink",
+			lister:       &OrganizationLoggingSinkLister{},
+			region:       "global",
+			organization: true,
+			setup: func(fake *testutil.Server) {
+				fake.Add("/v2/organizations/"+testOrganization+"/sinks", "sinks",
+					testutil.Item{"name": "_Required"},
+					testutil.Item{"name": "audit-export"})
+			},
+			want:     []string{"_Required", "audit-export"},
+			filtered: []string{"_Required"},
+			removed:  []string{"DELETE /v2/organizations/" + testOrganization + "/sinks/audit-export"},
+		},
+		{
+			name:         "policy",
+			lister:       &OrganizationPolicyLister{},
+			region:       "global",
+			organization: true,
+			setup: func(fake *testutil.Server) {
+				fake.Add("/v2/organizations/"+testOrganization+"/policies", "policies",
+					testutil.Item{"name": "organizations/" + testOrganization + "/policies/compute.vmExternalIpAccess"})
+			},
+			want: []string{"compute.vmExternalIpAccess"},
+			removed: []string{
+				"DELETE /v2/organizations/" + testOrganization + "/policies/compute.vmExternalIpAccess",
+			},
+		},
+		{
+			name:         "tag-key",
+			lister:       &ResourceManagerTagKeyLister{},
+			region:       "global",
+			organization: true,
+			setup: func(fake *testutil.Server) {
+				fake.AddTagKeys(
+					testutil.Item{
+						"name":           "tagKeys/1",
+						"parent":         "organizations/" + testOrganization,
+						"namespacedName": testOrganization + "/env",
+					},
+					testutil.Item{
+						"name":           "tagKeys/2",
+						"parent":         "organizations/999",
+						"namespacedName": "999/env",
+					})
+			},
+			want:    []string{testOrganization + "/env"},
+			removed: []string{"DELETE /v3/tagKeys/1"},
+		},
+		{
+			name:         "tag-value",
+			lister:       &ResourceManagerTagValueLister{},
+			region:       "global",
+			organization: true,
+			setup: func(fake *testutil.Server) {
+				fake.AddTagKeys(testutil.Item{
+					"name":           "tagKeys/1",
+					"parent":         "organizations/" + testOrganization,
+					"namespacedName": testOrganization + "/env",
+				})
+				fake.AddTagValues(testutil.Item{
+					"name":           "tagValues/10",
+					"parent":         "tagKeys/1",
+					"namespacedName": testOrganization + "/env/dev",
+				})
+			},
+			want:    []string{testOrganization + "/env/dev"},
+			removed: []string{"DELETE /v3/tagValues/10"},
+		},
+		{
+			name:         "organization-resource-skipped-in-region",
+			lister:       &OrganizationPolicyLister{},
+			region:       testRegion,
+			skipped:      true,
+			organization: true,
+		},
+	})
+}
+
+func TestOrganizationIAMRoleLister(t *testing.T) {
+	fake := testutil.NewIAMServer(t)
+	fake.AddRoles(
+		&adminpb.Role{Name: "organizations/" + testOrganization + "/roles/custom"},
+		&adminpb.Role{Name: "organizations/" + testOrganization + "/roles/deleted", Deleted: true},
+		&adminpb.Role{Name: "projects/test-project/roles/project-role"},
+	)
+
+	opts := newTestListerOpts("global", fake.ClientOptions())
+	opts.Project = nil
+	opts.Organization = ptr.String(testOrganization)
+
+	resources := listResources(t, &OrganizationIAMRoleLister{}, opts, []string{"custom", "deleted"})
+	filtered := removeResources(t, resources, nil)
+
+	assert.Equal(t, []string{"deleted"}, filtered)
+	require.Len(t, fake.Deleted(), 1)
+	assert.Equal(t, "organizations/"+testOrganization+"/roles/custom", fake.Deleted()[0])
+}
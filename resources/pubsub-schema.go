@@ -2,15 +2,13 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	pubsub "cloud.google.com/go/pubsub/v2/apiv1"
 	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
-	"google.golang.org/api/iterator"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -51,7 +49,7 @@ func (l *PubSubSchemaLister) List(ctx context.Context, o interface{}) ([]resourc
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = pubsub.NewSchemaClient(ctx, opts.ClientOptions...)
+		l.svc, err = pubsub.NewSchemaClient(ctx, opts.GRPCClientOptionsFor("pubsub.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,26 +59,24 @@ func (l *PubSubSchemaLister) List(ctx context.Context, o interface{}) ([]resourc
 		Parent: "projects/" + *opts.Project,
 	}
 
-	it := l.svc.ListSchemas(ctx, req)
-	for {
-		schema, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate pubsub schemas")
-			break
-		}
+	schemas, err := nuke.ListAll(ctx, opts, PubSubSchemaResource, func() nuke.Iterator[*pubsubpb.Schema] {
+		return l.svc.ListSchemas(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, schema := range schemas {
 		nameParts := strings.Split(schema.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &PubSubSchema{
-			svc:      l.svc,
-			Project:  opts.Project,
-			FullName: ptr.String(schema.Name),
-			Name:     ptr.String(name),
-			Type:     ptr.String(schema.Type.String()),
+			svc:       l.svc,
+			Project:   opts.Project,
+			FullName:  ptr.String(schema.Name),
+			Name:      ptr.String(name),
+			Type:      ptr.String(schema.Type.String()),
+			CreatedAt: nuke.CreatedAtFromProto(schema.RevisionCreateTime),
 		})
 	}
 
@@ -88,11 +84,12 @@ func (l *PubSubSchemaLister) List(ctx context.Context, o interface{}) ([]resourc
 }
 
 type PubSubSchema struct {
-	svc      *pubsub.SchemaClient
-	Project  *string
-	FullName *string
-	Name     *string `description:"The name of the Pub/Sub schema"`
-	Type     *string `description:"The type of the schema (AVRO, PROTOCOL_BUFFER)"`
+	svc       *pubsub.SchemaClient
+	Project   *string
+	FullName  *string
+	Name      *string    `description:"The name of the Pub/Sub schema"`
+	Type      *string    `description:"The type of the schema (AVRO, PROTOCOL_BUFFER)"`
+	CreatedAt *time.Time `description:"The time the schema revision was created"`
 }
 
 func (r *PubSubSchema) Remove(ctx context.Context) error {
@@ -2,15 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	"cloud.google.com/go/pubsub/v2"
 	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -50,7 +49,7 @@ func (l *PubSubSubscriptionLister) List(ctx context.Context, o interface{}) ([]r
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = pubsub.NewClient(ctx, *opts.Project, opts.ClientOptions...)
+		l.svc, err = pubsub.NewClient(ctx, *opts.Project, opts.GRPCClientOptionsFor("pubsub.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -60,17 +59,14 @@ func (l *PubSubSubscriptionLister) List(ctx context.Context, o interface{}) ([]r
 		Project: "projects/" + *opts.Project,
 	}
 
-	it := l.svc.SubscriptionAdminClient.ListSubscriptions(ctx, req)
-	for {
-		sub, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate pubsub subscriptions")
-			break
-		}
+	subs, err := nuke.ListAll(ctx, opts, PubSubSubscriptionResource, func() nuke.Iterator[*pubsubpb.Subscription] {
+		return l.svc.SubscriptionAdminClient.ListSubscriptions(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, sub := range subs {
 		nameParts := strings.Split(sub.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -108,6 +104,18 @@ func (r *PubSubSubscription) Remove(ctx context.Context) error {
 	})
 }
 
+// Mark adds the mark to the labels of the subscription, see nuke.Marker
+func (r *PubSubSubscription) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.SubscriptionAdminClient.UpdateSubscription(ctx, &pubsubpb.UpdateSubscriptionRequest{
+		Subscription: &pubsubpb.Subscription{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
+
 func (r *PubSubSubscription) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -2,15 +2,14 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	"cloud.google.com/go/pubsub/v2"
 	"cloud.google.com/go/pubsub/v2/apiv1/pubsubpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -51,7 +50,7 @@ func (l *PubSubTopicLister) List(ctx context.Context, o interface{}) ([]resource
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = pubsub.NewClient(ctx, *opts.Project, opts.ClientOptions...)
+		l.svc, err = pubsub.NewClient(ctx, *opts.Project, opts.GRPCClientOptionsFor("pubsub.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,17 +60,14 @@ func (l *PubSubTopicLister) List(ctx context.Context, o interface{}) ([]resource
 		Project: "projects/" + *opts.Project,
 	}
 
-	it := l.svc.TopicAdminClient.ListTopics(ctx, req)
-	for {
-		topic, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate pubsub topics")
-			break
-		}
+	topics, err := nuke.ListAll(ctx, opts, PubSubTopicResource, func() nuke.Iterator[*pubsubpb.Topic] {
+		return l.svc.TopicAdminClient.ListTopics(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, topic := range topics {
 		nameParts := strings.Split(topic.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -101,6 +97,18 @@ func (r *PubSubTopic) Remove(ctx context.Context) error {
 	})
 }
 
+// Mark adds the mark to the labels of the topic, see nuke.Marker
+func (r *PubSubTopic) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.TopicAdminClient.UpdateTopic(ctx, &pubsubpb.UpdateTopicRequest{
+		Topic: &pubsubpb.Topic{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
+
 func (r *PubSubTopic) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
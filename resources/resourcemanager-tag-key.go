@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gotidy/ptr"
+
+	"google.golang.org/api/cloudresourcemanager/v3"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const ResourceManagerTagKeyResource = "ResourceManagerTagKey"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:      ResourceManagerTagKeyResource,
+		Scope:     nuke.Organization,
+		Resource:  &ResourceManagerTagKey{},
+		Lister:    &ResourceManagerTagKeyLister{},
+		DependsOn: []string{ResourceManagerTagValueResource},
+	})
+}
+
+type ResourceManagerTagKeyLister struct {
+	svc *cloudresourcemanager.Service
+}
+
+// ListTagKeys returns all the tag keys that are parented by the organization, they are listed once per run. The
+// resourceType is the one of the calling lister.
+func (l *ResourceManagerTagKeyLister) ListTagKeys(
+	ctx context.Context, opts *nuke.ListerOpts, resourceType string,
+) ([]*cloudresourcemanager.TagKey, error) {
+	if l.svc == nil {
+		var err error
+		l.svc, err = cloudresourcemanager.NewService(ctx, opts.ClientOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nuke.Cached(ctx, opts, resourceType, "cloudresourcemanager.googleapis.com", "tagKeys",
+		func() ([]*cloudresourcemanager.TagKey, error) {
+			var tagKeys []*cloudresourcemanager.TagKey
+			if err := l.svc.TagKeys.List().
+				Parent(fmt.Sprintf("organizations/%s", *opts.Organization)).
+				Pages(ctx, func(page *cloudresourcemanager.ListTagKeysResponse) error {
+					tagKeys = append(tagKeys, page.TagKeys...)
+					return nil
+				}); err != nil {
+				return nil, err
+			}
+
+			return tagKeys, nil
+		})
+}
+
+func (l *ResourceManagerTagKeyLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Global, "cloudresourcemanager.googleapis.com",
+		ResourceManagerTagKeyResource); err != nil {
+		return resources, err
+	}
+
+	tagKeys, err := l.ListTagKeys(ctx, opts, ResourceManagerTagKeyResource)
+	if err != nil {
+		return nil, opts.ListError(ResourceManagerTagKeyResource, err)
+	}
+
+	for _, tagKey := range tagKeys {
+		resources = append(resources, &ResourceManagerTagKey{
+			svc:            l.svc,
+			fullName:       ptr.String(tagKey.Name),
+			Organization:   opts.Organization,
+			ShortName:      ptr.String(tagKey.ShortName),
+			NamespacedName: ptr.String(tagKey.NamespacedName),
+			Purpose:        ptr.String(tagKey.Purpose),
+			CreateTime:     ptr.String(tagKey.CreateTime),
+			CreatedAt:      nuke.ParseCreatedAt(tagKey.CreateTime),
+		})
+	}
+
+	return resources, nil
+}
+
+type ResourceManagerTagKey struct {
+	svc            *cloudresourcemanager.Service
+	fullName       *string
+	Organization   *string    `description:"The ID of the organization the tag key belongs to"`
+	ShortName      *string    `description:"The short name of the tag key"`
+	NamespacedName *string    `description:"The namespaced name of the tag key, i.e. 123456/environment"`
+	Purpose        *string    `description:"The purpose of the tag key, i.e. GCE_FIREWALL"`
+	CreateTime     *string    `description:"The time the tag key was created"`
+	CreatedAt      *time.Time `description:"The time the tag key was created"`
+}
+
+func (r *ResourceManagerTagKey) Remove(ctx context.Context) error {
+	_, err := r.svc.TagKeys.Delete(*r.fullName).Context(ctx).Do()
+	return err
+}
+
+func (r *ResourceManagerTagKey) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ResourceManagerTagKey) String() string {
+	return *r.NamespacedName
+}
@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/gotidy/ptr"
+
+	"google.golang.org/api/cloudresourcemanager/v3"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+)
+
+const ResourceManagerTagValueResource = "ResourceManagerTagValue"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ResourceManagerTagValueResource,
+		Scope:    nuke.Organization,
+		Resource: &ResourceManagerTagValue{},
+		Lister:   &ResourceManagerTagValueLister{},
+	})
+}
+
+type ResourceManagerTagValueLister struct {
+	svc *cloudresourcemanager.Service
+}
+
+func (l *ResourceManagerTagValueLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	var resources []resource.Resource
+
+	opts := o.(*nuke.ListerOpts)
+	if err := opts.BeforeList(nuke.Global, "cloudresourcemanager.googleapis.com",
+		ResourceManagerTagValueResource); err != nil {
+		return resources, err
+	}
+
+	keyLister := &ResourceManagerTagKeyLister{}
+	tagKeys, err := keyLister.ListTagKeys(ctx, opts, ResourceManagerTagValueResource)
+	if err != nil {
+		return nil, opts.ListError(ResourceManagerTagValueResource, err)
+	}
+
+	l.svc = keyLister.svc
+
+	for _, tagKey := range tagKeys {
+		if err := l.svc.TagValues.List().
+			Parent(tagKey.Name).
+			Pages(ctx, func(page *cloudresourcemanager.ListTagValuesResponse) error {
+				for _, tagValue := range page.TagValues {
+					resources = append(resources, &ResourceManagerTagValue{
+						svc:            l.svc,
+						fullName:       ptr.String(tagValue.Name),
+						Organization:   opts.Organization,
+						TagKey:         ptr.String(tagKey.ShortName),
+						ShortName:      ptr.String(tagValue.ShortName),
+						NamespacedName: ptr.String(tagValue.NamespacedName),
+						CreateTime:     ptr.String(tagValue.CreateTime),
+						CreatedAt:      nuke.ParseCreatedAt(tagValue.CreateTime),
+					})
+				}
+				return nil
+			}); err != nil {
+			return nil, opts.ListError(ResourceManagerTagValueResource, err)
+		}
+	}
+
+	return resources, nil
+}
+
+type ResourceManagerTagValue struct {
+	svc            *cloudresourcemanager.Service
+	fullName       *string
+	Organization   *string    `description:"The ID of the organization the tag value belongs to"`
+	TagKey         *string    `description:"The short name of the parent tag key"`
+	ShortName      *string    `description:"The short name of the tag value"`
+	NamespacedName *string    `description:"The namespaced name of the tag value, i.e. 123456/environment/production"`
+	CreateTime     *string    `description:"The time the tag value was created"`
+	CreatedAt      *time.Time `description:"The time the tag value was created"`
+}
+
+func (r *ResourceManagerTagValue) Remove(ctx context.Context) error {
+	_, err := r.svc.TagValues.Delete(*r.fullName).Context(ctx).Do()
+	return err
+}
+
+func (r *ResourceManagerTagValue) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ResourceManagerTagValue) String() string {
+	return *r.NamespacedName
+}
@@ -0,0 +1,267 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/api/option"
+
+	liberror "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/gcp-nuke/pkg/graph"
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+const (
+	testProject      = "test-project"
+	testOrganization = "123456789012"
+	testRegion       = "us-east1"
+	testZone         = "us-east1-b"
+)
+
+// testEnabledAPIs are all the APIs the listers under test check for in BeforeList
+var testEnabledAPIs = []string{
+	"cloudkms.googleapis.com",
+	"cloudresourcemanager.googleapis.com",
+	"compute.googleapis.com",
+	"iam.googleapis.com",
+	"storage.googleapis.com",
+}
+
+// listerTest is a single case of a table-driven lister test that runs against the fake REST server from testutil.
+// The resources returned by the lister are filtered and removed like libnuke would, waiting on the ones that implement
+// HandleWait.
+type listerTest struct {
+	name   string
+	lister registry.Lister
+	region string
+
+	// organization makes the lister run for the organization instead of the project
+	organization bool
+
+	// skipped is true when the lister is expected to skip the request, e.g. a global resource in a region
+	skipped bool
+
+	// setup seeds the fake server with the resources the lister should find
+	setup func(fake *testutil.Server)
+
+	// clientOptions overrides the client options used to talk to the fake server
+	clientOptions func(fake *testutil.Server) []option.ClientOption
+
+	// settings are given to the resources that implement resource.SettingsGetter
+	settings settings.Setting
+
+	// want is the String() of every resource the lister is expected to return
+	want []string
+
+	// filtered is the String() of every resource that is expected to be filtered
+	filtered []string
+
+	// removed are the requests, in the form "METHOD /path", the fake server is expected to receive on removal
+	removed []string
+
+	// verify makes additional assertions on the state of the fake server after the removal
+	verify func(t *testing.T, fake *testutil.Server)
+}
+
+func runListerTests(t *testing.T, tests []listerTest) {
+	t.Helper()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := testutil.NewServer(t)
+			if tc.setup != nil {
+				tc.setup(fake)
+			}
+
+			clientOptions := fake.ClientOptions()
+			if tc.clientOptions != nil {
+				clientOptions = tc.clientOptions(fake)
+			}
+
+			opts := newTestListerOpts(tc.region, clientOptions)
+			if tc.organization {
+				opts.Project = nil
+				opts.Organization = ptr.String(testOrganization)
+			}
+
+			if tc.skipped {
+				_, err := tc.lister.List(context.TODO(), opts)
+				var skipErr liberror.ErrSkipRequest
+				assert.ErrorAs(t, err, &skipErr)
+				return
+			}
+
+			filtered := removeResources(t, listResources(t, tc.lister, opts, tc.want), tc.settings)
+			assert.ElementsMatch(t, tc.filtered, filtered)
+
+			for _, req := range tc.removed {
+				method, requestPath, _ := strings.Cut(req, " ")
+				assert.Truef(t, fake.HasRequest(method, requestPath), "expected request %q, got %v", req, fake.Requests())
+			}
+
+			if tc.verify != nil {
+				tc.verify(t, fake)
+			}
+		})
+	}
+}
+
+func newTestListerOpts(region string, clientOptions []option.ClientOption) *nuke.ListerOpts {
+	if region == "" {
+		region = "global"
+	}
+
+	return &nuke.ListerOpts{
+		Project:       ptr.String(testProject),
+		Region:        ptr.String(region),
+		Zones:         []string{testZone},
+		EnabledAPIs:   testEnabledAPIs,
+		ClientOptions: clientOptions,
+
+		GRPCClientOptions: clientOptions,
+	}
+}
+
+// listResources runs the lister and checks that it returns the wanted resources
+func listResources(
+	t *testing.T, lister registry.Lister, opts *nuke.ListerOpts, want []string,
+) []resource.Resource {
+	t.Helper()
+
+	if lc, ok := lister.(registry.ListerWithClose); ok {
+		t.Cleanup(lc.Close)
+	}
+
+	resources, err := lister.List(context.TODO(), opts)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.(resource.LegacyStringer).String())
+	}
+
+	if want == nil {
+		want = []string{}
+	}
+	assert.ElementsMatch(t, want, names)
+
+	return resources
+}
+
+// removeResources filters and removes the resources, it returns the String() of the filtered resources
+func removeResources(t *testing.T, resources []resource.Resource, setting settings.Setting) []string {
+	t.Helper()
+
+	ctx := context.TODO()
+
+	filtered := make([]string, 0)
+	for _, r := range resources {
+		if s, ok := r.(resource.SettingsGetter); ok {
+			s.Settings(&setting)
+		}
+
+		if f, ok := r.(resource.Filter); ok {
+			if err := f.Filter(); err != nil {
+				filtered = append(filtered, r.(resource.LegacyStringer).String())
+				continue
+			}
+		}
+
+		require.NoError(t, r.Remove(ctx))
+
+		if w, ok := r.(resource.HandleWaitHook); ok {
+			require.NoError(t, waitForResource(ctx, w))
+		}
+	}
+
+	return filtered
+}
+
+// waitForResource calls HandleWait until the resource is no longer waiting, with a bound on the number of attempts
+func waitForResource(ctx context.Context, w resource.HandleWaitHook) error {
+	var err error
+	for range 5 {
+		err = w.HandleWait(ctx)
+
+		var waitErr liberror.ErrWaitResource
+		if !errors.As(err, &waitErr) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func TestListerErrors(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.SetError("GET", "/storage/v1/b", http.StatusForbidden, "permission denied")
+
+	opts := newTestListerOpts("global", fake.StorageClientOptions())
+	opts.ListingErrors = &nuke.ListingErrors{}
+
+	lister := &StorageBucketObjectLister{}
+	t.Cleanup(lister.Close)
+
+	resources, err := lister.List(context.TODO(), opts)
+	assert.Nil(t, resources)
+
+	var listErr *nuke.ListError
+	require.ErrorAs(t, err, &listErr)
+	assert.Equal(t, StorageBucketObjectResource, listErr.ResourceType)
+	assert.Equal(t, "global", listErr.Owner)
+	assert.Equal(t, nuke.ListErrorPermissionDenied, listErr.Kind)
+	assert.Equal(t, []*nuke.ListError{listErr}, opts.ListingErrors.Errors())
+}
+
+func TestListingCache(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddBuckets(
+		testutil.Item{"name": "east-bucket", "location": "US-EAST1"},
+		testutil.Item{"name": "west-bucket", "location": "US-WEST1"},
+	)
+
+	cache := nuke.NewListingCache()
+
+	lister := &StorageBucketLister{}
+	t.Cleanup(lister.Close)
+
+	for region, want := range map[string][]string{"us-east1": {"east-bucket"}, "us-west1": {"west-bucket"}} {
+		opts := newTestListerOpts(region, fake.StorageClientOptions())
+		opts.Cache = cache
+
+		listResources(t, lister, opts, want)
+	}
+
+	listed := 0
+	for _, r := range fake.Requests() {
+		if r.Method == "GET" && r.Path == "/storage/v1/b" {
+			listed++
+		}
+	}
+
+	assert.Equal(t, 1, listed)
+	assert.Equal(t, 1, cache.Stats().Hits)
+}
+
+// TestDependencyGraph checks that the DependsOn of the registrations only reference registered resource types and
+// have no cycle, otherwise the removal of the resource types of the cycle is retried until it fails
+func TestDependencyGraph(t *testing.T) {
+	g := graph.FromRegistry()
+
+	assert.Empty(t, g.Validate())
+
+	_, err := g.Order()
+	assert.NoError(t, err)
+}
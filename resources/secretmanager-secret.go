@@ -2,19 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/gotidy/ptr"
 
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
-
 	secretmanager "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -63,17 +59,14 @@ func (l *SecretManagerSecretLister) List(ctx context.Context, o interface{}) ([]
 	req := &secretmanagerpb.ListSecretsRequest{
 		Parent: fmt.Sprintf("projects/%s", *opts.Project),
 	}
-	it := l.svc.ListSecrets(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, SecretManagerSecretResource, func() nuke.Iterator[*secretmanagerpb.Secret] {
+		return l.svc.ListSecrets(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		nameParts := strings.Split(resp.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -84,6 +77,7 @@ func (l *SecretManagerSecretLister) List(ctx context.Context, o interface{}) ([]
 			project:    opts.Project,
 			CreateTime: resp.CreateTime.AsTime(),
 			Labels:     resp.Labels,
+			CreatedAt:  nuke.CreatedAtFromProto(resp.CreateTime),
 		})
 	}
 
@@ -96,6 +90,7 @@ type SecretManagerSecret struct {
 	fullName   *string
 	Name       *string
 	CreateTime time.Time
+	CreatedAt  *time.Time
 	Labels     map[string]string `property:"tagPrefix=label"`
 }
 
@@ -112,3 +107,15 @@ func (r *SecretManagerSecret) Properties() types.Properties {
 func (r *SecretManagerSecret) String() string {
 	return *r.Name
 }
+
+// Mark adds the mark to the labels of the secret, see nuke.Marker
+func (r *SecretManagerSecret) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.UpdateSecret(ctx, &secretmanagerpb.UpdateSecretRequest{
+		Secret: &secretmanagerpb.Secret{
+			Name:   *r.fullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
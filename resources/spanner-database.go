@@ -2,20 +2,22 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	database "cloud.google.com/go/spanner/admin/database/apiv1"
 	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
 	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
 	"cloud.google.com/go/spanner/admin/instance/apiv1/instancepb"
-	"google.golang.org/api/iterator"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
@@ -29,6 +31,10 @@ func init() {
 		Scope:    nuke.Project,
 		Resource: &SpannerDatabase{},
 		Lister:   &SpannerDatabaseLister{},
+		Settings: []string{
+			nuke.BackupBeforeDeleteSetting,
+			nuke.BackupRetentionSetting,
+		},
 	})
 }
 
@@ -56,7 +62,7 @@ func (l *SpannerDatabaseLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = database.NewDatabaseAdminClient(ctx, opts.ClientOptions...)
+		l.svc, err = database.NewDatabaseAdminClient(ctx, opts.GRPCClientOptionsFor("spanner.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -64,7 +70,7 @@ func (l *SpannerDatabaseLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.instancesSvc == nil {
 		var err error
-		l.instancesSvc, err = instance.NewInstanceAdminClient(ctx, opts.ClientOptions...)
+		l.instancesSvc, err = instance.NewInstanceAdminClient(ctx, opts.GRPCClientOptionsFor("spanner.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -74,32 +80,26 @@ func (l *SpannerDatabaseLister) List(ctx context.Context, o interface{}) ([]reso
 		Parent: "projects/" + *opts.Project,
 	}
 
-	instanceIt := l.instancesSvc.ListInstances(ctx, instanceReq)
-	for {
-		inst, err := instanceIt.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate spanner instances")
-			break
-		}
+	insts, err := nuke.ListAll(ctx, opts, SpannerDatabaseResource, func() nuke.Iterator[*instancepb.Instance] {
+		return l.instancesSvc.ListInstances(ctx, instanceReq)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, inst := range insts {
 		dbReq := &databasepb.ListDatabasesRequest{
 			Parent: inst.Name,
 		}
 
-		dbIt := l.svc.ListDatabases(ctx, dbReq)
-		for {
-			db, err := dbIt.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate spanner databases")
-				break
-			}
+		dbs, err := nuke.ListAll(ctx, opts, SpannerDatabaseResource, func() nuke.Iterator[*databasepb.Database] {
+			return l.svc.ListDatabases(ctx, dbReq)
+		})
+		if err != nil {
+			return nil, err
+		}
 
+		for _, db := range dbs {
 			nameParts := strings.Split(db.Name, "/")
 			name := nameParts[len(nameParts)-1]
 
@@ -107,12 +107,16 @@ func (l *SpannerDatabaseLister) List(ctx context.Context, o interface{}) ([]reso
 			instanceName := instanceParts[len(instanceParts)-1]
 
 			resources = append(resources, &SpannerDatabase{
-				svc:      l.svc,
-				Project:  opts.Project,
-				FullName: ptr.String(db.Name),
-				Name:     ptr.String(name),
-				Instance: ptr.String(instanceName),
-				State:    ptr.String(db.State.String()),
+				svc:          l.svc,
+				instanceName: inst.Name,
+				Project:      opts.Project,
+				FullName:     ptr.String(db.Name),
+				Name:         ptr.String(name),
+				Instance:     ptr.String(instanceName),
+				State:        ptr.String(db.State.String()),
+				CreatedAt:    nuke.CreatedAtFromProto(db.CreateTime),
+
+				waitTimeout: opts.WaitTimeout,
 			})
 		}
 	}
@@ -121,15 +125,28 @@ func (l *SpannerDatabaseLister) List(ctx context.Context, o interface{}) ([]reso
 }
 
 type SpannerDatabase struct {
-	svc      *database.DatabaseAdminClient
-	Project  *string
-	FullName *string
-	Name     *string `description:"The name of the Spanner database"`
-	Instance *string `description:"The instance this database belongs to"`
-	State    *string `description:"The current state of the database"`
+	svc          *database.DatabaseAdminClient
+	waitTimeout  time.Duration
+	settings     *settings.Setting
+	backedUp     bool
+	instanceName string
+	Project      *string
+	FullName     *string
+	Name         *string    `description:"The name of the Spanner database"`
+	Instance     *string    `description:"The instance this database belongs to"`
+	State        *string    `description:"The current state of the database"`
+	CreatedAt    *time.Time `description:"The time the database was created"`
+}
+
+func (r *SpannerDatabase) Settings(setting *settings.Setting) {
+	r.settings = setting
 }
 
 func (r *SpannerDatabase) Remove(ctx context.Context) error {
+	if err := r.backup(ctx); err != nil {
+		return err
+	}
+
 	return r.svc.DropDatabase(ctx, &databasepb.DropDatabaseRequest{
 		Database: *r.FullName,
 	})
@@ -142,3 +159,36 @@ func (r *SpannerDatabase) Properties() types.Properties {
 func (r *SpannerDatabase) String() string {
 	return *r.Name
 }
+
+// backup creates a backup of the database, when the BackupBeforeDelete setting is enabled, and waits for it. Spanner
+// backups have no labels, the run ID is part of the name of the backup and Spanner removes it when it expires.
+func (r *SpannerDatabase) backup(ctx context.Context) error {
+	policy, err := nuke.NewBackupPolicy(r.settings)
+	if err != nil || !policy.Enabled || r.backedUp {
+		return err
+	}
+
+	backupID := nuke.BackupID(*r.Name, 60)
+	logrus.WithField("backup", backupID).Debug("creating backup before removing spanner database")
+
+	op, err := r.svc.CreateBackup(ctx, &databasepb.CreateBackupRequest{
+		Parent:   r.instanceName,
+		BackupId: backupID,
+		Backup: &databasepb.Backup{
+			Database: *r.FullName,
+			// Note: Spanner requires the expire time to be at least 6 hours and at most 366 days away
+			ExpireTime: timestamppb.New(policy.ExpireTimeWithin(6*time.Hour, 366*24*time.Hour)),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	if err := nuke.WaitOperation(ctx, r.waitTimeout, nuke.LROResultOperation[*databasepb.Backup](op)); err != nil {
+		return fmt.Errorf("unable to create backup: %w", err)
+	}
+
+	r.backedUp = true
+
+	return nil
+}
@@ -2,15 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
 	"cloud.google.com/go/spanner/admin/instance/apiv1/instancepb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -51,7 +51,7 @@ func (l *SpannerInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = instance.NewInstanceAdminClient(ctx, opts.ClientOptions...)
+		l.svc, err = instance.NewInstanceAdminClient(ctx, opts.GRPCClientOptionsFor("spanner.googleapis.com")...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,28 +61,28 @@ func (l *SpannerInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 		Parent: "projects/" + *opts.Project,
 	}
 
-	it := l.svc.ListInstances(ctx, req)
-	for {
-		inst, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate spanner instances")
-			break
-		}
+	insts, err := nuke.ListAll(ctx, opts, SpannerInstanceResource, func() nuke.Iterator[*instancepb.Instance] {
+		return l.svc.ListInstances(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, inst := range insts {
 		nameParts := strings.Split(inst.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
 		resources = append(resources, &SpannerInstance{
-			svc:       l.svc,
-			Project:   opts.Project,
-			FullName:  ptr.String(inst.Name),
-			Name:      ptr.String(name),
-			NodeCount: ptr.Int32(inst.NodeCount),
-			State:     ptr.String(inst.State.String()),
-			Labels:    inst.Labels,
+			svc:             l.svc,
+			Project:         opts.Project,
+			FullName:        ptr.String(inst.Name),
+			Name:            ptr.String(name),
+			NodeCount:       ptr.Int32(inst.NodeCount),
+			ProcessingUnits: ptr.Int32(inst.ProcessingUnits),
+			State:           ptr.String(inst.State.String()),
+			Labels:          inst.Labels,
+			CreatedAt:       nuke.CreatedAtFromProto(inst.CreateTime),
+			autoscaled:      inst.AutoscalingConfig != nil,
 		})
 	}
 
@@ -90,13 +90,24 @@ func (l *SpannerInstanceLister) List(ctx context.Context, o interface{}) ([]reso
 }
 
 type SpannerInstance struct {
-	svc       *instance.InstanceAdminClient
-	Project   *string
-	FullName  *string
-	Name      *string           `description:"The name of the Spanner instance"`
-	State     *string           `description:"The current state of the instance"`
-	NodeCount *int32            `description:"The number of nodes in the instance"`
-	Labels    map[string]string `property:"tagPrefix=label" description:"Labels associated with the instance"`
+	svc             *instance.InstanceAdminClient
+	autoscaled      bool
+	Project         *string
+	FullName        *string
+	Name            *string           `description:"The name of the Spanner instance"`
+	State           *string           `description:"The current state of the instance"`
+	NodeCount       *int32            `description:"The number of nodes in the instance"`
+	ProcessingUnits *int32            `description:"The number of processing units of the instance"`
+	CreatedAt       *time.Time        `description:"The time the instance was created"`
+	Labels          map[string]string `property:"tagPrefix=label" description:"Labels associated with the instance"`
+}
+
+// spannerMinProcessingUnits is the smallest compute capacity of an instance, a suspended instance is scaled down to it
+const spannerMinProcessingUnits = 100
+
+// spannerInstanceState is the state of a suspended instance, see nuke.Suspender
+type spannerInstanceState struct {
+	ProcessingUnits int32 `json:"processingUnits"`
 }
 
 func (r *SpannerInstance) Remove(ctx context.Context) error {
@@ -105,6 +116,53 @@ func (r *SpannerInstance) Remove(ctx context.Context) error {
 	})
 }
 
+// Suspend scales the instance down to the minimum compute capacity, an instance cannot have less. The capacity of an
+// autoscaled instance is managed by its autoscaler. See nuke.Suspender.
+func (r *SpannerInstance) Suspend(ctx context.Context) (nuke.SuspendedState, error) {
+	if r.autoscaled {
+		return nil, nuke.NotSuspended("the compute capacity of the instance is managed by its autoscaler")
+	}
+
+	processingUnits := ptr.ToInt32(r.ProcessingUnits)
+	if processingUnits <= spannerMinProcessingUnits {
+		return nil, nuke.NotSuspended(fmt.Sprintf("the instance already has the minimum of %d processing units",
+			spannerMinProcessingUnits))
+	}
+
+	if err := r.setProcessingUnits(ctx, spannerMinProcessingUnits); err != nil {
+		return nil, err
+	}
+
+	return nuke.NewSuspendedState(&spannerInstanceState{ProcessingUnits: processingUnits})
+}
+
+// Resume scales the instance back to its compute capacity, see nuke.Suspender
+func (r *SpannerInstance) Resume(ctx context.Context, state nuke.SuspendedState) error {
+	suspended := &spannerInstanceState{}
+	if err := state.Decode(suspended); err != nil {
+		return err
+	}
+
+	return r.setProcessingUnits(ctx, suspended.ProcessingUnits)
+}
+
+// setProcessingUnits sets the compute capacity of the instance and waits for the operation
+func (r *SpannerInstance) setProcessingUnits(ctx context.Context, processingUnits int32) error {
+	op, err := r.svc.UpdateInstance(ctx, &instancepb.UpdateInstanceRequest{
+		Instance: &instancepb.Instance{
+			Name:            *r.FullName,
+			ProcessingUnits: processingUnits,
+		},
+		FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"processing_units"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = op.Wait(ctx)
+	return err
+}
+
 func (r *SpannerInstance) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -112,3 +170,20 @@ func (r *SpannerInstance) Properties() types.Properties {
 func (r *SpannerInstance) String() string {
 	return *r.Name
 }
+
+// Mark adds the mark to the labels of the instance, see nuke.Marker
+func (r *SpannerInstance) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.UpdateInstance(ctx, &instancepb.UpdateInstanceRequest{
+		Instance: &instancepb.Instance{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		FieldMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = op.Wait(ctx)
+	return err
+}
@@ -2,14 +2,10 @@ package resources
 
 import (
 	"context"
-	"errors"
+	"time"
 
 	"github.com/gotidy/ptr"
 
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
-
 	"cloud.google.com/go/storage"
 
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -57,25 +53,22 @@ func (l *StorageBucketObjectLister) List(ctx context.Context, o interface{}) ([]
 	}
 
 	bucketLister := &StorageBucketLister{}
-	buckets, err := bucketLister.ListBuckets(ctx, opts)
+	buckets, err := bucketLister.ListBuckets(ctx, opts, StorageBucketObjectResource)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, bucket := range buckets {
-		it := l.svc.Bucket(bucket.Name).Objects(ctx, &storage.Query{
-			Versions: true,
+		objects, err := nuke.ListAll(ctx, opts, StorageBucketObjectResource, func() nuke.Iterator[*storage.ObjectAttrs] {
+			return l.svc.Bucket(bucket.Name).Objects(ctx, &storage.Query{
+				Versions: true,
+			})
 		})
-		for {
-			resp, err := it.Next()
-			if errors.Is(err, iterator.Done) {
-				break
-			}
-			if err != nil {
-				logrus.WithError(err).Error("unable to iterate objects")
-				break
-			}
+		if err != nil {
+			return nil, err
+		}
 
+		for _, resp := range objects {
 			resources = append(resources, &StorageBucketObject{
 				svc:        l.svc,
 				Name:       ptr.String(resp.Name),
@@ -83,6 +76,7 @@ func (l *StorageBucketObjectLister) List(ctx context.Context, o interface{}) ([]
 				Project:    opts.Project,
 				Generation: ptr.Int64(resp.Generation),
 				Metadata:   resp.Metadata,
+				CreatedAt:  nuke.CreatedAtFromTime(resp.Created),
 			})
 		}
 	}
@@ -98,6 +92,7 @@ type StorageBucketObject struct {
 	Bucket     *string
 	Generation *int64
 	Metadata   map[string]string
+	CreatedAt  *time.Time
 }
 
 func (r *StorageBucketObject) Remove(ctx context.Context) error {
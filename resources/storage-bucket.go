@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +20,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/gcp-nuke/pkg/gcputil"
 	"github.com/ekristen/gcp-nuke/pkg/nuke"
 )
 
@@ -31,9 +31,7 @@ func init() {
 		Name:     StorageBucketResource,
 		Scope:    nuke.Project,
 		Resource: &StorageBucket{},
-		Lister: &StorageBucketLister{
-			multiRegion: make(map[string]string),
-		},
+		Lister:   &StorageBucketLister{},
 		Settings: []string{
 			"DeleteGoogleManagedBuckets",
 			"DisableDeletionProtection",
@@ -42,8 +40,7 @@ func init() {
 }
 
 type StorageBucketLister struct {
-	svc         *storage.Client
-	multiRegion map[string]string
+	svc *storage.Client
 }
 
 func (l *StorageBucketLister) Close() {
@@ -52,7 +49,11 @@ func (l *StorageBucketLister) Close() {
 	}
 }
 
-func (l *StorageBucketLister) ListBuckets(ctx context.Context, opts *nuke.ListerOpts) ([]*storage.BucketAttrs, error) {
+// ListBuckets returns every bucket of the project, a listing error is reported for the resourceType of the calling
+// lister. The buckets are listed once per run and shared by the listers of every region.
+func (l *StorageBucketLister) ListBuckets(
+	ctx context.Context, opts *nuke.ListerOpts, resourceType string,
+) ([]*storage.BucketAttrs, error) {
 	if l.svc == nil {
 		var err error
 		l.svc, err = storage.NewClient(ctx, opts.ClientOptions...)
@@ -61,72 +62,48 @@ func (l *StorageBucketLister) ListBuckets(ctx context.Context, opts *nuke.Lister
 		}
 	}
 
-	var allBuckets []*storage.BucketAttrs
-
-	it := l.svc.Buckets(ctx, *opts.Project)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
-
-		allBuckets = append(allBuckets, resp)
-	}
-
-	return allBuckets, nil
+	return nuke.Cached(ctx, opts, resourceType, "storage.googleapis.com", "buckets",
+		func() ([]*storage.BucketAttrs, error) {
+			return nuke.ListAll(ctx, opts, resourceType, func() nuke.Iterator[*storage.BucketAttrs] {
+				return l.svc.Buckets(ctx, *opts.Project)
+			})
+		})
 }
 
 func (l *StorageBucketLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
 	var resources []resource.Resource
 
 	opts := o.(*nuke.ListerOpts)
-	if err := opts.BeforeList(nuke.Regional, "storage.googleapis.com", StorageBucketResource); err != nil {
+	if err := opts.BeforeList(nuke.MultiRegional, "storage.googleapis.com", StorageBucketResource); err != nil {
 		return resources, err
 	}
 
-	buckets, err := l.ListBuckets(ctx, opts)
+	buckets, err := l.ListBuckets(ctx, opts, StorageBucketResource)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, bucket := range buckets {
-		loc := strings.ToLower(bucket.Location)
+		// Note: multi-region and dual-region buckets are owned by their pseudo-region, so that they are listed once
+		region := gcputil.LocationRegion(bucket.Location)
 		logrus.WithFields(logrus.Fields{
 			"bucket":   bucket.Name,
-			"location": loc,
+			"location": bucket.Location,
 			"region":   *opts.Region,
 		}).Debug("bucket details")
 
-		isMultiRegion := false
-		isAccountedFor := false
-		if bucket.Location == "US" {
-			isMultiRegion = true
-			if _, ok := l.multiRegion[bucket.Name]; !ok {
-				l.multiRegion[bucket.Name] = loc
-			} else {
-				isAccountedFor = true
-			}
-		}
-
-		if !isMultiRegion && loc != *opts.Region {
-			continue
-		}
-
-		if isMultiRegion && isAccountedFor {
+		if region != *opts.Region {
 			continue
 		}
 
 		resources = append(resources, &StorageBucket{
 			svc:         l.svc,
 			project:     opts.Project,
-			region:      ptr.String(loc),
+			region:      ptr.String(region),
 			Name:        ptr.String(bucket.Name),
 			Labels:      bucket.Labels,
-			MultiRegion: ptr.Bool(isMultiRegion),
+			MultiRegion: ptr.Bool(gcputil.IsPseudoRegion(region)),
+			CreatedAt:   nuke.CreatedAtFromTime(bucket.Created),
 		})
 	}
 
@@ -141,6 +118,7 @@ type StorageBucket struct {
 	Name        *string
 	Labels      map[string]string `property:"tagPrefix=label"`
 	MultiRegion *bool
+	CreatedAt   *time.Time
 }
 
 func (r *StorageBucket) Filter() error {
@@ -193,6 +171,15 @@ func (r *StorageBucket) Remove(ctx context.Context) error {
 	return err
 }
 
+// Mark adds the mark to the labels of the bucket, see nuke.Marker
+func (r *StorageBucket) Mark(ctx context.Context, markedAt time.Time) error {
+	attrs := storage.BucketAttrsToUpdate{}
+	attrs.SetLabel(nuke.MarkedLabel, nuke.MarkValue(markedAt))
+
+	_, err := r.svc.Bucket(*r.Name).Update(ctx, attrs)
+	return err
+}
+
 func (r *StorageBucket) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -0,0 +1,125 @@
+package resources
+
+import (
+	"testing"
+
+	"google.golang.org/api/option"
+
+	"github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/gcp-nuke/pkg/nuke"
+	"github.com/ekristen/gcp-nuke/pkg/testutil"
+)
+
+func TestStorageListers(t *testing.T) {
+	storageClientOptions := func(fake *testutil.Server) []option.ClientOption {
+		return fake.StorageClientOptions()
+	}
+
+	runListerTests(t, []listerTest{
+		{
+			name:          "bucket",
+			lister:        &StorageBucketLister{},
+			region:        testRegion,
+			clientOptions: storageClientOptions,
+			setup: func(fake *testutil.Server) {
+				fake.AddBuckets(
+					testutil.Item{"name": "regional-bucket", "location": "US-EAST1"},
+					testutil.Item{"name": "other-region-bucket", "location": "EUROPE-WEST1"},
+					testutil.Item{"name": "multi-region-bucket", "location": "US"},
+					testutil.Item{
+						"name":     "gcf-sources",
+						"location": "US-EAST1",
+						"labels":   map[string]string{"goog-managed-by": "cloudfunctions"},
+					},
+				)
+				fake.AddObjects("regional-bucket",
+					testutil.Item{"name": "object-1", "generation": "1"},
+					testutil.Item{"name": "object-2", "generation": "3"})
+			},
+			want:     []string{"regional-bucket", "gcf-sources"},
+			filtered: []string{"gcf-sources"},
+			removed: []string{
+				"DELETE /storage/v1/b/regional-bucket/o/object-1",
+				"DELETE /storage/v1/b/regional-bucket/o/object-2",
+				"DELETE /storage/v1/b/regional-bucket",
+			},
+		},
+		{
+			name:          "bucket-multi-region",
+			lister:        &StorageBucketLister{},
+			region:        "multi-region:us",
+			clientOptions: storageClientOptions,
+			setup: func(fake *testutil.Server) {
+				fake.AddBuckets(
+					testutil.Item{"name": "regional-bucket", "location": "US-EAST1"},
+					testutil.Item{"name": "us-bucket", "location": "US"},
+					testutil.Item{"name": "eu-bucket", "location": "EU"},
+					testutil.Item{"name": "dual-region-bucket", "location": "NAM4"},
+				)
+			},
+			want:    []string{"us-bucket"},
+			removed: []string{"DELETE /storage/v1/b/us-bucket"},
+		},
+		{
+			name:          "bucket-dual-region",
+			lister:        &StorageBucketLister{},
+			region:        "dual-region:nam4",
+			clientOptions: storageClientOptions,
+			setup: func(fake *testutil.Server) {
+				fake.AddBuckets(
+					testutil.Item{"name": "us-bucket", "location": "US"},
+					testutil.Item{"name": "dual-region-bucket", "location": "NAM4"},
+				)
+			},
+			want:    []string{"dual-region-bucket"},
+			removed: []string{"DELETE /storage/v1/b/dual-region-bucket"},
+		},
+		{
+			name:          "bucket-delete-google-managed",
+			lister:        &StorageBucketLister{},
+			region:        testRegion,
+			clientOptions: storageClientOptions,
+			settings:      settings.Setting{"DeleteGoogleManagedBuckets": true},
+			setup: func(fake *testutil.Server) {
+				fake.AddBuckets(testutil.Item{
+					"name":     "gcf-sources",
+					"location": "US-EAST1",
+					"labels":   map[string]string{"goog-managed-by": "cloudfunctions"},
+				})
+			},
+			want:    []string{"gcf-sources"},
+			removed: []string{"DELETE /storage/v1/b/gcf-sources"},
+		},
+		{
+			name:          "bucket-object",
+			lister:        &StorageBucketObjectLister{},
+			region:        "global",
+			clientOptions: storageClientOptions,
+			setup: func(fake *testutil.Server) {
+				fake.AddBuckets(testutil.Item{"name": "bucket", "location": "US-EAST1"})
+				fake.AddObjects("bucket", testutil.Item{"name": "object-1", "generation": "1"})
+			},
+			want:    []string{"object-1"},
+			removed: []string{"DELETE /storage/v1/b/bucket/o/object-1"},
+		},
+	})
+}
+
+// TestStorageBucketListAfterRemove lists, removes and lists again through the same options like libnuke does to check
+// that a removed resource is gone, the listing cache is disabled once the scan is over
+func TestStorageBucketListAfterRemove(t *testing.T) {
+	fake := testutil.NewServer(t)
+	fake.AddBuckets(testutil.Item{"name": "bucket", "location": "US-EAST1"})
+
+	opts := newTestListerOpts(testRegion, fake.StorageClientOptions())
+	opts.Cache = nuke.NewListingCache()
+
+	lister := &StorageBucketLister{}
+
+	removeResources(t, listResources(t, lister, opts, []string{"bucket"}), nil)
+	listResources(t, lister, opts, []string{"bucket"})
+
+	opts.Cache.Disable()
+	listResources(t, lister, opts, nil)
+}
@@ -2,17 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -51,7 +49,7 @@ func (l *VertexAIEndpointLister) List(ctx context.Context, o interface{}) ([]res
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = aiplatform.NewEndpointClient(ctx, opts.ClientOptions...)
+		l.svc, err = aiplatform.NewEndpointClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,17 +59,14 @@ func (l *VertexAIEndpointLister) List(ctx context.Context, o interface{}) ([]res
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	it := l.svc.ListEndpoints(ctx, req)
-	for {
-		endpoint, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate vertex ai endpoints")
-			break
-		}
+	endpoints, err := nuke.ListAll(ctx, opts, VertexAIEndpointResource, func() nuke.Iterator[*aiplatformpb.Endpoint] {
+		return l.svc.ListEndpoints(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, endpoint := range endpoints {
 		nameParts := strings.Split(endpoint.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -83,6 +78,9 @@ func (l *VertexAIEndpointLister) List(ctx context.Context, o interface{}) ([]res
 			Name:        ptr.String(name),
 			DisplayName: ptr.String(endpoint.DisplayName),
 			Labels:      endpoint.Labels,
+			CreatedAt:   nuke.CreatedAtFromProto(endpoint.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -92,11 +90,13 @@ func (l *VertexAIEndpointLister) List(ctx context.Context, o interface{}) ([]res
 type VertexAIEndpoint struct {
 	svc         *aiplatform.EndpointClient
 	removeOp    *aiplatform.DeleteEndpointOperation
+	removeWait  nuke.OperationTracker
 	Project     *string
 	Region      *string
 	FullName    *string
 	Name        *string           `description:"The resource name of the endpoint"`
 	DisplayName *string           `description:"The display name of the endpoint"`
+	CreatedAt   *time.Time        `description:"The time the endpoint was created"`
 	Labels      map[string]string `property:"tagPrefix=label" description:"Labels associated with the endpoint"`
 }
 
@@ -115,19 +115,22 @@ func (r *VertexAIEndpoint) String() string {
 	return *r.DisplayName
 }
 
+// Mark adds the mark to the labels of the endpoint, see nuke.Marker
+func (r *VertexAIEndpoint) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.UpdateEndpoint(ctx, &aiplatformpb.UpdateEndpointRequest{
+		Endpoint: &aiplatformpb.Endpoint{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
+
 func (r *VertexAIEndpoint) HandleWait(ctx context.Context) error {
 	if r.removeOp == nil {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("vertex ai endpoint remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
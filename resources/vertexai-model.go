@@ -2,17 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
-	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -52,7 +50,7 @@ func (l *VertexAIModelLister) List(ctx context.Context, o interface{}) ([]resour
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = aiplatform.NewModelClient(ctx, opts.ClientOptions...)
+		l.svc, err = aiplatform.NewModelClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -62,17 +60,14 @@ func (l *VertexAIModelLister) List(ctx context.Context, o interface{}) ([]resour
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	it := l.svc.ListModels(ctx, req)
-	for {
-		model, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate vertex ai models")
-			break
-		}
+	models, err := nuke.ListAll(ctx, opts, VertexAIModelResource, func() nuke.Iterator[*aiplatformpb.Model] {
+		return l.svc.ListModels(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, model := range models {
 		nameParts := strings.Split(model.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -84,6 +79,9 @@ func (l *VertexAIModelLister) List(ctx context.Context, o interface{}) ([]resour
 			Name:        ptr.String(name),
 			DisplayName: ptr.String(model.DisplayName),
 			Labels:      model.Labels,
+			CreatedAt:   nuke.CreatedAtFromProto(model.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -93,11 +91,13 @@ func (l *VertexAIModelLister) List(ctx context.Context, o interface{}) ([]resour
 type VertexAIModel struct {
 	svc         *aiplatform.ModelClient
 	removeOp    *aiplatform.DeleteModelOperation
+	removeWait  nuke.OperationTracker
 	Project     *string
 	Region      *string
 	FullName    *string
 	Name        *string           `description:"The resource name of the model"`
 	DisplayName *string           `description:"The display name of the model"`
+	CreatedAt   *time.Time        `description:"The time the model was created"`
 	Labels      map[string]string `property:"tagPrefix=label" description:"Labels associated with the model"`
 }
 
@@ -116,19 +116,22 @@ func (r *VertexAIModel) String() string {
 	return *r.DisplayName
 }
 
+// Mark adds the mark to the labels of the model, see nuke.Marker
+func (r *VertexAIModel) Mark(ctx context.Context, markedAt time.Time) error {
+	_, err := r.svc.UpdateModel(ctx, &aiplatformpb.UpdateModelRequest{
+		Model: &aiplatformpb.Model{
+			Name:   *r.FullName,
+			Labels: nuke.WithMark(r.Labels, markedAt),
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"labels"}},
+	})
+	return err
+}
+
 func (r *VertexAIModel) HandleWait(ctx context.Context) error {
 	if r.removeOp == nil {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("vertex ai model remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
@@ -2,17 +2,15 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	aiplatform "cloud.google.com/go/aiplatform/apiv1"
 	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
-	"google.golang.org/api/iterator"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -51,7 +49,7 @@ func (l *VertexAIPipelineJobLister) List(ctx context.Context, o interface{}) ([]
 
 	if l.svc == nil {
 		var err error
-		l.svc, err = aiplatform.NewPipelineClient(ctx, opts.ClientOptions...)
+		l.svc, err = aiplatform.NewPipelineClient(ctx, opts.GRPCClientOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -61,17 +59,14 @@ func (l *VertexAIPipelineJobLister) List(ctx context.Context, o interface{}) ([]
 		Parent: "projects/" + *opts.Project + "/locations/" + *opts.Region,
 	}
 
-	it := l.svc.ListPipelineJobs(ctx, req)
-	for {
-		job, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate vertex ai pipeline jobs")
-			break
-		}
+	jobs, err := nuke.ListAll(ctx, opts, VertexAIPipelineJobResource, func() nuke.Iterator[*aiplatformpb.PipelineJob] {
+		return l.svc.ListPipelineJobs(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, job := range jobs {
 		nameParts := strings.Split(job.Name, "/")
 		name := nameParts[len(nameParts)-1]
 
@@ -84,6 +79,9 @@ func (l *VertexAIPipelineJobLister) List(ctx context.Context, o interface{}) ([]
 			DisplayName: ptr.String(job.DisplayName),
 			State:       ptr.String(job.State.String()),
 			Labels:      job.Labels,
+			CreatedAt:   nuke.CreatedAtFromProto(job.CreateTime),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -93,12 +91,14 @@ func (l *VertexAIPipelineJobLister) List(ctx context.Context, o interface{}) ([]
 type VertexAIPipelineJob struct {
 	svc         *aiplatform.PipelineClient
 	removeOp    *aiplatform.DeletePipelineJobOperation
+	removeWait  nuke.OperationTracker
 	Project     *string
 	Region      *string
 	FullName    *string
 	Name        *string           `description:"The resource name of the pipeline job"`
 	DisplayName *string           `description:"The display name of the pipeline job"`
 	State       *string           `description:"The current state of the pipeline job"`
+	CreatedAt   *time.Time        `description:"The time the pipeline job was created"`
 	Labels      map[string]string `property:"tagPrefix=label" description:"Labels associated with the pipeline job"`
 }
 
@@ -132,14 +132,5 @@ func (r *VertexAIPipelineJob) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("vertex ai pipeline job remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.LROOperation(r.removeOp))
 }
@@ -2,17 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
-	liberror "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -60,17 +54,14 @@ func (l *VPCGlobalIPAddressLister) List(ctx context.Context, o interface{}) ([]r
 	req := &computepb.ListGlobalAddressesRequest{
 		Project: *opts.Project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, VPCGlobalIPAddressResource, func() nuke.Iterator[*computepb.Address] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &VPCGlobalIPAddress{
 			svc:         l.svc,
 			project:     opts.Project,
@@ -78,6 +69,13 @@ func (l *VPCGlobalIPAddressLister) List(ctx context.Context, o interface{}) ([]r
 			Name:        resp.Name,
 			Address:     resp.Address,
 			AddressType: resp.AddressType,
+			Status:      resp.Status,
+			CreatedAt:   nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+			Labels:      resp.Labels,
+
+			labelFingerprint: resp.LabelFingerprint,
+			removeWait:       opts.OperationTracker(),
+			waitTimeout:      opts.WaitTimeout,
 		})
 	}
 
@@ -85,13 +83,19 @@ func (l *VPCGlobalIPAddressLister) List(ctx context.Context, o interface{}) ([]r
 }
 
 type VPCGlobalIPAddress struct {
-	svc         *compute.GlobalAddressesClient
-	removeOp    *compute.Operation
-	project     *string
-	region      *string
-	Name        *string
-	Address     *string
-	AddressType *string
+	svc              *compute.GlobalAddressesClient
+	removeOp         *compute.Operation
+	removeWait       nuke.OperationTracker
+	project          *string
+	region           *string
+	labelFingerprint *string
+	waitTimeout      time.Duration
+	Name             *string
+	Address          *string
+	AddressType      *string
+	Status           *string
+	CreatedAt        *time.Time
+	Labels           map[string]string `property:"tagPrefix=label"`
 }
 
 func (r *VPCGlobalIPAddress) Remove(ctx context.Context) (err error) {
@@ -110,27 +114,27 @@ func (r *VPCGlobalIPAddress) String() string {
 	return *r.Name
 }
 
-func (r *VPCGlobalIPAddress) HandleWait(ctx context.Context) error {
-	if r.removeOp == nil {
-		return nil
-	}
-
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("network remove op polling encountered error")
+// Mark adds the mark to the labels of the address, see nuke.Marker
+func (r *VPCGlobalIPAddress) Mark(ctx context.Context, markedAt time.Time) error {
+	op, err := r.svc.SetLabels(ctx, &computepb.SetLabelsGlobalAddressRequest{
+		Project:  *r.project,
+		Resource: *r.Name,
+		GlobalSetLabelsRequestResource: &computepb.GlobalSetLabelsRequest{
+			Labels:           nuke.WithMark(r.Labels, markedAt),
+			LabelFingerprint: r.labelFingerprint,
+		},
+	})
+	if err != nil {
 		return err
 	}
 
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
+	return nuke.WaitOperation(ctx, r.waitTimeout, nuke.ComputeOperation(op))
+}
 
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Error("unable to delete vpc global ip address")
-			return removeErr
-		}
+func (r *VPCGlobalIPAddress) HandleWait(ctx context.Context) error {
+	if r.removeOp == nil {
+		return nil
 	}
 
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
@@ -2,9 +2,8 @@ package resources
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"strings"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -14,8 +13,6 @@ import (
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
-	"github.com/sirupsen/logrus"
-	"google.golang.org/api/iterator"
 )
 
 func isRetryableError(err error) bool {
@@ -73,21 +70,21 @@ func (l *VPCNetworkLister) List(ctx context.Context, o interface{}) ([]resource.
 	req := &computepb.ListNetworksRequest{
 		Project: *opts.Project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, VPCNetworkResource, func() nuke.Iterator[*computepb.Network] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &VPCNetwork{
-			svc:     l.svc,
-			project: opts.Project,
-			Name:    resp.Name,
+			svc:       l.svc,
+			project:   opts.Project,
+			Name:      resp.Name,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
+
+			removeWait: opts.OperationTracker(),
 		})
 	}
 
@@ -95,10 +92,12 @@ func (l *VPCNetworkLister) List(ctx context.Context, o interface{}) ([]resource.
 }
 
 type VPCNetwork struct {
-	svc      *compute.NetworksClient
-	removeOp *compute.Operation
-	project  *string
-	Name     *string
+	svc        *compute.NetworksClient
+	removeOp   *compute.Operation
+	removeWait nuke.OperationTracker
+	project    *string
+	Name       *string
+	CreatedAt  *time.Time
 }
 
 func (r *VPCNetwork) Remove(ctx context.Context) error {
@@ -134,22 +133,5 @@ func (r *VPCNetwork) HandleWait(ctx context.Context) error {
 		return nil
 	}
 
-	if err := r.removeOp.Poll(ctx); err != nil {
-		logrus.WithError(err).Trace("network remove op polling encountered error")
-		return err
-	}
-
-	if !r.removeOp.Done() {
-		return liberror.ErrWaitResource("waiting for operation to complete")
-	}
-
-	if r.removeOp.Done() {
-		if r.removeOp.Proto().GetError() != nil {
-			removeErr := fmt.Errorf("delete error on '%s': %s", r.removeOp.Proto().GetTargetLink(), r.removeOp.Proto().GetHttpErrorMessage())
-			logrus.WithError(removeErr).WithField("status_code", r.removeOp.Proto().GetError()).Error("unable to delete network")
-			return removeErr
-		}
-	}
-
-	return nil
+	return r.removeWait.Wait(ctx, nuke.ComputeOperation(r.removeOp))
 }
@@ -2,14 +2,11 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -61,17 +58,14 @@ func (l *VPCRouteLister) List(ctx context.Context, o interface{}) ([]resource.Re
 	req := &computepb.ListRoutesRequest{
 		Project: *opts.Project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, VPCRouteResource, func() nuke.Iterator[*computepb.Route] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &VPCRoute{
 			svc:         l.svc,
 			Project:     opts.Project,
@@ -79,6 +73,7 @@ func (l *VPCRouteLister) List(ctx context.Context, o interface{}) ([]resource.Re
 			Name:        resp.Name,
 			RouteType:   resp.RouteType,
 			Description: resp.Description,
+			CreatedAt:   nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -93,6 +88,7 @@ type VPCRoute struct {
 	Network     *string
 	RouteType   *string
 	Description *string `property:"-"`
+	CreatedAt   *time.Time
 }
 
 func (r *VPCRoute) Filter() error {
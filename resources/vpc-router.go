@@ -2,11 +2,7 @@ package resources
 
 import (
 	"context"
-	"errors"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
+	"time"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
@@ -59,22 +55,20 @@ func (l *VPCRouterLister) List(ctx context.Context, o interface{}) ([]resource.R
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, VPCRouterResource, func() nuke.Iterator[*computepb.Router] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &VPCRouter{
-			svc:     l.svc,
-			Project: opts.Project,
-			Region:  opts.Region,
-			Name:    resp.Name,
+			svc:       l.svc,
+			Project:   opts.Project,
+			Region:    opts.Region,
+			Name:      resp.Name,
+			CreatedAt: nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -82,10 +76,11 @@ func (l *VPCRouterLister) List(ctx context.Context, o interface{}) ([]resource.R
 }
 
 type VPCRouter struct {
-	svc     *compute.RoutersClient
-	Project *string
-	Region  *string
-	Name    *string
+	svc       *compute.RoutersClient
+	Project   *string
+	Region    *string
+	Name      *string
+	CreatedAt *time.Time
 }
 
 func (r *VPCRouter) Remove(ctx context.Context) error {
@@ -2,15 +2,13 @@ package resources
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
-	"google.golang.org/api/iterator"
-
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 
@@ -33,8 +31,10 @@ func init() {
 }
 
 type VPCSubnetLister struct {
-	svc               *compute.SubnetworksClient
-	networksSvc       *compute.NetworksClient
+	svc         *compute.SubnetworksClient
+	networksSvc *compute.NetworksClient
+
+	// networkAutoCreate is keyed by project and network name, the lister lists every project of the run
 	networkAutoCreate map[string]bool
 }
 
@@ -76,17 +76,14 @@ func (l *VPCSubnetLister) List(ctx context.Context, o interface{}) ([]resource.R
 		Project: *opts.Project,
 		Region:  *opts.Region,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, VPCSubnetResource, func() nuke.Iterator[*computepb.Subnetwork] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		networkParts := strings.Split(resp.GetNetwork(), "/")
 		networkName := networkParts[len(networkParts)-1]
 
@@ -101,6 +98,7 @@ func (l *VPCSubnetLister) List(ctx context.Context, o interface{}) ([]resource.R
 			IPV4Range:   resp.IpCidrRange,
 			IPV6Range:   resp.Ipv6CidrRange,
 			AutoCreated: ptr.Bool(autoCreated),
+			CreatedAt:   nuke.ParseCreatedAtPtr(resp.CreationTimestamp),
 		})
 	}
 
@@ -108,7 +106,8 @@ func (l *VPCSubnetLister) List(ctx context.Context, o interface{}) ([]resource.R
 }
 
 func (l *VPCSubnetLister) isNetworkAutoCreate(ctx context.Context, project, networkName string) bool {
-	if autoCreate, ok := l.networkAutoCreate[networkName]; ok {
+	key := project + "/" + networkName
+	if autoCreate, ok := l.networkAutoCreate[key]; ok {
 		return autoCreate
 	}
 
@@ -118,12 +117,12 @@ func (l *VPCSubnetLister) isNetworkAutoCreate(ctx context.Context, project, netw
 	})
 	if err != nil {
 		logrus.WithError(err).WithField("network", networkName).Trace("failed to get network")
-		l.networkAutoCreate[networkName] = false
+		l.networkAutoCreate[key] = false
 		return false
 	}
 
 	autoCreate := network.GetAutoCreateSubnetworks()
-	l.networkAutoCreate[networkName] = autoCreate
+	l.networkAutoCreate[key] = autoCreate
 	return autoCreate
 }
 
@@ -136,6 +135,7 @@ type VPCSubnet struct {
 	IPV4Range   *string
 	IPV6Range   *string
 	AutoCreated *bool
+	CreatedAt   *time.Time
 }
 
 func (r *VPCSubnet) Filter() error {
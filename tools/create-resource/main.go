@@ -16,11 +16,6 @@ const resourceTemplate = `package resources
 
 import (
 	"context"
-	"errors"
-
-	"github.com/sirupsen/logrus"
-
-	"google.golang.org/api/iterator"
 
 	{{.Service}} "cloud.google.com/go/{{.Service}}/apiv1"
 	"cloud.google.com/go/{{.Service}}/apiv1/{{.Service}}pb"
@@ -68,17 +63,14 @@ func (l *{{.Combined}}Lister) List(ctx context.Context, o interface{}) ([]resour
 	req := &{{.Service}}pb.List{{.ResourceTypeTitlePlural}}Request{
 		project: *opts.project,
 	}
-	it := l.svc.List(ctx, req)
-	for {
-		resp, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
-		}
-		if err != nil {
-			logrus.WithError(err).Error("unable to iterate networks")
-			break
-		}
+	items, err := nuke.ListAll(ctx, opts, {{.Combined}}Resource, func() nuke.Iterator[*{{.Service}}pb.{{.ResourceTypeTitle}}] {
+		return l.svc.List(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	for _, resp := range items {
 		resources = append(resources, &{{.Combined}}{
 			svc:     l.svc,
 			Name:    resp.Name,